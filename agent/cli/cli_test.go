@@ -16,10 +16,17 @@ func TestGetCommands_HasPluginsAndSkillsNamespaces(t *testing.T) {
 	assert.Nil(t, plugins.Action)
 	pluginsNames := make([]string, 0, len(plugins.Subcommands))
 	for _, sub := range plugins.Subcommands {
-		assert.NotNil(t, sub.Action, "plugins subcommand %q must have an Action", sub.Name)
 		pluginsNames = append(pluginsNames, sub.Name)
+		if sub.Name == "marketplace" {
+			assert.Nil(t, sub.Action)
+			require.Len(t, sub.Subcommands, 1)
+			assert.Equal(t, "generate", sub.Subcommands[0].Name)
+			assert.NotNil(t, sub.Subcommands[0].Action, "plugins marketplace generate must have an Action")
+			continue
+		}
+		assert.NotNil(t, sub.Action, "plugins subcommand %q must have an Action", sub.Name)
 	}
-	assert.ElementsMatch(t, []string{"publish", "install", "update", "delete", "list", "search"}, pluginsNames)
+	assert.ElementsMatch(t, []string{"publish", "install", "update", "delete", "list", "search", "sync", "verify", "export", "import", "rollback", "outdated", "validate", "marketplace", "deprecate", "diff", "convert"}, pluginsNames)
 
 	skills := commands[1]
 	assert.Equal(t, "skills", skills.Name)
@@ -30,7 +37,7 @@ func TestGetCommands_HasPluginsAndSkillsNamespaces(t *testing.T) {
 		skillsNames = append(skillsNames, sub.Name)
 	}
 	assert.ElementsMatch(t,
-		[]string{"list", "publish", "install", "update", "search", "delete"},
+		[]string{"list", "publish", "install", "update", "search", "delete", "sync", "verify", "export", "import", "gc", "rollback", "outdated", "validate", "deprecate", "diff"},
 		skillsNames,
 	)
 }
@@ -12,6 +12,9 @@ import (
 type AgentConfig struct {
 	GlobalDir  string `json:"globalDir"`
 	ProjectDir string `json:"projectDir"`
+	// DetectPaths are files or directories whose presence shows the agent is in use (e.g. "CLAUDE.md", "~/.claude").
+	// Relative paths are resolved against the project directory.
+	DetectPaths []string `json:"detectPaths,omitempty"`
 }
 
 // AgentSpec is a resolved agent; FromConfig marks JSON vs built-in.
@@ -0,0 +1,359 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	// BundleManifestFileName is the index at the root of an export bundle.
+	BundleManifestFileName = "bundle.json"
+	// BundleSchemaVersion is bumped when the bundle layout changes incompatibly.
+	BundleSchemaVersion = 1
+
+	bundlePackagesDir      = "packages"
+	bundleEvidenceFileName = "evidence.json"
+	bundleEnvelopeFileName = "evidence-%d.dsse.json"
+)
+
+// ErrNoBundledEvidence is returned for bundled packages that carry no evidence an import could verify.
+var ErrNoBundledEvidence = errors.New("the export bundle carries no evidence for this version")
+
+// downloadBundlePackageZip is swappable in tests.
+var downloadBundlePackageZip = DownloadPackageZip
+
+// uploadBundlePackageZip is swappable in tests.
+var uploadBundlePackageZip = UploadPublishArtifact
+
+// BundleManifest is bundle.json: the packages of an export bundle and where their files are.
+//
+// Layout:
+//
+//	bundle.json
+//	packages/skill/web-search/1.4.0/web-search-1.4.0.zip
+//	packages/skill/web-search/1.4.0/evidence.json
+//	packages/skill/web-search/1.4.0/evidence-1.dsse.json
+type BundleManifest struct {
+	SchemaVersion int `json:"schemaVersion"`
+	// Source is the Artifactory URL the packages were exported from.
+	Source   string          `json:"source,omitempty"`
+	Packages []BundlePackage `json:"packages"`
+}
+
+// BundlePackage is one package zip in an export bundle. Zip, Evidence and Envelopes are slash-separated bundle paths.
+type BundlePackage struct {
+	Kind    string `json:"kind"`
+	Slug    string `json:"slug"`
+	Version string `json:"version"`
+	// Repo is the repository the package was exported from.
+	Repo   string `json:"repo"`
+	SHA256 string `json:"sha256"`
+	Zip    string `json:"zip"`
+	// Evidence is set when the package evidence verified at export time; the file holds the exported evidence.
+	Evidence string `json:"evidence,omitempty"`
+	// Envelopes are the signed DSSE envelopes of the evidence, which an import verifies against --public-keys.
+	Envelopes []string `json:"envelopes,omitempty"`
+}
+
+// String renders the package for logs and errors, e.g. skill 'web-search' 1.4.0.
+func (p BundlePackage) String() string {
+	return fmt.Sprintf("%s '%s' %s", p.Kind, p.Slug, p.Version)
+}
+
+// ExportOptions configures ExportPackages.
+type ExportOptions struct {
+	ServerDetails *config.ServerDetails
+	RepoKey       string
+	// Kind is LockKindSkill or LockKindPlugin; every request must be of this kind.
+	Kind     string
+	Requests []PackageDependency
+	// ResolveVersion picks the version to export for a request ("" means latest).
+	ResolveVersion func(slug, requested string) (string, error)
+	OutputPath     string
+}
+
+// ExportPackages downloads each requested package zip with its evidence and writes them to one bundle archive.
+func ExportPackages(opts ExportOptions) ([]BundlePackage, error) {
+	tmpDir, err := os.MkdirTemp("", "agent-export-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer func() {
+		// Best-effort cleanup of export temp dir.
+		_ = os.RemoveAll(tmpDir)
+	}()
+
+	source := ""
+	if opts.ServerDetails != nil {
+		source = opts.ServerDetails.GetArtifactoryUrl()
+	}
+	writer := NewBundleWriter(filepath.Join(tmpDir, "bundle"), source)
+	seen := map[string]bool{}
+	for _, request := range opts.Requests {
+		if seen[request.Slug] {
+			return nil, fmt.Errorf("%s '%s' is listed more than once", opts.Kind, request.Slug)
+		}
+		seen[request.Slug] = true
+		if err := exportPackage(opts, writer, request, filepath.Join(tmpDir, "download", request.Slug)); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Write(opts.OutputPath); err != nil {
+		return nil, err
+	}
+	return writer.manifest.Packages, nil
+}
+
+func exportPackage(opts ExportOptions, writer *BundleWriter, request PackageDependency, downloadDir string) error {
+	version, err := opts.ResolveVersion(request.Slug, request.Version)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(downloadDir, InstallDirMode); err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	zipPath, err := downloadBundlePackageZip(opts.ServerDetails, opts.RepoKey, request.Slug, version, downloadDir, opts.Kind)
+	if err != nil {
+		return fmt.Errorf("download failed: %w", err)
+	}
+	sha256Hex, err := ComputeSHA256(zipPath)
+	if err != nil {
+		return fmt.Errorf("compute package checksum: %w", err)
+	}
+	pkg := BundlePackage{Kind: opts.Kind, Slug: request.Slug, Version: version, Repo: opts.RepoKey, SHA256: sha256Hex}
+
+	// Only evidence that verifies here is carried along, with its signed envelopes so an import can check it offline
+	// against the keys it trusts.
+	evidencePath := filepath.Join(downloadDir, bundleEvidenceFileName)
+	var envelopePaths []string
+	if err := VerifyPackageEvidence(opts.ServerDetails, opts.RepoKey, request.Slug, version); err != nil {
+		log.Warn(fmt.Sprintf("Exporting %s without evidence: %s", pkg, err.Error()))
+		evidencePath = ""
+	} else if err := ExportPackageEvidence(opts.ServerDetails, opts.RepoKey, request.Slug, version, evidencePath); err != nil {
+		return fmt.Errorf("export evidence of %s: %w", pkg, err)
+	} else if envelopePaths, err = exportEvidenceEnvelopes(opts.ServerDetails, evidencePath, downloadDir); err != nil {
+		return fmt.Errorf("export evidence of %s: %w", pkg, err)
+	}
+	log.Info(fmt.Sprintf("Exporting %s", pkg))
+	return writer.Add(pkg, zipPath, evidencePath, envelopePaths...)
+}
+
+// BundleWriter stages package zips and evidence in a directory and archives them as an export bundle.
+type BundleWriter struct {
+	stagingDir string
+	manifest   BundleManifest
+}
+
+// NewBundleWriter creates a writer that stages files under stagingDir.
+func NewBundleWriter(stagingDir, source string) *BundleWriter {
+	return &BundleWriter{
+		stagingDir: stagingDir,
+		manifest:   BundleManifest{SchemaVersion: BundleSchemaVersion, Source: source, Packages: []BundlePackage{}},
+	}
+}
+
+// Add copies a package zip, and its evidence and signed envelopes when evidencePath is set, into the bundle.
+func (w *BundleWriter) Add(pkg BundlePackage, zipPath, evidencePath string, envelopePaths ...string) error {
+	packageDir := path.Join(bundlePackagesDir, pkg.Kind, pkg.Slug, pkg.Version)
+	if err := os.MkdirAll(filepath.Join(w.stagingDir, filepath.FromSlash(packageDir)), InstallDirMode); err != nil {
+		return fmt.Errorf("create bundle directory: %w", err)
+	}
+	pkg.Zip = path.Join(packageDir, filepath.Base(zipPath))
+	if err := CopyFile(zipPath, filepath.Join(w.stagingDir, filepath.FromSlash(pkg.Zip))); err != nil {
+		return fmt.Errorf("add %s to bundle: %w", pkg, err)
+	}
+	pkg.Evidence, pkg.Envelopes = "", nil
+	if evidencePath != "" {
+		pkg.Evidence = path.Join(packageDir, bundleEvidenceFileName)
+		if err := CopyFile(evidencePath, filepath.Join(w.stagingDir, filepath.FromSlash(pkg.Evidence))); err != nil {
+			return fmt.Errorf("add evidence of %s to bundle: %w", pkg, err)
+		}
+		for _, envelopePath := range envelopePaths {
+			envelope := path.Join(packageDir, filepath.Base(envelopePath))
+			if err := CopyFile(envelopePath, filepath.Join(w.stagingDir, filepath.FromSlash(envelope))); err != nil {
+				return fmt.Errorf("add evidence of %s to bundle: %w", pkg, err)
+			}
+			pkg.Envelopes = append(pkg.Envelopes, envelope)
+		}
+	}
+	w.manifest.Packages = append(w.manifest.Packages, pkg)
+	return nil
+}
+
+// Write writes bundle.json and archives the staged files as a zip at outputPath.
+func (w *BundleWriter) Write(outputPath string) error {
+	data, err := json.MarshalIndent(w.manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", BundleManifestFileName, err)
+	}
+	if err := os.MkdirAll(w.stagingDir, InstallDirMode); err != nil {
+		return fmt.Errorf("create bundle directory: %w", err)
+	}
+	// #nosec G306 -- bundle manifest is staged in a private temp dir.
+	if err := os.WriteFile(filepath.Join(w.stagingDir, BundleManifestFileName), data, DefaultFileMode); err != nil {
+		return fmt.Errorf("write %s: %w", BundleManifestFileName, err)
+	}
+	files, _, err := CollectPublishFiles(w.stagingDir)
+	if err != nil {
+		return fmt.Errorf("collect bundle files: %w", err)
+	}
+	if _, err := writePublishZip(outputPath, w.stagingDir, files, zipEpoch, false); err != nil {
+		return fmt.Errorf("write bundle %s: %w", outputPath, err)
+	}
+	return nil
+}
+
+// Bundle is an export bundle extracted to Dir.
+type Bundle struct {
+	Dir      string
+	Manifest BundleManifest
+}
+
+// OpenBundle extracts the bundle archive into dir, validates bundle.json, and checks every zip against
+// the SHA-256 recorded at export time.
+func OpenBundle(archivePath, dir string) (*Bundle, error) {
+	if err := UnzipFile(archivePath, dir); err != nil {
+		return nil, fmt.Errorf("extract bundle %s: %w", archivePath, err)
+	}
+	// #nosec G304 -- bundle.json is read from the directory the bundle was just extracted to.
+	data, err := os.ReadFile(filepath.Join(dir, BundleManifestFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%s is not an export bundle: %s is missing", archivePath, BundleManifestFileName)
+		}
+		return nil, fmt.Errorf("read %s: %w", BundleManifestFileName, err)
+	}
+	bundle := &Bundle{Dir: dir}
+	if err := json.Unmarshal(data, &bundle.Manifest); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", BundleManifestFileName, err)
+	}
+	if bundle.Manifest.SchemaVersion > BundleSchemaVersion {
+		return nil, fmt.Errorf("bundle schema version %d is newer than this CLI supports (%d); upgrade JFrog CLI", bundle.Manifest.SchemaVersion, BundleSchemaVersion)
+	}
+	for _, pkg := range bundle.Manifest.Packages {
+		if err := bundle.validatePackage(pkg); err != nil {
+			return nil, err
+		}
+	}
+	return bundle, nil
+}
+
+func (b *Bundle) validatePackage(pkg BundlePackage) error {
+	if pkg.Kind != LockKindSkill && pkg.Kind != LockKindPlugin {
+		return fmt.Errorf("%s: unsupported package kind %q", BundleManifestFileName, pkg.Kind)
+	}
+	if err := ValidateSlug(pkg.Slug); err != nil {
+		return fmt.Errorf("%s: %w", BundleManifestFileName, err)
+	}
+	if err := ValidateSemver(pkg.Version); err != nil {
+		return fmt.Errorf("%s: %s '%s': %w", BundleManifestFileName, pkg.Kind, pkg.Slug, err)
+	}
+	for _, rel := range append([]string{pkg.Zip, pkg.Evidence}, pkg.Envelopes...) {
+		if rel != "" && !b.contains(rel) {
+			return fmt.Errorf("%s: %s refers to %q outside the bundle", BundleManifestFileName, pkg, rel)
+		}
+	}
+	if pkg.Zip == "" {
+		return fmt.Errorf("%s: %s has no zip", BundleManifestFileName, pkg)
+	}
+	actual, err := ComputeSHA256(b.File(pkg.Zip))
+	if err != nil {
+		return fmt.Errorf("%s: %w", pkg, err)
+	}
+	if !strings.EqualFold(actual, pkg.SHA256) {
+		return fmt.Errorf("checksum mismatch for %s: %s records sha256 %s but %s is %s", pkg, BundleManifestFileName, pkg.SHA256, pkg.Zip, actual)
+	}
+	return nil
+}
+
+func (b *Bundle) contains(rel string) bool {
+	cleaned := filepath.Clean(b.File(rel))
+	return strings.HasPrefix(cleaned, filepath.Clean(b.Dir)+string(os.PathSeparator))
+}
+
+// File returns the absolute path of a slash-separated bundle path.
+func (b *Bundle) File(rel string) string {
+	return filepath.Join(b.Dir, filepath.FromSlash(rel))
+}
+
+// Packages returns the bundled packages of kind in bundle order and warns about packages of other kinds.
+func (b *Bundle) Packages(kind string) []BundlePackage {
+	var packages []BundlePackage
+	skipped := 0
+	for _, pkg := range b.Manifest.Packages {
+		if pkg.Kind == kind {
+			packages = append(packages, pkg)
+		} else {
+			skipped++
+		}
+	}
+	if skipped > 0 {
+		log.Warn(fmt.Sprintf("Skipping %d package(s) that are not %ss; import them with the matching 'jf agent' command", skipped, kind))
+	}
+	return packages
+}
+
+// BundlePublishRow is one row in the summary of publishing a bundle to another repository.
+type BundlePublishRow struct {
+	Name    string `json:"name" col-name:"Name"`
+	Version string `json:"version" col-name:"Version"`
+	Target  string `json:"target" col-name:"Target"`
+	Status  string `json:"status" col-name:"Status"`
+	Detail  string `json:"detail" col-name:"Detail"`
+}
+
+// PublishBundle uploads every bundled package of kind to <repoKey>/<slug>/<version>/ on the target Artifactory.
+// Evidence is not re-attached: it is signed by the source platform, whose keys the target usually does not trust.
+func PublishBundle(serverDetails *config.ServerDetails, repoKey string, bundle *Bundle, kind string) []BundlePublishRow {
+	var results []BundlePublishRow
+	for _, pkg := range bundle.Packages(kind) {
+		target := fmt.Sprintf("%s/%s/%s/", repoKey, pkg.Slug, pkg.Version)
+		row := BundlePublishRow{Name: pkg.Slug, Version: pkg.Version, Target: target + path.Base(pkg.Zip)}
+		log.Info(fmt.Sprintf("Publishing %s to %s", pkg, target))
+		if _, err := uploadBundlePackageZip(serverDetails, bundle.File(pkg.Zip), target, false, nil); err != nil {
+			row.Status, row.Detail = SummaryStatusFailed, "upload failed: "+err.Error()
+		} else {
+			row.Status, row.Detail = SummaryStatusOK, "published"
+		}
+		results = append(results, row)
+	}
+	if len(results) > 0 {
+		log.Info("Evidence from the source platform stays in the bundle and is not attached in the target repository; create it there with 'jf evd create' if installs require it.")
+	}
+	return results
+}
+
+type bundlePublishSummaryJSON struct {
+	Results []BundlePublishRow `json:"results"`
+}
+
+// PrintBundlePublishSummary renders a table or JSON summary of PublishBundle and fails when any upload failed.
+func PrintBundlePublishSummary(entityLabel string, results []BundlePublishRow, format string) error {
+	if strings.EqualFold(format, "json") {
+		data, err := json.MarshalIndent(bundlePublishSummaryJSON{Results: results}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal publish summary: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		log.Info(entityLabel + " bundle publish summary:")
+		if err := coreutils.PrintTable(results, "Published", "No "+strings.ToLower(entityLabel)+"s in the bundle", false); err != nil {
+			log.Warn("Failed to render publish summary: " + err.Error())
+		}
+	}
+	for _, result := range results {
+		if result.Status != SummaryStatusOK {
+			return fmt.Errorf("publishing failed for one or more %ss (see summary above)", strings.ToLower(entityLabel))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package common
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-evidence/evidence/cryptox"
+	"github.com/jfrog/jfrog-cli-evidence/evidence/dsse"
+	"github.com/jfrog/jfrog-cli-evidence/evidence/intoto"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// readEvidenceEnvelope is swappable in tests.
+var readEvidenceEnvelope = downloadEvidenceEnvelope
+
+// EvidenceKeys are the public keys an import trusts to have signed bundled evidence.
+type EvidenceKeys []dsse.Verifier
+
+// exportedEvidence is the part of the evidence written by ExportPackageEvidence that locates the signed envelopes.
+type exportedEvidence struct {
+	Result struct {
+		Evidence []struct {
+			DownloadPath string `json:"downloadPath"`
+		} `json:"evidence"`
+	} `json:"result"`
+}
+
+// LoadEvidenceKeys reads the public key files given with --public-keys.
+func LoadEvidenceKeys(paths []string) (EvidenceKeys, error) {
+	var keys EvidenceKeys
+	for _, keyPath := range paths {
+		keyPath = strings.TrimSpace(keyPath)
+		if keyPath == "" {
+			continue
+		}
+		// #nosec G304 -- key path is provided by the user.
+		data, err := os.ReadFile(keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read public key %s: %w", keyPath, err)
+		}
+		key, err := cryptox.ReadPublicKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load public key %s: %w", keyPath, err)
+		}
+		verifiers, err := cryptox.CreateVerifier(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load public key %s: %w", keyPath, err)
+		}
+		keys = append(keys, verifiers...)
+	}
+	return keys, nil
+}
+
+// exportEvidenceEnvelopes writes the signed envelope of every evidence listed in evidencePath to dir and returns
+// their paths. An import verifies these, since the evidence listing itself is not signed.
+func exportEvidenceEnvelopes(serverDetails *config.ServerDetails, evidencePath, dir string) ([]string, error) {
+	// #nosec G304 -- evidence file was just written to the export temp dir.
+	data, err := os.ReadFile(evidencePath)
+	if err != nil {
+		return nil, err
+	}
+	var evidence exportedEvidence
+	if err := json.Unmarshal(data, &evidence); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", filepath.Base(evidencePath), err)
+	}
+	var envelopePaths []string
+	for i, entry := range evidence.Result.Evidence {
+		if entry.DownloadPath == "" {
+			continue
+		}
+		envelope, err := readEvidenceEnvelope(serverDetails, entry.DownloadPath)
+		if err != nil {
+			return nil, fmt.Errorf("download %s: %w", entry.DownloadPath, err)
+		}
+		envelopePath := filepath.Join(dir, fmt.Sprintf(bundleEnvelopeFileName, i+1))
+		// #nosec G306 -- envelope is staged in a private temp dir.
+		if err := os.WriteFile(envelopePath, envelope, DefaultFileMode); err != nil {
+			return nil, err
+		}
+		envelopePaths = append(envelopePaths, envelopePath)
+	}
+	return envelopePaths, nil
+}
+
+func downloadEvidenceEnvelope(serverDetails *config.ServerDetails, downloadPath string) ([]byte, error) {
+	sm, err := utils.CreateServiceManager(serverDetails, 3, 0, false)
+	if err != nil {
+		return nil, fmt.Errorf("could not create service manager: %w", err)
+	}
+	reader, err := sm.ReadRemoteFile(downloadPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+	return io.ReadAll(reader)
+}
+
+// HasVerifiedEvidence reports whether pkg carries evidence signed by one of keys whose subject is the zip in the
+// bundle. bundle.json and the zip checksum it records can be edited freely, so only a signature by a key the
+// importer trusts makes a bundled package count as attested.
+func (b *Bundle) HasVerifiedEvidence(pkg BundlePackage, keys EvidenceKeys) bool {
+	err := b.verifyEvidence(pkg, keys)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, ErrNoBundledEvidence):
+		log.Debug(fmt.Sprintf("%s: %s", pkg, err.Error()))
+	default:
+		log.Warn(fmt.Sprintf("Treating %s as having no evidence: %s", pkg, err.Error()))
+	}
+	return false
+}
+
+func (b *Bundle) verifyEvidence(pkg BundlePackage, keys EvidenceKeys) error {
+	if len(pkg.Envelopes) == 0 {
+		return ErrNoBundledEvidence
+	}
+	if len(keys) == 0 {
+		return errors.New("the bundled evidence cannot be verified without --public-keys")
+	}
+	zipSHA256, err := ComputeSHA256(b.File(pkg.Zip))
+	if err != nil {
+		return err
+	}
+	var verifyErr error
+	for _, envelope := range pkg.Envelopes {
+		if verifyErr = verifyEvidenceEnvelope(b.File(envelope), zipSHA256, keys); verifyErr == nil {
+			return nil
+		}
+	}
+	return verifyErr
+}
+
+// verifyEvidenceEnvelope checks the DSSE signature of an evidence envelope and that its in-toto subject is zipSHA256.
+func verifyEvidenceEnvelope(envelopePath, zipSHA256 string, keys EvidenceKeys) error {
+	name := filepath.Base(envelopePath)
+	// #nosec G304 -- envelope is read from the extracted bundle, inside the bundle directory.
+	data, err := os.ReadFile(envelopePath)
+	if err != nil {
+		return err
+	}
+	var envelope dsse.Envelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return fmt.Errorf("parse %s: %w", name, err)
+	}
+	signed := false
+	for _, key := range keys {
+		if envelope.Verify(key) == nil {
+			signed = true
+			break
+		}
+	}
+	if !signed {
+		return fmt.Errorf("%s is not signed by any of the --public-keys", name)
+	}
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return fmt.Errorf("decode %s: %w", name, err)
+	}
+	var statement intoto.Statement
+	if err := json.Unmarshal(payload, &statement); err != nil {
+		return fmt.Errorf("parse the statement in %s: %w", name, err)
+	}
+	for _, subject := range statement.Subject {
+		if strings.EqualFold(subject.Digest.Sha256, zipSHA256) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s attests a different zip than the one in the bundle", name)
+}
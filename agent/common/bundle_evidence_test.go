@@ -0,0 +1,118 @@
+package common
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-evidence/evidence/dsse"
+	"github.com/jfrog/jfrog-cli-evidence/evidence/intoto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestPublicKey generates a signing key and writes its public half as PEM.
+func writeTestPublicKey(t *testing.T) (ed25519.PrivateKey, string) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(public)
+	require.NoError(t, err)
+	keyPath := filepath.Join(t.TempDir(), "evidence.pub")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+	return private, keyPath
+}
+
+// signTestEnvelope returns a DSSE envelope signed by key that attests subjectSHA256.
+func signTestEnvelope(t *testing.T, key ed25519.PrivateKey, subjectSHA256 string) []byte {
+	t.Helper()
+	statement := intoto.NewStatement([]byte(`{"publisher":"ci"}`), "https://jfrog.com/evidence/publish/v1", "ci")
+	require.NoError(t, statement.SetSubject(subjectSHA256))
+	payload, err := statement.Marshal()
+	require.NoError(t, err)
+	envelope := dsse.Envelope{
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		PayloadType: intoto.PayloadType,
+		Signatures:  []dsse.Signature{{Sig: base64.StdEncoding.EncodeToString(ed25519.Sign(key, dsse.PAE(intoto.PayloadType, payload)))}},
+	}
+	data, err := json.Marshal(envelope)
+	require.NoError(t, err)
+	return data
+}
+
+// exportSignedTestBundle exports web-search with an evidence envelope signed by key.
+func exportSignedTestBundle(t *testing.T, key ed25519.PrivateKey) *Bundle {
+	t.Helper()
+	stubBundleExport(t)
+	restoreExport, restoreRead := exportEvidenceForPackageZip, readEvidenceEnvelope
+	t.Cleanup(func() { exportEvidenceForPackageZip, readEvidenceEnvelope = restoreExport, restoreRead })
+	exportEvidenceForPackageZip = func(_ *config.ServerDetails, opts ExportEvidenceOpts) error {
+		return os.WriteFile(opts.OutputPath, []byte(`{"result":{"evidence":[{"downloadPath":"skills-local/.evidence/publish.json"}]}}`), 0o600)
+	}
+	zipSHA256 := sha256.Sum256([]byte("skills-local/web-search@1.4.0"))
+	readEvidenceEnvelope = func(_ *config.ServerDetails, downloadPath string) ([]byte, error) {
+		assert.Equal(t, "skills-local/.evidence/publish.json", downloadPath)
+		return signTestEnvelope(t, key, hex.EncodeToString(zipSHA256[:])), nil
+	}
+	archivePath, _ := exportTestBundle(t, skillRequest("web-search", ""))
+	bundle, err := OpenBundle(archivePath, t.TempDir())
+	require.NoError(t, err)
+	return bundle
+}
+
+func TestBundle_HasVerifiedEvidence(t *testing.T) {
+	key, keyPath := writeTestPublicKey(t)
+	_, otherKeyPath := writeTestPublicKey(t)
+	bundle := exportSignedTestBundle(t, key)
+	pkg := bundle.Manifest.Packages[0]
+	require.Equal(t, []string{"packages/skill/web-search/1.4.0/evidence-1.dsse.json"}, pkg.Envelopes)
+
+	trusted, err := LoadEvidenceKeys([]string{keyPath})
+	require.NoError(t, err)
+	other, err := LoadEvidenceKeys([]string{otherKeyPath})
+	require.NoError(t, err)
+
+	assert.True(t, bundle.HasVerifiedEvidence(pkg, trusted))
+	assert.False(t, bundle.HasVerifiedEvidence(pkg, nil), "without trusted keys bundled evidence proves nothing")
+	assert.False(t, bundle.HasVerifiedEvidence(pkg, other), "signed by a key the importer does not trust")
+
+	unattested := pkg
+	unattested.Envelopes = nil
+	assert.False(t, bundle.HasVerifiedEvidence(unattested, trusted))
+}
+
+func TestBundle_HasVerifiedEvidence_ReplacedZip(t *testing.T) {
+	key, keyPath := writeTestPublicKey(t)
+	bundle := exportSignedTestBundle(t, key)
+	pkg := bundle.Manifest.Packages[0]
+
+	// An edited bundle swaps the zip and records its checksum in bundle.json, which OpenBundle cannot detect.
+	require.NoError(t, os.WriteFile(bundle.File(pkg.Zip), []byte("replaced"), 0o600))
+	replacedSHA256 := sha256.Sum256([]byte("replaced"))
+	pkg.SHA256 = hex.EncodeToString(replacedSHA256[:])
+	require.NoError(t, bundle.validatePackage(pkg))
+
+	trusted, err := LoadEvidenceKeys([]string{keyPath})
+	require.NoError(t, err)
+	assert.False(t, bundle.HasVerifiedEvidence(pkg, trusted))
+	assert.ErrorContains(t, bundle.verifyEvidence(pkg, trusted), "attests a different zip than the one in the bundle")
+}
+
+func TestLoadEvidenceKeys(t *testing.T) {
+	_, keyPath := writeTestPublicKey(t)
+	keys, err := LoadEvidenceKeys([]string{"", " " + keyPath + " "})
+	require.NoError(t, err)
+	assert.Len(t, keys, 1)
+
+	_, err = LoadEvidenceKeys([]string{filepath.Join(t.TempDir(), "missing.pub")})
+	assert.ErrorContains(t, err, "failed to read public key")
+}
@@ -0,0 +1,171 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/common/build"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/utils/io/content"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubBundleExport serves fake zips and evidence; slugs in withoutEvidence fail evidence verification.
+func stubBundleExport(t *testing.T, withoutEvidence ...string) {
+	t.Helper()
+	restoreDownload, restoreVerify, restoreExport := downloadBundlePackageZip, verifyEvidenceForPackageZip, exportEvidenceForPackageZip
+	t.Cleanup(func() {
+		downloadBundlePackageZip, verifyEvidenceForPackageZip, exportEvidenceForPackageZip = restoreDownload, restoreVerify, restoreExport
+	})
+	downloadBundlePackageZip = func(_ *config.ServerDetails, repoKey, slug, version, tmpDir, _ string) (string, error) {
+		zipPath := filepath.Join(tmpDir, slug+"-"+version+".zip")
+		return zipPath, os.WriteFile(zipPath, []byte(repoKey+"/"+slug+"@"+version), 0o600)
+	}
+	verifyEvidenceForPackageZip = func(_ *config.ServerDetails, opts VerifyEvidenceOpts) error {
+		for _, slug := range withoutEvidence {
+			if filepath.Base(filepath.Dir(filepath.Dir(opts.SubjectRepoPath))) == slug {
+				return errors.New("no evidence found")
+			}
+		}
+		return nil
+	}
+	exportEvidenceForPackageZip = func(_ *config.ServerDetails, opts ExportEvidenceOpts) error {
+		return os.WriteFile(opts.OutputPath, []byte(`{"result":{"repoPath":"`+opts.SubjectRepoPath+`"}}`), 0o600)
+	}
+}
+
+func exportTestBundle(t *testing.T, requests ...PackageDependency) (string, []BundlePackage) {
+	t.Helper()
+	outputPath := filepath.Join(t.TempDir(), "bundle.zip")
+	packages, err := ExportPackages(ExportOptions{
+		RepoKey:        "skills-local",
+		Kind:           LockKindSkill,
+		Requests:       requests,
+		ResolveVersion: func(slug, requested string) (string, error) { return "1.4.0", nil },
+		OutputPath:     outputPath,
+	})
+	require.NoError(t, err)
+	return outputPath, packages
+}
+
+func TestExportPackages_RoundTrip(t *testing.T) {
+	stubBundleExport(t, "fetch")
+	archivePath, exported := exportTestBundle(t, skillRequest("web-search", "^1.2"), skillRequest("fetch", ""))
+	require.Len(t, exported, 2)
+
+	bundle, err := OpenBundle(archivePath, t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, BundleSchemaVersion, bundle.Manifest.SchemaVersion)
+	require.Len(t, bundle.Manifest.Packages, 2)
+
+	webSearch := bundle.Manifest.Packages[0]
+	assert.Equal(t, "web-search", webSearch.Slug)
+	assert.Equal(t, "1.4.0", webSearch.Version)
+	assert.Equal(t, "skills-local", webSearch.Repo)
+	assert.Equal(t, "packages/skill/web-search/1.4.0/web-search-1.4.0.zip", webSearch.Zip)
+	assert.Equal(t, "packages/skill/web-search/1.4.0/evidence.json", webSearch.Evidence)
+	evidence, err := os.ReadFile(bundle.File(webSearch.Evidence))
+	require.NoError(t, err)
+	assert.Contains(t, string(evidence), "skills-local/web-search/1.4.0/web-search-1.4.0.zip")
+
+	assert.Empty(t, bundle.Manifest.Packages[1].Evidence, "packages whose evidence does not verify are exported without it")
+	assert.Len(t, bundle.Packages(LockKindSkill), 2)
+	assert.Empty(t, bundle.Packages(LockKindPlugin))
+}
+
+func TestExportPackages_DuplicateSlug(t *testing.T) {
+	stubBundleExport(t)
+	_, err := ExportPackages(ExportOptions{
+		Kind:           LockKindSkill,
+		Requests:       []PackageDependency{skillRequest("web", "1.0.0"), skillRequest("web", "2.0.0")},
+		ResolveVersion: func(slug, requested string) (string, error) { return requested, nil },
+		OutputPath:     filepath.Join(t.TempDir(), "bundle.zip"),
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "skill 'web' is listed more than once")
+}
+
+func TestOpenBundle_ChecksumMismatch(t *testing.T) {
+	stubBundleExport(t)
+	archivePath, _ := exportTestBundle(t, skillRequest("web-search", ""))
+	extracted := t.TempDir()
+	require.NoError(t, UnzipFile(archivePath, extracted))
+	require.NoError(t, os.WriteFile(filepath.Join(extracted, "packages", "skill", "web-search", "1.4.0", "web-search-1.4.0.zip"), []byte("tampered"), 0o600))
+
+	tamperedArchive := filepath.Join(t.TempDir(), "tampered.zip")
+	writer := &BundleWriter{stagingDir: extracted}
+	data, err := os.ReadFile(filepath.Join(extracted, BundleManifestFileName))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &writer.manifest))
+	require.NoError(t, writer.Write(tamperedArchive))
+
+	_, err = OpenBundle(tamperedArchive, t.TempDir())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum mismatch for skill 'web-search' 1.4.0")
+}
+
+func TestOpenBundle_RejectsPathOutsideBundle(t *testing.T) {
+	stagingDir := t.TempDir()
+	writer := NewBundleWriter(stagingDir, "")
+	writer.manifest.Packages = append(writer.manifest.Packages, BundlePackage{
+		Kind: LockKindSkill, Slug: "web", Version: "1.0.0", SHA256: "abc", Zip: "../../etc/passwd",
+	})
+	archivePath := filepath.Join(t.TempDir(), "bundle.zip")
+	require.NoError(t, writer.Write(archivePath))
+
+	_, err := OpenBundle(archivePath, t.TempDir())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "outside the bundle")
+}
+
+func TestOpenBundle_NotABundle(t *testing.T) {
+	stagingDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(stagingDir, "SKILL.md"), []byte("# skill"), 0o600))
+	files, _, err := CollectPublishFiles(stagingDir)
+	require.NoError(t, err)
+	archivePath := filepath.Join(t.TempDir(), "skill.zip")
+	_, err = writePublishZip(archivePath, stagingDir, files, zipEpoch, false)
+	require.NoError(t, err)
+
+	_, err = OpenBundle(archivePath, t.TempDir())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not an export bundle")
+}
+
+func TestPublishBundle(t *testing.T) {
+	stubBundleExport(t)
+	archivePath, _ := exportTestBundle(t, skillRequest("web-search", ""), skillRequest("fetch", ""))
+	bundle, err := OpenBundle(archivePath, t.TempDir())
+	require.NoError(t, err)
+
+	restore := uploadBundlePackageZip
+	t.Cleanup(func() { uploadBundlePackageZip = restore })
+	var uploaded []string
+	uploadBundlePackageZip = func(_ *config.ServerDetails, zipPath, uploadTarget string, _ bool, _ *build.BuildConfiguration) (*content.ContentReader, error) {
+		if filepath.Base(zipPath) == "fetch-1.4.0.zip" {
+			return nil, errors.New("403 forbidden")
+		}
+		uploaded = append(uploaded, uploadTarget)
+		return nil, nil
+	}
+
+	results := PublishBundle(nil, "skills-airgap", bundle, LockKindSkill)
+	assert.Equal(t, []string{"skills-airgap/web-search/1.4.0/"}, uploaded)
+	require.Len(t, results, 2)
+	assert.Equal(t, SummaryStatusOK, results[0].Status)
+	assert.Equal(t, "skills-airgap/web-search/1.4.0/web-search-1.4.0.zip", results[0].Target)
+	assert.Equal(t, SummaryStatusFailed, results[1].Status)
+	assert.Contains(t, results[1].Detail, "403 forbidden")
+
+	err = PrintBundlePublishSummary("Skill", results, "json")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "publishing failed for one or more skills")
+}
+
+func skillRequest(slug, version string) PackageDependency {
+	return PackageDependency{Kind: LockKindSkill, Slug: slug, Version: version}
+}
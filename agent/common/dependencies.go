@@ -0,0 +1,60 @@
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PackageDependency is one skill or plugin that a package needs installed alongside it.
+type PackageDependency struct {
+	// Kind is LockKindSkill or LockKindPlugin.
+	Kind string
+	Slug string
+	// Version is an exact version, a range such as "^1.2", or "" for the latest version.
+	Version string
+}
+
+// String renders the dependency for logs and errors, e.g. skill 'web-search' (^1.2).
+func (d PackageDependency) String() string {
+	if d.Version == "" {
+		return fmt.Sprintf("%s '%s'", d.Kind, d.Slug)
+	}
+	return fmt.Sprintf("%s '%s' (%s)", d.Kind, d.Slug, d.Version)
+}
+
+// ParseDependencyMap converts a slug -> version request map (as declared in plugin.json) into
+// dependencies of the given kind, sorted by slug.
+func ParseDependencyMap(kind string, requests map[string]string) ([]PackageDependency, error) {
+	dependencies := make([]PackageDependency, 0, len(requests))
+	for slug, version := range requests {
+		dependency, err := newPackageDependency(kind, slug, version)
+		if err != nil {
+			return nil, err
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	sort.Slice(dependencies, func(i, j int) bool { return dependencies[i].Slug < dependencies[j].Slug })
+	return dependencies, nil
+}
+
+// ParseDependencySpec parses "<slug>" or "<slug>@<version>" (as listed in SKILL.md front matter).
+func ParseDependencySpec(kind, spec string) (PackageDependency, error) {
+	slug, version, _ := strings.Cut(strings.TrimSpace(spec), "@")
+	return newPackageDependency(kind, slug, version)
+}
+
+func newPackageDependency(kind, slug, version string) (PackageDependency, error) {
+	slug = strings.TrimSpace(slug)
+	version = strings.TrimSpace(version)
+	if isLatestVersionRequest(version) {
+		version = ""
+	}
+	if err := ValidateSlug(slug); err != nil {
+		return PackageDependency{}, fmt.Errorf("invalid %s dependency: %w", kind, err)
+	}
+	if err := ValidateVersionRequest(version); err != nil {
+		return PackageDependency{}, fmt.Errorf("invalid version for %s dependency '%s': %w", kind, slug, err)
+	}
+	return PackageDependency{Kind: kind, Slug: slug, Version: version}, nil
+}
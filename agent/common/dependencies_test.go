@@ -0,0 +1,35 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDependencyMap(t *testing.T) {
+	dependencies, err := ParseDependencyMap(LockKindSkill, map[string]string{"web-search": "^1.2", "fetch": "latest", "lint": "2.0.0"})
+	require.NoError(t, err)
+	assert.Equal(t, []PackageDependency{
+		{Kind: LockKindSkill, Slug: "fetch"},
+		{Kind: LockKindSkill, Slug: "lint", Version: "2.0.0"},
+		{Kind: LockKindSkill, Slug: "web-search", Version: "^1.2"},
+	}, dependencies)
+
+	_, err = ParseDependencyMap(LockKindPlugin, map[string]string{"Bad Slug": ""})
+	assert.ErrorContains(t, err, "invalid plugin dependency")
+}
+
+func TestParseDependencySpec(t *testing.T) {
+	dependency, err := ParseDependencySpec(LockKindSkill, " web-search@>=2 <3 ")
+	require.NoError(t, err)
+	assert.Equal(t, PackageDependency{Kind: LockKindSkill, Slug: "web-search", Version: ">=2 <3"}, dependency)
+	assert.Equal(t, "skill 'web-search' (>=2 <3)", dependency.String())
+
+	dependency, err = ParseDependencySpec(LockKindSkill, "fetch")
+	require.NoError(t, err)
+	assert.Equal(t, "skill 'fetch'", dependency.String())
+
+	_, err = ParseDependencySpec(LockKindSkill, "fetch@one")
+	assert.ErrorContains(t, err, "invalid version for skill dependency 'fetch'")
+}
@@ -0,0 +1,296 @@
+package common
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	rtServicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Properties set on <repo>/<slug>/<version>/ and everything under it when a version is deprecated.
+// Deprecated versions stay downloadable, so pinned installs keep working, but version resolution skips them.
+const (
+	DeprecatedPropertyKey             = "agent.deprecated"
+	DeprecationReasonPropertyKey      = "agent.deprecated.reason"
+	DeprecationReplacementPropertyKey = "agent.deprecated.replacement"
+)
+
+// lookupDeprecatedVersions is swappable in tests.
+var lookupDeprecatedVersions = fetchDeprecatedVersions
+
+// Deprecation is why a package version is deprecated and which version replaces it.
+type Deprecation struct {
+	Reason      string `json:"reason"`
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// Summary returns the reason followed by the replacement, e.g. "broken hooks; use 1.2.1".
+func (d Deprecation) Summary() string {
+	summary := strings.TrimSpace(d.Reason)
+	if summary == "" {
+		summary = "no reason given"
+	}
+	if d.Replacement != "" {
+		summary += "; use " + d.Replacement
+	}
+	return summary
+}
+
+// DeprecatedVersions holds the deprecated versions of one repository, by slug and then version.
+// A nil DeprecatedVersions has no deprecated versions.
+type DeprecatedVersions map[string]map[string]Deprecation
+
+// Lookup returns the deprecation of slug at version, if any.
+func (d DeprecatedVersions) Lookup(slug, version string) (Deprecation, bool) {
+	deprecation, found := d[slug][version]
+	return deprecation, found
+}
+
+// Active returns the versions of slug that are not deprecated, in their original order.
+func (d DeprecatedVersions) Active(slug string, versions []string) []string {
+	if len(d[slug]) == 0 {
+		return versions
+	}
+	active := make([]string, 0, len(versions))
+	for _, version := range versions {
+		if _, deprecated := d[slug][version]; !deprecated {
+			active = append(active, version)
+		}
+	}
+	return active
+}
+
+func (d DeprecatedVersions) add(slug, version string, deprecation Deprecation) {
+	if d[slug] == nil {
+		d[slug] = map[string]Deprecation{}
+	}
+	d[slug][version] = deprecation
+}
+
+// ListDeprecatedVersions returns the deprecated versions in repoKey, only those of slug unless slug is empty.
+// A failed lookup is logged and treated as no deprecated versions, so servers without property search still
+// resolve and install packages.
+func ListDeprecatedVersions(serverDetails *config.ServerDetails, repoKey, slug string) DeprecatedVersions {
+	if serverDetails == nil || strings.TrimSpace(repoKey) == "" {
+		return nil
+	}
+	deprecated, err := lookupDeprecatedVersions(serverDetails, repoKey, slug)
+	if err != nil {
+		log.Debug(fmt.Sprintf("Could not list deprecated versions in '%s': %s", repoKey, err.Error()))
+		return nil
+	}
+	return deprecated
+}
+
+// LatestActiveVersion returns the greatest version of slug that is not deprecated.
+func LatestActiveVersion(slug string, versions []string, deprecated DeprecatedVersions) (string, error) {
+	active := deprecated.Active(slug, versions)
+	if len(active) == 0 && len(versions) > 0 {
+		return "", fmt.Errorf("all published versions of '%s' are deprecated", slug)
+	}
+	return LatestVersion(active)
+}
+
+// WarnIfDeprecated logs a warning when the resolved version of slug is deprecated.
+func WarnIfDeprecated(entityLabel, slug, version string, deprecated DeprecatedVersions) {
+	if deprecation, found := deprecated.Lookup(slug, version); found {
+		log.Warn(fmt.Sprintf("%s '%s' version '%s' is deprecated: %s", entityLabel, slug, version, deprecation.Summary()))
+	}
+}
+
+// FilterDeprecatedSearchRows drops search results whose version is deprecated in their repository.
+func FilterDeprecatedSearchRows(serverDetails *config.ServerDetails, rows []SearchResultRow) []SearchResultRow {
+	byRepo := map[string]DeprecatedVersions{}
+	filtered := make([]SearchResultRow, 0, len(rows))
+	for _, row := range rows {
+		deprecated, found := byRepo[row.Repository]
+		if !found {
+			deprecated = ListDeprecatedVersions(serverDetails, row.Repository, "")
+			byRepo[row.Repository] = deprecated
+		}
+		if _, isDeprecated := deprecated.Lookup(row.Name, row.Version); isDeprecated {
+			continue
+		}
+		filtered = append(filtered, row)
+	}
+	return filtered
+}
+
+func filterDeprecatedPropertyHits(serverDetails *config.ServerDetails, hits []PropertySearchResult) []PropertySearchResult {
+	byRepo := map[string]DeprecatedVersions{}
+	filtered := make([]PropertySearchResult, 0, len(hits))
+	for _, hit := range hits {
+		deprecated, found := byRepo[hit.Repo]
+		if !found {
+			deprecated = ListDeprecatedVersions(serverDetails, hit.Repo, "")
+			byRepo[hit.Repo] = deprecated
+		}
+		if _, isDeprecated := deprecated.Lookup(hit.Name, hit.Version); isDeprecated {
+			continue
+		}
+		filtered = append(filtered, hit)
+	}
+	return filtered
+}
+
+// DeprecateVersion marks <repoKey>/<slug>/<version>/ as deprecated. Marking it again replaces the reason and
+// replacement.
+func DeprecateVersion(serverDetails *config.ServerDetails, repoKey, slug, version string, deprecation Deprecation) error {
+	props := rtServicesUtils.NewProperties()
+	props.AddProperty(DeprecatedPropertyKey, "true")
+	props.AddProperty(DeprecationReasonPropertyKey, deprecation.Reason)
+	if deprecation.Replacement != "" {
+		props.AddProperty(DeprecationReplacementPropertyKey, deprecation.Replacement)
+	} else if err := sendVersionPropsRequest(serverDetails, http.MethodDelete, repoKey, slug, version, url.QueryEscape(DeprecationReplacementPropertyKey)); err != nil {
+		log.Debug(fmt.Sprintf("Could not clear %s on %s/%s/%s: %s", DeprecationReplacementPropertyKey, repoKey, slug, version, err.Error()))
+	}
+	return sendVersionPropsRequest(serverDetails, http.MethodPut, repoKey, slug, version, props.ToEncodedString(true))
+}
+
+// UndeprecateVersion removes the deprecation properties from <repoKey>/<slug>/<version>/.
+func UndeprecateVersion(serverDetails *config.ServerDetails, repoKey, slug, version string) error {
+	keys := []string{DeprecatedPropertyKey, DeprecationReasonPropertyKey, DeprecationReplacementPropertyKey}
+	for i, key := range keys {
+		keys[i] = url.QueryEscape(key)
+	}
+	return sendVersionPropsRequest(serverDetails, http.MethodDelete, repoKey, slug, version, strings.Join(keys, ","))
+}
+
+// DeprecationRequest is one run of `deprecate` for a skill or plugin version.
+type DeprecationRequest struct {
+	// EntityLabel names the package kind in messages, e.g. "Skill".
+	EntityLabel   string
+	ServerDetails *config.ServerDetails
+	RepoKey       string
+	Slug          string
+	Version       string
+	Deprecation   Deprecation
+	// Undo removes the deprecation instead of setting it.
+	Undo bool
+}
+
+// RunDeprecation validates request, checks that the version (and its replacement) are published, then
+// deprecates or undeprecates the version.
+func RunDeprecation(request DeprecationRequest) error {
+	label := strings.ToLower(request.EntityLabel)
+	if request.Version == "" {
+		return fmt.Errorf("--version is required for deprecate")
+	}
+	if err := ValidateSemver(request.Version); err != nil {
+		return err
+	}
+	if !request.Undo {
+		request.Deprecation.Reason = strings.TrimSpace(request.Deprecation.Reason)
+		if request.Deprecation.Reason == "" {
+			return fmt.Errorf("--reason is required for deprecate")
+		}
+		request.Deprecation.Replacement = strings.TrimSpace(request.Deprecation.Replacement)
+		if request.Deprecation.Replacement != "" {
+			if err := ValidateSemver(request.Deprecation.Replacement); err != nil {
+				return fmt.Errorf("invalid --replacement: %w", err)
+			}
+			if request.Deprecation.Replacement == request.Version {
+				return fmt.Errorf("--replacement must differ from the deprecated version")
+			}
+		}
+	}
+
+	versions := []string{request.Version}
+	if request.Deprecation.Replacement != "" && !request.Undo {
+		versions = append(versions, request.Deprecation.Replacement)
+	}
+	for _, version := range versions {
+		exists, err := PackageVersionExists(request.ServerDetails, request.RepoKey, request.Slug, version)
+		if err != nil {
+			return fmt.Errorf("failed to verify %s existence: %w", label, err)
+		}
+		if !exists {
+			return fmt.Errorf("%s '%s' v%s not found in repository '%s'", label, request.Slug, version, request.RepoKey)
+		}
+	}
+
+	if request.Undo {
+		if err := UndeprecateVersion(request.ServerDetails, request.RepoKey, request.Slug, request.Version); err != nil {
+			return err
+		}
+		log.Info(fmt.Sprintf("%s '%s' v%s in '%s' is no longer deprecated.", request.EntityLabel, request.Slug, request.Version, request.RepoKey))
+		return nil
+	}
+	if err := DeprecateVersion(request.ServerDetails, request.RepoKey, request.Slug, request.Version, request.Deprecation); err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("%s '%s' v%s in '%s' is deprecated: %s", request.EntityLabel, request.Slug, request.Version, request.RepoKey, request.Deprecation.Summary()))
+	return nil
+}
+
+// sendVersionPropsRequest sets (PUT) or deletes (DELETE) encodedProps on the version folder and its contents.
+func sendVersionPropsRequest(serverDetails *config.ServerDetails, method, repoKey, slug, version, encodedProps string) error {
+	sm, err := createPropertySearchServiceManager(serverDetails)
+	if err != nil {
+		return fmt.Errorf("failed to create service manager for properties: %w", err)
+	}
+	artURL := clientutils.AddTrailingSlashIfNeeded(sm.GetConfig().GetServiceDetails().GetUrl())
+	propsURL := fmt.Sprintf("%sapi/storage/%s/%s/%s?properties=%s&recursive=1", artURL, repoKey, slug, version, encodedProps)
+	httpDetails := sm.GetConfig().GetServiceDetails().CreateHttpClientDetails()
+	var resp *http.Response
+	var body []byte
+	if method == http.MethodDelete {
+		resp, body, err = sm.Client().SendDelete(propsURL, nil, &httpDetails)
+	} else {
+		resp, body, err = sm.Client().SendPut(propsURL, nil, &httpDetails)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update properties of %s/%s/%s: %w", repoKey, slug, version, err)
+	}
+	return errorutils.CheckResponseStatusWithBody(resp, body, http.StatusNoContent)
+}
+
+// fetchDeprecatedVersions finds deprecated items with property search, then reads the reason and replacement
+// of each deprecated version folder.
+func fetchDeprecatedVersions(serverDetails *config.ServerDetails, repoKey, slug string) (DeprecatedVersions, error) {
+	hits, err := SearchByProperty(serverDetails, PropertySearchOptions{
+		NamePropertyKey: DeprecatedPropertyKey,
+		Query:           "true",
+		RepoKey:         repoKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sm, err := createPropertySearchServiceManager(serverDetails)
+	if err != nil {
+		return nil, err
+	}
+	deprecated := DeprecatedVersions{}
+	for _, hit := range hits {
+		if hit.Repo != repoKey || (slug != "" && hit.Name != slug) {
+			continue
+		}
+		// The folder and the zip under it both carry the properties; read them once per version.
+		if _, found := deprecated.Lookup(hit.Name, hit.Version); found {
+			continue
+		}
+		deprecation := Deprecation{}
+		props, err := sm.GetItemProps(fmt.Sprintf("%s/%s/%s", hit.Repo, hit.Name, hit.Version))
+		if err != nil {
+			log.Debug(fmt.Sprintf("Could not read the deprecation of %s/%s/%s: %s", hit.Repo, hit.Name, hit.Version, err.Error()))
+		} else {
+			deprecation.Reason = firstPropertyValue(props.Properties[DeprecationReasonPropertyKey])
+			deprecation.Replacement = firstPropertyValue(props.Properties[DeprecationReplacementPropertyKey])
+		}
+		deprecated.add(hit.Name, hit.Version, deprecation)
+	}
+	return deprecated, nil
+}
+
+func firstPropertyValue(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
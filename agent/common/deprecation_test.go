@@ -0,0 +1,149 @@
+package common
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var deprecatedWeb = DeprecatedVersions{
+	"web": {
+		"2.0.0": {Reason: "broken hooks", Replacement: "1.2.0"},
+		"1.1.0": {},
+	},
+}
+
+func stubDeprecatedVersions(t *testing.T, deprecated DeprecatedVersions, err error) *[]string {
+	t.Helper()
+	restore := lookupDeprecatedVersions
+	t.Cleanup(func() { lookupDeprecatedVersions = restore })
+	var requested []string
+	lookupDeprecatedVersions = func(_ *config.ServerDetails, repoKey, slug string) (DeprecatedVersions, error) {
+		requested = append(requested, repoKey+"/"+slug)
+		return deprecated, err
+	}
+	return &requested
+}
+
+func TestDeprecationSummary(t *testing.T) {
+	assert.Equal(t, "broken hooks; use 1.2.0", deprecatedWeb["web"]["2.0.0"].Summary())
+	assert.Equal(t, "no reason given", Deprecation{}.Summary())
+}
+
+func TestDeprecatedVersions_Active(t *testing.T) {
+	available := []string{"1.0.0", "1.1.0", "1.2.0", "2.0.0"}
+	assert.Equal(t, []string{"1.0.0", "1.2.0"}, deprecatedWeb.Active("web", available))
+	assert.Equal(t, available, deprecatedWeb.Active("lint", available))
+	assert.Equal(t, available, DeprecatedVersions(nil).Active("web", available))
+
+	_, found := DeprecatedVersions(nil).Lookup("web", "2.0.0")
+	assert.False(t, found)
+}
+
+func TestLatestActiveVersion(t *testing.T) {
+	latest, err := LatestActiveVersion("web", []string{"1.0.0", "1.2.0", "2.0.0"}, deprecatedWeb)
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.0", latest)
+
+	_, err = LatestActiveVersion("web", []string{"1.1.0", "2.0.0"}, deprecatedWeb)
+	assert.EqualError(t, err, "all published versions of 'web' are deprecated")
+}
+
+func TestSelectPackageVersion_SkipsDeprecated(t *testing.T) {
+	opts := SelectPackageVersionOpts{
+		Available:  []string{"1.0.0", "1.1.0", "1.2.0", "2.0.0"},
+		RepoKey:    "skills-local",
+		Quiet:      true,
+		Slug:       "web",
+		Deprecated: deprecatedWeb,
+	}
+	tests := []struct {
+		requested string
+		want      string
+		wantErr   string
+	}{
+		{requested: "", want: "1.2.0"},
+		{requested: "latest", want: "1.2.0"},
+		{requested: "2.0.0", want: "2.0.0"},
+		{requested: "~1.1", wantErr: "no version"},
+		{requested: ">=1.0.0", want: "1.2.0"},
+		{requested: "9.9.9", wantErr: "Available versions: 1.0.0, 1.2.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.requested, func(t *testing.T) {
+			opts.Requested = tt.requested
+			got, err := SelectPackageVersion(opts)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestListDeprecatedVersions_LookupErrorIsIgnored(t *testing.T) {
+	requested := stubDeprecatedVersions(t, nil, errors.New("connection refused"))
+	assert.Nil(t, ListDeprecatedVersions(&config.ServerDetails{}, "skills-local", "web"))
+	assert.Nil(t, ListDeprecatedVersions(nil, "skills-local", "web"))
+	assert.Equal(t, []string{"skills-local/web"}, *requested, "no lookup without server details")
+}
+
+func TestFilterDeprecatedSearchRows(t *testing.T) {
+	requested := stubDeprecatedVersions(t, deprecatedWeb, nil)
+	rows := FilterDeprecatedSearchRows(&config.ServerDetails{}, []SearchResultRow{
+		{Name: "web", Version: "2.0.0", Repository: "skills-local"},
+		{Name: "web", Version: "1.2.0", Repository: "skills-local"},
+		{Name: "lint", Version: "2.0.0", Repository: "skills-local"},
+	})
+	assert.Equal(t, []SearchResultRow{
+		{Name: "web", Version: "1.2.0", Repository: "skills-local"},
+		{Name: "lint", Version: "2.0.0", Repository: "skills-local"},
+	}, rows)
+	assert.Equal(t, []string{"skills-local/"}, *requested, "deprecations are listed once per repository")
+}
+
+func TestRunDeprecation_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		request DeprecationRequest
+		wantErr string
+	}{
+		{name: "no version", request: DeprecationRequest{Deprecation: Deprecation{Reason: "broken"}}, wantErr: "--version is required"},
+		{name: "invalid version", request: DeprecationRequest{Version: "one", Deprecation: Deprecation{Reason: "broken"}}, wantErr: "one"},
+		{name: "no reason", request: DeprecationRequest{Version: "1.0.0", Deprecation: Deprecation{Reason: "  "}}, wantErr: "--reason is required"},
+		{name: "invalid replacement", request: DeprecationRequest{Version: "1.0.0", Deprecation: Deprecation{Reason: "broken", Replacement: "next"}}, wantErr: "invalid --replacement"},
+		{name: "self replacement", request: DeprecationRequest{Version: "1.0.0", Deprecation: Deprecation{Reason: "broken", Replacement: "1.0.0"}}, wantErr: "must differ"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorContains(t, RunDeprecation(tt.request), tt.wantErr)
+		})
+	}
+}
+
+func TestCollectOutdated_Deprecated(t *testing.T) {
+	globalDir := t.TempDir()
+	registry := map[string]AgentSpec{"cursor": {Name: "cursor", Config: AgentConfig{GlobalDir: globalDir}}}
+	writeOutdatedInstall(t, filepath.Join(globalDir, "web"), InstallInfoManifest{Repo: "skills-local", Slug: "web", InstalledVersion: "1.1.0"})
+	stubOutdatedEvidence(t, nil)
+	stubDeprecatedVersions(t, deprecatedWeb, nil)
+
+	rows, err := CollectOutdated(registry, OutdatedOptions{
+		ServerDetails:    &config.ServerDetails{},
+		ManifestFileName: testSkillManifest,
+		ListVersions: func(string, string) ([]string, error) {
+			return []string{"1.0.0", "1.1.0", "1.2.0", "2.0.0"}, nil
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "1.2.0", rows[0].Latest, "deprecated 2.0.0 is not offered")
+	assert.Equal(t, OutdatedStatusOutdated, rows[0].Status)
+	assert.Equal(t, "no reason given", rows[0].Deprecated)
+}
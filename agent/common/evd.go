@@ -3,6 +3,7 @@ package common
 import (
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-evidence/evidence/create"
+	"github.com/jfrog/jfrog-cli-evidence/evidence/get"
 	"github.com/jfrog/jfrog-cli-evidence/evidence/verify"
 	clientutils "github.com/jfrog/jfrog-client-go/utils"
 )
@@ -21,6 +22,11 @@ type VerifyEvidenceOpts struct {
 	SubjectRepoPath string
 }
 
+type ExportEvidenceOpts struct {
+	SubjectRepoPath string
+	OutputPath      string
+}
+
 // CreateEvidence attaches a signed publish-attestation to an artifact using jfrog-cli-evidence programmatically.
 func CreateEvidence(serverDetails *config.ServerDetails, opts CreateEvidenceOpts) error {
 	localServerDetails := *serverDetails
@@ -54,6 +60,20 @@ func VerifyEvidence(serverDetails *config.ServerDetails, opts VerifyEvidenceOpts
 	return cmd.Run()
 }
 
+// ExportEvidence writes the evidence attached to an artifact, including predicates, to a JSON file.
+func ExportEvidence(serverDetails *config.ServerDetails, opts ExportEvidenceOpts) error {
+	localServerDetails := *serverDetails
+	ensureServiceUrls(&localServerDetails)
+	cmd := get.NewGetEvidenceCustom(
+		&localServerDetails,
+		opts.SubjectRepoPath,
+		"json",
+		opts.OutputPath,
+		true,
+	)
+	return cmd.Run()
+}
+
 // ensureServiceUrls populates service-specific URLs that the evidence library requires.
 // Platform URL comes from config.ServerDetails (Url / ArtifactoryUrl via normalizeArtifactoryUrl).
 func ensureServiceUrls(localServerDetails *config.ServerDetails) {
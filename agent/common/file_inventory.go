@@ -0,0 +1,144 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	// FileInventoryFileName is the per-file SHA-256 inventory written to <installDir>/.jfrog at install time.
+	FileInventoryFileName = "file-inventory.json"
+	// FileInventorySchemaVersion is bumped when the inventory JSON shape changes incompatibly.
+	FileInventorySchemaVersion = 1
+	// symlinkInventoryPrefix marks an inventory entry that is a symlink; the link target follows it.
+	symlinkInventoryPrefix = "symlink:"
+)
+
+// FileInventory records the SHA-256 of every file of a package as published, keyed by
+// slash-separated path relative to the install directory. Symlinks are recorded as "symlink:<target>".
+// The .jfrog directory is not included.
+type FileInventory struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	Files         map[string]string `json:"files"`
+}
+
+// InventoryDiff lists the differences between an install directory and its recorded inventory.
+type InventoryDiff struct {
+	Modified []string `json:"modified,omitempty"`
+	Added    []string `json:"added,omitempty"`
+	Missing  []string `json:"missing,omitempty"`
+}
+
+// Clean reports whether the install directory matches its inventory.
+func (d InventoryDiff) Clean() bool {
+	return len(d.Modified) == 0 && len(d.Added) == 0 && len(d.Missing) == 0
+}
+
+// HashPackageTree hashes every regular file under root and records every symlink by its target, skipping the
+// top-level .jfrog directory. Symlinks are not followed, so an added or retargeted link shows up as a change.
+func HashPackageTree(root string) (map[string]string, error) {
+	files := map[string]string{}
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if relPath == jfrogInstallDirName {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.Type()&fs.ModeSymlink != 0 {
+			target, err := os.Readlink(path)
+			if err != nil {
+				return fmt.Errorf("read link %s: %w", relPath, err)
+			}
+			files[filepath.ToSlash(relPath)] = symlinkInventoryPrefix + filepath.ToSlash(target)
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		digest, err := ComputeSHA256(path)
+		if err != nil {
+			return fmt.Errorf("hash %s: %w", relPath, err)
+		}
+		files[filepath.ToSlash(relPath)] = digest
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// WriteFileInventory writes <installDir>/.jfrog/file-inventory.json from precomputed file hashes.
+func WriteFileInventory(installDir string, files map[string]string) error {
+	data, err := json.MarshalIndent(FileInventory{SchemaVersion: FileInventorySchemaVersion, Files: files}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal file inventory: %w", err)
+	}
+	path := installInfoManifestPath(installDir, FileInventoryFileName)
+	if err := os.MkdirAll(filepath.Dir(path), InstallDirMode); err != nil {
+		return fmt.Errorf("create .jfrog under install dir: %w", err)
+	}
+	// #nosec G306 -- inventory lives under user-owned install dir.
+	if err := os.WriteFile(path, data, InstallManifestFileMode); err != nil {
+		return fmt.Errorf("write file inventory: %w", err)
+	}
+	return nil
+}
+
+// ReadFileInventory reads <installDir>/.jfrog/file-inventory.json. A missing file returns (nil, nil).
+func ReadFileInventory(installDir string) (*FileInventory, error) {
+	path := installInfoManifestPath(installDir, FileInventoryFileName)
+	// #nosec G304 -- path is install directory joined with fixed .jfrog segments.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read file inventory: %w", err)
+	}
+	var inventory FileInventory
+	if err := json.Unmarshal(data, &inventory); err != nil {
+		return nil, fmt.Errorf("parse file inventory %s: %w", path, err)
+	}
+	return &inventory, nil
+}
+
+// DiffFileInventory re-hashes installDir and compares it with the recorded inventory.
+func DiffFileInventory(installDir string, inventory *FileInventory) (InventoryDiff, error) {
+	current, err := HashPackageTree(installDir)
+	if err != nil {
+		return InventoryDiff{}, err
+	}
+	var diff InventoryDiff
+	for relPath, recorded := range inventory.Files {
+		digest, found := current[relPath]
+		switch {
+		case !found:
+			diff.Missing = append(diff.Missing, relPath)
+		case digest != recorded:
+			diff.Modified = append(diff.Modified, relPath)
+		}
+	}
+	for relPath := range current {
+		if _, found := inventory.Files[relPath]; !found {
+			diff.Added = append(diff.Added, relPath)
+		}
+	}
+	sort.Strings(diff.Modified)
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Missing)
+	return diff, nil
+}
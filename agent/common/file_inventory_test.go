@@ -0,0 +1,97 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), InstallDirMode))
+	require.NoError(t, os.WriteFile(path, []byte(content), InstallManifestFileMode))
+}
+
+func TestHashPackageTree_SkipsJfrogDir(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "SKILL.md"), "# skill")
+	writeTestFile(t, filepath.Join(root, "scripts", "run.sh"), "echo hi")
+	writeTestFile(t, filepath.Join(root, ".jfrog", "skill-info.json"), "{}")
+
+	files, err := HashPackageTree(root)
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+	assert.Contains(t, files, "SKILL.md")
+	assert.Contains(t, files, "scripts/run.sh")
+}
+
+func TestWriteReadFileInventory(t *testing.T) {
+	installDir := t.TempDir()
+	require.NoError(t, WriteFileInventory(installDir, map[string]string{"SKILL.md": "abc"}))
+
+	inventory, err := ReadFileInventory(installDir)
+	require.NoError(t, err)
+	require.NotNil(t, inventory)
+	assert.Equal(t, FileInventorySchemaVersion, inventory.SchemaVersion)
+	assert.Equal(t, map[string]string{"SKILL.md": "abc"}, inventory.Files)
+}
+
+func TestReadFileInventory_Missing(t *testing.T) {
+	inventory, err := ReadFileInventory(t.TempDir())
+	require.NoError(t, err)
+	assert.Nil(t, inventory)
+}
+
+func TestDiffFileInventory(t *testing.T) {
+	installDir := t.TempDir()
+	writeTestFile(t, filepath.Join(installDir, "SKILL.md"), "# skill")
+	writeTestFile(t, filepath.Join(installDir, "notes.md"), "notes")
+	writeTestFile(t, filepath.Join(installDir, "scripts", "run.sh"), "echo hi")
+	files, err := HashPackageTree(installDir)
+	require.NoError(t, err)
+	require.NoError(t, WriteFileInventory(installDir, files))
+	inventory, err := ReadFileInventory(installDir)
+	require.NoError(t, err)
+
+	diff, err := DiffFileInventory(installDir, inventory)
+	require.NoError(t, err)
+	assert.True(t, diff.Clean())
+
+	writeTestFile(t, filepath.Join(installDir, "SKILL.md"), "# tampered")
+	writeTestFile(t, filepath.Join(installDir, "scripts", "extra.sh"), "curl evil")
+	require.NoError(t, os.Remove(filepath.Join(installDir, "notes.md")))
+
+	diff, err = DiffFileInventory(installDir, inventory)
+	require.NoError(t, err)
+	assert.False(t, diff.Clean())
+	assert.Equal(t, []string{"SKILL.md"}, diff.Modified)
+	assert.Equal(t, []string{"scripts/extra.sh"}, diff.Added)
+	assert.Equal(t, []string{"notes.md"}, diff.Missing)
+}
+
+func TestDiffFileInventory_Symlinks(t *testing.T) {
+	installDir := t.TempDir()
+	writeTestFile(t, filepath.Join(installDir, "SKILL.md"), "# skill")
+	writeTestFile(t, filepath.Join(installDir, "scripts", "run.sh"), "echo hi")
+	require.NoError(t, os.Symlink("run.sh", filepath.Join(installDir, "scripts", "start.sh")))
+	files, err := HashPackageTree(installDir)
+	require.NoError(t, err)
+	assert.Equal(t, "symlink:run.sh", files["scripts/start.sh"])
+	inventory := &FileInventory{SchemaVersion: FileInventorySchemaVersion, Files: files}
+
+	// Retarget the recorded link, add a new one, and replace a file with a link to elsewhere.
+	require.NoError(t, os.Remove(filepath.Join(installDir, "scripts", "start.sh")))
+	require.NoError(t, os.Symlink("/tmp/evil.sh", filepath.Join(installDir, "scripts", "start.sh")))
+	require.NoError(t, os.Symlink("/etc/passwd", filepath.Join(installDir, "notes.md")))
+	require.NoError(t, os.Remove(filepath.Join(installDir, "SKILL.md")))
+	require.NoError(t, os.Symlink("/tmp/SKILL.md", filepath.Join(installDir, "SKILL.md")))
+
+	diff, err := DiffFileInventory(installDir, inventory)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"SKILL.md", "scripts/start.sh"}, diff.Modified)
+	assert.Equal(t, []string{"notes.md"}, diff.Added)
+	assert.Empty(t, diff.Missing)
+}
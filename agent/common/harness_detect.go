@@ -0,0 +1,114 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// DetectedHarness is an agent found on this machine and the path that showed it is in use.
+type DetectedHarness struct {
+	Spec  AgentSpec
+	Found string
+}
+
+// DetectHarnesses returns the agents in registry that appear to be in use, sorted by name. An agent is in use when
+// its install directory for the scope, its global directory, or one of its DetectPaths exists. Agents without an
+// install directory for the scope are skipped. projectDirAbs resolves project-relative paths; "" means the
+// current directory.
+func DetectHarnesses(registry map[string]AgentSpec, projectDirAbs string, global bool) []DetectedHarness {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var detected []DetectedHarness
+	for _, name := range names {
+		spec := registry[name]
+		if _, err := ResolveAgentInstallDir(spec, projectDirAbs, global); err != nil {
+			continue
+		}
+		if found := harnessDetectPath(spec, projectDirAbs); found != "" {
+			detected = append(detected, DetectedHarness{Spec: spec, Found: found})
+		}
+	}
+	return detected
+}
+
+// harnessDetectPath returns the first existing path that shows spec is in use, or "".
+func harnessDetectPath(spec AgentSpec, projectDirAbs string) string {
+	if projectDirAbs == "" {
+		projectDirAbs = "."
+	}
+	var candidates []string
+	if spec.Config.ProjectDir != "" {
+		candidates = append(candidates, filepath.Join(projectDirAbs, spec.Config.ProjectDir))
+	}
+	if spec.Config.GlobalDir != "" {
+		candidates = append(candidates, ExpandHome(spec.Config.GlobalDir))
+	}
+	for _, path := range spec.Config.DetectPaths {
+		path = strings.TrimSpace(path)
+		switch {
+		case path == "":
+			continue
+		case strings.HasPrefix(path, "~/"):
+			path = ExpandHome(path)
+		case !filepath.IsAbs(path):
+			path = filepath.Join(projectDirAbs, path)
+		}
+		candidates = append(candidates, path)
+	}
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// resolveInstallHarnesses resolves --harness, or the harnesses in use when it is omitted: all of them with
+// --auto-harness, or the ones the user picks when prompts are allowed.
+func resolveInstallHarnesses(registry map[string]AgentSpec, input InstallFlagInput, projectDirAbs string, helpExample AgentRegistryHelpExample) ([]AgentSpec, error) {
+	if input.RawHarness != "" {
+		if input.AutoHarness {
+			return nil, fmt.Errorf("--auto-harness cannot be combined with --harness")
+		}
+		return resolveHarnessSpecs(registry, input.RawHarness, helpExample)
+	}
+	if !input.AutoHarness && IsNonInteractive() {
+		return nil, requireHarnessWhenNotPath(input.RawHarness, registry)
+	}
+	detected := DetectHarnesses(registry, projectDirAbs, input.IsGlobal)
+	if len(detected) == 0 {
+		return nil, fmt.Errorf("no harness was detected on this machine; set --harness. Supported harnesses: %s", AgentNames(registry))
+	}
+	if input.AutoHarness {
+		specs := make([]AgentSpec, 0, len(detected))
+		for _, harness := range detected {
+			log.Info(fmt.Sprintf("Detected harness %s (found %s)", harness.Spec.Name, harness.Found))
+			specs = append(specs, harness.Spec)
+		}
+		return specs, nil
+	}
+	return selectDetectedHarnesses(detected)
+}
+
+// selectDetectedHarnesses asks which detected harnesses to use, one at a time.
+func selectDetectedHarnesses(detected []DetectedHarness) ([]AgentSpec, error) {
+	var specs []AgentSpec
+	for _, harness := range detected {
+		if AskYesNo(fmt.Sprintf("Use harness %s (found %s)?", harness.Spec.Name, harness.Found), true) {
+			specs = append(specs, harness.Spec)
+		}
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("no harness selected; set --harness or answer yes for at least one detected harness")
+	}
+	return specs, nil
+}
@@ -0,0 +1,124 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-artifactory/agent/common/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testDetectRegistry = map[string]AgentSpec{
+	"claude-code": {Name: "claude-code", Config: AgentConfig{GlobalDir: "~/.claude/skills", ProjectDir: ".claude/skills", DetectPaths: []string{".claude", "CLAUDE.md", "~/.claude"}}},
+	"cursor":      {Name: "cursor", Config: AgentConfig{GlobalDir: "~/.cursor/skills", ProjectDir: ".cursor/skills", DetectPaths: []string{".cursor", "~/.cursor"}}},
+	"codex":       {Name: "codex", Config: AgentConfig{GlobalDir: "~/.codex/skills", DetectPaths: []string{"~/.codex"}}},
+}
+
+// withDetectHome points the home directory at a fresh temp dir so global markers on the host are ignored.
+func withDetectHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	return home
+}
+
+func detectedNames(detected []DetectedHarness) []string {
+	names := make([]string, 0, len(detected))
+	for _, harness := range detected {
+		names = append(names, harness.Spec.Name)
+	}
+	return names
+}
+
+func TestDetectHarnesses(t *testing.T) {
+	home := withDetectHome(t)
+	project := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(project, "CLAUDE.md"), []byte("# notes"), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".cursor"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".codex"), 0o755))
+
+	detected := DetectHarnesses(testDetectRegistry, project, false)
+	assert.Equal(t, []string{"claude-code", "cursor"}, detectedNames(detected), "codex has no project directory")
+	assert.Equal(t, filepath.Join(project, "CLAUDE.md"), detected[0].Found)
+	assert.Equal(t, filepath.Join(home, ".cursor"), detected[1].Found)
+
+	detected = DetectHarnesses(testDetectRegistry, "", true)
+	assert.Equal(t, []string{"codex", "cursor"}, detectedNames(detected))
+}
+
+func TestValidateInstallFlags_AutoHarness(t *testing.T) {
+	testutil.WithJfrogHome(t)
+	withDetectHome(t)
+	project := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(project, ".cursor", "skills"), 0o755))
+
+	c := testutil.NewCLIContext()
+	c.AddBoolFlag(InstallAutoHarnessFlag, true)
+	c.AddStringFlag(InstallProjectDirFlag, project)
+	flags, err := ValidateInstallFlags(c, testSkillsAgents, SkillsAgentsKey, testSkillsHelp)
+	require.NoError(t, err)
+	require.Len(t, flags.Specs, 1)
+	assert.Equal(t, "cursor", flags.Specs[0].Name)
+}
+
+func TestValidateInstallFlags_AutoHarnessErrors(t *testing.T) {
+	testutil.WithJfrogHome(t)
+	withDetectHome(t)
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, input *InstallFlagInput)
+		wantErr string
+	}{
+		{name: "nothing detected", wantErr: "no harness was detected"},
+		{
+			name:    "with harness",
+			setup:   func(_ *testing.T, input *InstallFlagInput) { input.RawHarness = "cursor" },
+			wantErr: "--auto-harness cannot be combined with --harness",
+		},
+		{
+			name:    "with path",
+			setup:   func(t *testing.T, input *InstallFlagInput) { input.PathInstallBase = t.TempDir() },
+			wantErr: "--path cannot be combined with --auto-harness",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := InstallFlagInput{ProjectDir: t.TempDir(), AutoHarness: true}
+			if tt.setup != nil {
+				tt.setup(t, &input)
+			}
+			_, done, err := validatePathInstallFlags(input)
+			if !done {
+				_, err = validateHarnessInstallFlags(input, testSkillsAgents, SkillsAgentsKey, testSkillsHelp)
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestSelectDetectedHarnesses(t *testing.T) {
+	restore := askYesNoPrompt
+	t.Cleanup(func() { askYesNoPrompt = restore })
+	detected := []DetectedHarness{
+		{Spec: testDetectRegistry["claude-code"], Found: "CLAUDE.md"},
+		{Spec: testDetectRegistry["cursor"], Found: ".cursor"},
+	}
+
+	var asked []string
+	askYesNoPrompt = func(prompt string, defaultValue bool) bool {
+		asked = append(asked, prompt)
+		return len(asked) == 2
+	}
+	specs, err := selectDetectedHarnesses(detected)
+	require.NoError(t, err)
+	require.Len(t, specs, 1)
+	assert.Equal(t, "cursor", specs[0].Name)
+	assert.Equal(t, "Use harness claude-code (found CLAUDE.md)?", asked[0])
+
+	askYesNoPrompt = func(string, bool) bool { return false }
+	_, err = selectDetectedHarnesses(detected)
+	assert.ErrorContains(t, err, "no harness selected")
+}
@@ -12,10 +12,11 @@ import (
 )
 
 const (
-	InstallPathFlag       = "path"
-	InstallHarnessFlag    = "harness"
-	InstallProjectDirFlag = "project-dir"
-	InstallGlobalFlag     = "global"
+	InstallPathFlag        = "path"
+	InstallHarnessFlag     = "harness"
+	InstallProjectDirFlag  = "project-dir"
+	InstallGlobalFlag      = "global"
+	InstallAutoHarnessFlag = "auto-harness"
 )
 
 // InstallFlagInput holds install flag values shared by skills and plugins install validation.
@@ -24,6 +25,8 @@ type InstallFlagInput struct {
 	RawHarness      string
 	ProjectDir      string
 	IsGlobal        bool
+	// AutoHarness uses every detected harness when RawHarness is empty, also in non-interactive runs.
+	AutoHarness bool
 }
 
 // InstallFlagsResult holds validated install/update flags after harness or --path resolution.
@@ -42,13 +45,44 @@ func (r InstallFlagsResult) PathMode() bool {
 	return r.AbsoluteInstallBaseDir != ""
 }
 
-// ValidateInstallFlags validates `--path | (--harness [, --project-dir | --global])` for install/update.
+// Scope returns the install scope selected by the flags.
+func (r InstallFlagsResult) Scope() InstallScope {
+	switch {
+	case r.PathMode():
+		return InstallScopePath
+	case r.IsGlobal:
+		return InstallScopeGlobal
+	default:
+		return InstallScopeProject
+	}
+}
+
+// HarnessNames returns the selected harness names, or PathAgentName in path mode.
+func (r InstallFlagsResult) HarnessNames() []string {
+	if r.PathMode() {
+		return []string{PathAgentName}
+	}
+	names := make([]string, 0, len(r.Specs))
+	for _, spec := range r.Specs {
+		names = append(names, spec.Name)
+	}
+	return names
+}
+
+// LockfilePath returns the lockfile location for the selected scope.
+func (r InstallFlagsResult) LockfilePath() (string, error) {
+	return LockfilePath(r.Scope(), r.ProjectDirAbs, r.AbsoluteInstallBaseDir)
+}
+
+// ValidateInstallFlags validates `--path | (--harness | --auto-harness [, --project-dir | --global])` for install/update.
+// Without --harness, interactive runs offer the detected harnesses to pick from.
 func ValidateInstallFlags(c *components.Context, builtIns map[string]AgentConfig, configSectionKey string, helpExample AgentRegistryHelpExample) (InstallFlagsResult, error) {
 	input := InstallFlagInput{
 		PathInstallBase: strings.TrimSpace(c.GetStringFlagValue(InstallPathFlag)),
 		RawHarness:      strings.TrimSpace(c.GetStringFlagValue(InstallHarnessFlag)),
 		ProjectDir:      strings.TrimSpace(c.GetStringFlagValue(InstallProjectDirFlag)),
 		IsGlobal:        c.GetBoolFlagValue(InstallGlobalFlag),
+		AutoHarness:     c.GetBoolFlagValue(InstallAutoHarnessFlag),
 	}
 	if result, done, err := validatePathInstallFlags(input); done {
 		return result, err
@@ -74,16 +108,12 @@ func validateHarnessInstallFlags(input InstallFlagInput, builtIns map[string]Age
 	if err != nil {
 		return InstallFlagsResult{}, err
 	}
-	if err := requireHarnessWhenNotPath(input.RawHarness, registry); err != nil {
-		return InstallFlagsResult{}, err
-	}
-
-	specs, err := resolveHarnessSpecs(registry, input.RawHarness, helpExample)
+	projectDirAbs, err := ResolveInstallProjectDir(input.ProjectDir, input.IsGlobal)
 	if err != nil {
 		return InstallFlagsResult{}, err
 	}
 
-	projectDirAbs, err := ResolveInstallProjectDir(input.ProjectDir, input.IsGlobal)
+	specs, err := resolveInstallHarnesses(registry, input, projectDirAbs, helpExample)
 	if err != nil {
 		return InstallFlagsResult{}, err
 	}
@@ -94,12 +124,12 @@ func validateHarnessInstallFlags(input InstallFlagInput, builtIns map[string]Age
 	}, nil
 }
 
-// requireHarnessWhenNotPath ensures --harness is present when not using --path.
+// requireHarnessWhenNotPath ensures --harness is present when not using --path or --auto-harness.
 func requireHarnessWhenNotPath(rawHarness string, registry map[string]AgentSpec) error {
 	if rawHarness != "" {
 		return nil
 	}
-	return fmt.Errorf("--harness is required unless --path is set. Supported harnesses: %s", AgentNames(registry))
+	return fmt.Errorf("--harness is required unless --path or --auto-harness is set. Supported harnesses: %s", AgentNames(registry))
 }
 
 // resolveHarnessSpecs parses --harness and resolves each name against the agent registry.
@@ -129,6 +159,9 @@ func ResolvePathInstallBase(flags InstallFlagInput) (string, error) {
 	if flags.RawHarness != "" {
 		return "", fmt.Errorf("--path cannot be combined with --harness")
 	}
+	if flags.AutoHarness {
+		return "", fmt.Errorf("--path cannot be combined with --auto-harness")
+	}
 	if flags.IsGlobal {
 		return "", fmt.Errorf("--path cannot be combined with --global")
 	}
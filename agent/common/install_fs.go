@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
@@ -32,6 +33,14 @@ type InstallInfoManifest struct {
 	Scope            string `json:"scope"`
 	Agent            string `json:"agent"`
 	ProjectDir       string `json:"projectDir,omitempty"`
+	// Constraint is the version range the package was installed with (e.g. "^1.2"); update stays within it.
+	Constraint string `json:"constraint,omitempty"`
+	// StorePath is the shared store version this install links to (--link); empty for copied installs.
+	StorePath string `json:"storePath,omitempty"`
+	// SHA256 is the digest of the package zip the install came from; rollback re-pins it in the lockfile.
+	SHA256 string `json:"sha256,omitempty"`
+	// ConvertedFrom is the harness a plugin was published for when it was converted to the layout of Agent.
+	ConvertedFrom string `json:"convertedFrom,omitempty"`
 }
 
 // installInfoManifestPath is <installDir>/.jfrog/<manifestFileName>.
@@ -78,6 +87,45 @@ func ReadInstallInfoManifest(installDir, manifestFileName string) (*InstallInfoM
 	return &manifest, nil
 }
 
+// ConstraintGroup is a set of install targets whose update version is resolved from the same request.
+type ConstraintGroup struct {
+	// Requested is passed to version resolution: the explicit --version or the recorded constraint.
+	Requested string
+	// Constraint is written to each target's install manifest by the update.
+	Constraint string
+	Targets    []InstallTarget
+}
+
+// GroupTargetsByConstraint groups update targets by version request. An explicit --version applies to
+// every target and replaces any recorded constraint; otherwise each target keeps the range recorded in
+// its install manifest, and targets without one resolve to the latest version.
+func GroupTargetsByConstraint(targets []InstallTarget, manifestFileName, requested string) []ConstraintGroup {
+	requested = strings.TrimSpace(requested)
+	if requested != "" {
+		constraint := ""
+		if IsVersionRange(requested) {
+			constraint = requested
+		}
+		return []ConstraintGroup{{Requested: requested, Constraint: constraint, Targets: targets}}
+	}
+	var groups []ConstraintGroup
+	groupIndex := map[string]int{}
+	for _, target := range targets {
+		constraint := ""
+		if manifest, err := ReadInstallInfoManifest(target.DestinationDir, manifestFileName); err == nil && manifest != nil {
+			constraint = strings.TrimSpace(manifest.Constraint)
+		}
+		index, found := groupIndex[constraint]
+		if !found {
+			index = len(groups)
+			groupIndex[constraint] = index
+			groups = append(groups, ConstraintGroup{Requested: constraint, Constraint: constraint})
+		}
+		groups[index].Targets = append(groups[index].Targets, target)
+	}
+	return groups
+}
+
 // packageZipDownloadManager downloads package zips from Artifactory; swappable in tests.
 type packageZipDownloadManager interface {
 	DownloadFiles(params ...services.DownloadParams) (totalDownloaded, totalFailed int, err error)
@@ -94,7 +142,7 @@ func DownloadPackageZip(serverDetails *config.ServerDetails, repoKey, slug, vers
 		return "", fmt.Errorf("create download service manager: %w", err)
 	}
 
-	pattern := fmt.Sprintf("%s/%s/%s/%s-%s.zip", repoKey, slug, version, slug, version)
+	pattern := packageZipRepoPath(repoKey, slug, version)
 	downloadParams := services.NewDownloadParams()
 	downloadParams.Pattern = pattern
 	downloadParams.Target = tmpDir + "/"
@@ -118,13 +166,28 @@ func DownloadPackageZip(serverDetails *config.ServerDetails, repoKey, slug, vers
 // verifyEvidenceForPackageZip is swappable in tests.
 var verifyEvidenceForPackageZip = VerifyEvidence
 
+// exportEvidenceForPackageZip is swappable in tests.
+var exportEvidenceForPackageZip = ExportEvidence
+
 // VerifyPackageEvidence verifies evidence for a published package zip in Artifactory.
 func VerifyPackageEvidence(serverDetails *config.ServerDetails, repoKey, slug, version string) error {
 	if repoKey == "" || slug == "" || version == "" {
 		return fmt.Errorf("cannot verify evidence: repoKey, slug, and version must all be set")
 	}
-	subjectRepoPath := fmt.Sprintf("%s/%s/%s/%s-%s.zip", repoKey, slug, version, slug, version)
 	return verifyEvidenceForPackageZip(serverDetails, VerifyEvidenceOpts{
-		SubjectRepoPath: subjectRepoPath,
+		SubjectRepoPath: packageZipRepoPath(repoKey, slug, version),
 	})
 }
+
+// ExportPackageEvidence writes the evidence of a published package zip to outputPath as JSON.
+func ExportPackageEvidence(serverDetails *config.ServerDetails, repoKey, slug, version, outputPath string) error {
+	return exportEvidenceForPackageZip(serverDetails, ExportEvidenceOpts{
+		SubjectRepoPath: packageZipRepoPath(repoKey, slug, version),
+		OutputPath:      outputPath,
+	})
+}
+
+// packageZipRepoPath is <repo>/<slug>/<version>/<slug>-<version>.zip.
+func packageZipRepoPath(repoKey, slug, version string) string {
+	return fmt.Sprintf("%s/%s/%s/%s-%s.zip", repoKey, slug, version, slug, version)
+}
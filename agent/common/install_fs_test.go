@@ -137,3 +137,31 @@ func TestDownloadPackageZip_DownloadFailed(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "download failed for local/my-plugin/2.0.0/my-plugin-2.0.0.zip")
 }
+
+func TestGroupTargetsByConstraint(t *testing.T) {
+	root := t.TempDir()
+	pinned := InstallTarget{Agent: AgentSpec{Name: "cursor"}, DestinationDir: filepath.Join(root, "cursor")}
+	ranged := InstallTarget{Agent: AgentSpec{Name: "claude-code"}, DestinationDir: filepath.Join(root, "claude")}
+	missing := InstallTarget{Agent: AgentSpec{Name: "windsurf"}, DestinationDir: filepath.Join(root, "windsurf")}
+	require.NoError(t, WriteInstallInfoManifest(pinned.DestinationDir, "info.json", InstallInfoManifest{Slug: "web", InstalledVersion: "1.0.0"}))
+	require.NoError(t, WriteInstallInfoManifest(ranged.DestinationDir, "info.json", InstallInfoManifest{Slug: "web", InstalledVersion: "1.2.0", Constraint: "^1.2"}))
+	targets := []InstallTarget{pinned, ranged, missing}
+
+	groups := GroupTargetsByConstraint(targets, "info.json", "")
+	require.Len(t, groups, 2)
+	assert.Equal(t, "", groups[0].Requested)
+	assert.Equal(t, []InstallTarget{pinned, missing}, groups[0].Targets)
+	assert.Equal(t, "^1.2", groups[1].Requested)
+	assert.Equal(t, "^1.2", groups[1].Constraint)
+	assert.Equal(t, []InstallTarget{ranged}, groups[1].Targets)
+
+	groups = GroupTargetsByConstraint(targets, "info.json", "2.0.0")
+	require.Len(t, groups, 1)
+	assert.Equal(t, "2.0.0", groups[0].Requested)
+	assert.Empty(t, groups[0].Constraint)
+	assert.Len(t, groups[0].Targets, 3)
+
+	groups = GroupTargetsByConstraint(targets, "info.json", "~2.1.0")
+	require.Len(t, groups, 1)
+	assert.Equal(t, "~2.1.0", groups[0].Constraint)
+}
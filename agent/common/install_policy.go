@@ -0,0 +1,319 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	// InstallPolicyKey holds the organization install policy in agent-config.json.
+	InstallPolicyKey = "install-policy"
+	// DefaultOrgInstallPolicyPath is the well-known Artifactory path, as <repo>/<path>, of the organization
+	// install policy. It applies to installs from every repository, so only admins of that repository control it.
+	DefaultOrgInstallPolicyPath = "agent-policies/agent-install-policy.json"
+	// EnvOrgInstallPolicyPath points the organization install policy at another <repo>/<path>.
+	EnvOrgInstallPolicyPath = "JFROG_AGENT_INSTALL_POLICY"
+)
+
+// fetchOrgInstallPolicy is swappable in tests.
+var fetchOrgInstallPolicy = downloadOrgInstallPolicy
+
+// InstallPolicyRules is one install policy document. Allow and deny lists take exact names or path.Match
+// patterns such as "team-*"; an empty allow list allows everything that is not denied.
+type InstallPolicyRules struct {
+	AllowRepos     []string `json:"allowRepos,omitempty"`
+	DenyRepos      []string `json:"denyRepos,omitempty"`
+	AllowSlugs     []string `json:"allowSlugs,omitempty"`
+	DenySlugs      []string `json:"denySlugs,omitempty"`
+	AllowHarnesses []string `json:"allowHarnesses,omitempty"`
+	// RequireEvidence fails installs of versions without verified evidence instead of warning or prompting.
+	RequireEvidence bool `json:"requireEvidence,omitempty"`
+	// RequireXrayApproval installs only versions whose Xray status is APPROVED.
+	RequireXrayApproval bool `json:"requireXrayApproval,omitempty"`
+}
+
+// InstallPolicy is the effective install policy: every rule set it holds must allow an install.
+// A nil InstallPolicy allows everything.
+type InstallPolicy struct {
+	sets []installPolicySource
+}
+
+type installPolicySource struct {
+	source string
+	rules  InstallPolicyRules
+}
+
+// PolicyViolationError is returned when the install policy blocks a package, a harness, or an unverified version.
+type PolicyViolationError struct {
+	Source string
+	Detail string
+}
+
+func (e *PolicyViolationError) Error() string {
+	return fmt.Sprintf("blocked by install policy (%s): %s", e.Source, e.Detail)
+}
+
+// IsPolicyViolation reports whether err comes from the install policy.
+func IsPolicyViolation(err error) bool {
+	var violation *PolicyViolationError
+	return errors.As(err, &violation)
+}
+
+// LoadInstallPolicy returns the install policy: the rules under InstallPolicyKey in agent-config.json and, when
+// serverDetails is set, the organization policy at OrgInstallPolicyPath. The organization policy does not depend on
+// the repository being installed from, so publishing to a repository cannot change or remove it.
+//
+// When the default path holds no policy the organization has none and nil may be returned. The same holds when the
+// user may not read the default path (403): most users have no read access to agent-policies, and a policy they
+// cannot read cannot be enforced on them, so the install goes ahead with a warning. Organizations that require the
+// policy to apply set EnvOrgInstallPolicyPath. A configured path fails closed: a missing or forbidden policy is an
+// error. A policy that exists but cannot be read or parsed is always an error.
+func LoadInstallPolicy(serverDetails *config.ServerDetails) (*InstallPolicy, error) {
+	policy := &InstallPolicy{}
+	section, configPath, err := LoadAgentConfigSection(InstallPolicyKey)
+	if err != nil {
+		return nil, err
+	}
+	if section != nil {
+		if err := policy.add(section, configPath); err != nil {
+			return nil, fmt.Errorf("failed to parse %q in %s: %w", InstallPolicyKey, configPath, err)
+		}
+	}
+	if serverDetails != nil {
+		policyPath, configured := OrgInstallPolicyPath()
+		data, err := fetchOrgInstallPolicy(serverDetails, policyPath)
+		if errors.Is(err, errOrgInstallPolicyForbidden) && !configured {
+			log.Warn(fmt.Sprintf("No permission to read the organization install policy %s; installing without it.", policyPath))
+			err = nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the organization install policy %s: %w", policyPath, err)
+		}
+		switch {
+		case data != nil:
+			if err := policy.add(data, policyPath); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", policyPath, err)
+			}
+		case configured:
+			return nil, fmt.Errorf("the organization install policy %s set by %s was not found", policyPath, EnvOrgInstallPolicyPath)
+		default:
+			log.Debug(fmt.Sprintf("No organization install policy at %s", policyPath))
+		}
+	}
+	if len(policy.sets) == 0 {
+		return nil, nil
+	}
+	return policy, nil
+}
+
+// OrgInstallPolicyPath returns the <repo>/<path> of the organization install policy and whether it was set by
+// EnvOrgInstallPolicyPath rather than defaulted.
+func OrgInstallPolicyPath() (string, bool) {
+	if configured := strings.Trim(strings.TrimSpace(os.Getenv(EnvOrgInstallPolicyPath)), "/"); configured != "" {
+		return configured, true
+	}
+	return DefaultOrgInstallPolicyPath, false
+}
+
+// add parses one policy document. Unknown keys are rejected so a misspelled rule is not silently ignored.
+func (p *InstallPolicy) add(data []byte, source string) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var rules InstallPolicyRules
+	if err := decoder.Decode(&rules); err != nil {
+		return err
+	}
+	p.sets = append(p.sets, installPolicySource{source: source, rules: rules})
+	return nil
+}
+
+// CheckPackage returns a PolicyViolationError when repoKey or slug is not allowed.
+func (p *InstallPolicy) CheckPackage(repoKey, slug string) error {
+	if p == nil {
+		return nil
+	}
+	for _, set := range p.sets {
+		if detail := checkAllowDeny("repository", repoKey, set.rules.AllowRepos, set.rules.DenyRepos); detail != "" {
+			return &PolicyViolationError{Source: set.source, Detail: detail}
+		}
+		if detail := checkAllowDeny("package", slug, set.rules.AllowSlugs, set.rules.DenySlugs); detail != "" {
+			return &PolicyViolationError{Source: set.source, Detail: detail}
+		}
+	}
+	return nil
+}
+
+// CheckHarness returns a PolicyViolationError when packages may not be installed for harness.
+func (p *InstallPolicy) CheckHarness(harness string) error {
+	if p == nil {
+		return nil
+	}
+	for _, set := range p.sets {
+		if detail := checkAllowDeny("harness", harness, set.rules.AllowHarnesses, nil); detail != "" {
+			return &PolicyViolationError{Source: set.source, Detail: detail}
+		}
+	}
+	return nil
+}
+
+// CheckEvidence turns a failed evidence verification into a PolicyViolationError when the policy requires
+// evidence. It returns nil when verifyErr is nil or evidence is not required.
+func (p *InstallPolicy) CheckEvidence(label, slug, version string, verifyErr error) error {
+	if p == nil || verifyErr == nil {
+		return nil
+	}
+	for _, set := range p.sets {
+		if set.rules.RequireEvidence {
+			return &PolicyViolationError{
+				Source: set.source,
+				Detail: fmt.Sprintf("%s '%s' v%s has no verified evidence: %s", label, slug, version, verifyErr.Error()),
+			}
+		}
+	}
+	return nil
+}
+
+// CheckXrayApproval returns a PolicyViolationError when the policy requires Xray approval and the version is not
+// APPROVED. Without serverDetails, e.g. for bundled installs, approval cannot be confirmed and the install is refused.
+func (p *InstallPolicy) CheckXrayApproval(serverDetails *config.ServerDetails, kind XrayGateKind, repoKey, slug, version string) error {
+	if p == nil {
+		return nil
+	}
+	label := strings.ToLower(kind.Label)
+	for _, set := range p.sets {
+		if !set.rules.RequireXrayApproval {
+			continue
+		}
+		if serverDetails == nil {
+			return &PolicyViolationError{Source: set.source, Detail: fmt.Sprintf("Xray approval of %s '%s' v%s cannot be confirmed offline", label, slug, version)}
+		}
+		status, err := XrayStatus(serverDetails, kind, repoKey, slug, version)
+		if err != nil {
+			return &PolicyViolationError{Source: set.source, Detail: fmt.Sprintf("could not confirm Xray approval of %s '%s' v%s: %s", label, slug, version, err.Error())}
+		}
+		if status != services.SkillXrayStatusApproved {
+			return &PolicyViolationError{Source: set.source, Detail: fmt.Sprintf("%s '%s' v%s has Xray status %s, not %s", label, slug, version, status, services.SkillXrayStatusApproved)}
+		}
+		log.Debug(fmt.Sprintf("Xray approved %s '%s' v%s", label, slug, version))
+		return nil
+	}
+	return nil
+}
+
+// CheckTarget returns a PolicyViolationError when packages may not be installed into target. Path-mode targets
+// are not tied to a harness and are always allowed.
+func (p *InstallPolicy) CheckTarget(target InstallTarget) error {
+	if target.Agent.Name == PathAgentName {
+		return nil
+	}
+	return p.CheckHarness(target.Agent.Name)
+}
+
+// AllowedTargets splits targets into those the policy allows and failed summary rows for the rest.
+func (p *InstallPolicy) AllowedTargets(targets []InstallTarget) ([]InstallTarget, []SummaryRow) {
+	if p == nil {
+		return targets, nil
+	}
+	allowed := make([]InstallTarget, 0, len(targets))
+	var rows []SummaryRow
+	for _, target := range targets {
+		if err := p.CheckTarget(target); err != nil {
+			rows = append(rows, InstallFailureRow(target.Agent.Name, string(target.Scope), target.DestinationDir, err))
+			continue
+		}
+		allowed = append(allowed, target)
+	}
+	return allowed, rows
+}
+
+// checkAllowDeny returns why value is not allowed by allow and deny, or "" when it is.
+func checkAllowDeny(kind, value string, allow, deny []string) string {
+	if matchesPolicyPattern(value, deny) {
+		return fmt.Sprintf("%s '%s' is denied", kind, value)
+	}
+	if len(allow) > 0 && !matchesPolicyPattern(value, allow) {
+		return fmt.Sprintf("%s '%s' is not in the allowed list (%s)", kind, value, strings.Join(allow, ", "))
+	}
+	return ""
+}
+
+// matchesPolicyPattern reports whether value equals or matches one of patterns. Invalid patterns never match.
+func matchesPolicyPattern(value string, patterns []string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == value {
+			return true
+		}
+		if matched, err := path.Match(pattern, value); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// errOrgInstallPolicyForbidden is returned when the user may not read the organization install policy.
+var errOrgInstallPolicyForbidden = errors.New("permission denied (403)")
+
+// InstallPolicyLoader loads the install policy on first use and returns the same policy to every install command of
+// a CLI run, so the organization policy is downloaded once however many packages the run installs. It is safe for
+// concurrent use.
+type InstallPolicyLoader struct {
+	serverDetails *config.ServerDetails
+	once          sync.Once
+	policy        *InstallPolicy
+	err           error
+}
+
+// NewInstallPolicyLoader returns a loader for the policy that applies to installs from serverDetails; nil means only
+// the local rules apply, as for bundled installs.
+func NewInstallPolicyLoader(serverDetails *config.ServerDetails) *InstallPolicyLoader {
+	return &InstallPolicyLoader{serverDetails: serverDetails}
+}
+
+// Load returns the install policy, reading it on the first call.
+func (l *InstallPolicyLoader) Load() (*InstallPolicy, error) {
+	l.once.Do(func() {
+		l.policy, l.err = LoadInstallPolicy(l.serverDetails)
+	})
+	return l.policy, l.err
+}
+
+// downloadOrgInstallPolicy reads the policy at policyPath (<repo>/<path>). It returns nil data when there is none.
+func downloadOrgInstallPolicy(serverDetails *config.ServerDetails, policyPath string) ([]byte, error) {
+	sm, err := utils.CreateServiceManager(serverDetails, 3, 0, false)
+	if err != nil {
+		return nil, fmt.Errorf("could not create service manager: %w", err)
+	}
+	artURL := clientutils.AddTrailingSlashIfNeeded(sm.GetConfig().GetServiceDetails().GetUrl())
+	policyURL := artURL + policyPath
+	log.Debug("Install policy request:", policyURL)
+	httpDetails := sm.GetConfig().GetServiceDetails().CreateHttpClientDetails()
+	resp, body, _, err := sm.Client().SendGet(policyURL, true, &httpDetails)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return nil, nil
+	case http.StatusForbidden:
+		return nil, errOrgInstallPolicyForbidden
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
@@ -0,0 +1,178 @@
+package common
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-artifactory/agent/common/testutil"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testPolicyServer = &config.ServerDetails{ArtifactoryUrl: "https://example.jfrog.io/artifactory/"}
+
+// withOrgInstallPolicy serves body as the organization policy; nil means there is none. Requested paths are
+// appended to the returned slice.
+func withOrgInstallPolicy(t *testing.T, body []byte, err error) *[]string {
+	t.Helper()
+	restore := fetchOrgInstallPolicy
+	t.Cleanup(func() { fetchOrgInstallPolicy = restore })
+	var requested []string
+	fetchOrgInstallPolicy = func(_ *config.ServerDetails, policyPath string) ([]byte, error) {
+		requested = append(requested, policyPath)
+		return body, err
+	}
+	return &requested
+}
+
+func TestLoadInstallPolicy_None(t *testing.T) {
+	testutil.WithJfrogHome(t)
+	withOrgInstallPolicy(t, nil, nil)
+
+	policy, err := LoadInstallPolicy(testPolicyServer)
+	require.NoError(t, err)
+	assert.Nil(t, policy)
+	assert.NoError(t, policy.CheckPackage("skills-local", "any"))
+	assert.NoError(t, policy.CheckHarness("cursor"))
+}
+
+func TestLoadInstallPolicy_MergesConfigAndOrganization(t *testing.T) {
+	home := testutil.WithJfrogHome(t)
+	testutil.WriteAgentConfig(t, home, `{"install-policy": {"allowRepos": ["team-*"], "allowHarnesses": ["cursor", "claude*"]}}`)
+	withOrgInstallPolicy(t, []byte(`{"denySlugs": ["legacy-*"], "denyRepos": ["team-sandbox"]}`), nil)
+
+	policy, err := LoadInstallPolicy(testPolicyServer)
+	require.NoError(t, err)
+	require.NotNil(t, policy)
+
+	assert.NoError(t, policy.CheckPackage("team-skills", "web"))
+	err = policy.CheckPackage("other-skills", "web")
+	assert.True(t, IsPolicyViolation(err))
+	assert.ErrorContains(t, err, "repository 'other-skills' is not in the allowed list (team-*)")
+	assert.ErrorContains(t, err, "agent-config.json")
+
+	err = policy.CheckPackage("team-skills", "legacy-web")
+	assert.ErrorContains(t, err, "package 'legacy-web' is denied")
+	assert.ErrorContains(t, err, DefaultOrgInstallPolicyPath)
+	assert.ErrorContains(t, policy.CheckPackage("team-sandbox", "web"), "repository 'team-sandbox' is denied")
+
+	assert.NoError(t, policy.CheckHarness("claude-code"))
+	assert.ErrorContains(t, policy.CheckHarness("codex"), "harness 'codex' is not in the allowed list")
+}
+
+func TestLoadInstallPolicy_AppliesToRepositoriesWithoutPolicyFile(t *testing.T) {
+	testutil.WithJfrogHome(t)
+	requested := withOrgInstallPolicy(t, []byte(`{"denySlugs": ["legacy-*"]}`), nil)
+
+	// The policy is read from the well-known path only; the repository installed from holds no policy file.
+	policy, err := LoadInstallPolicy(testPolicyServer)
+	require.NoError(t, err)
+	assert.Equal(t, []string{DefaultOrgInstallPolicyPath}, *requested)
+	assert.ErrorContains(t, policy.CheckPackage("unmanaged-skills", "legacy-web"), "package 'legacy-web' is denied")
+}
+
+func TestLoadInstallPolicy_ConfiguredPathFailsClosed(t *testing.T) {
+	testutil.WithJfrogHome(t)
+	t.Setenv(EnvOrgInstallPolicyPath, "/security/agents/policy.json")
+	requested := withOrgInstallPolicy(t, nil, nil)
+
+	_, err := LoadInstallPolicy(testPolicyServer)
+	assert.ErrorContains(t, err, "the organization install policy security/agents/policy.json set by "+EnvOrgInstallPolicyPath+" was not found")
+	assert.Equal(t, []string{"security/agents/policy.json"}, *requested)
+}
+
+func TestLoadInstallPolicy_Forbidden(t *testing.T) {
+	home := testutil.WithJfrogHome(t)
+	testutil.WriteAgentConfig(t, home, `{"install-policy": {"allowRepos": ["team-*"]}}`)
+	withOrgInstallPolicy(t, nil, errOrgInstallPolicyForbidden)
+
+	// Users who may not read the default policy install under the local rules only.
+	policy, err := LoadInstallPolicy(testPolicyServer)
+	require.NoError(t, err)
+	require.NotNil(t, policy)
+	assert.NoError(t, policy.CheckPackage("team-skills", "web"))
+	assert.True(t, IsPolicyViolation(policy.CheckPackage("other-skills", "web")))
+
+	// A configured policy must be readable.
+	t.Setenv(EnvOrgInstallPolicyPath, "security/agents/policy.json")
+	_, err = LoadInstallPolicy(testPolicyServer)
+	assert.ErrorContains(t, err, "failed to read the organization install policy security/agents/policy.json: permission denied (403)")
+}
+
+func TestInstallPolicyLoader_LoadsOnce(t *testing.T) {
+	testutil.WithJfrogHome(t)
+	requested := withOrgInstallPolicy(t, []byte(`{"denySlugs": ["legacy-*"]}`), nil)
+
+	loader := NewInstallPolicyLoader(testPolicyServer)
+	for i := 0; i < 3; i++ {
+		policy, err := loader.Load()
+		require.NoError(t, err)
+		assert.True(t, IsPolicyViolation(policy.CheckPackage("skills-local", "legacy-web")))
+	}
+	assert.Equal(t, []string{DefaultOrgInstallPolicyPath}, *requested)
+}
+
+func TestLoadInstallPolicy_Errors(t *testing.T) {
+	home := testutil.WithJfrogHome(t)
+	testutil.WriteAgentConfig(t, home, `{"install-policy": {"allowRepo": ["team-*"]}}`)
+	withOrgInstallPolicy(t, nil, nil)
+	_, err := LoadInstallPolicy(nil)
+	assert.ErrorContains(t, err, `failed to parse "install-policy"`)
+
+	testutil.WriteAgentConfig(t, home, `{}`)
+	withOrgInstallPolicy(t, nil, errors.New("server response: 500 Internal Server Error"))
+	_, err = LoadInstallPolicy(testPolicyServer)
+	assert.ErrorContains(t, err, "failed to read the organization install policy "+DefaultOrgInstallPolicyPath)
+}
+
+func TestInstallPolicy_CheckXrayApproval(t *testing.T) {
+	policy := &InstallPolicy{sets: []installPolicySource{{source: "test", rules: InstallPolicyRules{RequireXrayApproval: true}}}}
+	restore := lookupXrayStatus
+	t.Cleanup(func() { lookupXrayStatus = restore })
+
+	status := services.SkillXrayStatusApproved
+	lookupXrayStatus = func(*config.ServerDetails, XrayGateKind, string, string) (string, error) {
+		return status, nil
+	}
+	assert.NoError(t, policy.CheckXrayApproval(testPolicyServer, XrayGatePlugins, "plugins", "web", "1.0.0"))
+
+	status = services.SkillXrayStatusScanInProgress
+	err := policy.CheckXrayApproval(testPolicyServer, XrayGatePlugins, "plugins", "web", "1.0.0")
+	assert.True(t, IsPolicyViolation(err))
+	assert.ErrorContains(t, err, "plugin 'web' v1.0.0 has Xray status "+status)
+
+	assert.ErrorContains(t, policy.CheckXrayApproval(nil, XrayGateSkills, "skills", "web", "1.0.0"), "cannot be confirmed offline")
+}
+
+func TestInstallPolicy_CheckEvidence(t *testing.T) {
+	verifyErr := errors.New("no evidence found")
+	var none *InstallPolicy
+	assert.NoError(t, none.CheckEvidence("skill", "web", "1.0.0", verifyErr))
+
+	lenient := &InstallPolicy{sets: []installPolicySource{{source: "test"}}}
+	assert.NoError(t, lenient.CheckEvidence("skill", "web", "1.0.0", verifyErr))
+
+	strict := &InstallPolicy{sets: []installPolicySource{{source: "test", rules: InstallPolicyRules{RequireEvidence: true}}}}
+	assert.NoError(t, strict.CheckEvidence("skill", "web", "1.0.0", nil))
+	assert.ErrorContains(t, strict.CheckEvidence("skill", "web", "1.0.0", verifyErr), "skill 'web' v1.0.0 has no verified evidence: no evidence found")
+}
+
+func TestInstallPolicy_AllowedTargets(t *testing.T) {
+	policy := &InstallPolicy{sets: []installPolicySource{{source: "test", rules: InstallPolicyRules{AllowHarnesses: []string{"cursor"}}}}}
+	targets := []InstallTarget{
+		{Agent: AgentSpec{Name: "cursor"}, Scope: InstallScopeProject, DestinationDir: "/p/.cursor/skills/web"},
+		{Agent: AgentSpec{Name: "codex"}, Scope: InstallScopeGlobal, DestinationDir: "/h/.codex/skills/web"},
+		{Agent: AgentSpec{Name: PathAgentName}, DestinationDir: "/opt/skills/web"},
+	}
+
+	allowed, rows := policy.AllowedTargets(targets)
+	require.Len(t, allowed, 2)
+	assert.Equal(t, "cursor", allowed[0].Agent.Name)
+	assert.Equal(t, PathAgentName, allowed[1].Agent.Name)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "codex", rows[0].Agent)
+	assert.Equal(t, SummaryStatusFailed, rows[0].Status)
+	assert.Contains(t, rows[0].Detail, "blocked by install policy (test)")
+}
@@ -14,6 +14,7 @@ const (
 	SummaryStatusOK        = "ok"
 	SummaryStatusFailed    = "failed"
 	SummaryStatusSkipped   = "skipped"
+	SummaryStatusRemoved   = "removed"
 	SummaryDetailOKInstall = "Executed successfully with no issues."
 )
 
@@ -77,6 +78,15 @@ type updateAllSummaryJSON struct {
 
 // PrintUpdateAllSummary renders one table or JSON blob for update --all across many packages.
 func PrintUpdateAllSummary(entityLabel string, results []UpdateAllSummaryRow, format string) error {
+	return printCombinedSummary(entityLabel+" update summary (--all):", "Updated", "No "+strings.ToLower(entityLabel)+"s updated", results, format)
+}
+
+// PrintSyncSummary renders one table or JSON blob for a sync run across every declared package.
+func PrintSyncSummary(entityLabel string, results []UpdateAllSummaryRow, format string) error {
+	return printCombinedSummary(entityLabel+" sync summary:", "Synced", "No "+strings.ToLower(entityLabel)+"s synced", results, format)
+}
+
+func printCombinedSummary(heading, title, emptyMsg string, results []UpdateAllSummaryRow, format string) error {
 	if len(results) == 0 {
 		return nil
 	}
@@ -84,15 +94,14 @@ func PrintUpdateAllSummary(entityLabel string, results []UpdateAllSummaryRow, fo
 		payload := updateAllSummaryJSON{Results: results}
 		data, err := json.MarshalIndent(payload, "", "  ")
 		if err != nil {
-			return fmt.Errorf("failed to marshal update-all summary: %w", err)
+			return fmt.Errorf("failed to marshal summary: %w", err)
 		}
 		fmt.Println(string(data))
 		return nil
 	}
-	log.Info(entityLabel + " update summary (--all):")
-	emptyMsg := "No " + strings.ToLower(entityLabel) + "s updated"
-	if err := coreutils.PrintTable(results, "Updated", emptyMsg, false); err != nil {
-		log.Warn("Failed to render update-all summary: " + err.Error())
+	log.Info(heading)
+	if err := coreutils.PrintTable(results, title, emptyMsg, false); err != nil {
+		log.Warn("Failed to render summary: " + err.Error())
 	}
 	return nil
 }
@@ -78,3 +78,26 @@ func BuildPathInstallTarget(slug, path string) (InstallTarget, error) {
 		DestinationDir: filepath.Join(base, slug),
 	}, nil
 }
+
+// TargetAgentNames returns the agent name of each target, in order.
+func TargetAgentNames(targets []InstallTarget) []string {
+	names := make([]string, 0, len(targets))
+	for _, target := range targets {
+		names = append(names, target.Agent.Name)
+	}
+	return names
+}
+
+// FilterAgentSpecs returns the specs whose names appear in names, preserving spec order.
+func FilterAgentSpecs(specs []AgentSpec, names []string) []AgentSpec {
+	filtered := make([]AgentSpec, 0, len(specs))
+	for _, spec := range specs {
+		for _, name := range names {
+			if spec.Name == name {
+				filtered = append(filtered, spec)
+				break
+			}
+		}
+	}
+	return filtered
+}
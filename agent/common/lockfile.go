@@ -0,0 +1,256 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+)
+
+const (
+	// LockfileName is the file that pins installed agent packages for reproducible installs.
+	LockfileName = "agents-lock.json"
+	// LockfileSchemaVersion is bumped when the lockfile JSON shape changes incompatibly.
+	LockfileSchemaVersion = 1
+
+	// LockKindSkill and LockKindPlugin identify the package kind of a lockfile entry.
+	LockKindSkill  = "skill"
+	LockKindPlugin = "plugin"
+)
+
+// LockEntry pins one package for one harness.
+type LockEntry struct {
+	Kind    string `json:"kind"`
+	Slug    string `json:"slug"`
+	Repo    string `json:"repo"`
+	Version string `json:"version"`
+	SHA256  string `json:"sha256"`
+	Harness string `json:"harness"`
+}
+
+// Lockfile is the on-disk shape of agents-lock.json.
+type Lockfile struct {
+	SchemaVersion int         `json:"schemaVersion"`
+	Entries       []LockEntry `json:"entries"`
+}
+
+// LockfilePath returns where the lockfile lives for an install scope:
+//   - project: <projectDir>/.jfrog/agents-lock.json (meant to be checked in)
+//   - global:  ~/.jfrog/agents/agents-lock.json
+//   - path:    <installPath>/.jfrog/agents-lock.json
+func LockfilePath(scope InstallScope, projectDir, installPath string) (string, error) {
+	switch {
+	case installPath != "" || scope == InstallScopePath:
+		if installPath == "" {
+			return "", fmt.Errorf("install path is required to locate the lockfile")
+		}
+		return filepath.Join(installPath, jfrogInstallDirName, LockfileName), nil
+	case scope == InstallScopeGlobal:
+		home, err := coreutils.GetJfrogHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("resolve JFrog home dir: %w", err)
+		}
+		return filepath.Join(home, agentsConfigSubdir, LockfileName), nil
+	default:
+		if projectDir == "" {
+			return "", fmt.Errorf("project directory is required to locate the lockfile")
+		}
+		return filepath.Join(projectDir, jfrogInstallDirName, LockfileName), nil
+	}
+}
+
+// ReadLockfile reads the lockfile at path. A missing file returns an empty lockfile.
+func ReadLockfile(path string) (*Lockfile, error) {
+	// #nosec G304 -- path is derived from the project, install, or JFrog home directory.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &Lockfile{SchemaVersion: LockfileSchemaVersion}, nil
+		}
+		return nil, fmt.Errorf("read lockfile: %w", err)
+	}
+	var lockfile Lockfile
+	if err := json.Unmarshal(data, &lockfile); err != nil {
+		return nil, fmt.Errorf("parse lockfile %s: %w", path, err)
+	}
+	if lockfile.SchemaVersion > LockfileSchemaVersion {
+		return nil, fmt.Errorf("lockfile %s has schema version %d; this CLI supports up to %d", path, lockfile.SchemaVersion, LockfileSchemaVersion)
+	}
+	return &lockfile, nil
+}
+
+// WriteLockfile writes the lockfile with entries sorted by kind, slug, and harness so diffs stay stable.
+func WriteLockfile(path string, lockfile *Lockfile) error {
+	lockfile.SchemaVersion = LockfileSchemaVersion
+	sort.SliceStable(lockfile.Entries, func(i, j int) bool {
+		a, b := lockfile.Entries[i], lockfile.Entries[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Slug != b.Slug {
+			return a.Slug < b.Slug
+		}
+		return a.Harness < b.Harness
+	})
+	if lockfile.Entries == nil {
+		lockfile.Entries = []LockEntry{}
+	}
+	data, err := json.MarshalIndent(lockfile, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal lockfile: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), InstallDirMode); err != nil {
+		return fmt.Errorf("create lockfile directory: %w", err)
+	}
+	// #nosec G306 -- the project lockfile is meant to be committed and read by other tools.
+	if err := os.WriteFile(path, append(data, '\n'), DefaultFileMode); err != nil {
+		return fmt.Errorf("write lockfile: %w", err)
+	}
+	return nil
+}
+
+// Find returns the entry for kind, slug, and harness.
+func (l *Lockfile) Find(kind, slug, harness string) (LockEntry, bool) {
+	for _, entry := range l.Entries {
+		if entry.Kind == kind && entry.Slug == slug && entry.Harness == harness {
+			return entry, true
+		}
+	}
+	return LockEntry{}, false
+}
+
+// Upsert replaces the entry with the same kind, slug, and harness, or appends it.
+func (l *Lockfile) Upsert(entry LockEntry) {
+	for i, existing := range l.Entries {
+		if existing.Kind == entry.Kind && existing.Slug == entry.Slug && existing.Harness == entry.Harness {
+			l.Entries[i] = entry
+			return
+		}
+	}
+	l.Entries = append(l.Entries, entry)
+}
+
+// Remove drops the entry for kind, slug, and harness. It reports whether an entry was removed.
+func (l *Lockfile) Remove(kind, slug, harness string) bool {
+	for i, existing := range l.Entries {
+		if existing.Kind == kind && existing.Slug == slug && existing.Harness == harness {
+			l.Entries = append(l.Entries[:i], l.Entries[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Slugs returns the sorted, de-duplicated slugs of kind locked for any of the given harnesses.
+// An empty harness list matches every harness.
+func (l *Lockfile) Slugs(kind string, harnesses []string) []string {
+	seen := map[string]bool{}
+	var slugs []string
+	for _, entry := range l.Entries {
+		if entry.Kind != kind || seen[entry.Slug] {
+			continue
+		}
+		if len(harnesses) > 0 && !slices.Contains(harnesses, entry.Harness) {
+			continue
+		}
+		seen[entry.Slug] = true
+		slugs = append(slugs, entry.Slug)
+	}
+	sort.Strings(slugs)
+	return slugs
+}
+
+// LockedHarnesses returns the candidates that have an entry for kind and slug, in candidate order.
+func (l *Lockfile) LockedHarnesses(kind, slug string, candidates []string) []string {
+	var harnesses []string
+	for _, harness := range candidates {
+		if _, ok := l.Find(kind, slug, harness); ok {
+			harnesses = append(harnesses, harness)
+		}
+	}
+	return harnesses
+}
+
+// LockedPin returns the single pin shared by every requested harness for a slug.
+// It fails when a harness has no entry or when harnesses pin different versions or checksums,
+// because one download has to satisfy every target.
+func (l *Lockfile) LockedPin(kind, slug string, harnesses []string) (LockEntry, error) {
+	if len(harnesses) == 0 {
+		return LockEntry{}, fmt.Errorf("no harness given to look up %s '%s' in the lockfile", kind, slug)
+	}
+	var pin LockEntry
+	for i, harness := range harnesses {
+		entry, ok := l.Find(kind, slug, harness)
+		if !ok {
+			return LockEntry{}, fmt.Errorf("%s '%s' is not locked for harness '%s'; run install without --frozen to update %s", kind, slug, harness, LockfileName)
+		}
+		if entry.Version == "" || entry.SHA256 == "" {
+			return LockEntry{}, fmt.Errorf("lockfile entry for %s '%s' (harness '%s') is missing a version or sha256", kind, slug, harness)
+		}
+		if i == 0 {
+			pin = entry
+			continue
+		}
+		if entry.Version != pin.Version || entry.Repo != pin.Repo || !strings.EqualFold(entry.SHA256, pin.SHA256) {
+			return LockEntry{}, fmt.Errorf(
+				"lockfile pins different versions of %s '%s' (%s@%s for %s vs %s@%s for %s); install those harnesses separately",
+				kind, slug, pin.Repo, pin.Version, pin.Harness, entry.Repo, entry.Version, entry.Harness,
+			)
+		}
+	}
+	return pin, nil
+}
+
+// RecordLockEntries merges entries into the lockfile at path, creating it when missing.
+func RecordLockEntries(path string, entries []LockEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	lockfile, err := ReadLockfile(path)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		lockfile.Upsert(entry)
+	}
+	return WriteLockfile(path, lockfile)
+}
+
+// LockEntriesForRows builds lockfile entries for the successful rows of an install or update summary.
+func LockEntriesForRows(kind, repoKey, slug, version, sha256Hex string, rows []SummaryRow) []LockEntry {
+	var entries []LockEntry
+	for _, row := range rows {
+		if row.Status != SummaryStatusOK {
+			continue
+		}
+		entries = append(entries, LockEntry{
+			Kind:    kind,
+			Slug:    slug,
+			Repo:    repoKey,
+			Version: version,
+			SHA256:  sha256Hex,
+			Harness: row.Agent,
+		})
+	}
+	return entries
+}
+
+// VerifyLockedChecksum hashes zipPath and compares it with the pinned SHA-256.
+// It returns the computed digest so callers can record it.
+func VerifyLockedChecksum(zipPath, expectedSHA256 string) (string, error) {
+	actual, err := ComputeSHA256(zipPath)
+	if err != nil {
+		return "", fmt.Errorf("compute package checksum: %w", err)
+	}
+	if expectedSHA256 != "" && !strings.EqualFold(actual, expectedSHA256) {
+		return actual, fmt.Errorf("checksum mismatch for %s: lockfile pins sha256 %s but the downloaded zip is %s", filepath.Base(zipPath), expectedSHA256, actual)
+	}
+	return actual, nil
+}
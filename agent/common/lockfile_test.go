@@ -0,0 +1,134 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-artifactory/agent/common/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockfilePath(t *testing.T) {
+	home := testutil.WithJfrogHome(t)
+	projectDir := t.TempDir()
+	installPath := t.TempDir()
+
+	got, err := LockfilePath(InstallScopeProject, projectDir, "")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(projectDir, ".jfrog", LockfileName), got)
+
+	got, err = LockfilePath(InstallScopeGlobal, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, "agents", LockfileName), got)
+
+	got, err = LockfilePath(InstallScopeProject, projectDir, installPath)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(installPath, ".jfrog", LockfileName), got)
+
+	_, err = LockfilePath(InstallScopeProject, "", "")
+	require.Error(t, err)
+}
+
+func TestReadLockfile_MissingReturnsEmpty(t *testing.T) {
+	lockfile, err := ReadLockfile(filepath.Join(t.TempDir(), LockfileName))
+	require.NoError(t, err)
+	require.NotNil(t, lockfile)
+	assert.Empty(t, lockfile.Entries)
+}
+
+func TestReadLockfile_NewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), LockfileName)
+	require.NoError(t, os.WriteFile(path, []byte(`{"schemaVersion": 99, "entries": []}`), 0o644))
+	_, err := ReadLockfile(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "schema version 99")
+}
+
+func TestRecordLockEntries_UpsertsAndSorts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".jfrog", LockfileName)
+	require.NoError(t, RecordLockEntries(path, []LockEntry{
+		{Kind: LockKindSkill, Slug: "zeta", Repo: "skills", Version: "1.0.0", SHA256: "aa", Harness: "cursor"},
+		{Kind: LockKindSkill, Slug: "alpha", Repo: "skills", Version: "1.0.0", SHA256: "bb", Harness: "cursor"},
+	}))
+	require.NoError(t, RecordLockEntries(path, []LockEntry{
+		{Kind: LockKindSkill, Slug: "zeta", Repo: "skills", Version: "1.1.0", SHA256: "cc", Harness: "cursor"},
+	}))
+
+	lockfile, err := ReadLockfile(path)
+	require.NoError(t, err)
+	require.Len(t, lockfile.Entries, 2)
+	assert.Equal(t, "alpha", lockfile.Entries[0].Slug)
+	assert.Equal(t, "zeta", lockfile.Entries[1].Slug)
+	assert.Equal(t, "1.1.0", lockfile.Entries[1].Version)
+	assert.Equal(t, "cc", lockfile.Entries[1].SHA256)
+}
+
+func TestLockfile_LockedPin(t *testing.T) {
+	lockfile := &Lockfile{Entries: []LockEntry{
+		{Kind: LockKindPlugin, Slug: "p", Repo: "plugins", Version: "1.0.0", SHA256: "aa", Harness: "claude"},
+		{Kind: LockKindPlugin, Slug: "p", Repo: "plugins", Version: "1.0.0", SHA256: "AA", Harness: "cursor"},
+		{Kind: LockKindPlugin, Slug: "p", Repo: "plugins", Version: "2.0.0", SHA256: "bb", Harness: "codex"},
+	}}
+
+	pin, err := lockfile.LockedPin(LockKindPlugin, "p", []string{"claude", "cursor"})
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", pin.Version)
+
+	_, err = lockfile.LockedPin(LockKindPlugin, "p", []string{"claude", "codex"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "different versions")
+
+	_, err = lockfile.LockedPin(LockKindPlugin, "p", []string{"windsurf"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not locked for harness 'windsurf'")
+
+	_, err = lockfile.LockedPin(LockKindSkill, "p", []string{"claude"})
+	require.Error(t, err)
+}
+
+func TestLockfile_SlugsAndHarnesses(t *testing.T) {
+	lockfile := &Lockfile{Entries: []LockEntry{
+		{Kind: LockKindSkill, Slug: "b", Harness: "cursor"},
+		{Kind: LockKindSkill, Slug: "a", Harness: "claude-code"},
+		{Kind: LockKindSkill, Slug: "a", Harness: "cursor"},
+		{Kind: LockKindPlugin, Slug: "c", Harness: "cursor"},
+	}}
+	assert.Equal(t, []string{"a", "b"}, lockfile.Slugs(LockKindSkill, []string{"cursor"}))
+	assert.Equal(t, []string{"a"}, lockfile.Slugs(LockKindSkill, []string{"claude-code"}))
+	assert.Equal(t, []string{"a", "b"}, lockfile.Slugs(LockKindSkill, nil))
+	assert.Equal(t, []string{"cursor"}, lockfile.LockedHarnesses(LockKindSkill, "b", []string{"claude-code", "cursor"}))
+
+	assert.True(t, lockfile.Remove(LockKindSkill, "b", "cursor"))
+	assert.False(t, lockfile.Remove(LockKindSkill, "b", "cursor"))
+}
+
+func TestLockEntriesForRows_SkipsFailedRows(t *testing.T) {
+	rows := []SummaryRow{
+		{Agent: "cursor", Status: SummaryStatusOK},
+		{Agent: "claude-code", Status: SummaryStatusFailed},
+		{Agent: "codex", Status: SummaryStatusSkipped},
+	}
+	entries := LockEntriesForRows(LockKindSkill, "skills", "web", "1.0.0", "abc", rows)
+	require.Len(t, entries, 1)
+	assert.Equal(t, LockEntry{Kind: LockKindSkill, Slug: "web", Repo: "skills", Version: "1.0.0", SHA256: "abc", Harness: "cursor"}, entries[0])
+}
+
+func TestVerifyLockedChecksum(t *testing.T) {
+	zipPath := filepath.Join(t.TempDir(), "web-1.0.0.zip")
+	require.NoError(t, os.WriteFile(zipPath, []byte("zip-bytes"), 0o644))
+	actual, err := ComputeSHA256(zipPath)
+	require.NoError(t, err)
+
+	got, err := VerifyLockedChecksum(zipPath, "")
+	require.NoError(t, err)
+	assert.Equal(t, actual, got)
+
+	_, err = VerifyLockedChecksum(zipPath, actual)
+	require.NoError(t, err)
+
+	_, err = VerifyLockedChecksum(zipPath, "deadbeef")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum mismatch for web-1.0.0.zip")
+}
@@ -0,0 +1,305 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Outdated statuses for an install compared with its repository.
+const (
+	// OutdatedStatusCurrent means the install is at the latest version it may be updated to.
+	OutdatedStatusCurrent = "current"
+	// OutdatedStatusOutdated means update would move the install to a newer version.
+	OutdatedStatusOutdated = "outdated"
+	// OutdatedStatusHeld means a newer version exists but the recorded constraint excludes it.
+	OutdatedStatusHeld = "held"
+	// OutdatedStatusUnknown means the versions in the repository could not be compared.
+	OutdatedStatusUnknown = "unknown"
+)
+
+// verifyOutdatedEvidence is swappable in tests.
+var verifyOutdatedEvidence = VerifyPackageEvidence
+
+// OutdatedRow is one install in the outdated report.
+type OutdatedRow struct {
+	Agent      string `json:"agent" col-name:"Agent"`
+	Name       string `json:"name" col-name:"Name"`
+	Scope      string `json:"scope" col-name:"Scope"`
+	Repo       string `json:"repo" col-name:"Repo"`
+	Current    string `json:"current" col-name:"Current"`
+	Wanted     string `json:"wanted" col-name:"Wanted"`
+	Latest     string `json:"latest" col-name:"Latest"`
+	Status     string `json:"status" col-name:"Status"`
+	Evidence   string `json:"evidence" col-name:"Evidence"`
+	Xray       string `json:"xray,omitempty" col-name:"Xray" omitempty:"true"`
+	Deprecated string `json:"deprecated,omitempty" col-name:"Deprecated" omitempty:"true"`
+	Path       string `json:"path" col-name:"Path"`
+	Detail     string `json:"detail,omitempty" col-name:"Detail" omitempty:"true"`
+}
+
+// OutdatedOptions configures CollectOutdated.
+type OutdatedOptions struct {
+	ServerDetails *config.ServerDetails
+	// ManifestFileName is the install-info manifest under .jfrog (e.g. skill-info.json).
+	ManifestFileName string
+	// ProjectDirs are the project roots scanned in addition to each harness's global directory.
+	ProjectDirs []string
+	// ListVersions returns the versions published for slug in repoKey.
+	ListVersions func(repoKey, slug string) ([]string, error)
+	// XrayStatus returns the Xray gate status of a published version; nil when the package kind has no gate.
+	XrayStatus func(repoKey, slug, version string) (string, error)
+}
+
+// outdatedLookups caches repository calls shared by installs of the same package in several harnesses.
+type outdatedLookups struct {
+	opts       OutdatedOptions
+	versions   map[string][]string
+	errs       map[string]error
+	evidence   map[string]string
+	xray       map[string]string
+	deprecated map[string]DeprecatedVersions
+}
+
+// CollectOutdated reads the install manifest of every package installed in the global and project directories
+// of each harness in registry, and compares the installed version with the repository it was installed from.
+func CollectOutdated(registry map[string]AgentSpec, opts OutdatedOptions) ([]OutdatedRow, error) {
+	lookups := &outdatedLookups{opts: opts, versions: map[string][]string{}, errs: map[string]error{}, evidence: map[string]string{}, xray: map[string]string{}, deprecated: map[string]DeprecatedVersions{}}
+	seen := map[string]bool{}
+	var rows []OutdatedRow
+	for _, target := range outdatedInstallDirs(registry, opts.ProjectDirs) {
+		slugs, err := DiscoverInstalledSlugs(target.DestinationDir, opts.ManifestFileName)
+		if err != nil {
+			return nil, err
+		}
+		for _, slug := range slugs {
+			installDir := filepath.Join(target.DestinationDir, slug)
+			// Harnesses may share an install directory; report each install once.
+			if seen[installDir] {
+				continue
+			}
+			seen[installDir] = true
+			manifest, err := ReadInstallInfoManifest(installDir, opts.ManifestFileName)
+			if err != nil {
+				log.Warn(fmt.Sprintf("Skipping %s: %s", installDir, err.Error()))
+				continue
+			}
+			rows = append(rows, lookups.row(target, installDir, slug, manifest))
+		}
+	}
+	return rows, nil
+}
+
+// ParseOutdatedProjectDirs turns the comma-separated --projects value into absolute project roots.
+// An empty value means the current directory.
+func ParseOutdatedProjectDirs(raw string) ([]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		raw = "."
+	}
+	var dirs []string
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		abs, err := filepath.Abs(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --projects path %q: %w", part, err)
+		}
+		dirs = append(dirs, abs)
+	}
+	return dirs, nil
+}
+
+// outdatedInstallDirs returns the global install directory of each harness and its install directory in each
+// project, sorted by harness name. DestinationDir is the directory that holds the installed packages.
+func outdatedInstallDirs(registry map[string]AgentSpec, projectDirs []string) []InstallTarget {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var dirs []InstallTarget
+	for _, name := range names {
+		spec := registry[name]
+		if spec.Config.GlobalDir != "" {
+			if dir, err := ResolveAgentInstallDir(spec, "", true); err == nil {
+				dirs = append(dirs, InstallTarget{Agent: spec, Scope: InstallScopeGlobal, DestinationDir: dir})
+			}
+		}
+		if spec.Config.ProjectDir == "" {
+			continue
+		}
+		for _, projectDir := range projectDirs {
+			if dir, err := ResolveAgentInstallDir(spec, projectDir, false); err == nil {
+				dirs = append(dirs, InstallTarget{Agent: spec, Scope: InstallScopeProject, DestinationDir: dir})
+			}
+		}
+	}
+	return dirs
+}
+
+func (l *outdatedLookups) row(target InstallTarget, installDir, slug string, manifest *InstallInfoManifest) OutdatedRow {
+	row := OutdatedRow{
+		Agent:    target.Agent.Name,
+		Name:     slug,
+		Scope:    string(target.Scope),
+		Repo:     strings.TrimSpace(manifest.Repo),
+		Current:  strings.TrimSpace(manifest.InstalledVersion),
+		Status:   OutdatedStatusUnknown,
+		Evidence: VerifyStatusUnverified,
+		Path:     installDir,
+	}
+	if row.Repo == "" {
+		row.Detail = "install manifest records no repository"
+		return row
+	}
+	available, err := l.listVersions(row.Repo, slug)
+	if err != nil {
+		row.Detail = err.Error()
+		return row
+	}
+	deprecated := l.deprecatedVersions(row.Repo, slug)
+	if deprecation, found := deprecated.Lookup(slug, row.Current); found {
+		row.Deprecated = deprecation.Summary()
+	}
+	// Update never moves an install to a deprecated version, so neither wanted nor latest may be one.
+	available = deprecated.Active(slug, available)
+	row.Latest, err = LatestVersion(available)
+	if err != nil {
+		row.Detail = err.Error()
+		return row
+	}
+	row.Wanted = row.Latest
+	if constraint := strings.TrimSpace(manifest.Constraint); constraint != "" {
+		parsed, err := ParseVersionConstraint(constraint)
+		if err != nil {
+			row.Detail = err.Error()
+			return row
+		}
+		row.Wanted, _ = parsed.MaxSatisfying(available)
+	}
+	row.Status, row.Detail = outdatedStatus(row.Current, row.Wanted, row.Latest)
+	row.Evidence = l.evidenceStatus(row.Repo, slug, row.Current)
+	if l.opts.XrayStatus != nil {
+		row.Xray = l.xrayStatus(row.Repo, slug, row.Current)
+	}
+	return row
+}
+
+// outdatedStatus compares the installed version with the version update would pick (wanted) and the latest one.
+func outdatedStatus(current, wanted, latest string) (string, string) {
+	if wanted == "" {
+		return OutdatedStatusUnknown, "no published version satisfies the recorded constraint"
+	}
+	toWanted, err := CompareSemver(current, wanted)
+	if err != nil {
+		return OutdatedStatusUnknown, err.Error()
+	}
+	if toWanted < 0 {
+		return OutdatedStatusOutdated, ""
+	}
+	if toLatest, err := CompareSemver(current, latest); err == nil && toLatest < 0 {
+		return OutdatedStatusHeld, ""
+	}
+	return OutdatedStatusCurrent, ""
+}
+
+func (l *outdatedLookups) listVersions(repoKey, slug string) ([]string, error) {
+	key := repoKey + "/" + slug
+	if versions, found := l.versions[key]; found {
+		return versions, l.errs[key]
+	}
+	versions, err := l.opts.ListVersions(repoKey, slug)
+	if err != nil {
+		if IsHTTPNotFound(err) {
+			err = fmt.Errorf("'%s' not found in repository '%s'", slug, repoKey)
+		} else {
+			err = fmt.Errorf("failed to list versions: %w", err)
+		}
+	}
+	l.versions[key], l.errs[key] = versions, err
+	return versions, err
+}
+
+func (l *outdatedLookups) deprecatedVersions(repoKey, slug string) DeprecatedVersions {
+	key := repoKey + "/" + slug
+	if deprecated, found := l.deprecated[key]; found {
+		return deprecated
+	}
+	deprecated := ListDeprecatedVersions(l.opts.ServerDetails, repoKey, slug)
+	l.deprecated[key] = deprecated
+	return deprecated
+}
+
+func (l *outdatedLookups) evidenceStatus(repoKey, slug, version string) string {
+	key := packageZipRepoPath(repoKey, slug, version)
+	if status, found := l.evidence[key]; found {
+		return status
+	}
+	status := VerifyEvidenceVerified
+	if err := verifyOutdatedEvidence(l.opts.ServerDetails, repoKey, slug, version); err != nil {
+		log.Debug(fmt.Sprintf("No verified evidence for %s: %s", key, err.Error()))
+		status = VerifyStatusUnverified
+	}
+	l.evidence[key] = status
+	return status
+}
+
+func (l *outdatedLookups) xrayStatus(repoKey, slug, version string) string {
+	key := packageZipRepoPath(repoKey, slug, version)
+	if status, found := l.xray[key]; found {
+		return status
+	}
+	status, err := l.opts.XrayStatus(repoKey, slug, version)
+	if err != nil {
+		log.Debug(fmt.Sprintf("Could not read the Xray status of %s: %s", key, err.Error()))
+		status = OutdatedStatusUnknown
+	}
+	l.xray[key] = status
+	return status
+}
+
+type outdatedSummaryJSON struct {
+	Results []OutdatedRow `json:"results"`
+}
+
+// PrintOutdatedSummary renders the outdated report as a table or JSON.
+func PrintOutdatedSummary(entityLabel string, rows []OutdatedRow, format string) error {
+	if rows == nil {
+		rows = []OutdatedRow{}
+	}
+	if strings.EqualFold(format, "json") {
+		data, err := json.MarshalIndent(outdatedSummaryJSON{Results: rows}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal outdated summary: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	log.Info(entityLabel + " outdated report:")
+	if err := coreutils.PrintTable(rows, "Installed", "No installed "+strings.ToLower(entityLabel)+"s found", false); err != nil {
+		log.Warn("Failed to render outdated summary: " + err.Error())
+	}
+	return nil
+}
+
+// OutdatedError returns an error naming how many installs update would move to a newer version, or nil when none.
+func OutdatedError(entityLabel string, rows []OutdatedRow) error {
+	outdated := 0
+	for _, row := range rows {
+		if row.Status == OutdatedStatusOutdated {
+			outdated++
+		}
+	}
+	if outdated > 0 {
+		return fmt.Errorf("%d %s install(s) are outdated", outdated, strings.ToLower(entityLabel))
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package common
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeOutdatedInstall(t *testing.T, installDir string, manifest InstallInfoManifest) {
+	t.Helper()
+	require.NoError(t, WriteInstallInfoManifest(installDir, testSkillManifest, manifest))
+}
+
+func stubOutdatedEvidence(t *testing.T, verified map[string]bool) {
+	t.Helper()
+	restore := verifyOutdatedEvidence
+	t.Cleanup(func() { verifyOutdatedEvidence = restore })
+	verifyOutdatedEvidence = func(_ *config.ServerDetails, repoKey, slug, version string) error {
+		if verified[slug+"@"+version] {
+			return nil
+		}
+		return errors.New("no evidence")
+	}
+}
+
+func TestCollectOutdated(t *testing.T) {
+	globalDir := t.TempDir()
+	project := t.TempDir()
+	registry := map[string]AgentSpec{
+		"cursor": {Name: "cursor", Config: AgentConfig{GlobalDir: globalDir, ProjectDir: ".cursor/skills"}},
+		// Shares the project directory with cursor; its installs are reported once.
+		"agents": {Name: "agents", Config: AgentConfig{ProjectDir: ".cursor/skills"}},
+	}
+	projectSkills := filepath.Join(project, ".cursor", "skills")
+	writeOutdatedInstall(t, filepath.Join(globalDir, "web"), InstallInfoManifest{Repo: "skills-local", Slug: "web", InstalledVersion: "1.0.0"})
+	writeOutdatedInstall(t, filepath.Join(projectSkills, "web"), InstallInfoManifest{Repo: "skills-local", Slug: "web", InstalledVersion: "1.2.0", Constraint: "^1.0"})
+	writeOutdatedInstall(t, filepath.Join(projectSkills, "lint"), InstallInfoManifest{Repo: "skills-local", Slug: "lint", InstalledVersion: "3.0.0"})
+	writeOutdatedInstall(t, filepath.Join(projectSkills, "local"), InstallInfoManifest{Slug: "local", InstalledVersion: "0.1.0"})
+	stubOutdatedEvidence(t, map[string]bool{"lint@3.0.0": true})
+
+	listed := map[string]int{}
+	rows, err := CollectOutdated(registry, OutdatedOptions{
+		ManifestFileName: testSkillManifest,
+		ProjectDirs:      []string{project},
+		ListVersions: func(repoKey, slug string) ([]string, error) {
+			listed[slug]++
+			if slug == "lint" {
+				return []string{"2.0.0", "3.0.0"}, nil
+			}
+			return []string{"1.0.0", "1.2.0", "2.0.0"}, nil
+		},
+		XrayStatus: func(repoKey, slug, version string) (string, error) {
+			return "APPROVED", nil
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+
+	byPath := map[string]OutdatedRow{}
+	for _, row := range rows {
+		byPath[row.Path] = row
+	}
+	global := byPath[filepath.Join(globalDir, "web")]
+	assert.Equal(t, OutdatedRow{Agent: "cursor", Name: "web", Scope: "global", Repo: "skills-local", Current: "1.0.0", Wanted: "2.0.0", Latest: "2.0.0",
+		Status: OutdatedStatusOutdated, Evidence: VerifyStatusUnverified, Xray: "APPROVED", Path: filepath.Join(globalDir, "web")}, global)
+
+	held := byPath[filepath.Join(projectSkills, "web")]
+	assert.Equal(t, "agents", held.Agent, "harnesses are scanned in name order")
+	assert.Equal(t, "1.2.0", held.Wanted)
+	assert.Equal(t, OutdatedStatusHeld, held.Status)
+
+	current := byPath[filepath.Join(projectSkills, "lint")]
+	assert.Equal(t, OutdatedStatusCurrent, current.Status)
+	assert.Equal(t, VerifyEvidenceVerified, current.Evidence)
+
+	unknown := byPath[filepath.Join(projectSkills, "local")]
+	assert.Equal(t, OutdatedStatusUnknown, unknown.Status)
+	assert.Contains(t, unknown.Detail, "no repository")
+
+	assert.Equal(t, 1, listed["web"], "versions are listed once per package")
+	assert.ErrorContains(t, OutdatedError("Skill", rows), "1 skill install(s) are outdated")
+}
+
+func TestCollectOutdated_ListErrorIsReported(t *testing.T) {
+	globalDir := t.TempDir()
+	registry := map[string]AgentSpec{"cursor": {Name: "cursor", Config: AgentConfig{GlobalDir: globalDir}}}
+	writeOutdatedInstall(t, filepath.Join(globalDir, "web"), InstallInfoManifest{Repo: "skills-local", Slug: "web", InstalledVersion: "1.0.0"})
+	stubOutdatedEvidence(t, nil)
+
+	rows, err := CollectOutdated(registry, OutdatedOptions{
+		ManifestFileName: testSkillManifest,
+		ListVersions:     func(string, string) ([]string, error) { return nil, errors.New("connection refused") },
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, OutdatedStatusUnknown, rows[0].Status)
+	assert.Contains(t, rows[0].Detail, "connection refused")
+	assert.Empty(t, rows[0].Xray)
+	assert.NoError(t, OutdatedError("Skill", rows))
+}
+
+func TestParseOutdatedProjectDirs(t *testing.T) {
+	cwd, err := filepath.Abs(".")
+	require.NoError(t, err)
+	dirs, err := ParseOutdatedProjectDirs("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{cwd}, dirs)
+
+	dirs, err = ParseOutdatedProjectDirs("/work/a, /work/b,")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/work/a", "/work/b"}, dirs)
+}
@@ -0,0 +1,324 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	packageCacheSubdir    = "cache"
+	packageCacheBlobsDir  = "blobs"
+	packageCacheIndexFile = "index.json"
+
+	// PackageCacheSchemaVersion is bumped when the cache index JSON shape changes incompatibly.
+	PackageCacheSchemaVersion = 1
+	// DefaultPackageCacheMaxMB bounds the package cache; least recently used zips are evicted beyond it.
+	DefaultPackageCacheMaxMB = 512
+	// EnvPackageCacheMaxMB overrides DefaultPackageCacheMaxMB. 0 disables the cache.
+	EnvPackageCacheMaxMB = "JFROG_AGENTS_CACHE_MAX_MB"
+)
+
+// packageCacheMu serializes index updates within one process; blobs are written atomically,
+// so concurrent processes can at worst lose a recency update or an index entry.
+var packageCacheMu sync.Mutex
+
+// openPackageCache is swappable in tests.
+var openPackageCache = OpenPackageCache
+
+// downloadPackageZipForCache is swappable in tests.
+var downloadPackageZipForCache = DownloadPackageZip
+
+// PackageZipFetch identifies a package zip for FetchPackageZip.
+type PackageZipFetch struct {
+	ServerDetails *config.ServerDetails
+	RepoKey       string
+	Slug          string
+	Version       string
+	// ArtifactKind is "skill" or "plugin", used in not-found errors.
+	ArtifactKind string
+	// ExpectedSHA256, when set (e.g. a lockfile pin), makes a cached zip with another digest a miss.
+	ExpectedSHA256 string
+	// NoCache downloads without reading or writing the local cache (--no-cache).
+	NoCache bool
+}
+
+// FetchPackageZip returns <slug>-<version>.zip in tmpDir, copied from the local package cache when it
+// holds the version and downloaded (then cached) otherwise. Cache failures only fall back to downloading.
+func FetchPackageZip(fetch PackageZipFetch, tmpDir string) (string, error) {
+	download := func() (string, error) {
+		return downloadPackageZipForCache(fetch.ServerDetails, fetch.RepoKey, fetch.Slug, fetch.Version, tmpDir, fetch.ArtifactKind)
+	}
+	if fetch.NoCache {
+		return download()
+	}
+	cache, err := openPackageCache()
+	if err != nil {
+		log.Debug("Package cache unavailable: " + err.Error())
+		return download()
+	}
+	if cache.Disabled() {
+		return download()
+	}
+	key := PackageCacheKey(fetch.ServerDetails, fetch.RepoKey, fetch.Slug, fetch.Version)
+	destPath := filepath.Join(tmpDir, fmt.Sprintf("%s-%s.zip", fetch.Slug, fetch.Version))
+	if cache.Get(key, fetch.ExpectedSHA256, destPath) {
+		log.Debug(fmt.Sprintf("Using cached %s '%s' version '%s'", fetch.ArtifactKind, fetch.Slug, fetch.Version))
+		return destPath, nil
+	}
+	zipPath, err := download()
+	if err != nil {
+		return "", err
+	}
+	if err := cache.Put(key, zipPath); err != nil {
+		log.Debug("Failed to cache package zip: " + err.Error())
+	}
+	return zipPath, nil
+}
+
+// PackageCacheKey identifies a package version across servers: <artifactory-url>|<repo>/<slug>/<version>.
+func PackageCacheKey(serverDetails *config.ServerDetails, repoKey, slug, version string) string {
+	server := ""
+	if serverDetails != nil {
+		server = strings.TrimSuffix(serverDetails.GetArtifactoryUrl(), "/")
+	}
+	return fmt.Sprintf("%s|%s/%s/%s", server, repoKey, slug, version)
+}
+
+// PackageCache is a content-addressed store of package zips. index.json maps cache keys to the SHA-256
+// of a zip stored once under blobs/<sha256>.zip, so the same zip in two repositories is kept once.
+type PackageCache struct {
+	dir      string
+	maxBytes int64
+}
+
+type packageCacheIndex struct {
+	SchemaVersion int                          `json:"schemaVersion"`
+	Entries       map[string]packageCacheEntry `json:"entries"`
+}
+
+type packageCacheEntry struct {
+	SHA256   string    `json:"sha256"`
+	Size     int64     `json:"size"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+// OpenPackageCache returns the cache under ~/.jfrog/agents/cache, bounded by EnvPackageCacheMaxMB.
+func OpenPackageCache() (*PackageCache, error) {
+	home, err := coreutils.GetJfrogHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("resolve JFrog home dir: %w", err)
+	}
+	maxMB := int64(DefaultPackageCacheMaxMB)
+	if raw := strings.TrimSpace(os.Getenv(EnvPackageCacheMaxMB)); raw != "" {
+		maxMB, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || maxMB < 0 {
+			return nil, fmt.Errorf("invalid %s %q: must be a non-negative number of megabytes", EnvPackageCacheMaxMB, raw)
+		}
+	}
+	return NewPackageCache(filepath.Join(home, agentsConfigSubdir, packageCacheSubdir), maxMB<<20), nil
+}
+
+// NewPackageCache returns a cache rooted at dir that keeps at most maxBytes of zips.
+func NewPackageCache(dir string, maxBytes int64) *PackageCache {
+	return &PackageCache{dir: dir, maxBytes: maxBytes}
+}
+
+// Disabled reports whether the size limit is 0.
+func (c *PackageCache) Disabled() bool {
+	return c.maxBytes <= 0
+}
+
+func (c *PackageCache) blobPath(sha256Hex string) string {
+	return filepath.Join(c.dir, packageCacheBlobsDir, sha256Hex+".zip")
+}
+
+// Get copies the cached zip for key to destPath and reports whether it did. A blob whose digest no longer
+// matches the index is dropped; with expectedSHA256 set, an entry with another digest is a miss.
+func (c *PackageCache) Get(key, expectedSHA256, destPath string) bool {
+	packageCacheMu.Lock()
+	defer packageCacheMu.Unlock()
+
+	index, err := c.readIndex()
+	if err != nil {
+		log.Debug("Ignoring package cache: " + err.Error())
+		return false
+	}
+	entry, found := index.Entries[key]
+	if !found || (expectedSHA256 != "" && !strings.EqualFold(entry.SHA256, expectedSHA256)) {
+		return false
+	}
+	actual, err := ComputeSHA256(c.blobPath(entry.SHA256))
+	if err != nil || actual != entry.SHA256 {
+		log.Debug(fmt.Sprintf("Dropping corrupt or missing cache entry %s", key))
+		delete(index.Entries, key)
+		c.removeUnreferencedBlob(index, entry.SHA256)
+		_ = c.writeIndex(index) // best-effort; the entry is re-added on the next download
+		return false
+	}
+	if err := CopyFile(c.blobPath(entry.SHA256), destPath); err != nil {
+		log.Debug("Failed to copy cached package zip: " + err.Error())
+		return false
+	}
+	entry.LastUsed = time.Now().UTC()
+	index.Entries[key] = entry
+	if err := c.writeIndex(index); err != nil {
+		log.Debug("Failed to update package cache index: " + err.Error())
+	}
+	return true
+}
+
+// Put stores the zip at zipPath under key and evicts least recently used zips beyond the size limit.
+func (c *PackageCache) Put(key, zipPath string) error {
+	packageCacheMu.Lock()
+	defer packageCacheMu.Unlock()
+
+	sha256Hex, err := ComputeSHA256(zipPath)
+	if err != nil {
+		return fmt.Errorf("compute package checksum: %w", err)
+	}
+	info, err := os.Stat(zipPath)
+	if err != nil {
+		return err
+	}
+	if info.Size() > c.maxBytes {
+		return nil
+	}
+	if err := c.storeBlob(zipPath, sha256Hex); err != nil {
+		return err
+	}
+	index, err := c.readIndex()
+	if err != nil {
+		// An unreadable index is replaced rather than blocking installs.
+		index = &packageCacheIndex{SchemaVersion: PackageCacheSchemaVersion, Entries: map[string]packageCacheEntry{}}
+	}
+	if previous, found := index.Entries[key]; found && previous.SHA256 != sha256Hex {
+		delete(index.Entries, key)
+		c.removeUnreferencedBlob(index, previous.SHA256)
+	}
+	index.Entries[key] = packageCacheEntry{SHA256: sha256Hex, Size: info.Size(), LastUsed: time.Now().UTC()}
+	c.evict(index)
+	return c.writeIndex(index)
+}
+
+// storeBlob copies zipPath to blobs/<sha256>.zip through a temp file so readers never see a partial zip.
+func (c *PackageCache) storeBlob(zipPath, sha256Hex string) error {
+	blobPath := c.blobPath(sha256Hex)
+	if _, err := os.Stat(blobPath); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(blobPath), InstallDirMode); err != nil {
+		return fmt.Errorf("create package cache: %w", err)
+	}
+	tmpPath := fmt.Sprintf("%s.%d.tmp", blobPath, os.Getpid())
+	if err := CopyFile(zipPath, tmpPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("cache package zip: %w", err)
+	}
+	if err := os.Rename(tmpPath, blobPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("cache package zip: %w", err)
+	}
+	return nil
+}
+
+// evict drops least recently used entries until the unique blobs fit within maxBytes.
+func (c *PackageCache) evict(index *packageCacheIndex) {
+	blobSizes := map[string]int64{}
+	for _, entry := range index.Entries {
+		blobSizes[entry.SHA256] = entry.Size
+	}
+	var total int64
+	for _, size := range blobSizes {
+		total += size
+	}
+	if total <= c.maxBytes {
+		return
+	}
+	keys := make([]string, 0, len(index.Entries))
+	for key := range index.Entries {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return index.Entries[keys[i]].LastUsed.Before(index.Entries[keys[j]].LastUsed)
+	})
+	for _, key := range keys {
+		if total <= c.maxBytes {
+			return
+		}
+		entry := index.Entries[key]
+		delete(index.Entries, key)
+		if c.removeUnreferencedBlob(index, entry.SHA256) {
+			total -= entry.Size
+		}
+	}
+}
+
+// removeUnreferencedBlob deletes blobs/<sha256>.zip when no index entry uses it and reports whether it did.
+func (c *PackageCache) removeUnreferencedBlob(index *packageCacheIndex, sha256Hex string) bool {
+	for _, entry := range index.Entries {
+		if entry.SHA256 == sha256Hex {
+			return false
+		}
+	}
+	if err := os.Remove(c.blobPath(sha256Hex)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Debug("Failed to evict cached package zip: " + err.Error())
+	}
+	return true
+}
+
+func (c *PackageCache) readIndex() (*packageCacheIndex, error) {
+	index := &packageCacheIndex{SchemaVersion: PackageCacheSchemaVersion, Entries: map[string]packageCacheEntry{}}
+	// #nosec G304 -- path is derived from the JFrog home dir.
+	data, err := os.ReadFile(filepath.Join(c.dir, packageCacheIndexFile))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return index, nil
+		}
+		return nil, fmt.Errorf("read package cache index: %w", err)
+	}
+	if err := json.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("parse package cache index: %w", err)
+	}
+	if index.SchemaVersion != PackageCacheSchemaVersion {
+		return nil, fmt.Errorf("package cache index schema version %d is not supported", index.SchemaVersion)
+	}
+	if index.Entries == nil {
+		index.Entries = map[string]packageCacheEntry{}
+	}
+	return index, nil
+}
+
+// writeIndex replaces index.json atomically.
+func (c *PackageCache) writeIndex(index *packageCacheIndex) error {
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal package cache index: %w", err)
+	}
+	if err := os.MkdirAll(c.dir, InstallDirMode); err != nil {
+		return fmt.Errorf("create package cache: %w", err)
+	}
+	path := filepath.Join(c.dir, packageCacheIndexFile)
+	tmpPath := fmt.Sprintf("%s.%d.tmp", path, os.Getpid())
+	// #nosec G306 -- cache index lives under the user's JFrog home dir.
+	if err := os.WriteFile(tmpPath, data, InstallManifestFileMode); err != nil {
+		return fmt.Errorf("write package cache index: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("write package cache index: %w", err)
+	}
+	return nil
+}
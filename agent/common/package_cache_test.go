@@ -0,0 +1,133 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubPackageCache points FetchPackageZip at a temp cache and counts downloads; zips contain repo/slug@version.
+func stubPackageCache(t *testing.T, maxBytes int64) (*PackageCache, *int) {
+	t.Helper()
+	restoreOpen, restoreDownload := openPackageCache, downloadPackageZipForCache
+	t.Cleanup(func() { openPackageCache, downloadPackageZipForCache = restoreOpen, restoreDownload })
+	cache := NewPackageCache(t.TempDir(), maxBytes)
+	openPackageCache = func() (*PackageCache, error) { return cache, nil }
+	downloads := 0
+	downloadPackageZipForCache = func(_ *config.ServerDetails, repoKey, slug, version, tmpDir, _ string) (string, error) {
+		downloads++
+		zipPath := filepath.Join(tmpDir, slug+"-"+version+".zip")
+		return zipPath, os.WriteFile(zipPath, []byte(repoKey+"/"+slug+"@"+version), 0o600)
+	}
+	return cache, &downloads
+}
+
+func fetchTestZip(t *testing.T, fetch PackageZipFetch) string {
+	t.Helper()
+	zipPath, err := FetchPackageZip(fetch, t.TempDir())
+	require.NoError(t, err)
+	data, err := os.ReadFile(zipPath)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func webSearchFetch(version string) PackageZipFetch {
+	return PackageZipFetch{RepoKey: "skills-local", Slug: "web-search", Version: version, ArtifactKind: "skill"}
+}
+
+func TestFetchPackageZip_CachesDownload(t *testing.T) {
+	_, downloads := stubPackageCache(t, 1<<20)
+
+	assert.Equal(t, "skills-local/web-search@1.0.0", fetchTestZip(t, webSearchFetch("1.0.0")))
+	assert.Equal(t, "skills-local/web-search@1.0.0", fetchTestZip(t, webSearchFetch("1.0.0")))
+	assert.Equal(t, 1, *downloads, "second fetch is served from the cache")
+
+	fetchTestZip(t, webSearchFetch("1.1.0"))
+	assert.Equal(t, 2, *downloads, "another version is a miss")
+}
+
+func TestFetchPackageZip_NoCache(t *testing.T) {
+	cache, downloads := stubPackageCache(t, 1<<20)
+	fetch := webSearchFetch("1.0.0")
+	fetch.NoCache = true
+
+	fetchTestZip(t, fetch)
+	fetchTestZip(t, fetch)
+	assert.Equal(t, 2, *downloads)
+	_, err := os.Stat(filepath.Join(cache.dir, packageCacheIndexFile))
+	assert.ErrorIs(t, err, os.ErrNotExist, "--no-cache does not write the cache")
+}
+
+func TestFetchPackageZip_CorruptBlobIsRedownloaded(t *testing.T) {
+	cache, downloads := stubPackageCache(t, 1<<20)
+	fetchTestZip(t, webSearchFetch("1.0.0"))
+
+	blobs, err := filepath.Glob(filepath.Join(cache.dir, packageCacheBlobsDir, "*.zip"))
+	require.NoError(t, err)
+	require.Len(t, blobs, 1)
+	require.NoError(t, os.WriteFile(blobs[0], []byte("tampered"), 0o600))
+
+	assert.Equal(t, "skills-local/web-search@1.0.0", fetchTestZip(t, webSearchFetch("1.0.0")))
+	assert.Equal(t, 2, *downloads)
+}
+
+func TestFetchPackageZip_ExpectedChecksumMismatchIsMiss(t *testing.T) {
+	_, downloads := stubPackageCache(t, 1<<20)
+	fetchTestZip(t, webSearchFetch("1.0.0"))
+
+	fetch := webSearchFetch("1.0.0")
+	fetch.ExpectedSHA256 = "0000"
+	fetchTestZip(t, fetch)
+	assert.Equal(t, 2, *downloads)
+}
+
+func TestFetchPackageZip_KeyedByServer(t *testing.T) {
+	_, downloads := stubPackageCache(t, 1<<20)
+	fetch := webSearchFetch("1.0.0")
+	fetch.ServerDetails = &config.ServerDetails{ArtifactoryUrl: "https://a.example.com/artifactory/"}
+	fetchTestZip(t, fetch)
+
+	fetch.ServerDetails = &config.ServerDetails{ArtifactoryUrl: "https://b.example.com/artifactory/"}
+	fetchTestZip(t, fetch)
+	assert.Equal(t, 2, *downloads)
+}
+
+func TestPackageCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	zipSize := int64(len("skills-local/web-search@1.0.0"))
+	cache, downloads := stubPackageCache(t, 2*zipSize)
+
+	fetchTestZip(t, webSearchFetch("1.0.0"))
+	fetchTestZip(t, webSearchFetch("1.1.0"))
+	// Make 1.0.0 the most recently used so 1.1.0 is evicted when 1.2.0 arrives.
+	time.Sleep(10 * time.Millisecond)
+	fetchTestZip(t, webSearchFetch("1.0.0"))
+	fetchTestZip(t, webSearchFetch("1.2.0"))
+	require.Equal(t, 3, *downloads)
+
+	index, err := cache.readIndex()
+	require.NoError(t, err)
+	assert.Len(t, index.Entries, 2)
+	assert.Contains(t, index.Entries, PackageCacheKey(nil, "skills-local", "web-search", "1.0.0"))
+	assert.NotContains(t, index.Entries, PackageCacheKey(nil, "skills-local", "web-search", "1.1.0"))
+	blobs, err := filepath.Glob(filepath.Join(cache.dir, packageCacheBlobsDir, "*.zip"))
+	require.NoError(t, err)
+	assert.Len(t, blobs, 2, "evicted blobs are deleted")
+}
+
+func TestPackageCache_SharesIdenticalZips(t *testing.T) {
+	cache := NewPackageCache(t.TempDir(), 1<<20)
+	zipPath := filepath.Join(t.TempDir(), "web-search-1.0.0.zip")
+	require.NoError(t, os.WriteFile(zipPath, []byte("same zip"), 0o600))
+
+	require.NoError(t, cache.Put("a|skills-local/web-search/1.0.0", zipPath))
+	require.NoError(t, cache.Put("a|skills-remote/web-search/1.0.0", zipPath))
+	blobs, err := filepath.Glob(filepath.Join(cache.dir, packageCacheBlobsDir, "*.zip"))
+	require.NoError(t, err)
+	assert.Len(t, blobs, 1)
+	assert.True(t, cache.Get("a|skills-remote/web-search/1.0.0", "", filepath.Join(t.TempDir(), "out.zip")))
+}
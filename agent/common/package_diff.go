@@ -0,0 +1,332 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Diff statuses of a file that differs between two package trees.
+const (
+	DiffStatusAdded    = "added"
+	DiffStatusRemoved  = "removed"
+	DiffStatusModified = "modified"
+)
+
+const (
+	// diffContextLines is how many unchanged lines surround each hunk, as with `diff -u`.
+	diffContextLines = 3
+	// installedDiffLabel names the installed tree in diff headers and output.
+	installedDiffLabel = "installed"
+)
+
+// fetchDiffPackageZip is swappable in tests.
+var fetchDiffPackageZip = FetchPackageZip
+
+// FileDiff is one file that differs between two package trees.
+type FileDiff struct {
+	Path   string `json:"path" col-name:"Path"`
+	Status string `json:"status" col-name:"Status"`
+	Binary bool   `json:"binary"`
+	// Unified is the unified diff of a text file; empty for binaries.
+	Unified string `json:"diff,omitempty"`
+}
+
+// PackageDiff is the difference between one package tree and the version it is compared with.
+type PackageDiff struct {
+	From string `json:"from"`
+	// Path is the installed tree compared with the version; empty when two versions are compared.
+	Path  string     `json:"path,omitempty"`
+	Files []FileDiff `json:"files"`
+}
+
+type packageDiffJSON struct {
+	Slug    string        `json:"slug"`
+	To      string        `json:"to"`
+	Results []PackageDiff `json:"results"`
+}
+
+type binaryDiffRow struct {
+	Path   string `col-name:"Path"`
+	Status string `col-name:"Status"`
+}
+
+// PackageDiffRequest describes a diff between two published versions of a package, or between installed
+// copies of it and a published version.
+type PackageDiffRequest struct {
+	ServerDetails *config.ServerDetails
+	RepoKey       string
+	Slug          string
+	// ArtifactKind is "skill" or "plugin".
+	ArtifactKind string
+	// EntityLabel is "Skill" or "Plugin".
+	EntityLabel string
+	// FromVersion is compared with ToVersion unless Installed is set.
+	FromVersion string
+	ToVersion   string
+	// Installed lists install targets to compare with ToVersion instead of FromVersion.
+	Installed []InstallTarget
+	// ManifestFileName is the install-info manifest that marks a target as installed by the JFrog CLI.
+	ManifestFileName string
+	NoCache          bool
+	Format           string
+}
+
+// RunPackageDiff downloads the versions of request, through the package cache, and prints what differs.
+func RunPackageDiff(request PackageDiffRequest) error {
+	tmpDir, err := os.MkdirTemp("", request.ArtifactKind+"-diff-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer func() {
+		// Best-effort cleanup of diff temp dir.
+		_ = os.RemoveAll(tmpDir)
+	}()
+
+	toDir, err := extractDiffVersion(request, request.ToVersion, filepath.Join(tmpDir, "to"))
+	if err != nil {
+		return err
+	}
+	var diffs []PackageDiff
+	if len(request.Installed) > 0 {
+		diffs, err = diffInstalledTargets(request, toDir)
+	} else {
+		diffs, err = diffVersions(request, toDir, filepath.Join(tmpDir, "from"))
+	}
+	if err != nil {
+		return err
+	}
+	return PrintPackageDiff(request.EntityLabel, request.Slug, request.ToVersion, diffs, request.Format)
+}
+
+// diffVersions fetches request.FromVersion into workDir and compares it with the tree in toDir.
+func diffVersions(request PackageDiffRequest, toDir, workDir string) ([]PackageDiff, error) {
+	fromDir, err := extractDiffVersion(request, request.FromVersion, workDir)
+	if err != nil {
+		return nil, err
+	}
+	files, err := DiffPackageTrees(fromDir, toDir, request.FromVersion, request.ToVersion)
+	if err != nil {
+		return nil, err
+	}
+	return []PackageDiff{{From: request.FromVersion, Files: files}}, nil
+}
+
+// diffInstalledTargets compares every target installed by the JFrog CLI with the tree in toDir.
+func diffInstalledTargets(request PackageDiffRequest, toDir string) ([]PackageDiff, error) {
+	var diffs []PackageDiff
+	for _, target := range request.Installed {
+		manifest, err := ReadInstallInfoManifest(target.DestinationDir, request.ManifestFileName)
+		if err != nil {
+			return nil, err
+		}
+		if manifest == nil {
+			log.Debug(fmt.Sprintf("No %s '%s' installed by the JFrog CLI at %s", request.ArtifactKind, request.Slug, target.DestinationDir))
+			continue
+		}
+		// Linked installs hold symlinks into the package store; compare the store version they link to.
+		installedDir := target.DestinationDir
+		if manifest.StorePath != "" {
+			installedDir = manifest.StorePath
+		} else if resolved, err := filepath.EvalSymlinks(installedDir); err == nil {
+			installedDir = resolved
+		}
+		files, err := DiffPackageTrees(installedDir, toDir, installedDiffLabel, request.ToVersion)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, PackageDiff{From: installedDiffLabel + " " + manifest.InstalledVersion, Path: target.DestinationDir, Files: files})
+	}
+	if len(diffs) == 0 {
+		return nil, fmt.Errorf("%s '%s' is not installed in the selected location(s)", request.ArtifactKind, request.Slug)
+	}
+	return diffs, nil
+}
+
+// extractDiffVersion fetches one version zip into workDir and extracts it, returning the extracted tree.
+func extractDiffVersion(request PackageDiffRequest, version, workDir string) (string, error) {
+	if err := os.MkdirAll(workDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	zipPath, err := fetchDiffPackageZip(PackageZipFetch{
+		ServerDetails: request.ServerDetails,
+		RepoKey:       request.RepoKey,
+		Slug:          request.Slug,
+		Version:       version,
+		ArtifactKind:  request.ArtifactKind,
+		NoCache:       request.NoCache,
+	}, workDir)
+	if err != nil {
+		return "", fmt.Errorf("download of %s '%s' version '%s' failed: %w", request.ArtifactKind, request.Slug, version, err)
+	}
+	contentsDir := filepath.Join(workDir, "contents")
+	if err := UnzipFile(zipPath, contentsDir); err != nil {
+		return "", fmt.Errorf("unzip failed: %w", err)
+	}
+	return contentsDir, nil
+}
+
+// DiffPackageTrees compares the package trees under fromDir and toDir, skipping their top-level .jfrog
+// directories, and returns the files that differ sorted by path. fromLabel and toLabel prefix the paths
+// in the unified diff headers.
+func DiffPackageTrees(fromDir, toDir, fromLabel, toLabel string) ([]FileDiff, error) {
+	fromFiles, err := HashPackageTree(fromDir)
+	if err != nil {
+		return nil, err
+	}
+	toFiles, err := HashPackageTree(toDir)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(fromFiles)+len(toFiles))
+	for relPath := range fromFiles {
+		paths = append(paths, relPath)
+	}
+	for relPath := range toFiles {
+		if _, found := fromFiles[relPath]; !found {
+			paths = append(paths, relPath)
+		}
+	}
+	sort.Strings(paths)
+
+	diffs := []FileDiff{}
+	for _, relPath := range paths {
+		fromDigest, inFrom := fromFiles[relPath]
+		toDigest, inTo := toFiles[relPath]
+		status := DiffStatusModified
+		switch {
+		case !inFrom:
+			status = DiffStatusAdded
+		case !inTo:
+			status = DiffStatusRemoved
+		case fromDigest == toDigest:
+			continue
+		}
+		fileDiff, err := diffPackageFile(fromDir, toDir, relPath, status, fromLabel, toLabel)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, fileDiff)
+	}
+	return diffs, nil
+}
+
+// diffPackageFile builds the FileDiff of relPath. Added and removed files are diffed against /dev/null.
+func diffPackageFile(fromDir, toDir, relPath, status, fromLabel, toLabel string) (FileDiff, error) {
+	fileDiff := FileDiff{Path: relPath, Status: status}
+	fromName, toName := fromLabel+"/"+relPath, toLabel+"/"+relPath
+	var before, after []byte
+	var err error
+	if status == DiffStatusAdded {
+		fromName = "/dev/null"
+	} else if before, err = readDiffFile(fromDir, relPath); err != nil {
+		return fileDiff, err
+	}
+	if status == DiffStatusRemoved {
+		toName = "/dev/null"
+	} else if after, err = readDiffFile(toDir, relPath); err != nil {
+		return fileDiff, err
+	}
+	if isBinaryContent(before) || isBinaryContent(after) {
+		fileDiff.Binary = true
+		return fileDiff, nil
+	}
+	fileDiff.Unified, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitDiffLines(before),
+		B:        splitDiffLines(after),
+		FromFile: fromName,
+		ToFile:   toName,
+		Context:  diffContextLines,
+	})
+	if err != nil {
+		return fileDiff, fmt.Errorf("diff %s: %w", relPath, err)
+	}
+	return fileDiff, nil
+}
+
+// splitDiffLines splits data into lines that each end in a newline, adding one to an unterminated last line.
+func splitDiffLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	if last := len(lines) - 1; lines[last] == "" {
+		lines = lines[:last]
+	} else {
+		lines[last] += "\n"
+	}
+	return lines
+}
+
+// readDiffFile returns the content of relPath under root. A symlink is shown as its target, as recorded in the
+// file inventory, rather than followed.
+func readDiffFile(root, relPath string) ([]byte, error) {
+	path := filepath.Join(root, filepath.FromSlash(relPath))
+	if target, err := os.Readlink(path); err == nil {
+		return []byte(symlinkInventoryPrefix + filepath.ToSlash(target) + "\n"), nil
+	}
+	// #nosec G304 -- relPath comes from walking root, a package tree chosen by the user.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", relPath, err)
+	}
+	return data, nil
+}
+
+// isBinaryContent reports whether data looks binary: a NUL byte in its first binarySniffLength bytes.
+func isBinaryContent(data []byte) bool {
+	if len(data) > binarySniffLength {
+		data = data[:binarySniffLength]
+	}
+	return bytes.IndexByte(data, 0) >= 0
+}
+
+// PrintPackageDiff prints the unified diff of each changed text file followed by a table of changed binaries,
+// or all of it as JSON.
+func PrintPackageDiff(entityLabel, slug, toVersion string, diffs []PackageDiff, format string) error {
+	if strings.EqualFold(format, "json") {
+		data, err := json.MarshalIndent(packageDiffJSON{Slug: slug, To: toVersion, Results: diffs}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal diff: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	for _, diff := range diffs {
+		if diff.Path != "" {
+			log.Info(fmt.Sprintf("Comparing %s '%s' at %s (%s) with version %s:", strings.ToLower(entityLabel), slug, diff.Path, diff.From, toVersion))
+		}
+		if len(diff.Files) == 0 {
+			log.Info(fmt.Sprintf("%s '%s': no differences between %s and %s.", entityLabel, slug, diff.From, toVersion))
+			continue
+		}
+		var binaries []binaryDiffRow
+		counts := map[string]int{}
+		for _, file := range diff.Files {
+			counts[file.Status]++
+			if file.Binary {
+				binaries = append(binaries, binaryDiffRow{Path: file.Path, Status: file.Status})
+				continue
+			}
+			fmt.Print(file.Unified)
+		}
+		if len(binaries) > 0 {
+			log.Info("Changed binary files:")
+			if err := coreutils.PrintTable(binaries, "Binary files", "", false); err != nil {
+				log.Warn("Failed to render binary file list: " + err.Error())
+			}
+		}
+		log.Info(fmt.Sprintf("%d file(s) changed: %d added, %d removed, %d modified.",
+			len(diff.Files), counts[DiffStatusAdded], counts[DiffStatusRemoved], counts[DiffStatusModified]))
+	}
+	return nil
+}
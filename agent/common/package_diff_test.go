@@ -0,0 +1,118 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-artifactory/agent/common/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeDiffTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for relPath, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(relPath))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return root
+}
+
+func TestDiffPackageTrees(t *testing.T) {
+	fromDir := writeDiffTree(t, map[string]string{
+		"SKILL.md":           "# web\nstep one\nstep two\n",
+		"scripts/old.sh":     "echo old\n",
+		"assets/logo.png":    "PNG\x00one",
+		"README.md":          "same\n",
+		".jfrog/skill-info":  "{}",
+		"docs/unchanged.txt": "keep\n",
+	})
+	toDir := writeDiffTree(t, map[string]string{
+		"SKILL.md":           "# web\nstep one\nstep 2\n",
+		"scripts/new.sh":     "echo new\n",
+		"assets/logo.png":    "PNG\x00two",
+		"README.md":          "same\n",
+		"docs/unchanged.txt": "keep\n",
+	})
+
+	diffs, err := DiffPackageTrees(fromDir, toDir, "1.0.0", "1.1.0")
+	require.NoError(t, err)
+	require.Len(t, diffs, 4)
+
+	assert.Equal(t, "SKILL.md", diffs[0].Path)
+	assert.Equal(t, DiffStatusModified, diffs[0].Status)
+	assert.Equal(t, "--- 1.0.0/SKILL.md\n+++ 1.1.0/SKILL.md\n@@ -1,3 +1,3 @@\n # web\n step one\n-step two\n+step 2\n", diffs[0].Unified)
+	assert.Equal(t, FileDiff{Path: "assets/logo.png", Status: DiffStatusModified, Binary: true}, diffs[1])
+
+	assert.Equal(t, "scripts/new.sh", diffs[2].Path)
+	assert.Equal(t, DiffStatusAdded, diffs[2].Status)
+	assert.Contains(t, diffs[2].Unified, "--- /dev/null")
+	assert.Contains(t, diffs[2].Unified, "+echo new")
+
+	assert.Equal(t, "scripts/old.sh", diffs[3].Path)
+	assert.Equal(t, DiffStatusRemoved, diffs[3].Status)
+	assert.Contains(t, diffs[3].Unified, "+++ /dev/null")
+}
+
+func TestDiffPackageTrees_Symlink(t *testing.T) {
+	fromDir := writeDiffTree(t, map[string]string{"SKILL.md": "# web\n"})
+	toDir := writeDiffTree(t, map[string]string{"SKILL.md": "# web\n"})
+	require.NoError(t, os.Symlink("/etc/passwd", filepath.Join(toDir, "notes.md")))
+
+	diffs, err := DiffPackageTrees(fromDir, toDir, "installed", "1.1.0")
+	require.NoError(t, err)
+	require.Len(t, diffs, 1)
+	assert.Equal(t, "notes.md", diffs[0].Path)
+	assert.Equal(t, DiffStatusAdded, diffs[0].Status)
+	assert.Contains(t, diffs[0].Unified, "+symlink:/etc/passwd")
+}
+
+func TestDiffPackageTrees_Identical(t *testing.T) {
+	files := map[string]string{"SKILL.md": "# web\n"}
+	diffs, err := DiffPackageTrees(writeDiffTree(t, files), writeDiffTree(t, files), "1.0.0", "1.0.1")
+	require.NoError(t, err)
+	assert.Empty(t, diffs)
+}
+
+func TestRunPackageDiff_Installed(t *testing.T) {
+	restore := fetchDiffPackageZip
+	t.Cleanup(func() { fetchDiffPackageZip = restore })
+	var fetched []string
+	fetchDiffPackageZip = func(fetch PackageZipFetch, tmpDir string) (string, error) {
+		fetched = append(fetched, fetch.Version)
+		zipPath := filepath.Join(tmpDir, fetch.Slug+"-"+fetch.Version+".zip")
+		testutil.CreateTestZip(t, zipPath, map[string]string{"SKILL.md": "# web " + fetch.Version + "\n"})
+		return zipPath, nil
+	}
+
+	installed := writeDiffTree(t, map[string]string{"SKILL.md": "# web 1.0.0\n"})
+	require.NoError(t, WriteInstallInfoManifest(installed, "skill-info.json", InstallInfoManifest{Slug: "web", InstalledVersion: "1.0.0"}))
+	notInstalled := filepath.Join(t.TempDir(), "web")
+
+	request := PackageDiffRequest{
+		Slug:             "web",
+		ArtifactKind:     "skill",
+		EntityLabel:      "Skill",
+		ToVersion:        "1.1.0",
+		ManifestFileName: "skill-info.json",
+		Format:           "json",
+		Installed: []InstallTarget{
+			{Agent: AgentSpec{Name: "cursor"}, DestinationDir: installed},
+			{Agent: AgentSpec{Name: "codex"}, DestinationDir: notInstalled},
+		},
+	}
+	require.NoError(t, RunPackageDiff(request))
+	assert.Equal(t, []string{"1.1.0"}, fetched)
+
+	diffs, err := diffInstalledTargets(request, t.TempDir())
+	require.NoError(t, err)
+	require.Len(t, diffs, 1)
+	assert.Equal(t, "installed 1.0.0", diffs[0].From)
+	assert.Equal(t, installed, diffs[0].Path)
+
+	request.Installed = request.Installed[1:]
+	assert.ErrorContains(t, RunPackageDiff(request), "skill 'web' is not installed")
+}
@@ -0,0 +1,405 @@
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	packageStoreSubdir = "store"
+	// storeLinksFileName lists the install directories linked to a store version, under its .jfrog dir.
+	storeLinksFileName = "links.json"
+	// storeStagingPrefix marks a store version that is still being written.
+	storeStagingPrefix = ".staging-"
+	// storeDigestSeparator joins a version and its content digest in a store directory name. Semver never contains it.
+	storeDigestSeparator = "_"
+	// storeDigestLength is how many hex characters of the content digest name a store version.
+	storeDigestLength = 12
+)
+
+// Store gc statuses.
+const (
+	StoreStatusInUse   = "in use"
+	StoreStatusRemoved = "removed"
+	StoreStatusUnused  = "unused"
+)
+
+// packageStoreRoot is swappable in tests.
+var packageStoreRoot = defaultPackageStoreRoot
+
+func defaultPackageStoreRoot() (string, error) {
+	home, err := coreutils.GetJfrogHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve JFrog home dir: %w", err)
+	}
+	return filepath.Join(home, agentsConfigSubdir, packageStoreSubdir), nil
+}
+
+// StoreVersionDir returns the shared store directory of a package version with the given inventory:
+// ~/.jfrog/agents/store/<slug>/<version>_<digest>. The digest covers the package files, so the same version
+// published with different content, e.g. in another repository or on another server, gets its own directory.
+func StoreVersionDir(slug, version string, files map[string]string) (string, error) {
+	root, err := packageStoreRoot()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, slug, version+storeDigestSeparator+packageContentDigest(files)[:storeDigestLength]), nil
+}
+
+// packageContentDigest is the SHA-256 of an inventory: every slash-separated path with its file hash, in path order.
+func packageContentDigest(files map[string]string) string {
+	hash := sha256.New()
+	for _, relPath := range slices.Sorted(maps.Keys(files)) {
+		_, _ = fmt.Fprintf(hash, "%s\x00%s\n", relPath, files[relPath])
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// EnsureStoreVersion places the extracted package in unzipDir into the store once and returns its directory.
+// files is the package inventory (HashPackageTree of unzipDir). A store version whose files still match is
+// reused; one whose files were modified on disk is restored, keeping its link list. A store version recorded with
+// other files is never replaced while installs still link to it.
+func EnsureStoreVersion(slug, version, unzipDir string, files map[string]string) (string, error) {
+	storeDir, err := StoreVersionDir(slug, version, files)
+	if err != nil {
+		return "", err
+	}
+	if storeVersionIntact(storeDir, files) {
+		return storeDir, nil
+	}
+	links, err := readStoreLinks(storeDir)
+	if err != nil {
+		return "", err
+	}
+	if recorded, err := ReadFileInventory(storeDir); err == nil && recorded != nil && !maps.Equal(recorded.Files, files) && len(links) > 0 {
+		return "", fmt.Errorf("store version %s holds other files of %s %s and is linked from %d install(s); run gc or install without --link", storeDir, slug, version, len(links))
+	}
+	if err := os.MkdirAll(filepath.Dir(storeDir), InstallDirMode); err != nil {
+		return "", fmt.Errorf("create package store: %w", err)
+	}
+	stagingDir, err := os.MkdirTemp(filepath.Dir(storeDir), storeStagingPrefix+filepath.Base(storeDir)+"-*")
+	if err != nil {
+		return "", fmt.Errorf("create package store: %w", err)
+	}
+	defer func() {
+		// Best-effort cleanup when the staged version was not moved into place.
+		_ = os.RemoveAll(stagingDir)
+	}()
+	if err := CopyDir(unzipDir, stagingDir); err != nil {
+		return "", fmt.Errorf("copy %s %s into the package store: %w", slug, version, err)
+	}
+	if err := WriteFileInventory(stagingDir, files); err != nil {
+		return "", err
+	}
+	if err := writeStoreLinks(stagingDir, links); err != nil {
+		return "", err
+	}
+	if err := os.RemoveAll(storeDir); err != nil {
+		return "", fmt.Errorf("replace modified store version %s: %w", storeDir, err)
+	}
+	if err := os.Rename(stagingDir, storeDir); err != nil {
+		return "", fmt.Errorf("move package into the store: %w", err)
+	}
+	return storeDir, nil
+}
+
+// storeVersionIntact reports whether storeDir holds exactly the files of the given inventory.
+func storeVersionIntact(storeDir string, files map[string]string) bool {
+	inventory, err := ReadFileInventory(storeDir)
+	if err != nil || inventory == nil || !maps.Equal(inventory.Files, files) {
+		return false
+	}
+	diff, err := DiffFileInventory(storeDir, inventory)
+	return err == nil && diff.Clean()
+}
+
+// LinkStoreVersion makes installDir a linked install of storeDir: every top-level package entry becomes a
+// symlink into the store, while installDir/.jfrog stays a real directory for the per-harness manifest.
+// Entries the package provides replace what was there; other symlinks (e.g. from a previous version) are removed.
+func LinkStoreVersion(storeDir, installDir string) error {
+	if err := EnsureDestinationDir(installDir); err != nil {
+		return err
+	}
+	existing, err := os.ReadDir(installDir)
+	if err != nil {
+		return fmt.Errorf("read install destination %q: %w", installDir, err)
+	}
+	for _, entry := range existing {
+		if entry.Type()&fs.ModeSymlink != 0 {
+			if err := os.Remove(filepath.Join(installDir, entry.Name())); err != nil {
+				return fmt.Errorf("remove previous link %s: %w", entry.Name(), err)
+			}
+		}
+	}
+	names, err := storePackageEntries(storeDir)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		linkPath := filepath.Join(installDir, name)
+		if err := os.RemoveAll(linkPath); err != nil {
+			return fmt.Errorf("replace %s: %w", linkPath, err)
+		}
+		if err := os.Symlink(filepath.Join(storeDir, name), linkPath); err != nil {
+			return fmt.Errorf("link %s into the package store: %w (on Windows, creating symlinks requires Developer Mode or administrator rights)", linkPath, err)
+		}
+	}
+	return addStoreLink(storeDir, installDir)
+}
+
+// StoreLinkProblems checks a linked install against storeDir. relinked lists package entries whose link no
+// longer points into the store; extra lists top-level entries the package does not provide.
+func StoreLinkProblems(storeDir, installDir string) (relinked, extra []string, err error) {
+	names, err := storePackageEntries(storeDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, name := range names {
+		target, err := os.Readlink(filepath.Join(installDir, name))
+		if err != nil || filepath.Clean(target) != filepath.Join(storeDir, name) {
+			relinked = append(relinked, name)
+		}
+	}
+	present, err := os.ReadDir(installDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read install %s: %w", installDir, err)
+	}
+	for _, entry := range present {
+		if entry.Name() != jfrogInstallDirName && !slices.Contains(names, entry.Name()) {
+			extra = append(extra, entry.Name())
+		}
+	}
+	return relinked, extra, nil
+}
+
+// storePackageEntries returns the top-level entries of a store version, without its .jfrog directory.
+func storePackageEntries(storeDir string) ([]string, error) {
+	entries, err := os.ReadDir(storeDir)
+	if err != nil {
+		return nil, fmt.Errorf("read store version %s: %w", storeDir, err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Name() != jfrogInstallDirName {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+func readStoreLinks(storeDir string) ([]string, error) {
+	// #nosec G304 -- path is the package store joined with fixed .jfrog segments.
+	data, err := os.ReadFile(installInfoManifestPath(storeDir, storeLinksFileName))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read store links: %w", err)
+	}
+	var links []string
+	if err := json.Unmarshal(data, &links); err != nil {
+		return nil, fmt.Errorf("parse store links of %s: %w", storeDir, err)
+	}
+	return links, nil
+}
+
+func writeStoreLinks(storeDir string, links []string) error {
+	sort.Strings(links)
+	data, err := json.MarshalIndent(links, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal store links: %w", err)
+	}
+	path := installInfoManifestPath(storeDir, storeLinksFileName)
+	if err := os.MkdirAll(filepath.Dir(path), InstallDirMode); err != nil {
+		return fmt.Errorf("create .jfrog under store version: %w", err)
+	}
+	// #nosec G306 -- link list lives under the user's JFrog home dir.
+	if err := os.WriteFile(path, data, InstallManifestFileMode); err != nil {
+		return fmt.Errorf("write store links: %w", err)
+	}
+	return nil
+}
+
+func addStoreLink(storeDir, installDir string) error {
+	links, err := readStoreLinks(storeDir)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(links, installDir) {
+		return nil
+	}
+	return writeStoreLinks(storeDir, append(links, installDir))
+}
+
+// StoreGCRow is one store version in the gc summary.
+type StoreGCRow struct {
+	Name    string `json:"name" col-name:"Name"`
+	Version string `json:"version" col-name:"Version"`
+	Path    string `json:"path" col-name:"Path"`
+	Status  string `json:"status" col-name:"Status"`
+	Detail  string `json:"detail" col-name:"Detail"`
+}
+
+// GCStore removes store versions that no install links to any more, as recorded in each version's link list
+// and confirmed by the linked install's manifest. Versions an install keeps for rollback stay. With dryRun nothing is removed.
+func GCStore(manifestFileName string, dryRun bool) ([]StoreGCRow, error) {
+	root, err := packageStoreRoot()
+	if err != nil {
+		return nil, err
+	}
+	slugs, err := os.ReadDir(root)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read package store: %w", err)
+	}
+	var rows []StoreGCRow
+	for _, slugEntry := range slugs {
+		if !slugEntry.IsDir() {
+			continue
+		}
+		slugDir := filepath.Join(root, slugEntry.Name())
+		versions, err := os.ReadDir(slugDir)
+		if err != nil {
+			return nil, fmt.Errorf("read package store: %w", err)
+		}
+		for _, versionEntry := range versions {
+			if !versionEntry.IsDir() {
+				continue
+			}
+			version, _, _ := strings.Cut(versionEntry.Name(), storeDigestSeparator)
+			row := gcStoreVersion(slugEntry.Name(), version, filepath.Join(slugDir, versionEntry.Name()), manifestFileName, dryRun)
+			rows = append(rows, row)
+		}
+		if !dryRun {
+			// Removes the slug directory only once its last version is gone.
+			_ = os.Remove(slugDir)
+		}
+	}
+	return rows, nil
+}
+
+func gcStoreVersion(slug, version, storeDir, manifestFileName string, dryRun bool) StoreGCRow {
+	row := StoreGCRow{Name: slug, Version: version, Path: storeDir}
+	if strings.HasPrefix(version, storeStagingPrefix) {
+		// Leftover from an interrupted install.
+		row.Version = ""
+		return removeStoreVersion(row, dryRun, "incomplete store version")
+	}
+	links, err := readStoreLinks(storeDir)
+	if err != nil {
+		row.Status, row.Detail = SummaryStatusFailed, err.Error()
+		return row
+	}
+	var live []string
+	for _, installDir := range links {
+		if linksToStore(installDir, storeDir, manifestFileName) || linksToStore(PreviousInstallDir(installDir), storeDir, manifestFileName) {
+			live = append(live, installDir)
+		}
+	}
+	if len(live) > 0 {
+		row.Status, row.Detail = StoreStatusInUse, fmt.Sprintf("%d link(s)", len(live))
+		if !dryRun && len(live) != len(links) {
+			if err := writeStoreLinks(storeDir, live); err != nil {
+				log.Warn(fmt.Sprintf("Could not drop stale links of %s: %s", storeDir, err.Error()))
+			}
+		}
+		return row
+	}
+	return removeStoreVersion(row, dryRun, "no installs link to this version")
+}
+
+// linksToStore reports whether the install at installDir is linked to storeDir according to its manifest.
+func linksToStore(installDir, storeDir, manifestFileName string) bool {
+	manifest, err := ReadInstallInfoManifest(installDir, manifestFileName)
+	return err == nil && manifest != nil && manifest.StorePath == storeDir
+}
+
+func removeStoreVersion(row StoreGCRow, dryRun bool, reason string) StoreGCRow {
+	if dryRun {
+		row.Status, row.Detail = StoreStatusUnused, reason
+		return row
+	}
+	if err := os.RemoveAll(row.Path); err != nil {
+		row.Status, row.Detail = SummaryStatusFailed, err.Error()
+		return row
+	}
+	row.Status, row.Detail = StoreStatusRemoved, reason
+	return row
+}
+
+type storeGCSummaryJSON struct {
+	Results []StoreGCRow `json:"results"`
+}
+
+// PrintStoreGCSummary renders gc results as a table or JSON and returns an error when a version could not be removed.
+func PrintStoreGCSummary(rows []StoreGCRow, format string) error {
+	if strings.EqualFold(format, "json") {
+		data, err := json.MarshalIndent(storeGCSummaryJSON{Results: rows}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal gc summary: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		log.Info("Package store summary:")
+		if err := coreutils.PrintTable(rows, "Store", "The package store is empty", false); err != nil {
+			log.Warn("Failed to render gc summary: " + err.Error())
+		}
+	}
+	for _, row := range rows {
+		if row.Status == SummaryStatusFailed {
+			return fmt.Errorf("could not clean up one or more store versions (see summary above)")
+		}
+	}
+	return nil
+}
+
+// UnlinkStoreVersion removes the store links of a linked install so that copying a package into installDir
+// does not write through them into the shared store. Installs that are not linked are left untouched.
+func UnlinkStoreVersion(installDir, manifestFileName string) error {
+	manifest, err := ReadInstallInfoManifest(installDir, manifestFileName)
+	if err != nil || manifest == nil || manifest.StorePath == "" {
+		return err
+	}
+	entries, err := os.ReadDir(installDir)
+	if err != nil {
+		return fmt.Errorf("read install destination %q: %w", installDir, err)
+	}
+	for _, entry := range entries {
+		if entry.Type()&fs.ModeSymlink != 0 {
+			if err := os.Remove(filepath.Join(installDir, entry.Name())); err != nil {
+				return fmt.Errorf("remove store link %s: %w", entry.Name(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// PlacePackage puts an extracted package at installDir: as links into storeDir when it is set (--link),
+// otherwise as a copy of unzipDir.
+func PlacePackage(unzipDir, storeDir, installDir, manifestFileName string) error {
+	if storeDir != "" {
+		return LinkStoreVersion(storeDir, installDir)
+	}
+	if err := UnlinkStoreVersion(installDir, manifestFileName); err != nil {
+		return err
+	}
+	if err := EnsureDestinationDir(installDir); err != nil {
+		return err
+	}
+	return CopyDir(unzipDir, installDir)
+}
@@ -0,0 +1,191 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSkillManifest = "skill-info.json"
+
+func stubPackageStore(t *testing.T) string {
+	t.Helper()
+	restore := packageStoreRoot
+	t.Cleanup(func() { packageStoreRoot = restore })
+	root := filepath.Join(t.TempDir(), "store")
+	packageStoreRoot = func() (string, error) { return root, nil }
+	return root
+}
+
+// extractTestPackage writes a package tree with the given SKILL.md body and returns it with its inventory.
+func extractTestPackage(t *testing.T, body string) (string, map[string]string) {
+	t.Helper()
+	unzipDir := t.TempDir()
+	writeTestFile(t, filepath.Join(unzipDir, "SKILL.md"), body)
+	writeTestFile(t, filepath.Join(unzipDir, "scripts/run.sh"), "echo run")
+	files, err := HashPackageTree(unzipDir)
+	require.NoError(t, err)
+	return unzipDir, files
+}
+
+// linkTestInstall links installDir to the store version of web@version like skills install --link.
+func linkTestInstall(t *testing.T, installDir, version, body string) string {
+	t.Helper()
+	unzipDir, files := extractTestPackage(t, body)
+	storeDir, err := EnsureStoreVersion("web", version, unzipDir, files)
+	require.NoError(t, err)
+	require.NoError(t, PlacePackage(unzipDir, storeDir, installDir, testSkillManifest))
+	require.NoError(t, WriteInstallInfoManifest(installDir, testSkillManifest, InstallInfoManifest{Slug: "web", InstalledVersion: version, StorePath: storeDir}))
+	require.NoError(t, WriteFileInventory(installDir, files))
+	return storeDir
+}
+
+func TestEnsureStoreVersion_ReusesIntactVersion(t *testing.T) {
+	root := stubPackageStore(t)
+	unzipDir, files := extractTestPackage(t, "# web")
+
+	storeDir, err := EnsureStoreVersion("web", "1.0.0", unzipDir, files)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "web", "1.0.0_"+packageContentDigest(files)[:storeDigestLength]), storeDir)
+	marker := filepath.Join(storeDir, jfrogInstallDirName, "marker")
+	require.NoError(t, os.WriteFile(marker, nil, 0o600))
+
+	_, err = EnsureStoreVersion("web", "1.0.0", unzipDir, files)
+	require.NoError(t, err)
+	assert.FileExists(t, marker, "an intact store version is not rewritten")
+}
+
+func TestEnsureStoreVersion_ReplacesModifiedVersion(t *testing.T) {
+	stubPackageStore(t)
+	installDir := filepath.Join(t.TempDir(), "web")
+	storeDir := linkTestInstall(t, installDir, "1.0.0", "# web")
+	writeTestFile(t, filepath.Join(storeDir, "SKILL.md"), "# tampered")
+
+	unzipDir, files := extractTestPackage(t, "# web")
+	_, err := EnsureStoreVersion("web", "1.0.0", unzipDir, files)
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(storeDir, "SKILL.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# web", string(data))
+	links, err := readStoreLinks(storeDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{installDir}, links, "the link list survives the replacement")
+}
+
+func TestEnsureStoreVersion_SameVersionOtherContent(t *testing.T) {
+	stubPackageStore(t)
+	installDir := filepath.Join(t.TempDir(), "web")
+	storeDir := linkTestInstall(t, installDir, "1.0.0", "# web")
+
+	// The same version from another repository or server with different files.
+	unzipDir, files := extractTestPackage(t, "# other web")
+	otherStoreDir, err := EnsureStoreVersion("web", "1.0.0", unzipDir, files)
+	require.NoError(t, err)
+	assert.NotEqual(t, storeDir, otherStoreDir)
+	data, err := os.ReadFile(filepath.Join(installDir, "SKILL.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# web", string(data), "existing links keep serving the files they were installed with")
+}
+
+func TestEnsureStoreVersion_KeepsLinkedVersionWithOtherFiles(t *testing.T) {
+	stubPackageStore(t)
+	installDir := filepath.Join(t.TempDir(), "web")
+	storeDir := linkTestInstall(t, installDir, "1.0.0", "# web")
+	// A store version whose recorded inventory is not the one being installed, e.g. a digest prefix collision.
+	require.NoError(t, WriteFileInventory(storeDir, map[string]string{"SKILL.md": "0000"}))
+
+	unzipDir, files := extractTestPackage(t, "# web")
+	_, err := EnsureStoreVersion("web", "1.0.0", unzipDir, files)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is linked from 1 install(s)")
+	assert.FileExists(t, filepath.Join(storeDir, "SKILL.md"))
+}
+
+func TestLinkStoreVersion_RepointsToNewVersion(t *testing.T) {
+	stubPackageStore(t)
+	installDir := filepath.Join(t.TempDir(), "web")
+	linkTestInstall(t, installDir, "1.0.0", "# v1")
+	newStore := linkTestInstall(t, installDir, "2.0.0", "# v2")
+
+	data, err := os.ReadFile(filepath.Join(installDir, "SKILL.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# v2", string(data))
+	target, err := os.Readlink(filepath.Join(installDir, "scripts"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(newStore, "scripts"), target)
+	assert.DirExists(t, filepath.Join(installDir, jfrogInstallDirName), ".jfrog stays a real directory")
+}
+
+func TestPlacePackage_CopyOverLinkedInstallKeepsStore(t *testing.T) {
+	stubPackageStore(t)
+	installDir := filepath.Join(t.TempDir(), "web")
+	storeDir := linkTestInstall(t, installDir, "1.0.0", "# web")
+
+	unzipDir, _ := extractTestPackage(t, "# copied")
+	require.NoError(t, PlacePackage(unzipDir, "", installDir, testSkillManifest))
+
+	data, err := os.ReadFile(filepath.Join(storeDir, "SKILL.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# web", string(data), "copying must not write through the store links")
+	info, err := os.Lstat(filepath.Join(installDir, "SKILL.md"))
+	require.NoError(t, err)
+	assert.True(t, info.Mode().IsRegular())
+}
+
+func TestVerifyInstalls_LinkedInstall(t *testing.T) {
+	stubPackageStore(t)
+	installDir := filepath.Join(t.TempDir(), "web")
+	storeDir := linkTestInstall(t, installDir, "1.0.0", "# web")
+	target := InstallTarget{Agent: AgentSpec{Name: "cursor"}, DestinationDir: installDir}
+	opts := VerifyOptions{ManifestFileName: testSkillManifest}
+
+	results := VerifyInstalls([]InstallTarget{target}, opts)
+	require.Len(t, results, 1)
+	assert.Equal(t, SummaryStatusOK, results[0].Status)
+
+	writeTestFile(t, filepath.Join(storeDir, "scripts/run.sh"), "curl evil")
+	require.NoError(t, os.Remove(filepath.Join(installDir, "SKILL.md")))
+	writeTestFile(t, filepath.Join(installDir, "SKILL.md"), "# local copy")
+	writeTestFile(t, filepath.Join(installDir, "notes.md"), "mine")
+
+	results = VerifyInstalls([]InstallTarget{target}, opts)
+	require.Len(t, results, 1)
+	assert.Equal(t, VerifyStatusModified, results[0].Status)
+	assert.Equal(t, []string{"SKILL.md", "scripts/run.sh"}, results[0].Diff.Modified)
+	assert.Equal(t, []string{"notes.md"}, results[0].Diff.Added)
+}
+
+func TestGCStore(t *testing.T) {
+	stubPackageStore(t)
+	projects := t.TempDir()
+	cursorDir := filepath.Join(projects, "cursor", "web")
+	claudeDir := filepath.Join(projects, "claude", "web")
+	v1 := linkTestInstall(t, cursorDir, "1.0.0", "# v1")
+	linkTestInstall(t, claudeDir, "1.0.0", "# v1")
+	// cursor moves to 2.0.0; claude still uses 1.0.0.
+	v2 := linkTestInstall(t, cursorDir, "2.0.0", "# v2")
+	unzipDir, files := extractTestPackage(t, "# v3")
+	v3, err := EnsureStoreVersion("web", "3.0.0", unzipDir, files)
+	require.NoError(t, err)
+
+	rows, err := GCStore(testSkillManifest, true)
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, StoreStatusUnused, rows[2].Status)
+	assert.DirExists(t, v3, "dry run removes nothing")
+
+	require.NoError(t, os.RemoveAll(claudeDir))
+	rows, err = GCStore(testSkillManifest, false)
+	require.NoError(t, err)
+	statuses := map[string]string{}
+	for _, row := range rows {
+		statuses[row.Version] = row.Status
+	}
+	assert.Equal(t, map[string]string{"1.0.0": StoreStatusRemoved, "2.0.0": StoreStatusInUse, "3.0.0": StoreStatusRemoved}, statuses)
+	assert.NoDirExists(t, v1)
+	assert.DirExists(t, v2)
+	assert.NoDirExists(t, v3)
+}
@@ -0,0 +1,56 @@
+package common
+
+import (
+	"sync"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+)
+
+// promptMu keeps prompts from parallel pipeline workers from interleaving on the terminal.
+var promptMu sync.Mutex
+
+// askYesNoPrompt is swappable in tests.
+var askYesNoPrompt = coreutils.AskYesNo
+
+// RunPackagePipeline runs fetch(i) for every i in [0, count) on up to threads workers and calls apply(i)
+// on the calling goroutine in index order, as soon as fetch(i) and every earlier apply have finished.
+// fetch is the network-bound part (version resolution, download, Xray gate, evidence, extraction);
+// apply touches install directories, the lockfile, and the summary, so it never runs concurrently.
+func RunPackagePipeline(count, threads int, fetch, apply func(index int)) {
+	if threads < 1 {
+		threads = 1
+	}
+	threads = min(threads, count)
+	fetched := make([]chan struct{}, count)
+	for i := range fetched {
+		fetched[i] = make(chan struct{})
+	}
+	indexes := make(chan int)
+	var workers sync.WaitGroup
+	for range threads {
+		workers.Go(func() {
+			for i := range indexes {
+				fetch(i)
+				close(fetched[i])
+			}
+		})
+	}
+	go func() {
+		for i := range count {
+			indexes <- i
+		}
+		close(indexes)
+	}()
+	for i := range count {
+		<-fetched[i]
+		apply(i)
+	}
+	workers.Wait()
+}
+
+// AskYesNo prompts like coreutils.AskYesNo, one prompt at a time across pipeline workers.
+func AskYesNo(promptPrefix string, defaultValue bool) bool {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	return askYesNoPrompt(promptPrefix, defaultValue)
+}
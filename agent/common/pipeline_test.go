@@ -0,0 +1,57 @@
+package common
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunPackagePipeline_AppliesInOrder(t *testing.T) {
+	var applied []int
+	RunPackagePipeline(5, 3, func(i int) {
+		// Later packages finish fetching first.
+		time.Sleep(time.Duration(5-i) * 5 * time.Millisecond)
+	}, func(i int) {
+		applied = append(applied, i)
+	})
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, applied)
+}
+
+func TestRunPackagePipeline_BoundsWorkers(t *testing.T) {
+	var running, peak atomic.Int32
+	var applying atomic.Int32
+	RunPackagePipeline(8, 3, func(int) {
+		current := running.Add(1)
+		for {
+			seen := peak.Load()
+			if current <= seen || peak.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		running.Add(-1)
+	}, func(int) {
+		assert.Equal(t, int32(1), applying.Add(1), "apply never runs concurrently")
+		applying.Add(-1)
+	})
+	assert.Equal(t, int32(3), peak.Load())
+}
+
+func TestRunPackagePipeline_NoJobs(t *testing.T) {
+	RunPackagePipeline(0, 4, func(int) { t.Fatal("fetch called") }, func(int) { t.Fatal("apply called") })
+}
+
+func TestAskYesNo_SerializesPrompts(t *testing.T) {
+	restore := askYesNoPrompt
+	t.Cleanup(func() { askYesNoPrompt = restore })
+	var open atomic.Int32
+	askYesNoPrompt = func(string, bool) bool {
+		assert.Equal(t, int32(1), open.Add(1), "prompts do not overlap")
+		time.Sleep(5 * time.Millisecond)
+		open.Add(-1)
+		return true
+	}
+	RunPackagePipeline(4, 4, func(int) { assert.True(t, AskYesNo("Continue?", false)) }, func(int) {})
+}
@@ -0,0 +1,111 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// ProjectManifestName is the checked-in file that declares the skills and plugins a project needs.
+	ProjectManifestName = "agents.json"
+	// ProjectManifestSchemaVersion is bumped when the manifest JSON shape changes incompatibly.
+	ProjectManifestSchemaVersion = 1
+)
+
+// ManifestPackage declares one skill or plugin and the harnesses it must be installed for.
+type ManifestPackage struct {
+	Slug string `json:"slug"`
+	// Version is an exact version, a range such as "^1.2", or "latest"; empty means latest.
+	Version   string   `json:"version,omitempty"`
+	Harnesses []string `json:"harnesses"`
+	// Repo overrides the repository resolved from --repo or the environment.
+	Repo string `json:"repo,omitempty"`
+}
+
+// ProjectManifest is the on-disk shape of .jfrog/agents.json.
+//
+// Example:
+//
+//	{
+//	  "schemaVersion": 1,
+//	  "skills": [
+//	    {"slug": "web-search", "version": "1.2.0", "harnesses": ["cursor", "claude-code"]}
+//	  ],
+//	  "plugins": [
+//	    {"slug": "code-review", "harnesses": ["claude"], "repo": "agent-plugins"}
+//	  ]
+//	}
+type ProjectManifest struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	Skills        []ManifestPackage `json:"skills,omitempty"`
+	Plugins       []ManifestPackage `json:"plugins,omitempty"`
+}
+
+// ProjectManifestPath returns <projectDir>/.jfrog/agents.json.
+func ProjectManifestPath(projectDir string) string {
+	return filepath.Join(projectDir, jfrogInstallDirName, ProjectManifestName)
+}
+
+// ReadProjectManifest reads and validates the project manifest at path.
+func ReadProjectManifest(path string) (*ProjectManifest, error) {
+	// #nosec G304 -- path is the project manifest chosen by the user or derived from --project-dir.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("project manifest %s not found; create it or pass --manifest <file>", path)
+		}
+		return nil, fmt.Errorf("read project manifest: %w", err)
+	}
+	var manifest ProjectManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("parse project manifest %s: %w", path, err)
+	}
+	if manifest.SchemaVersion > ProjectManifestSchemaVersion {
+		return nil, fmt.Errorf("project manifest %s has schema version %d; this CLI supports up to %d", path, manifest.SchemaVersion, ProjectManifestSchemaVersion)
+	}
+	if err := validateManifestPackages("skills", manifest.Skills); err != nil {
+		return nil, fmt.Errorf("project manifest %s: %w", path, err)
+	}
+	if err := validateManifestPackages("plugins", manifest.Plugins); err != nil {
+		return nil, fmt.Errorf("project manifest %s: %w", path, err)
+	}
+	return &manifest, nil
+}
+
+func validateManifestPackages(section string, packages []ManifestPackage) error {
+	seen := map[string]bool{}
+	for i, pkg := range packages {
+		if err := ValidateSlug(pkg.Slug); err != nil {
+			return fmt.Errorf("%s[%d]: %w", section, i, err)
+		}
+		if seen[pkg.Slug] {
+			return fmt.Errorf("%s: '%s' is declared more than once; list every harness in a single entry", section, pkg.Slug)
+		}
+		seen[pkg.Slug] = true
+		if len(pkg.Harnesses) == 0 {
+			return fmt.Errorf("%s: '%s' must list at least one harness", section, pkg.Slug)
+		}
+		if err := ValidateVersionRequest(pkg.Version); err != nil {
+			return fmt.Errorf("%s: '%s': %w", section, pkg.Slug, err)
+		}
+	}
+	return nil
+}
+
+// DeclaredSlugsByHarness maps each harness named in packages to the set of slugs declared for it.
+func DeclaredSlugsByHarness(packages []ManifestPackage) map[string]map[string]bool {
+	declared := map[string]map[string]bool{}
+	for _, pkg := range packages {
+		for _, harness := range pkg.Harnesses {
+			if declared[harness] == nil {
+				declared[harness] = map[string]bool{}
+			}
+			declared[harness][pkg.Slug] = true
+		}
+	}
+	return declared
+}
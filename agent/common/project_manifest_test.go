@@ -0,0 +1,70 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeProjectManifest(t *testing.T, body string) string {
+	t.Helper()
+	path := ProjectManifestPath(t.TempDir())
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(body), 0o644))
+	return path
+}
+
+func TestReadProjectManifest(t *testing.T) {
+	path := writeProjectManifest(t, `{
+  "schemaVersion": 1,
+  "skills": [{"slug": "web", "version": "1.2.0", "harnesses": ["cursor", "claude-code"]}],
+  "plugins": [{"slug": "review", "harnesses": ["claude"], "repo": "agent-plugins"}]
+}`)
+	manifest, err := ReadProjectManifest(path)
+	require.NoError(t, err)
+	require.Len(t, manifest.Skills, 1)
+	assert.Equal(t, []string{"cursor", "claude-code"}, manifest.Skills[0].Harnesses)
+	require.Len(t, manifest.Plugins, 1)
+	assert.Equal(t, "agent-plugins", manifest.Plugins[0].Repo)
+}
+
+func TestReadProjectManifest_Missing(t *testing.T) {
+	_, err := ReadProjectManifest(filepath.Join(t.TempDir(), ProjectManifestName))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--manifest")
+}
+
+func TestReadProjectManifest_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"newer schema", `{"schemaVersion": 2}`, "schema version 2"},
+		{"bad slug", `{"skills": [{"slug": "Web", "harnesses": ["cursor"]}]}`, "skills[0]"},
+		{"duplicate", `{"skills": [{"slug": "web", "harnesses": ["cursor"]}, {"slug": "web", "harnesses": ["codex"]}]}`, "more than once"},
+		{"no harness", `{"plugins": [{"slug": "web"}]}`, "at least one harness"},
+		{"bad version", `{"plugins": [{"slug": "web", "version": "one", "harnesses": ["claude"]}]}`, "plugins: 'web'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadProjectManifest(writeProjectManifest(t, tt.body))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestDeclaredSlugsByHarness(t *testing.T) {
+	declared := DeclaredSlugsByHarness([]ManifestPackage{
+		{Slug: "a", Harnesses: []string{"cursor", "claude-code"}},
+		{Slug: "b", Harnesses: []string{"cursor"}},
+	})
+	assert.True(t, declared["cursor"]["a"])
+	assert.True(t, declared["cursor"]["b"])
+	assert.False(t, declared["claude-code"]["b"])
+	assert.Equal(t, []string{"claude-code", "cursor"}, SortedHarnesses(declared))
+}
@@ -179,6 +179,7 @@ func GetItemPropertyDescription(
 }
 
 // SearchRowsByProperty runs property search and resolves optional description properties per hit.
+// Deprecated versions are left out.
 func SearchRowsByProperty(
 	serverDetails *config.ServerDetails,
 	opts PropertySearchOptions,
@@ -188,6 +189,7 @@ func SearchRowsByProperty(
 	if err != nil {
 		return nil, err
 	}
+	hits = filterDeprecatedPropertyHits(serverDetails, hits)
 	rows := make([]SearchResultRow, 0, len(hits))
 	for _, hit := range hits {
 		desc := ""
@@ -0,0 +1,211 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// previousInstallDirName is where update keeps the version it replaced: <installDir>/.jfrog/previous.
+const previousInstallDirName = "previous"
+
+// PreviousInstallDir returns where the version replaced by the last update of installDir is kept for rollback.
+func PreviousInstallDir(installDir string) string {
+	return filepath.Join(installDir, jfrogInstallDirName, previousInstallDirName)
+}
+
+// KeepPreviousInstall moves replacedDir, the install tree an update just replaced, to PreviousInstallDir(installDir)
+// so that rollback can restore it. Only one previous version is kept.
+func KeepPreviousInstall(replacedDir, installDir string) error {
+	if err := RemovePath(PreviousInstallDir(replacedDir)); err != nil {
+		return fmt.Errorf("remove older kept version: %w", err)
+	}
+	previousDir := PreviousInstallDir(installDir)
+	if err := RemovePath(previousDir); err != nil {
+		return fmt.Errorf("remove older kept version: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(previousDir), InstallDirMode); err != nil {
+		return fmt.Errorf("create .jfrog under install dir: %w", err)
+	}
+	if err := MovePath(replacedDir, previousDir); err != nil {
+		return fmt.Errorf("keep previous version: %w", err)
+	}
+	return nil
+}
+
+// RollbackResult is the outcome of rolling back one install target.
+type RollbackResult struct {
+	SummaryRow
+	// Restored is the install manifest of the version rolled back to; nil unless Status is SummaryStatusOK.
+	Restored *InstallInfoManifest `json:"-"`
+}
+
+// RollbackInstalls restores the version kept by the last update of each target.
+func RollbackInstalls(targets []InstallTarget, manifestFileName string) []RollbackResult {
+	results := make([]RollbackResult, 0, len(targets))
+	for _, target := range targets {
+		results = append(results, rollbackInstall(target, manifestFileName))
+	}
+	return results
+}
+
+// rollbackInstall swaps the live install with the kept previous version using renames, so the target always holds
+// one complete version. The version rolled back from is kept in turn, so a second rollback undoes the first.
+func rollbackInstall(target InstallTarget, manifestFileName string) RollbackResult {
+	installDir := target.DestinationDir
+	result := RollbackResult{SummaryRow: SummaryRow{Agent: target.Agent.Name, Scope: string(target.Scope), Path: installDir}}
+	current, err := ReadInstallInfoManifest(installDir, manifestFileName)
+	if err != nil {
+		return result.failed(err)
+	}
+	if current == nil {
+		result.Status, result.Detail = SummaryStatusSkipped, "Not installed."
+		return result
+	}
+	previous, err := ReadInstallInfoManifest(PreviousInstallDir(installDir), manifestFileName)
+	if err != nil {
+		return result.failed(err)
+	}
+	if previous == nil {
+		result.Status, result.Detail = SummaryStatusSkipped, "No previous version to roll back to."
+		return result
+	}
+	if previous.StorePath != "" {
+		if _, err := os.Stat(previous.StorePath); err != nil {
+			return result.failed(fmt.Errorf("store version %s of the previous install is no longer available: %w", previous.StorePath, err))
+		}
+	}
+
+	asidePath, err := reserveRollbackPath(installDir)
+	if err != nil {
+		return result.failed(err)
+	}
+	if err := MovePath(installDir, asidePath); err != nil {
+		return result.failed(fmt.Errorf("could not move current install aside for rollback: %w", err))
+	}
+	if err := MovePath(PreviousInstallDir(asidePath), installDir); err != nil {
+		if restoreErr := MovePath(asidePath, installDir); restoreErr != nil {
+			return result.failed(fmt.Errorf("could not restore previous version: %w; current install left at %s", err, asidePath))
+		}
+		return result.failed(fmt.Errorf("could not restore previous version: %w", err))
+	}
+	if err := KeepPreviousInstall(asidePath, installDir); err != nil {
+		log.Warn(fmt.Sprintf("Rolled back %s but could not keep version %s for a later rollback: %s", installDir, current.InstalledVersion, err.Error()))
+		_ = RemovePath(asidePath)
+	}
+
+	// Where the package is installed is taken from the current manifest; what is installed from the restored one.
+	restored := *previous
+	restored.SchemaVersion = InstallInfoManifestSchemaVersion
+	restored.Scope, restored.Agent, restored.ProjectDir = current.Scope, current.Agent, current.ProjectDir
+	if err := WriteInstallInfoManifest(installDir, manifestFileName, restored); err != nil {
+		return result.failed(err)
+	}
+	if restored.StorePath != "" {
+		if err := addStoreLink(restored.StorePath, installDir); err != nil {
+			log.Warn(fmt.Sprintf("Could not record %s as a link of %s: %s", installDir, restored.StorePath, err.Error()))
+		}
+	}
+	result.Status = SummaryStatusOK
+	result.Detail = fmt.Sprintf("Rolled back from %s to %s.", current.InstalledVersion, restored.InstalledVersion)
+	result.Restored = &restored
+	return result
+}
+
+func (r RollbackResult) failed(err error) RollbackResult {
+	r.Status, r.Detail = SummaryStatusFailed, err.Error()
+	return r
+}
+
+// reserveRollbackPath returns an unused path next to installDir, so moving the install there is a rename.
+func reserveRollbackPath(installDir string) (string, error) {
+	reserved, err := os.MkdirTemp(filepath.Dir(installDir), "."+filepath.Base(installDir)+"-rollback-*")
+	if err != nil {
+		return "", fmt.Errorf("could not reserve rollback path: %w", err)
+	}
+	if err := os.Remove(reserved); err != nil {
+		return "", fmt.Errorf("could not prepare rollback path: %w", err)
+	}
+	return reserved, nil
+}
+
+// RecordRollbackLockEntries re-pins the restored version of every rolled-back install in the lockfile at path.
+// Installs made before checksums were recorded in the install manifest cannot be pinned and are reported.
+func RecordRollbackLockEntries(path, kind string, results []RollbackResult) error {
+	var entries []LockEntry
+	for _, result := range results {
+		if result.Restored == nil {
+			continue
+		}
+		if result.Restored.SHA256 == "" {
+			log.Warn(fmt.Sprintf("%s was rolled back to %s, which has no recorded checksum; %s was not updated for it", result.Path, result.Restored.InstalledVersion, LockfileName))
+			continue
+		}
+		entries = append(entries, LockEntry{
+			Kind:    kind,
+			Slug:    result.Restored.Slug,
+			Repo:    result.Restored.Repo,
+			Version: result.Restored.InstalledVersion,
+			SHA256:  result.Restored.SHA256,
+			Harness: result.Agent,
+		})
+	}
+	if err := RecordLockEntries(path, entries); err != nil {
+		return fmt.Errorf("update %s: %w", LockfileName, err)
+	}
+	return nil
+}
+
+type rollbackSummaryJSON struct {
+	Slug    string           `json:"slug"`
+	Results []RollbackResult `json:"results"`
+}
+
+// PrintRollbackSummary renders rollback results as a table or JSON.
+func PrintRollbackSummary(entityLabel, slug string, results []RollbackResult, format string) error {
+	if len(results) == 0 {
+		return nil
+	}
+	if strings.EqualFold(format, "json") {
+		data, err := json.MarshalIndent(rollbackSummaryJSON{Slug: slug, Results: results}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal rollback summary: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	rows := make([]SummaryRow, 0, len(results))
+	for _, result := range results {
+		rows = append(rows, result.SummaryRow)
+	}
+	log.Info(entityLabel + " rollback summary for '" + slug + "':")
+	if err := coreutils.PrintTable(rows, "Rolled back", "No "+strings.ToLower(entityLabel)+"s rolled back", false); err != nil {
+		log.Warn("Failed to render rollback summary: " + err.Error())
+	}
+	return nil
+}
+
+// RollbackError returns an error when any target failed to roll back or none had a version to roll back to.
+func RollbackError(entityLabel, slug string, results []RollbackResult) error {
+	failed, rolledBack := 0, 0
+	for _, result := range results {
+		switch result.Status {
+		case SummaryStatusFailed:
+			failed++
+		case SummaryStatusOK:
+			rolledBack++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("rollback failed for %d target(s) (see summary above)", failed)
+	}
+	if rolledBack == 0 {
+		return fmt.Errorf("%s '%s' has no previous version to roll back to in the selected targets", strings.ToLower(entityLabel), slug)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// installTestVersion writes web@version at installDir the way install does: package files plus manifest.
+func installTestVersion(t *testing.T, installDir, version string) {
+	t.Helper()
+	writeTestFile(t, filepath.Join(installDir, "SKILL.md"), "# web "+version)
+	require.NoError(t, WriteInstallInfoManifest(installDir, testSkillManifest, InstallInfoManifest{
+		Repo: "skills-local", Slug: "web", InstalledVersion: version, Scope: "project", Agent: "cursor", SHA256: "sha-" + version,
+	}))
+}
+
+// updateTestVersion replaces the install at installDir with web@version the way update does.
+func updateTestVersion(t *testing.T, installDir, version string) {
+	t.Helper()
+	replaced := filepath.Join(t.TempDir(), "backup")
+	require.NoError(t, MovePath(installDir, replaced))
+	installTestVersion(t, installDir, version)
+	require.NoError(t, KeepPreviousInstall(replaced, installDir))
+}
+
+func readTestSkill(t *testing.T, installDir string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(installDir, "SKILL.md"))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func rollbackTestTarget(installDir string) InstallTarget {
+	return InstallTarget{Agent: AgentSpec{Name: "cursor"}, Scope: InstallScopeProject, DestinationDir: installDir}
+}
+
+func TestKeepPreviousInstall_KeepsOneVersion(t *testing.T) {
+	installDir := filepath.Join(t.TempDir(), "web")
+	installTestVersion(t, installDir, "1.0.0")
+	updateTestVersion(t, installDir, "2.0.0")
+	updateTestVersion(t, installDir, "3.0.0")
+
+	assert.Equal(t, "# web 2.0.0", readTestSkill(t, PreviousInstallDir(installDir)))
+	assert.NoDirExists(t, PreviousInstallDir(PreviousInstallDir(installDir)))
+}
+
+func TestRollbackInstalls_RestoresPreviousVersion(t *testing.T) {
+	installDir := filepath.Join(t.TempDir(), "web")
+	installTestVersion(t, installDir, "1.0.0")
+	updateTestVersion(t, installDir, "2.0.0")
+
+	results := RollbackInstalls([]InstallTarget{rollbackTestTarget(installDir)}, testSkillManifest)
+	require.Len(t, results, 1)
+	assert.Equal(t, SummaryStatusOK, results[0].Status)
+	assert.Equal(t, "Rolled back from 2.0.0 to 1.0.0.", results[0].Detail)
+	assert.Equal(t, "# web 1.0.0", readTestSkill(t, installDir))
+	manifest, err := ReadInstallInfoManifest(installDir, testSkillManifest)
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", manifest.InstalledVersion)
+	assert.NoError(t, RollbackError("Skill", "web", results))
+
+	entries, err := os.ReadDir(filepath.Dir(installDir))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "nothing is left next to the install")
+
+	// The version rolled back from is kept, so rolling back again undoes the rollback.
+	results = RollbackInstalls([]InstallTarget{rollbackTestTarget(installDir)}, testSkillManifest)
+	assert.Equal(t, SummaryStatusOK, results[0].Status)
+	assert.Equal(t, "# web 2.0.0", readTestSkill(t, installDir))
+}
+
+func TestRollbackInstalls_NothingToRollBack(t *testing.T) {
+	base := t.TempDir()
+	installed := filepath.Join(base, "installed", "web")
+	installTestVersion(t, installed, "1.0.0")
+	targets := []InstallTarget{rollbackTestTarget(installed), rollbackTestTarget(filepath.Join(base, "missing", "web"))}
+
+	results := RollbackInstalls(targets, testSkillManifest)
+	require.Len(t, results, 2)
+	assert.Equal(t, SummaryStatusSkipped, results[0].Status)
+	assert.Equal(t, "No previous version to roll back to.", results[0].Detail)
+	assert.Equal(t, SummaryStatusSkipped, results[1].Status)
+	assert.Equal(t, "# web 1.0.0", readTestSkill(t, installed))
+	assert.ErrorContains(t, RollbackError("Skill", "web", results), "no previous version")
+}
+
+func TestRecordRollbackLockEntries(t *testing.T) {
+	installDir := filepath.Join(t.TempDir(), "web")
+	installTestVersion(t, installDir, "1.0.0")
+	updateTestVersion(t, installDir, "2.0.0")
+	lockPath := filepath.Join(t.TempDir(), LockfileName)
+	require.NoError(t, RecordLockEntries(lockPath, []LockEntry{{Kind: LockKindSkill, Slug: "web", Repo: "skills-local", Version: "2.0.0", SHA256: "sha-2.0.0", Harness: "cursor"}}))
+
+	results := RollbackInstalls([]InstallTarget{rollbackTestTarget(installDir)}, testSkillManifest)
+	require.NoError(t, RecordRollbackLockEntries(lockPath, LockKindSkill, results))
+
+	lockfile, err := ReadLockfile(lockPath)
+	require.NoError(t, err)
+	entry, found := lockfile.Find(LockKindSkill, "web", "cursor")
+	require.True(t, found)
+	assert.Equal(t, "1.0.0", entry.Version)
+	assert.Equal(t, "sha-1.0.0", entry.SHA256)
+}
+
+func TestGCStore_KeepsVersionKeptForRollback(t *testing.T) {
+	stubPackageStore(t)
+	installDir := filepath.Join(t.TempDir(), "web")
+	v1 := linkTestInstall(t, installDir, "1.0.0", "# v1")
+	replaced := filepath.Join(t.TempDir(), "backup")
+	require.NoError(t, MovePath(installDir, replaced))
+	v2 := linkTestInstall(t, installDir, "2.0.0", "# v2")
+	require.NoError(t, KeepPreviousInstall(replaced, installDir))
+
+	rows, err := GCStore(testSkillManifest, false)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, StoreStatusInUse, rows[0].Status)
+	assert.DirExists(t, v1)
+
+	results := RollbackInstalls([]InstallTarget{rollbackTestTarget(installDir)}, testSkillManifest)
+	require.Equal(t, SummaryStatusOK, results[0].Status, results[0].Detail)
+	data, err := os.ReadFile(filepath.Join(installDir, "SKILL.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# v1", string(data))
+	assert.DirExists(t, v2)
+}
@@ -0,0 +1,326 @@
+package common
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	pluginsCommon "github.com/jfrog/jfrog-cli-core/v2/plugins/common"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Metadata properties read from the version zip of each search result. Publish pipelines or admins set them,
+// e.g. with `jf rt set-props`; tags and harnesses hold comma-separated values.
+const (
+	TagsPropertyKey      = "agent.tags"
+	AuthorPropertyKey    = "agent.author"
+	HarnessesPropertyKey = "agent.harnesses"
+)
+
+// Fields search results can be sorted by. Without one, results keep the order the server returned.
+const (
+	SearchSortName       = "name"
+	SearchSortVersion    = "version"
+	SearchSortRepository = "repository"
+)
+
+// Search flag names shared by skills and plugins search.
+const (
+	SearchTagFlag        = "tag"
+	SearchAuthorFlag     = "author"
+	SearchHarnessFlag    = "harness"
+	SearchMinVersionFlag = "min-version"
+	SearchEvidenceFlag   = "has-evidence"
+	SearchXrayFlag       = "xray-status"
+	SearchSortByFlag     = "sort-by"
+	SearchSortOrderFlag  = "sort-order"
+	SearchLimitFlag      = "limit"
+	SearchOffsetFlag     = "offset"
+)
+
+// searchXrayStatuses are the values --xray-status accepts.
+var searchXrayStatuses = []string{
+	services.SkillXrayStatusApproved,
+	services.SkillXrayStatusBlocked,
+	services.SkillXrayStatusScanInProgress,
+	services.SkillXrayStatusNotInEntitlement,
+	services.SkillXrayStatusDisabledForRepo,
+}
+
+// newSearchServiceManager, lookupSearchItemProps, lookupSearchXrayStatus and verifySearchEvidence are swappable in tests.
+var (
+	newSearchServiceManager = createPropertySearchServiceManager
+	lookupSearchItemProps   = fetchSearchItemProps
+	lookupSearchXrayStatus  = fetchXrayStatusWith
+	verifySearchEvidence    = VerifyPackageEvidence
+)
+
+// SearchFilters narrows, sorts and pages search results. The zero value keeps every result in server order.
+type SearchFilters struct {
+	// Tags must all be present on a result, compared case-insensitively.
+	Tags   []string
+	Author string
+	// Harness keeps results that list it in their harnesses property, or that list no harnesses at all.
+	Harness    string
+	MinVersion string
+	// HasEvidence keeps results whose evidence verifies in Artifactory.
+	HasEvidence bool
+	// XrayStatus keeps results whose Xray gate status equals it, e.g. APPROVED.
+	XrayStatus string
+	// XrayKind is the Xray gate of the package kind searched; used with XrayStatus.
+	XrayKind  XrayGateKind
+	SortBy    string
+	SortOrder string
+	Limit     int
+	Offset    int
+	// Threads bounds the rows whose metadata, evidence and Xray lookups run at once; below 1 means one.
+	Threads int
+}
+
+// Active reports whether any filter, sort or page option is set.
+func (f SearchFilters) Active() bool {
+	return f.narrows() || f.SortBy != "" || f.Limit > 0 || f.Offset > 0
+}
+
+// narrows reports whether f may drop results.
+func (f SearchFilters) narrows() bool {
+	return len(f.Tags) > 0 || f.Author != "" || f.Harness != "" || f.MinVersion != "" || f.HasEvidence || f.XrayStatus != ""
+}
+
+func (f SearchFilters) needsMetadata() bool {
+	return len(f.Tags) > 0 || f.Author != "" || f.Harness != ""
+}
+
+// ParseSearchFilters reads the search filter, sort and page flags.
+func ParseSearchFilters(c *components.Context, xrayKind XrayGateKind) (SearchFilters, error) {
+	filters := SearchFilters{
+		Author:      strings.TrimSpace(c.GetStringFlagValue(SearchAuthorFlag)),
+		Harness:     strings.ToLower(strings.TrimSpace(c.GetStringFlagValue(SearchHarnessFlag))),
+		MinVersion:  strings.TrimSpace(c.GetStringFlagValue(SearchMinVersionFlag)),
+		HasEvidence: c.GetBoolFlagValue(SearchEvidenceFlag),
+		XrayStatus:  strings.ToUpper(strings.TrimSpace(c.GetStringFlagValue(SearchXrayFlag))),
+		XrayKind:    xrayKind,
+		SortBy:      strings.ToLower(strings.TrimSpace(c.GetStringFlagValue(SearchSortByFlag))),
+		SortOrder:   strings.ToLower(strings.TrimSpace(c.GetStringFlagValue(SearchSortOrderFlag))),
+	}
+	for _, tag := range strings.Split(c.GetStringFlagValue(SearchTagFlag), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			filters.Tags = append(filters.Tags, tag)
+		}
+	}
+	var err error
+	if filters.Limit, err = parseSearchCount(c.GetStringFlagValue(SearchLimitFlag), SearchLimitFlag); err != nil {
+		return filters, err
+	}
+	if filters.Offset, err = parseSearchCount(c.GetStringFlagValue(SearchOffsetFlag), SearchOffsetFlag); err != nil {
+		return filters, err
+	}
+	if filters.Threads, err = pluginsCommon.GetThreadsCount(c); err != nil {
+		return filters, err
+	}
+	return filters, filters.Validate()
+}
+
+func parseSearchCount(raw, flagName string) (int, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, nil
+	}
+	count, err := strconv.Atoi(raw)
+	if err != nil || count < 0 {
+		return 0, fmt.Errorf("--%s must be a non-negative integer, got: %q", flagName, raw)
+	}
+	return count, nil
+}
+
+// Validate rejects unknown sort fields, sort orders and Xray statuses, and a minimum version that is not semver.
+func (f SearchFilters) Validate() error {
+	switch f.SortBy {
+	case "", SearchSortName, SearchSortVersion, SearchSortRepository:
+	default:
+		return fmt.Errorf("--sort-by accepts '%s', '%s' or '%s', got: %q", SearchSortName, SearchSortVersion, SearchSortRepository, f.SortBy)
+	}
+	switch f.SortOrder {
+	case "", "asc", "desc":
+	default:
+		return fmt.Errorf("--sort-order accepts 'asc' or 'desc', got: %q", f.SortOrder)
+	}
+	if f.SortOrder != "" && f.SortBy == "" {
+		return fmt.Errorf("--sort-order requires --sort-by")
+	}
+	if f.MinVersion != "" {
+		if err := ValidateSemver(f.MinVersion); err != nil {
+			return fmt.Errorf("invalid --min-version: %w", err)
+		}
+	}
+	if f.XrayStatus != "" && !slices.Contains(searchXrayStatuses, f.XrayStatus) {
+		return fmt.Errorf("--xray-status accepts %s, got: %q", strings.Join(searchXrayStatuses, ", "), f.XrayStatus)
+	}
+	return nil
+}
+
+// ApplySearchFilters drops the rows filters exclude, then sorts and pages the rest. Metadata, evidence and Xray
+// lookups run only for the filters that need them, and fill the matching columns of the returned rows.
+func ApplySearchFilters(serverDetails *config.ServerDetails, rows []SearchResultRow, filters SearchFilters) ([]SearchResultRow, error) {
+	if filters.narrows() {
+		var err error
+		if rows, err = filterSearchRows(serverDetails, rows, filters); err != nil {
+			return nil, err
+		}
+	}
+	sortSearchRows(rows, filters.SortBy, filters.SortOrder == "desc")
+	return pageSearchRows(rows, filters.Offset, filters.Limit), nil
+}
+
+// filterSearchRows keeps the rows that match filters, in server order. The lookups of up to filters.Threads rows
+// run at once, and share one services manager.
+func filterSearchRows(serverDetails *config.ServerDetails, rows []SearchResultRow, filters SearchFilters) ([]SearchResultRow, error) {
+	var serviceManager artifactory.ArtifactoryServicesManager
+	if filters.needsMetadata() || filters.XrayStatus != "" {
+		var err error
+		if serviceManager, err = newSearchServiceManager(serverDetails); err != nil {
+			return nil, err
+		}
+	}
+	matched := make([]bool, len(rows))
+	kept := make([]SearchResultRow, 0, len(rows))
+	RunPackagePipeline(len(rows), filters.Threads,
+		func(i int) {
+			// Each worker fills in only its own row.
+			matched[i] = matchesSearchFilters(serverDetails, serviceManager, &rows[i], filters)
+		},
+		func(i int) {
+			if matched[i] {
+				kept = append(kept, rows[i])
+			}
+		})
+	return kept, nil
+}
+
+// matchesSearchFilters checks the cheap filters first so remote lookups run only for rows still in the running.
+func matchesSearchFilters(serverDetails *config.ServerDetails, serviceManager artifactory.ArtifactoryServicesManager, row *SearchResultRow, filters SearchFilters) bool {
+	if filters.MinVersion != "" {
+		if cmp, err := CompareSemver(row.Version, filters.MinVersion); err != nil || cmp < 0 {
+			return false
+		}
+	}
+	repoPath := packageZipRepoPath(row.Repository, row.Name, row.Version)
+	if filters.needsMetadata() {
+		props, err := lookupSearchItemProps(serviceManager, repoPath)
+		if err != nil {
+			log.Debug(fmt.Sprintf("Could not read the metadata of %s: %s", repoPath, err.Error()))
+			return false
+		}
+		row.Tags = strings.Join(splitPropertyValues(props[TagsPropertyKey]), ",")
+		row.Author = firstPropertyValue(props[AuthorPropertyKey])
+		row.Harnesses = strings.Join(splitPropertyValues(props[HarnessesPropertyKey]), ",")
+		if !matchesSearchMetadata(*row, filters) {
+			return false
+		}
+	}
+	if filters.HasEvidence {
+		if err := verifySearchEvidence(serverDetails, row.Repository, row.Name, row.Version); err != nil {
+			log.Debug(fmt.Sprintf("No verified evidence for %s: %s", repoPath, err.Error()))
+			return false
+		}
+		row.Evidence = VerifyEvidenceVerified
+	}
+	if filters.XrayStatus != "" {
+		status, err := lookupSearchXrayStatus(serviceManager, filters.XrayKind, row.Repository, PackageArtifactPath(row.Name, row.Version))
+		if err != nil {
+			log.Debug(fmt.Sprintf("Could not read the Xray status of %s: %s", repoPath, err.Error()))
+			return false
+		}
+		if status != filters.XrayStatus {
+			return false
+		}
+		row.Xray = status
+	}
+	return true
+}
+
+func matchesSearchMetadata(row SearchResultRow, filters SearchFilters) bool {
+	tags := strings.Split(strings.ToLower(row.Tags), ",")
+	for _, tag := range filters.Tags {
+		if !slices.Contains(tags, strings.ToLower(tag)) {
+			return false
+		}
+	}
+	if filters.Author != "" && !strings.EqualFold(row.Author, filters.Author) {
+		return false
+	}
+	if filters.Harness != "" && row.Harnesses != "" {
+		return slices.Contains(strings.Split(strings.ToLower(row.Harnesses), ","), filters.Harness)
+	}
+	return true
+}
+
+// sortSearchRows sorts rows by field, keeping server order for ties. Versions compare as semver, and versions
+// that do not parse sort after those that do.
+func sortSearchRows(rows []SearchResultRow, field string, descending bool) {
+	if field == "" {
+		return
+	}
+	less := func(a, b SearchResultRow) bool {
+		switch field {
+		case SearchSortVersion:
+			cmp, err := CompareSemver(a.Version, b.Version)
+			if err != nil {
+				return ValidateSemver(a.Version) == nil && ValidateSemver(b.Version) != nil
+			}
+			return cmp < 0
+		case SearchSortRepository:
+			return a.Repository < b.Repository
+		default:
+			return a.Name < b.Name
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if descending {
+			return less(rows[j], rows[i])
+		}
+		return less(rows[i], rows[j])
+	})
+}
+
+func pageSearchRows(rows []SearchResultRow, offset, limit int) []SearchResultRow {
+	if offset >= len(rows) {
+		return nil
+	}
+	rows = rows[offset:]
+	if limit > 0 && len(rows) > limit {
+		rows = rows[:limit]
+	}
+	return rows
+}
+
+// splitPropertyValues flattens property values that may each hold a comma-separated list.
+func splitPropertyValues(values []string) []string {
+	var split []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				split = append(split, part)
+			}
+		}
+	}
+	return split
+}
+
+// fetchSearchItemProps returns the properties set on repoPath.
+func fetchSearchItemProps(serviceManager artifactory.ArtifactoryServicesManager, repoPath string) (map[string][]string, error) {
+	props, err := serviceManager.GetItemProps(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	if props == nil {
+		return nil, nil
+	}
+	return props.Properties, nil
+}
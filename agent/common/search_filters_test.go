@@ -0,0 +1,171 @@
+package common
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-artifactory/agent/common/testutil"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSearchRows = []SearchResultRow{
+	{Name: "web", Version: "1.2.0", Repository: "skills-b"},
+	{Name: "api", Version: "2.0.0", Repository: "skills-a"},
+	{Name: "db", Version: "0.9.0", Repository: "skills-a"},
+	{Name: "cli", Version: "1.10.0", Repository: "skills-c"},
+}
+
+func searchRowNames(rows []SearchResultRow) []string {
+	names := make([]string, 0, len(rows))
+	for _, row := range rows {
+		names = append(names, row.Name)
+	}
+	return names
+}
+
+func copySearchRows() []SearchResultRow {
+	return append([]SearchResultRow(nil), testSearchRows...)
+}
+
+// applySearchFilters runs ApplySearchFilters on a copy of testSearchRows.
+func applySearchFilters(t *testing.T, serverDetails *config.ServerDetails, filters SearchFilters) []SearchResultRow {
+	t.Helper()
+	rows, err := ApplySearchFilters(serverDetails, copySearchRows(), filters)
+	require.NoError(t, err)
+	return rows
+}
+
+// withSearchServiceManager stubs the services manager of filtered searches and returns how many were created.
+func withSearchServiceManager(t *testing.T) *int {
+	t.Helper()
+	restore := newSearchServiceManager
+	t.Cleanup(func() { newSearchServiceManager = restore })
+	created := 0
+	newSearchServiceManager = func(*config.ServerDetails) (artifactory.ArtifactoryServicesManager, error) {
+		created++
+		return nil, nil
+	}
+	return &created
+}
+
+func TestApplySearchFilters_SortAndPage(t *testing.T) {
+	rows := applySearchFilters(t, nil, SearchFilters{})
+	assert.Equal(t, []string{"web", "api", "db", "cli"}, searchRowNames(rows), "no sort keeps server order")
+
+	rows = applySearchFilters(t, nil, SearchFilters{SortBy: SearchSortVersion, SortOrder: "desc"})
+	assert.Equal(t, []string{"api", "cli", "web", "db"}, searchRowNames(rows))
+
+	rows = applySearchFilters(t, nil, SearchFilters{SortBy: SearchSortRepository})
+	assert.Equal(t, []string{"api", "db", "web", "cli"}, searchRowNames(rows), "ties keep server order")
+
+	rows = applySearchFilters(t, nil, SearchFilters{SortBy: SearchSortName, Offset: 1, Limit: 2})
+	assert.Equal(t, []string{"cli", "db"}, searchRowNames(rows))
+
+	assert.Empty(t, applySearchFilters(t, nil, SearchFilters{Offset: 10}))
+}
+
+func TestApplySearchFilters_Metadata(t *testing.T) {
+	withSearchServiceManager(t)
+	restore := lookupSearchItemProps
+	t.Cleanup(func() { lookupSearchItemProps = restore })
+	props := map[string]map[string][]string{
+		"skills-b/web/1.2.0/web-1.2.0.zip":   {TagsPropertyKey: {"Frontend,testing"}, AuthorPropertyKey: {"alice"}, HarnessesPropertyKey: {"cursor", "claude-code"}},
+		"skills-a/api/2.0.0/api-2.0.0.zip":   {TagsPropertyKey: {"backend", "testing"}, AuthorPropertyKey: {"Alice"}},
+		"skills-c/cli/1.10.0/cli-1.10.0.zip": {TagsPropertyKey: {"testing"}, AuthorPropertyKey: {"bob"}, HarnessesPropertyKey: {"codex"}},
+	}
+	lookupSearchItemProps = func(_ artifactory.ArtifactoryServicesManager, repoPath string) (map[string][]string, error) {
+		if found, ok := props[repoPath]; ok {
+			return found, nil
+		}
+		return nil, errors.New("not found")
+	}
+
+	rows := applySearchFilters(t, nil, SearchFilters{Tags: []string{"testing"}, Author: "ALICE"})
+	assert.Equal(t, []string{"web", "api"}, searchRowNames(rows))
+	assert.Equal(t, "Frontend,testing", rows[0].Tags)
+	assert.Equal(t, "cursor,claude-code", rows[0].Harnesses)
+
+	rows = applySearchFilters(t, nil, SearchFilters{Tags: []string{"frontend", "testing"}})
+	assert.Equal(t, []string{"web"}, searchRowNames(rows))
+
+	rows = applySearchFilters(t, nil, SearchFilters{Harness: "codex"})
+	assert.Equal(t, []string{"api", "cli"}, searchRowNames(rows), "rows without harnesses are compatible with every harness")
+
+	rows = applySearchFilters(t, nil, SearchFilters{MinVersion: "1.2.0", Harness: "cursor"})
+	assert.Equal(t, []string{"web", "api"}, searchRowNames(rows))
+}
+
+func TestApplySearchFilters_EvidenceAndXray(t *testing.T) {
+	created := withSearchServiceManager(t)
+	restoreEvidence, restoreXray := verifySearchEvidence, lookupSearchXrayStatus
+	t.Cleanup(func() {
+		verifySearchEvidence = restoreEvidence
+		lookupSearchXrayStatus = restoreXray
+	})
+	verifySearchEvidence = func(_ *config.ServerDetails, _, slug, _ string) error {
+		if slug == "db" {
+			return errors.New("no evidence found")
+		}
+		return nil
+	}
+	lookupSearchXrayStatus = func(_ artifactory.ArtifactoryServicesManager, _ XrayGateKind, _, artifactPath string) (string, error) {
+		if artifactPath == PackageArtifactPath("cli", "1.10.0") {
+			return services.SkillXrayStatusBlocked, nil
+		}
+		return services.SkillXrayStatusApproved, nil
+	}
+
+	rows := applySearchFilters(t, testPolicyServer, SearchFilters{HasEvidence: true, XrayStatus: services.SkillXrayStatusApproved, XrayKind: XrayGateSkills, Threads: 3})
+	assert.Equal(t, []string{"web", "api"}, searchRowNames(rows), "workers keep server order")
+	assert.Equal(t, 1, *created, "one services manager per search")
+	assert.Equal(t, VerifyEvidenceVerified, rows[0].Evidence)
+	assert.Equal(t, services.SkillXrayStatusApproved, rows[0].Xray)
+}
+
+func TestParseSearchFilters(t *testing.T) {
+	c := testutil.NewCLIContext()
+	c.AddStringFlag(SearchTagFlag, " testing, frontend ,")
+	c.AddStringFlag(SearchHarnessFlag, "Cursor")
+	c.AddStringFlag(SearchXrayFlag, "approved")
+	c.AddStringFlag(SearchSortByFlag, "Version")
+	c.AddStringFlag(SearchLimitFlag, "5")
+	c.AddBoolFlag(SearchEvidenceFlag, true)
+
+	filters, err := ParseSearchFilters(c, XrayGatePlugins)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"testing", "frontend"}, filters.Tags)
+	assert.Equal(t, "cursor", filters.Harness)
+	assert.Equal(t, services.SkillXrayStatusApproved, filters.XrayStatus)
+	assert.Equal(t, SearchSortVersion, filters.SortBy)
+	assert.Equal(t, 5, filters.Limit)
+	assert.True(t, filters.HasEvidence)
+	assert.Equal(t, XrayGatePlugins, filters.XrayKind)
+	assert.True(t, filters.Active())
+}
+
+func TestParseSearchFilters_Errors(t *testing.T) {
+	tests := []struct {
+		flag    string
+		value   string
+		wantErr string
+	}{
+		{flag: SearchLimitFlag, value: "-1", wantErr: "--limit must be a non-negative integer"},
+		{flag: SearchOffsetFlag, value: "x", wantErr: "--offset must be a non-negative integer"},
+		{flag: SearchSortByFlag, value: "downloads", wantErr: "--sort-by accepts"},
+		{flag: SearchSortOrderFlag, value: "desc", wantErr: "--sort-order requires --sort-by"},
+		{flag: SearchMinVersionFlag, value: "1.x", wantErr: "invalid --min-version"},
+		{flag: SearchXrayFlag, value: "clean", wantErr: "--xray-status accepts"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.flag, func(t *testing.T) {
+			c := testutil.NewCLIContext()
+			c.AddStringFlag(tt.flag, tt.value)
+			_, err := ParseSearchFilters(c, XrayGateSkills)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
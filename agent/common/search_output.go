@@ -1,8 +1,11 @@
 package common
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
@@ -15,9 +18,19 @@ type SearchResultRow struct {
 	Version     string `json:"version" col-name:"Version"`
 	Repository  string `json:"repository" col-name:"Repository"`
 	Description string `json:"description" col-name:"Description"`
+	// Tags, Author and Harnesses are filled only when a search filter reads the metadata properties.
+	Tags      string `json:"tags,omitempty" col-name:"Tags" omitempty:"true"`
+	Author    string `json:"author,omitempty" col-name:"Author" omitempty:"true"`
+	Harnesses string `json:"harnesses,omitempty" col-name:"Harnesses" omitempty:"true"`
+	// Evidence and Xray are filled only when filtering by evidence or Xray status.
+	Evidence string `json:"evidence,omitempty" col-name:"Evidence" omitempty:"true"`
+	Xray     string `json:"xray,omitempty" col-name:"Xray" omitempty:"true"`
 }
 
-// PrintSearchResultsOptions configures table/json output for search commands.
+// searchCSVHeader names the columns of CSV search output, in SearchResultRow order.
+var searchCSVHeader = []string{"name", "version", "repository", "description", "tags", "author", "harnesses", "evidence", "xray"}
+
+// PrintSearchResultsOptions configures table/json/csv output for search commands.
 type PrintSearchResultsOptions struct {
 	Query           string
 	Format          string
@@ -26,7 +39,7 @@ type PrintSearchResultsOptions struct {
 	NotFoundMessage string
 }
 
-// PrintSearchResults prints search rows as a table or JSON.
+// PrintSearchResults prints search rows as a table, JSON or CSV.
 func PrintSearchResults(rows []SearchResultRow, opts PrintSearchResultsOptions) error {
 	if len(rows) == 0 {
 		log.Info(fmt.Sprintf(opts.NotFoundMessage, opts.Query))
@@ -40,5 +53,20 @@ func PrintSearchResults(rows []SearchResultRow, opts PrintSearchResultsOptions)
 		fmt.Println(string(data))
 		return nil
 	}
+	if strings.EqualFold(opts.Format, "csv") {
+		return writeSearchCSV(os.Stdout, rows)
+	}
 	return coreutils.PrintTable(rows, opts.TableTitle, opts.EmptyTableLabel, false)
 }
+
+func writeSearchCSV(out io.Writer, rows []SearchResultRow) error {
+	writer := csv.NewWriter(out)
+	records := [][]string{searchCSVHeader}
+	for _, row := range rows {
+		records = append(records, []string{row.Name, row.Version, row.Repository, row.Description, row.Tags, row.Author, row.Harnesses, row.Evidence, row.Xray})
+	}
+	if err := writer.WriteAll(records); err != nil {
+		return fmt.Errorf("failed to write CSV results: %w", err)
+	}
+	return nil
+}
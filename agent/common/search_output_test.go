@@ -53,3 +53,13 @@ func TestPrintSearchResults_JSON(t *testing.T) {
 	require.NoError(t, json.Unmarshal(buf.Bytes(), &parsed))
 	assert.Len(t, parsed, 1)
 }
+
+func TestWriteSearchCSV(t *testing.T) {
+	rows := []SearchResultRow{
+		{Name: "web", Version: "1.0.0", Repository: "r", Description: "search, fetch", Tags: "a,b", Xray: "APPROVED"},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, writeSearchCSV(&buf, rows))
+	assert.Equal(t, "name,version,repository,description,tags,author,harnesses,evidence,xray\n"+
+		"web,1.0.0,r,\"search, fetch\",\"a,b\",,,,APPROVED\n", buf.String())
+}
@@ -0,0 +1,259 @@
+package common
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// SecretsAllowlistFile is the package-relative file listing known false positives of the secret scan.
+// It lives under .jfrog, which publish never zips.
+//
+// Each non-comment line is a slash-separated path glob, optionally followed by ":<kind>" to silence only one
+// kind of finding, e.g.:
+//
+//	# test fixtures
+//	tests/fixtures/*.json
+//	docs/auth.md:jfrog-token
+//	examples/
+//
+// A glob ending in "/" matches every file under that directory.
+const SecretsAllowlistFile = ".jfrog/secrets-allowlist"
+
+// Secret kinds reported by the scan and accepted after ":" in SecretsAllowlistFile.
+const (
+	SecretKindJFrogToken  = "jfrog-token"
+	SecretKindAWSKey      = "aws-key"
+	SecretKindGCPKey      = "gcp-key"
+	SecretKindAzureKey    = "azure-key"
+	SecretKindGitHubToken = "github-token"
+	SecretKindSlackToken  = "slack-token"
+	SecretKindPrivateKey  = "private-key"
+	SecretKindDotenv      = "dotenv"
+	SecretKindHighEntropy = "high-entropy"
+)
+
+// secretScanChunkSize bounds how much of one line the scan holds in memory.
+const secretScanChunkSize = 1 << 20
+
+// secretRedactPrefixSize is how many leading characters of a finding are shown.
+const secretRedactPrefixSize = 4
+
+// minHighEntropyLength and minHighEntropyBits bound which assigned or quoted values are reported as
+// high-entropy strings: long enough to be a credential, and close to random base64 (6 bits per char).
+const (
+	minHighEntropyLength = 24
+	minHighEntropyBits   = 4.3
+)
+
+type secretPattern struct {
+	kind        string
+	description string
+	regex       *regexp.Regexp
+}
+
+// secretPatterns are matched against every line of every text file in a package.
+var secretPatterns = []secretPattern{
+	{SecretKindJFrogToken, "JFrog reference token", regexp.MustCompile(`\bcmVmdGtuOjAxOj[A-Za-z0-9+/=]{20,}`)},
+	{SecretKindJFrogToken, "JFrog API key", regexp.MustCompile(`\bAKCp[A-Za-z0-9]{60,}\b`)},
+	{SecretKindJFrogToken, "JFrog access token", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{SecretKindAWSKey, "AWS access key ID", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{SecretKindAWSKey, "AWS secret access key", regexp.MustCompile(`(?i)aws_?secret_?access_?key\s*[:=]\s*["']?[A-Za-z0-9/+=]{40}\b`)},
+	{SecretKindGCPKey, "Google Cloud API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{SecretKindGCPKey, "Google Cloud service account key", regexp.MustCompile(`"type"\s*:\s*"service_account"`)},
+	{SecretKindAzureKey, "Azure storage account key", regexp.MustCompile(`AccountKey=[A-Za-z0-9+/=]{80,}`)},
+	{SecretKindGitHubToken, "GitHub token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{60,})\b`)},
+	{SecretKindSlackToken, "Slack token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{SecretKindPrivateKey, "private key", regexp.MustCompile(`-----BEGIN (?:[A-Z]+ )?PRIVATE KEY(?: BLOCK)?-----`)},
+}
+
+// secretCandidateRegex captures values assigned with ':' or '=' or quoted, the usual shape of hardcoded credentials.
+var secretCandidateRegex = regexp.MustCompile(`(?:[:=]\s*|["'])([A-Za-z0-9+/_=-]{24,})`)
+
+// dotenvAssignmentRegex matches a KEY=value line of a dotenv file with a non-empty value.
+var dotenvAssignmentRegex = regexp.MustCompile(`^\s*(?:export\s+)?([A-Za-z_][A-Za-z0-9_]*)\s*=\s*(\S.*)$`)
+
+// dotenvExampleSuffixes mark dotenv files that document variables instead of holding them.
+var dotenvExampleSuffixes = []string{".example", ".sample", ".template", ".dist"}
+
+type secretsAllowlistEntry struct {
+	glob string
+	kind string
+}
+
+type secretsAllowlist []secretsAllowlistEntry
+
+// loadSecretsAllowlist reads SecretsAllowlistFile under sourceDir. A missing file is an empty allowlist.
+func loadSecretsAllowlist(sourceDir string) (secretsAllowlist, error) {
+	// #nosec G304 -- path is a fixed name under the user-provided package directory.
+	data, err := os.ReadFile(filepath.Join(sourceDir, filepath.FromSlash(SecretsAllowlistFile)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", SecretsAllowlistFile, err)
+	}
+	var allowlist secretsAllowlist
+	for lineNo, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		glob, kind, _ := strings.Cut(line, ":")
+		glob = strings.TrimPrefix(strings.TrimSpace(glob), "./")
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid pattern %q: %w", SecretsAllowlistFile, lineNo+1, glob, err)
+		}
+		allowlist = append(allowlist, secretsAllowlistEntry{glob: glob, kind: strings.TrimSpace(kind)})
+	}
+	return allowlist, nil
+}
+
+func (a secretsAllowlist) allows(relPath, kind string) bool {
+	for _, entry := range a {
+		if entry.kind != "" && entry.kind != kind {
+			continue
+		}
+		if strings.HasSuffix(entry.glob, "/") {
+			if strings.HasPrefix(relPath, entry.glob) {
+				return true
+			}
+			continue
+		}
+		if matched, _ := path.Match(entry.glob, relPath); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// scanSecrets reports likely credentials in relPath as ValidationRuleSecret errors, except those silenced by
+// allowlist. content is streamed line by line; lines longer than secretScanChunkSize are scanned in chunks.
+func scanSecrets(report *ValidationReport, allowlist secretsAllowlist, relPath string, content io.Reader) error {
+	dotenv := isDotenvFile(relPath)
+	reader := bufio.NewReaderSize(content, secretScanChunkSize)
+	for lineNo := 1; ; {
+		chunk, isPrefix, err := reader.ReadLine()
+		for _, finding := range secretsInLine(string(chunk), dotenv) {
+			if allowlist.allows(relPath, finding.kind) {
+				continue
+			}
+			report.Issues = append(report.Issues, ValidationIssue{
+				Rule:     ValidationRuleSecret,
+				Severity: ValidationSeverityError,
+				File:     relPath,
+				Line:     lineNo,
+				Message:  fmt.Sprintf("possible %s (%s, %s); remove it or add %q to %s", finding.description, finding.kind, redactSecret(finding.value), relPath+":"+finding.kind, SecretsAllowlistFile),
+			})
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !isPrefix {
+			lineNo++
+		}
+	}
+}
+
+type secretMatch struct {
+	kind        string
+	description string
+	value       string
+}
+
+// secretsInLine returns at most one finding per kind in line; known patterns take precedence over entropy.
+func secretsInLine(line string, dotenv bool) []secretMatch {
+	var matches []secretMatch
+	found := map[string]bool{}
+	for _, pattern := range secretPatterns {
+		if found[pattern.kind] {
+			continue
+		}
+		if value := pattern.regex.FindString(line); value != "" {
+			matches = append(matches, secretMatch{kind: pattern.kind, description: pattern.description, value: value})
+			found[pattern.kind] = true
+		}
+	}
+	if dotenv {
+		if assignment := dotenvAssignmentRegex.FindStringSubmatch(line); assignment != nil {
+			matches = append(matches, secretMatch{kind: SecretKindDotenv, description: "value of " + assignment[1] + " in a dotenv file", value: assignment[2]})
+			return matches
+		}
+	}
+	if len(matches) > 0 {
+		return matches
+	}
+	for _, candidate := range secretCandidateRegex.FindAllStringSubmatch(line, -1) {
+		if isHighEntropySecret(candidate[1]) {
+			return append(matches, secretMatch{kind: SecretKindHighEntropy, description: "high-entropy string", value: candidate[1]})
+		}
+	}
+	return matches
+}
+
+func isDotenvFile(relPath string) bool {
+	name := path.Base(relPath)
+	if name != ".env" && !strings.HasPrefix(name, ".env.") {
+		return false
+	}
+	for _, suffix := range dotenvExampleSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+// isHighEntropySecret reports whether value mixes upper case, lower case and digits and is close to random.
+// Paths and identifiers rarely pass both checks.
+func isHighEntropySecret(value string) bool {
+	if len(value) < minHighEntropyLength || strings.Count(value, "/") > 2 {
+		return false
+	}
+	var upper, lower, digit bool
+	for _, c := range value {
+		switch {
+		case c >= 'A' && c <= 'Z':
+			upper = true
+		case c >= 'a' && c <= 'z':
+			lower = true
+		case c >= '0' && c <= '9':
+			digit = true
+		}
+	}
+	return upper && lower && digit && shannonEntropy(value) >= minHighEntropyBits
+}
+
+// shannonEntropy returns the bits of entropy per character of s.
+func shannonEntropy(s string) float64 {
+	counts := map[rune]int{}
+	for _, c := range s {
+		counts[c]++
+	}
+	length := float64(len(s))
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / length
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// redactSecret keeps only enough of value to locate it.
+func redactSecret(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) <= secretRedactPrefixSize*2 {
+		return strings.Repeat("*", len(value))
+	}
+	return value[:secretRedactPrefixSize] + strings.Repeat("*", 8)
+}
@@ -0,0 +1,116 @@
+package common
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test secrets are assembled at run time so this file does not itself look like it leaks credentials.
+var (
+	testAWSKeyID      = "AKIA" + "IOSFODNN7EXAMPLE"
+	testJFrogRefToken = "cmVmdGtuOjAxOj" + "E3OTk5NjQ4MDA6dGVzdHRva2VuZm9ydW5pdHRlc3Rz"
+	testPrivateKey    = "-----BEGIN RSA " + "PRIVATE KEY-----"
+	testRandomSecret  = "q7Xv2LmP9sK4" + "tR8wZ3nB6yC1"
+)
+
+func secretIssues(report ValidationReport) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, issue := range report.Issues {
+		if issue.Rule == ValidationRuleSecret {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+func TestValidatePackageFiles_Secrets(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "SKILL.md"), strings.Join([]string{
+		"# Deploy",
+		"Use the token " + testJFrogRefToken + " to log in.",
+		"Checksum: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+	}, "\n"))
+	writeTestFile(t, filepath.Join(dir, "scripts", "deploy.sh"), "export AWS_ACCESS_KEY_ID="+testAWSKeyID+"\n")
+	writeTestFile(t, filepath.Join(dir, "config.yaml"), "name: deploy\napi_token: \""+testRandomSecret+"\"\n")
+	writeTestFile(t, filepath.Join(dir, "keys", "id_rsa"), testPrivateKey+"\n")
+	writeTestFile(t, filepath.Join(dir, ".env"), "# local\nAPI_URL=https://example.com\nEMPTY=\n")
+	writeTestFile(t, filepath.Join(dir, ".env.example"), "API_KEY=changeme\n")
+
+	report := NewValidationReport("skill", dir)
+	require.NoError(t, ValidatePackageFiles(&report, dir))
+
+	locations := map[string]string{}
+	for _, issue := range secretIssues(report) {
+		assert.Equal(t, ValidationSeverityError, issue.Severity)
+		assert.NotContains(t, issue.Message, testAWSKeyID, "findings are redacted")
+		locations[issue.Location()] = issue.Message
+	}
+	assert.Len(t, locations, 5)
+	assert.Contains(t, locations[".env:2"], "(dotenv,")
+	assert.Contains(t, locations["SKILL.md:2"], "JFrog reference token")
+	assert.Contains(t, locations["config.yaml:2"], "(high-entropy,")
+	assert.Contains(t, locations["keys/id_rsa:1"], "private key")
+	assert.Contains(t, locations["scripts/deploy.sh:1"], "AWS access key ID (aws-key, AKIA********)")
+}
+
+func TestValidatePackageFiles_SecretsAllowlist(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "SKILL.md"), "Token: "+testJFrogRefToken+"\nKey: "+testAWSKeyID+"\n")
+	writeTestFile(t, filepath.Join(dir, "fixtures", "keys.txt"), testPrivateKey+"\n")
+	writeTestFile(t, filepath.Join(dir, filepath.FromSlash(SecretsAllowlistFile)), strings.Join([]string{
+		"# known false positives",
+		"fixtures/",
+		"./SKILL.md:jfrog-token",
+	}, "\n"))
+
+	report := NewValidationReport("skill", dir)
+	require.NoError(t, ValidatePackageFiles(&report, dir))
+	issues := secretIssues(report)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "SKILL.md:2", issues[0].Location())
+
+	report.Downgrade(ValidationRuleSecret)
+	assert.NoError(t, CheckPublishValidation("Skill", report))
+}
+
+func TestValidatePackageFiles_SecretsInOversizedAndBinaryFiles(t *testing.T) {
+	restore := maxPublishFileSize
+	t.Cleanup(func() { maxPublishFileSize = restore })
+	maxPublishFileSize = 64
+
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "notes.txt"), strings.Repeat("filler line\n", 10)+"key: "+testAWSKeyID+"\n")
+	writeTestFile(t, filepath.Join(dir, "tool.bin"), "\x00\x01"+testPrivateKey+"\x00\n")
+	// A line longer than one scan chunk is scanned in pieces and still counts as one line.
+	writeTestFile(t, filepath.Join(dir, "data.json"), strings.Repeat("a", secretScanChunkSize+10)+"\n"+testJFrogRefToken+"\n")
+
+	report := NewValidationReport("skill", dir)
+	require.NoError(t, ValidatePackageFiles(&report, dir))
+
+	locations := map[string]string{}
+	for _, issue := range secretIssues(report) {
+		locations[issue.Location()] = issue.Message
+	}
+	assert.Contains(t, locations["notes.txt:11"], "AWS access key ID")
+	assert.Contains(t, locations["tool.bin:1"], "private key")
+	assert.Contains(t, locations["data.json:2"], "JFrog reference token")
+	rules := map[string]string{}
+	for _, issue := range report.Issues {
+		if issue.Rule != ValidationRuleSecret {
+			rules[issue.File] = issue.Rule
+		}
+	}
+	assert.Equal(t, ValidationRuleFileSize, rules["notes.txt"])
+	assert.Equal(t, ValidationRuleBinaryFile, rules["tool.bin"])
+}
+
+func TestIsHighEntropySecret(t *testing.T) {
+	assert.True(t, isHighEntropySecret(testRandomSecret))
+	assert.False(t, isHighEntropySecret("ThisIsAVeryLongIdentifierName1"), "readable identifiers repeat characters")
+	assert.False(t, isHighEntropySecret("9f86d081884c7d659a2feaa0c55ad015"), "hex digests have no upper case")
+	assert.False(t, isHighEntropySecret("Short1x"))
+}
@@ -17,32 +17,41 @@ type SelectPackageVersionOpts struct {
 	Requested string
 	RepoKey   string
 	Quiet     bool
+	// Slug and Deprecated exclude the deprecated versions of Slug from latest, range, and prompt resolution.
+	// An exact request still resolves to a deprecated version.
+	Slug       string
+	Deprecated DeprecatedVersions
 }
 
-// SelectPackageVersion resolves "" / "latest" / exact match / interactive prompt for install and update.
+// SelectPackageVersion resolves "" / "latest" / exact match / version range / interactive prompt for install and update.
+// A range (e.g. "^1.2", "~1.4.0", ">=2 <3", "1.x") picks the greatest available version that satisfies it.
 func SelectPackageVersion(opts SelectPackageVersionOpts) (string, error) {
 	requested := strings.TrimSpace(opts.Requested)
+	active := opts.Deprecated.Active(opts.Slug, opts.Available)
 	if isLatestVersionRequest(requested) {
-		return selectLatestPackageVersion(opts.Available)
+		return selectLatestPackageVersion(opts.Slug, opts.Available, opts.Deprecated)
 	}
 	if version, found := findPackageVersion(opts.Available, requested); found {
 		return version, nil
 	}
+	if IsVersionRange(requested) {
+		return selectPackageVersionInRange(active, requested, opts.RepoKey)
+	}
 	if opts.Quiet || IsNonInteractive() {
 		return "", fmt.Errorf(
 			"version '%s' not found in repository '%s'.\nAvailable versions: %s",
-			requested, opts.RepoKey, strings.Join(opts.Available, ", "),
+			requested, opts.RepoKey, strings.Join(active, ", "),
 		)
 	}
-	return promptPackageVersionFromList(requested, opts.Available), nil
+	return promptPackageVersionFromList(requested, active), nil
 }
 
 func isLatestVersionRequest(requested string) bool {
 	return requested == "" || requested == latestVersionKeyword
 }
 
-func selectLatestPackageVersion(available []string) (string, error) {
-	latest, err := LatestVersion(available)
+func selectLatestPackageVersion(slug string, available []string, deprecated DeprecatedVersions) (string, error) {
+	latest, err := LatestActiveVersion(slug, available, deprecated)
 	if err != nil {
 		return "", fmt.Errorf("failed to determine latest version: %w", err)
 	}
@@ -50,6 +59,22 @@ func selectLatestPackageVersion(available []string) (string, error) {
 	return latest, nil
 }
 
+func selectPackageVersionInRange(available []string, requested, repoKey string) (string, error) {
+	constraint, err := ParseVersionConstraint(requested)
+	if err != nil {
+		return "", err
+	}
+	version, found := constraint.MaxSatisfying(available)
+	if !found {
+		return "", fmt.Errorf(
+			"no version in repository '%s' satisfies '%s'.\nAvailable versions: %s",
+			repoKey, requested, strings.Join(available, ", "),
+		)
+	}
+	log.Info(fmt.Sprintf("Using version %s (satisfies %s)", version, requested))
+	return version, nil
+}
+
 func findPackageVersion(available []string, requested string) (string, bool) {
 	for _, version := range available {
 		if version == requested {
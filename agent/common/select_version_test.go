@@ -76,3 +76,25 @@ func TestIsLatestVersionRequest(t *testing.T) {
 	assert.True(t, isLatestVersionRequest("latest"))
 	assert.False(t, isLatestVersionRequest("1.0.0"))
 }
+
+func TestSelectPackageVersion_RangePicksHighestSatisfying(t *testing.T) {
+	got, err := SelectPackageVersion(SelectPackageVersionOpts{
+		Available: []string{"1.2.0", "1.4.2", "1.9.0", "2.0.0", "2.1.0-beta.1"},
+		Requested: "^1.2",
+		RepoKey:   "skills-local",
+		Quiet:     true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "1.9.0", got)
+}
+
+func TestSelectPackageVersion_RangeWithoutMatch(t *testing.T) {
+	_, err := SelectPackageVersion(SelectPackageVersionOpts{
+		Available: []string{"1.0.0", "2.0.0"},
+		Requested: ">=3",
+		RepoKey:   "skills-local",
+		Quiet:     true,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "satisfies '>=3'")
+}
@@ -1,6 +1,7 @@
 package common
 
 import (
+	"cmp"
 	"fmt"
 	"sort"
 	"strconv"
@@ -11,7 +12,9 @@ type semverParts struct {
 	Major int
 	Minor int
 	Patch int
-	Raw   string
+	// Prerelease holds the dot-separated identifiers after '-' (e.g. ["rc", "1"] for 1.2.3-rc.1).
+	Prerelease []string
+	Raw        string
 }
 
 // LatestVersion returns the greatest semver from a list of version strings.
@@ -33,14 +36,8 @@ func LatestVersion(versions []string) (string, error) {
 		return "", fmt.Errorf("no valid semver versions found")
 	}
 
-	sort.Slice(parsed, func(i, j int) bool {
-		if parsed[i].Major != parsed[j].Major {
-			return parsed[i].Major < parsed[j].Major
-		}
-		if parsed[i].Minor != parsed[j].Minor {
-			return parsed[i].Minor < parsed[j].Minor
-		}
-		return parsed[i].Patch < parsed[j].Patch
+	sort.SliceStable(parsed, func(i, j int) bool {
+		return compareSemverParts(parsed[i], parsed[j]) < 0
 	})
 
 	return parsed[len(parsed)-1].Raw, nil
@@ -58,16 +55,55 @@ func CompareSemver(firstVersion, secondVersion string) (int, error) {
 	if err != nil {
 		return 0, err
 	}
-	if firstVersionParts.Major != secondVersionParts.Major {
-		return firstVersionParts.Major - secondVersionParts.Major, nil
+	return compareSemverParts(firstVersionParts, secondVersionParts), nil
+}
+
+// compareSemverParts orders versions by semver 2.0 precedence: major, minor, patch, then
+// pre-release. A version without a pre-release is greater than the same version with one
+// (1.0.0-rc.1 < 1.0.0). Build metadata is ignored.
+func compareSemverParts(first, second semverParts) int {
+	if first.Major != second.Major {
+		return cmp.Compare(first.Major, second.Major)
 	}
-	if firstVersionParts.Minor != secondVersionParts.Minor {
-		return firstVersionParts.Minor - secondVersionParts.Minor, nil
+	if first.Minor != second.Minor {
+		return cmp.Compare(first.Minor, second.Minor)
 	}
-	if firstVersionParts.Patch != secondVersionParts.Patch {
-		return firstVersionParts.Patch - secondVersionParts.Patch, nil
+	if first.Patch != second.Patch {
+		return cmp.Compare(first.Patch, second.Patch)
 	}
-	return 0, nil
+	return comparePrerelease(first.Prerelease, second.Prerelease)
+}
+
+// comparePrerelease compares identifiers left to right: numeric identifiers compare numerically
+// and sort before alphanumeric ones; a shorter list that is a prefix of a longer one sorts first.
+func comparePrerelease(first, second []string) int {
+	switch {
+	case len(first) == 0 && len(second) == 0:
+		return 0
+	case len(first) == 0:
+		return 1
+	case len(second) == 0:
+		return -1
+	}
+	for i := 0; i < len(first) && i < len(second); i++ {
+		firstNumber, firstErr := strconv.Atoi(first[i])
+		secondNumber, secondErr := strconv.Atoi(second[i])
+		switch {
+		case firstErr == nil && secondErr == nil:
+			if firstNumber != secondNumber {
+				return cmp.Compare(firstNumber, secondNumber)
+			}
+		case firstErr == nil:
+			return -1
+		case secondErr == nil:
+			return 1
+		default:
+			if order := strings.Compare(first[i], second[i]); order != 0 {
+				return order
+			}
+		}
+	}
+	return cmp.Compare(len(first), len(second))
 }
 
 // NextMinorVersion takes a semver string and returns the next minor version
@@ -112,13 +148,17 @@ func parseSemver(version string) (semverParts, error) {
 		return semverParts{}, fmt.Errorf("invalid version %q: minor must be a number (got %q)", version, versionSegments[1])
 	}
 
-	// Patch may contain pre-release or build metadata; take numeric part only for comparison
-	patchSegment := strings.SplitN(versionSegments[2], "-", 2)[0]
-	patchSegment = strings.SplitN(patchSegment, "+", 2)[0]
+	// Patch may carry pre-release identifiers and build metadata: 3-rc.1+build.5
+	patchSegment := strings.SplitN(versionSegments[2], "+", 2)[0]
+	patchSegment, prerelease, hasPrerelease := strings.Cut(patchSegment, "-")
 	patch, err := strconv.Atoi(patchSegment)
 	if err != nil {
 		return semverParts{}, fmt.Errorf("invalid version %q: patch must be a number (got %q)", version, patchSegment)
 	}
 
-	return semverParts{Major: major, Minor: minor, Patch: patch, Raw: version}, nil
+	parts := semverParts{Major: major, Minor: minor, Patch: patch, Raw: version}
+	if hasPrerelease && prerelease != "" {
+		parts.Prerelease = strings.Split(prerelease, ".")
+	}
+	return parts, nil
 }
@@ -0,0 +1,444 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// VersionConstraint is an npm-style version range such as "^1.2", "~1.4.0", ">=2 <3", "1.x",
+// "1.2.0 - 1.4", or a union of those joined with "||".
+//
+// Pre-release versions follow npm rules: 1.3.0-rc.1 satisfies a range only when one of the
+// range's comparators names a pre-release of the same major.minor.patch (e.g. ">=1.3.0-rc.0"),
+// so "^1.2.0" never picks up an unreleased 1.3.0-rc.1.
+type VersionConstraint struct {
+	raw  string
+	sets [][]versionComparator
+}
+
+type versionComparator struct {
+	operator string // one of "<", "<=", ">", ">=", "="
+	version  semverParts
+}
+
+// IsVersionRange reports whether requested is a range rather than "", "latest", or an exact version.
+func IsVersionRange(requested string) bool {
+	requested = strings.TrimSpace(requested)
+	if isLatestVersionRequest(requested) {
+		return false
+	}
+	return ValidateSemver(requested) != nil
+}
+
+// ValidateVersionRequest accepts "", "latest", an exact semantic version, or a version range.
+func ValidateVersionRequest(requested string) error {
+	requested = strings.TrimSpace(requested)
+	if isLatestVersionRequest(requested) {
+		return nil
+	}
+	if !IsVersionRange(requested) {
+		return ValidateSemver(requested)
+	}
+	_, err := ParseVersionConstraint(requested)
+	return err
+}
+
+// VersionMatchesRequest reports whether an exact version satisfies a --version request:
+// "" and "latest" match anything, exact versions must be equal, and ranges must be satisfied.
+func VersionMatchesRequest(version, requested string) bool {
+	requested = strings.TrimSpace(requested)
+	switch {
+	case isLatestVersionRequest(requested):
+		return true
+	case !IsVersionRange(requested):
+		return version == requested
+	}
+	constraint, err := ParseVersionConstraint(requested)
+	return err == nil && constraint.Matches(version)
+}
+
+// ParseVersionConstraint parses an npm-style range.
+func ParseVersionConstraint(raw string) (*VersionConstraint, error) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return nil, fmt.Errorf("version range must not be empty")
+	}
+	constraint := &VersionConstraint{raw: trimmed}
+	for _, alternative := range strings.Split(trimmed, "||") {
+		set, err := parseComparatorSet(strings.TrimSpace(alternative))
+		if err != nil {
+			return nil, fmt.Errorf("invalid version range %q: %w", trimmed, err)
+		}
+		constraint.sets = append(constraint.sets, set)
+	}
+	return constraint, nil
+}
+
+// String returns the range as written.
+func (c *VersionConstraint) String() string {
+	return c.raw
+}
+
+// Matches reports whether version satisfies the range. Unparseable versions never match.
+func (c *VersionConstraint) Matches(version string) bool {
+	parts, err := parseSemver(strings.TrimSpace(version))
+	if err != nil {
+		return false
+	}
+	for _, set := range c.sets {
+		if setMatches(set, parts) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxSatisfying returns the greatest version in available that satisfies the range.
+func (c *VersionConstraint) MaxSatisfying(available []string) (string, bool) {
+	var best *semverParts
+	for _, version := range available {
+		if !c.Matches(version) {
+			continue
+		}
+		parts, _ := parseSemver(strings.TrimSpace(version))
+		if best == nil || compareSemverParts(parts, *best) > 0 {
+			best = &parts
+		}
+	}
+	if best == nil {
+		return "", false
+	}
+	return best.Raw, true
+}
+
+func setMatches(set []versionComparator, version semverParts) bool {
+	for _, comparator := range set {
+		if !comparator.matches(version) {
+			return false
+		}
+	}
+	if len(version.Prerelease) == 0 {
+		return true
+	}
+	for _, comparator := range set {
+		bound := comparator.version
+		if len(bound.Prerelease) > 0 && bound.Major == version.Major && bound.Minor == version.Minor && bound.Patch == version.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+func (vc versionComparator) matches(version semverParts) bool {
+	order := compareSemverParts(version, vc.version)
+	switch vc.operator {
+	case "<":
+		return order < 0
+	case "<=":
+		return order <= 0
+	case ">":
+		return order > 0
+	case ">=":
+		return order >= 0
+	default:
+		return order == 0
+	}
+}
+
+// parseComparatorSet parses one "||" alternative: a hyphen range or space-separated comparators.
+func parseComparatorSet(text string) ([]versionComparator, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty comparator set")
+	}
+	if len(fields) == 3 && fields[1] == "-" {
+		return parseHyphenRange(fields[0], fields[2])
+	}
+	var set []versionComparator
+	for i := 0; i < len(fields); i++ {
+		token := fields[i]
+		// Allow a space between an operator and its version: ">= 1.2.0".
+		if isComparatorOperator(token) {
+			if i+1 >= len(fields) {
+				return nil, fmt.Errorf("operator %q has no version", token)
+			}
+			i++
+			token += fields[i]
+		}
+		comparators, err := parseComparator(token)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, comparators...)
+	}
+	return set, nil
+}
+
+func isComparatorOperator(token string) bool {
+	switch token {
+	case "<", "<=", ">", ">=", "=", "^", "~":
+		return true
+	}
+	return false
+}
+
+func parseHyphenRange(lowText, highText string) ([]versionComparator, error) {
+	low, err := parsePartialVersion(lowText)
+	if err != nil {
+		return nil, err
+	}
+	high, err := parsePartialVersion(highText)
+	if err != nil {
+		return nil, err
+	}
+	var set []versionComparator
+	if !low.anyMajor() {
+		set = append(set, versionComparator{operator: ">=", version: low.floor()})
+	}
+	switch {
+	case high.anyMajor():
+	case high.exact():
+		set = append(set, versionComparator{operator: "<=", version: high.floor()})
+	default:
+		set = append(set, versionComparator{operator: "<", version: high.nextUnspecified()})
+	}
+	return anyVersionIfEmpty(set), nil
+}
+
+func parseComparator(token string) ([]versionComparator, error) {
+	operator := ""
+	for _, candidate := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(token, candidate) {
+			operator = candidate
+			break
+		}
+	}
+	partial, err := parsePartialVersion(strings.TrimPrefix(token, operator))
+	if err != nil {
+		return nil, err
+	}
+	switch operator {
+	case "^":
+		return caretRange(partial), nil
+	case "~":
+		return tildeRange(partial), nil
+	case "", "=":
+		return xRange(partial), nil
+	case ">":
+		if partial.anyMajor() {
+			// Nothing is greater than every version.
+			return []versionComparator{{operator: "<", version: semverParts{Prerelease: []string{"0"}}}}, nil
+		}
+		if partial.exact() {
+			return []versionComparator{{operator: ">", version: partial.floor()}}, nil
+		}
+		lower := partial.nextUnspecified()
+		lower.Prerelease = nil
+		return []versionComparator{{operator: ">=", version: lower}}, nil
+	case ">=":
+		return anyVersionIfEmpty(lowerBound(partial)), nil
+	case "<":
+		if partial.anyMajor() {
+			return []versionComparator{{operator: "<", version: semverParts{Prerelease: []string{"0"}}}}, nil
+		}
+		floor := partial.floor()
+		if !partial.exact() {
+			floor.Prerelease = []string{"0"}
+		}
+		return []versionComparator{{operator: "<", version: floor}}, nil
+	default: // "<="
+		if partial.anyMajor() {
+			return anyVersionIfEmpty(nil), nil
+		}
+		if partial.exact() {
+			return []versionComparator{{operator: "<=", version: partial.floor()}}, nil
+		}
+		return []versionComparator{{operator: "<", version: partial.nextUnspecified()}}, nil
+	}
+}
+
+// caretRange allows changes that do not modify the left-most non-zero part: ^1.2.3 := >=1.2.3 <2.0.0-0,
+// ^0.2.3 := >=0.2.3 <0.3.0-0, ^0.0.3 := >=0.0.3 <0.0.4-0.
+func caretRange(partial partialVersion) []versionComparator {
+	if partial.anyMajor() {
+		return anyVersionIfEmpty(nil)
+	}
+	upper := semverParts{Major: partial.major + 1}
+	switch {
+	case partial.major > 0 || partial.minor == nil:
+	case *partial.minor > 0 || partial.patch == nil:
+		upper = semverParts{Minor: *partial.minor + 1}
+	default:
+		upper = semverParts{Patch: *partial.patch + 1}
+	}
+	upper.Prerelease = []string{"0"}
+	return append(lowerBound(partial), versionComparator{operator: "<", version: upper})
+}
+
+// tildeRange allows patch-level changes when a minor is given: ~1.2.3 := >=1.2.3 <1.3.0-0, ~1 := >=1.0.0 <2.0.0-0.
+func tildeRange(partial partialVersion) []versionComparator {
+	if partial.anyMajor() {
+		return anyVersionIfEmpty(nil)
+	}
+	upper := semverParts{Major: partial.major + 1, Prerelease: []string{"0"}}
+	if partial.minor != nil {
+		upper = semverParts{Major: partial.major, Minor: *partial.minor + 1, Prerelease: []string{"0"}}
+	}
+	return append(lowerBound(partial), versionComparator{operator: "<", version: upper})
+}
+
+// xRange handles bare and "=" versions: 1.2.3 is exact, 1.2 / 1.2.x := >=1.2.0 <1.3.0-0, * matches anything.
+func xRange(partial partialVersion) []versionComparator {
+	if partial.anyMajor() {
+		return anyVersionIfEmpty(nil)
+	}
+	if partial.exact() {
+		return []versionComparator{{operator: "=", version: partial.floor()}}
+	}
+	return append(lowerBound(partial), versionComparator{operator: "<", version: partial.nextUnspecified()})
+}
+
+func lowerBound(partial partialVersion) []versionComparator {
+	if partial.anyMajor() {
+		return nil
+	}
+	return []versionComparator{{operator: ">=", version: partial.floor()}}
+}
+
+// anyVersionIfEmpty returns a comparator matching every release so an empty set never matches pre-releases by accident.
+func anyVersionIfEmpty(set []versionComparator) []versionComparator {
+	if len(set) > 0 {
+		return set
+	}
+	return []versionComparator{{operator: ">=", version: semverParts{}}}
+}
+
+// partialVersion is a version with optional minor and patch; nil parts were omitted or written as x, X, or *.
+type partialVersion struct {
+	major      int
+	majorAny   bool
+	minor      *int
+	patch      *int
+	prerelease []string
+}
+
+func parsePartialVersion(text string) (partialVersion, error) {
+	text = strings.TrimPrefix(strings.TrimSpace(text), "v")
+	text = strings.SplitN(text, "+", 2)[0]
+	core, prerelease, hasPrerelease := strings.Cut(text, "-")
+	segments := strings.Split(core, ".")
+	if core == "" || len(segments) > 3 {
+		return partialVersion{}, fmt.Errorf("invalid version %q", text)
+	}
+	numbers := make([]*int, 3)
+	wildcard := false
+	for i, segment := range segments {
+		if segment == "x" || segment == "X" || segment == "*" {
+			wildcard = true
+			continue
+		}
+		if wildcard {
+			return partialVersion{}, fmt.Errorf("invalid version %q: a wildcard must not be followed by a number", text)
+		}
+		number, err := strconv.Atoi(segment)
+		if err != nil || number < 0 {
+			return partialVersion{}, fmt.Errorf("invalid version %q: %q is not a number or wildcard", text, segment)
+		}
+		numbers[i] = &number
+	}
+	partial := partialVersion{minor: numbers[1], patch: numbers[2]}
+	if numbers[0] == nil {
+		partial.majorAny = true
+	} else {
+		partial.major = *numbers[0]
+	}
+	if hasPrerelease {
+		if partial.patch == nil {
+			return partialVersion{}, fmt.Errorf("invalid version %q: a pre-release needs major.minor.patch", text)
+		}
+		partial.prerelease = strings.Split(prerelease, ".")
+	}
+	return partial, nil
+}
+
+func (p partialVersion) anyMajor() bool {
+	return p.majorAny
+}
+
+func (p partialVersion) exact() bool {
+	return !p.majorAny && p.minor != nil && p.patch != nil
+}
+
+// floor fills omitted parts with zero: 1.2 -> 1.2.0.
+func (p partialVersion) floor() semverParts {
+	parts := semverParts{Major: p.major, Prerelease: p.prerelease}
+	if p.minor != nil {
+		parts.Minor = *p.minor
+	}
+	if p.patch != nil {
+		parts.Patch = *p.patch
+	}
+	return parts
+}
+
+// nextUnspecified bumps the last given part: 1 -> 2.0.0-0, 1.2 -> 1.3.0-0.
+func (p partialVersion) nextUnspecified() semverParts {
+	if p.minor == nil {
+		return semverParts{Major: p.major + 1, Prerelease: []string{"0"}}
+	}
+	return semverParts{Major: p.major, Minor: *p.minor + 1, Prerelease: []string{"0"}}
+}
+
+// IntersectVersionRequests returns a request satisfied by exactly the versions that satisfy both first and second,
+// so a package required by several dependents can be resolved once for all of them. Two different exact versions,
+// or an exact version outside the other range, have no intersection and return an error.
+func IntersectVersionRequests(first, second string) (string, error) {
+	first, second = strings.TrimSpace(first), strings.TrimSpace(second)
+	switch {
+	case isLatestVersionRequest(first):
+		return second, nil
+	case isLatestVersionRequest(second), first == second:
+		return first, nil
+	case !IsVersionRange(first):
+		return exactVersionWithin(first, second)
+	case !IsVersionRange(second):
+		return exactVersionWithin(second, first)
+	}
+	firstConstraint, err := ParseVersionConstraint(first)
+	if err != nil {
+		return "", err
+	}
+	secondConstraint, err := ParseVersionConstraint(second)
+	if err != nil {
+		return "", err
+	}
+	// (a || b) && (c || d) is (a c) || (a d) || (b c) || (b d); comparators in a set are ANDed.
+	var alternatives []string
+	for _, firstSet := range firstConstraint.sets {
+		for _, secondSet := range secondConstraint.sets {
+			comparators := make([]string, 0, len(firstSet)+len(secondSet))
+			for _, comparator := range append(append([]versionComparator{}, firstSet...), secondSet...) {
+				comparators = append(comparators, comparator.String())
+			}
+			alternatives = append(alternatives, strings.Join(comparators, " "))
+		}
+	}
+	return strings.Join(alternatives, " || "), nil
+}
+
+func exactVersionWithin(version, requested string) (string, error) {
+	if !VersionMatchesRequest(version, requested) {
+		return "", fmt.Errorf("no version satisfies both %s and %s", version, requested)
+	}
+	return version, nil
+}
+
+// String renders the comparator so that parseComparator reads it back unchanged, e.g. ">=1.2.0" or "<2.0.0-0".
+func (vc versionComparator) String() string {
+	version := fmt.Sprintf("%d.%d.%d", vc.version.Major, vc.version.Minor, vc.version.Patch)
+	if len(vc.version.Prerelease) > 0 {
+		version += "-" + strings.Join(vc.version.Prerelease, ".")
+	}
+	return vc.operator + version
+}
@@ -0,0 +1,120 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionConstraint_Matches(t *testing.T) {
+	tests := []struct {
+		constraint string
+		matches    []string
+		rejects    []string
+	}{
+		{constraint: "^1.2", matches: []string{"1.2.0", "1.9.9"}, rejects: []string{"1.1.9", "2.0.0", "2.0.0-rc.1"}},
+		{constraint: "^0.2.3", matches: []string{"0.2.3", "0.2.9"}, rejects: []string{"0.3.0", "0.2.2"}},
+		{constraint: "^0.0.3", matches: []string{"0.0.3"}, rejects: []string{"0.0.4"}},
+		{constraint: "~1.4.0", matches: []string{"1.4.0", "1.4.7"}, rejects: []string{"1.5.0", "1.3.9"}},
+		{constraint: "~1", matches: []string{"1.0.0", "1.9.0"}, rejects: []string{"2.0.0"}},
+		{constraint: ">=2 <3", matches: []string{"2.0.0", "2.99.0"}, rejects: []string{"1.9.9", "3.0.0", "3.0.0-alpha"}},
+		{constraint: ">= 2.1.0", matches: []string{"2.1.0", "10.0.0"}, rejects: []string{"2.0.9"}},
+		{constraint: "1.x", matches: []string{"1.0.0", "1.99.1"}, rejects: []string{"0.9.0", "2.0.0"}},
+		{constraint: "1.2.*", matches: []string{"1.2.0", "1.2.5"}, rejects: []string{"1.3.0"}},
+		{constraint: "*", matches: []string{"0.0.1", "5.0.0"}, rejects: []string{"5.0.0-beta"}},
+		{constraint: "1.2.3 - 2.3", matches: []string{"1.2.3", "2.3.9"}, rejects: []string{"1.2.2", "2.4.0"}},
+		{constraint: "^1.0.0 || ^3.0.0", matches: []string{"1.5.0", "3.1.0"}, rejects: []string{"2.0.0"}},
+		{constraint: ">1.2", matches: []string{"1.3.0"}, rejects: []string{"1.2.9"}},
+		{constraint: "<=1.2", matches: []string{"1.2.9"}, rejects: []string{"1.3.0"}},
+		// Pre-releases only match when the range names a pre-release of the same major.minor.patch.
+		{constraint: "^1.2.3-beta.2", matches: []string{"1.2.3-beta.2", "1.2.3-beta.10", "1.2.3", "1.9.0"}, rejects: []string{"1.2.3-beta.1", "1.2.4-beta.3"}},
+		{constraint: ">=1.0.0-rc.1 <2", matches: []string{"1.0.0-rc.2", "1.4.0"}, rejects: []string{"1.1.0-rc.1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			constraint, err := ParseVersionConstraint(tt.constraint)
+			require.NoError(t, err)
+			for _, version := range tt.matches {
+				assert.True(t, constraint.Matches(version), "%s should satisfy %s", version, tt.constraint)
+			}
+			for _, version := range tt.rejects {
+				assert.False(t, constraint.Matches(version), "%s should not satisfy %s", version, tt.constraint)
+			}
+		})
+	}
+}
+
+func TestParseVersionConstraint_Invalid(t *testing.T) {
+	for _, raw := range []string{"", "^", ">=abc", "1.2.3 -", "~x.1", "1.2.3.4"} {
+		_, err := ParseVersionConstraint(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestVersionConstraint_MaxSatisfying(t *testing.T) {
+	constraint, err := ParseVersionConstraint("~1.4.0")
+	require.NoError(t, err)
+	best, found := constraint.MaxSatisfying([]string{"1.3.9", "1.4.2", "1.4.10", "1.4.11-rc.1", "1.5.0", "junk"})
+	require.True(t, found)
+	assert.Equal(t, "1.4.10", best)
+
+	_, found = constraint.MaxSatisfying([]string{"2.0.0"})
+	assert.False(t, found)
+}
+
+func TestIsVersionRange(t *testing.T) {
+	for _, requested := range []string{"", "latest", "1.2.3", "1.2.3-rc.1"} {
+		assert.False(t, IsVersionRange(requested), requested)
+	}
+	for _, requested := range []string{"^1.2", "~1.4.0", ">=2 <3", "1.x", "1.2"} {
+		assert.True(t, IsVersionRange(requested), requested)
+	}
+}
+
+func TestVersionMatchesRequest(t *testing.T) {
+	assert.True(t, VersionMatchesRequest("1.4.0", ""))
+	assert.True(t, VersionMatchesRequest("1.4.0", "latest"))
+	assert.True(t, VersionMatchesRequest("1.4.0", "1.4.0"))
+	assert.False(t, VersionMatchesRequest("1.4.0", "1.4.1"))
+	assert.True(t, VersionMatchesRequest("1.4.0", "^1.2"))
+	assert.False(t, VersionMatchesRequest("2.0.0", "^1.2"))
+}
+
+func TestValidateVersionRequest(t *testing.T) {
+	assert.NoError(t, ValidateVersionRequest(""))
+	assert.NoError(t, ValidateVersionRequest("1.0.0"))
+	assert.NoError(t, ValidateVersionRequest("^1"))
+	assert.Error(t, ValidateVersionRequest("not-a-version"))
+}
+
+func TestIntersectVersionRequests(t *testing.T) {
+	tests := []struct {
+		first, second string
+		matches       []string
+		rejects       []string
+	}{
+		{first: "^1", second: "<1.8", matches: []string{"1.0.0", "1.7.9"}, rejects: []string{"1.8.0", "1.9.0", "0.9.0"}},
+		{first: "^1.0.0 || ^3.0.0", second: ">=1.5", matches: []string{"1.5.0", "3.2.0"}, rejects: []string{"1.4.0", "2.0.0"}},
+		{first: "1.2.3 - 2.3", second: "~2.3.1", matches: []string{"2.3.1", "2.3.9"}, rejects: []string{"2.3.0", "2.4.0"}},
+		{first: "", second: "~1.4.0", matches: []string{"1.4.2"}, rejects: []string{"1.5.0"}},
+		{first: "1.4.2", second: "^1", matches: []string{"1.4.2"}, rejects: []string{"1.4.3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.first+" & "+tt.second, func(t *testing.T) {
+			intersection, err := IntersectVersionRequests(tt.first, tt.second)
+			require.NoError(t, err)
+			for _, version := range tt.matches {
+				assert.True(t, VersionMatchesRequest(version, intersection), "%s should satisfy %s", version, intersection)
+			}
+			for _, version := range tt.rejects {
+				assert.False(t, VersionMatchesRequest(version, intersection), "%s should not satisfy %s", version, intersection)
+			}
+		})
+	}
+
+	_, err := IntersectVersionRequests("1.4.2", "1.5.0")
+	assert.Error(t, err)
+	_, err = IntersectVersionRequests("2.0.0", "^1")
+	assert.Error(t, err)
+}
@@ -133,3 +133,23 @@ func TestNextMinorVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestCompareSemver_Prerelease(t *testing.T) {
+	t.Parallel()
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0"}
+	for i := 1; i < len(ordered); i++ {
+		comparison, err := CompareSemver(ordered[i-1], ordered[i])
+		require.NoError(t, err)
+		assert.Less(t, comparison, 0, "%s < %s", ordered[i-1], ordered[i])
+	}
+
+	comparison, err := CompareSemver("1.0.0+build.1", "1.0.0")
+	require.NoError(t, err)
+	assert.Zero(t, comparison)
+}
+
+func TestLatestVersion_ReleaseOutranksPrerelease(t *testing.T) {
+	latest, err := LatestVersion([]string{"1.0.0-rc.1", "1.0.0", "0.9.0"})
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", latest)
+}
@@ -0,0 +1,223 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// SyncAction is what sync does for one install target.
+type SyncAction string
+
+const (
+	SyncActionInstall  SyncAction = "install"
+	SyncActionUpdate   SyncAction = "update"
+	SyncActionUpToDate SyncAction = "up-to-date"
+	SyncActionFail     SyncAction = "fail"
+)
+
+// SyncTargetPlan is the planned action for one declared package at one install target.
+type SyncTargetPlan struct {
+	Target           InstallTarget
+	Action           SyncAction
+	InstalledVersion string
+	FailureReason    string
+}
+
+// PlanSyncTargets compares each target with the desired version. readInstalledVersion returns an
+// error wrapping fs.ErrNotExist when nothing is installed at a target.
+func PlanSyncTargets(targets []InstallTarget, desiredVersion string, readInstalledVersion func(dir string) (string, error)) []SyncTargetPlan {
+	plans := make([]SyncTargetPlan, 0, len(targets))
+	for _, target := range targets {
+		plan := SyncTargetPlan{Target: target}
+		installedVersion, err := readInstalledVersion(target.DestinationDir)
+		switch {
+		case err != nil && errors.Is(err, fs.ErrNotExist):
+			plan.Action = SyncActionInstall
+		case err != nil:
+			plan.Action = SyncActionFail
+			plan.FailureReason = err.Error()
+		case installedVersion == desiredVersion:
+			plan.Action = SyncActionUpToDate
+			plan.InstalledVersion = installedVersion
+		default:
+			plan.Action = SyncActionUpdate
+			plan.InstalledVersion = installedVersion
+		}
+		plans = append(plans, plan)
+	}
+	return plans
+}
+
+// SplitSyncPlans turns plans that need no download (failed, up-to-date, or every plan in dry-run mode)
+// into summary rows and returns the remaining targets to install or update.
+func SplitSyncPlans(entityLabel, slug, desiredVersion string, plans []SyncTargetPlan, dryRun bool) (rows []SummaryRow, toInstall, toUpdate []InstallTarget) {
+	for _, plan := range plans {
+		if dryRun {
+			logSyncPlan(entityLabel, slug, desiredVersion, plan)
+		}
+		switch {
+		case plan.Action == SyncActionFail || plan.Action == SyncActionUpToDate || dryRun:
+			rows = append(rows, syncPlanRow(desiredVersion, plan))
+		case plan.Action == SyncActionInstall:
+			toInstall = append(toInstall, plan.Target)
+		default:
+			toUpdate = append(toUpdate, plan.Target)
+		}
+	}
+	return rows, toInstall, toUpdate
+}
+
+func syncPlanRow(desiredVersion string, plan SyncTargetPlan) SummaryRow {
+	row := SummaryRow{
+		Agent:  plan.Target.Agent.Name,
+		Scope:  string(plan.Target.Scope),
+		Path:   plan.Target.DestinationDir,
+		Status: SummaryStatusSkipped,
+	}
+	switch plan.Action {
+	case SyncActionFail:
+		row.Status = SummaryStatusFailed
+		row.Detail = plan.FailureReason
+	case SyncActionUpToDate:
+		row.Detail = fmt.Sprintf("version already %s", desiredVersion)
+	case SyncActionInstall:
+		row.Detail = fmt.Sprintf("dry run: would install %s", desiredVersion)
+	default:
+		row.Detail = fmt.Sprintf("dry run: would update %s -> %s", plan.InstalledVersion, desiredVersion)
+	}
+	return row
+}
+
+// FailedSyncRows reports the same failure for every target of a package.
+func FailedSyncRows(targets []InstallTarget, err error) []SummaryRow {
+	rows := make([]SummaryRow, 0, len(targets))
+	for _, target := range targets {
+		rows = append(rows, InstallFailureRow(target.Agent.Name, string(target.Scope), target.DestinationDir, err))
+	}
+	return rows
+}
+
+// SyncFailureError returns an error when any row of a sync summary failed, so CI runs fail on drift they could not fix.
+func SyncFailureError(results []UpdateAllSummaryRow) error {
+	failed := 0
+	for _, row := range results {
+		if row.Status == SummaryStatusFailed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("sync failed for %d target(s) (see summary above)", failed)
+	}
+	return nil
+}
+
+func logSyncPlan(entityLabel, slug, desiredVersion string, plan SyncTargetPlan) {
+	dest := plan.Target.DestinationDir
+	switch plan.Action {
+	case SyncActionFail:
+		log.Info(fmt.Sprintf("[dry-run] Would skip %s at %s: %s", slug, dest, plan.FailureReason))
+	case SyncActionUpToDate:
+		log.Info(fmt.Sprintf("[dry-run] %s '%s' already at v%s at %s", entityLabel, slug, desiredVersion, dest))
+	case SyncActionInstall:
+		log.Info(fmt.Sprintf("[dry-run] Would install %s '%s' v%s to %s", strings.ToLower(entityLabel), slug, desiredVersion, dest))
+	case SyncActionUpdate:
+		log.Info(fmt.Sprintf("[dry-run] Would update %s '%s' from v%s -> v%s at %s", strings.ToLower(entityLabel), slug, plan.InstalledVersion, desiredVersion, dest))
+	}
+}
+
+// FindUndeclaredInstalls returns CLI-managed installs under each spec's project directory whose slug
+// the manifest does not declare for that harness. Installs without .jfrog/<manifestFileName> are never returned.
+func FindUndeclaredInstalls(specs []AgentSpec, projectDirAbs, manifestFileName string, declared map[string]map[string]bool) ([]InstallTarget, error) {
+	var undeclared []InstallTarget
+	for _, spec := range specs {
+		installDir, err := ResolveAgentInstallDir(spec, projectDirAbs, false)
+		if err != nil {
+			return nil, err
+		}
+		slugs, err := DiscoverInstalledSlugs(installDir, manifestFileName)
+		if err != nil {
+			return nil, err
+		}
+		for _, slug := range slugs {
+			if declared[spec.Name][slug] {
+				continue
+			}
+			undeclared = append(undeclared, InstallTarget{
+				Agent:          spec,
+				Scope:          InstallScopeProject,
+				DestinationDir: filepath.Join(installDir, slug),
+			})
+		}
+	}
+	return undeclared, nil
+}
+
+// PruneInstalls deletes undeclared installs and drops their lockfile entries. In dry-run mode it only reports them.
+func PruneInstalls(entityLabel, lockKind, lockPath string, targets []InstallTarget, dryRun bool) []UpdateAllSummaryRow {
+	rows := make([]UpdateAllSummaryRow, 0, len(targets))
+	removed := make([]InstallTarget, 0, len(targets))
+	for _, target := range targets {
+		slug := filepath.Base(target.DestinationDir)
+		row := UpdateAllSummaryRow{
+			Agent: target.Agent.Name,
+			Name:  slug,
+			Scope: string(target.Scope),
+			Path:  target.DestinationDir,
+		}
+		switch {
+		case dryRun:
+			log.Info(fmt.Sprintf("[dry-run] Would remove undeclared %s '%s' at %s", strings.ToLower(entityLabel), slug, target.DestinationDir))
+			row.Status = SummaryStatusSkipped
+			row.Detail = "dry run: would remove (not declared in manifest)"
+		default:
+			if err := RemovePath(target.DestinationDir); err != nil {
+				row.Status = SummaryStatusFailed
+				row.Detail = fmt.Sprintf("could not remove undeclared install: %s", err.Error())
+				break
+			}
+			row.Status = SummaryStatusRemoved
+			row.Detail = "not declared in manifest"
+			removed = append(removed, target)
+		}
+		rows = append(rows, row)
+	}
+	if len(removed) > 0 {
+		if err := removeLockEntries(lockPath, lockKind, removed); err != nil {
+			log.Warn(fmt.Sprintf("Undeclared %ss were removed but %s was not updated: %s", strings.ToLower(entityLabel), LockfileName, err.Error()))
+		}
+	}
+	return rows
+}
+
+func removeLockEntries(lockPath, lockKind string, targets []InstallTarget) error {
+	lockfile, err := ReadLockfile(lockPath)
+	if err != nil {
+		return err
+	}
+	changed := false
+	for _, target := range targets {
+		if lockfile.Remove(lockKind, filepath.Base(target.DestinationDir), target.Agent.Name) {
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return WriteLockfile(lockPath, lockfile)
+}
+
+// SortedHarnesses returns the harness names of a DeclaredSlugsByHarness map in sorted order.
+func SortedHarnesses(declared map[string]map[string]bool) []string {
+	names := make([]string, 0, len(declared))
+	for name := range declared {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
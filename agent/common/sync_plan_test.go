@@ -0,0 +1,98 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanSyncTargets(t *testing.T) {
+	installed := map[string]string{"/a/up": "1.0.0", "/a/old": "0.9.0"}
+	read := func(dir string) (string, error) {
+		if dir == "/a/broken" {
+			return "", errors.New("bad manifest")
+		}
+		if version, ok := installed[dir]; ok {
+			return version, nil
+		}
+		return "", fmt.Errorf("missing: %w", fs.ErrNotExist)
+	}
+	targets := []InstallTarget{
+		{Agent: AgentSpec{Name: "a"}, DestinationDir: "/a/up"},
+		{Agent: AgentSpec{Name: "b"}, DestinationDir: "/a/old"},
+		{Agent: AgentSpec{Name: "c"}, DestinationDir: "/a/new"},
+		{Agent: AgentSpec{Name: "d"}, DestinationDir: "/a/broken"},
+	}
+	plans := PlanSyncTargets(targets, "1.0.0", read)
+	require.Len(t, plans, 4)
+	assert.Equal(t, SyncActionUpToDate, plans[0].Action)
+	assert.Equal(t, SyncActionUpdate, plans[1].Action)
+	assert.Equal(t, "0.9.0", plans[1].InstalledVersion)
+	assert.Equal(t, SyncActionInstall, plans[2].Action)
+	assert.Equal(t, SyncActionFail, plans[3].Action)
+
+	rows, toInstall, toUpdate := SplitSyncPlans("Skill", "web", "1.0.0", plans, false)
+	require.Len(t, rows, 2)
+	assert.Equal(t, SummaryStatusSkipped, rows[0].Status)
+	assert.Equal(t, SummaryStatusFailed, rows[1].Status)
+	assert.Equal(t, "/a/new", toInstall[0].DestinationDir)
+	assert.Equal(t, "/a/old", toUpdate[0].DestinationDir)
+
+	rows, toInstall, toUpdate = SplitSyncPlans("Skill", "web", "1.0.0", plans, true)
+	assert.Len(t, rows, 4)
+	assert.Empty(t, toInstall)
+	assert.Empty(t, toUpdate)
+	assert.Equal(t, "dry run: would update 0.9.0 -> 1.0.0", rows[1].Detail)
+}
+
+func TestFindUndeclaredInstallsAndPrune(t *testing.T) {
+	projectDir := t.TempDir()
+	spec := AgentSpec{Name: "cursor", Config: AgentConfig{ProjectDir: ".cursor/skills"}}
+	installDir := filepath.Join(projectDir, ".cursor", "skills")
+	for _, slug := range []string{"kept", "stale"} {
+		require.NoError(t, WriteInstallInfoManifest(filepath.Join(installDir, slug), "skill-info.json", InstallInfoManifest{Slug: slug}))
+	}
+	// Hand-made installs without a CLI manifest are never pruned.
+	require.NoError(t, os.MkdirAll(filepath.Join(installDir, "manual"), 0o755))
+
+	lockPath, err := LockfilePath(InstallScopeProject, projectDir, "")
+	require.NoError(t, err)
+	require.NoError(t, RecordLockEntries(lockPath, []LockEntry{
+		{Kind: LockKindSkill, Slug: "kept", Harness: "cursor"},
+		{Kind: LockKindSkill, Slug: "stale", Harness: "cursor"},
+	}))
+
+	declared := map[string]map[string]bool{"cursor": {"kept": true}}
+	undeclared, err := FindUndeclaredInstalls([]AgentSpec{spec}, projectDir, "skill-info.json", declared)
+	require.NoError(t, err)
+	require.Len(t, undeclared, 1)
+	assert.Equal(t, filepath.Join(installDir, "stale"), undeclared[0].DestinationDir)
+
+	rows := PruneInstalls("Skill", LockKindSkill, lockPath, undeclared, true)
+	require.Len(t, rows, 1)
+	assert.Equal(t, SummaryStatusSkipped, rows[0].Status)
+	assert.DirExists(t, filepath.Join(installDir, "stale"))
+
+	rows = PruneInstalls("Skill", LockKindSkill, lockPath, undeclared, false)
+	require.Len(t, rows, 1)
+	assert.Equal(t, SummaryStatusRemoved, rows[0].Status)
+	assert.NoDirExists(t, filepath.Join(installDir, "stale"))
+	assert.DirExists(t, filepath.Join(installDir, "manual"))
+
+	lockfile, err := ReadLockfile(lockPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"kept"}, lockfile.Slugs(LockKindSkill, nil))
+}
+
+func TestSyncFailureError(t *testing.T) {
+	require.NoError(t, SyncFailureError([]UpdateAllSummaryRow{{Status: SummaryStatusOK}, {Status: SummaryStatusRemoved}}))
+	err := SyncFailureError([]UpdateAllSummaryRow{{Status: SummaryStatusOK}, {Status: SummaryStatusFailed}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 target(s)")
+}
@@ -0,0 +1,377 @@
+package common
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// ValidationSchemaVersion versions the rule set applied by validate and publish. It is bumped whenever a rule is
+// added or its severity changes, so pre-commit hooks and CI jobs can tell which rules produced a report.
+const ValidationSchemaVersion = "2"
+
+// Validation issue severities. Errors block publish; warnings are reported only.
+const (
+	ValidationSeverityError   = "error"
+	ValidationSeverityWarning = "warning"
+)
+
+// Validation rule identifiers, stable across releases for use in tooling.
+const (
+	ValidationRuleManifestSyntax       = "manifest-syntax"
+	ValidationRuleManifestName         = "manifest-name"
+	ValidationRuleManifestDescription  = "manifest-description"
+	ValidationRuleManifestVersion      = "manifest-version"
+	ValidationRuleManifestDependencies = "manifest-dependencies"
+	ValidationRuleFileSize             = "file-size"
+	ValidationRuleBinaryFile           = "binary-file"
+	ValidationRuleAbsolutePath         = "absolute-path"
+	ValidationRuleBrokenLink           = "broken-link"
+	ValidationRuleSecret               = "secret"
+)
+
+// binarySniffLength is how much of a file is read to decide whether it is binary, as git does.
+const binarySniffLength = 8000
+
+// maxPublishFileSize is the largest single file allowed in a published package.
+// maxPublishFileSize is swappable in tests.
+var maxPublishFileSize int64 = 5 << 20
+
+// markdownLinkRegex matches inline markdown links and images: [text](target "title").
+var markdownLinkRegex = regexp.MustCompile(`!?\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+["'][^)]*["'])?\s*\)`)
+
+// inlineCodeRegex matches inline code spans, whose content is not parsed for links.
+var inlineCodeRegex = regexp.MustCompile("`[^`]*`")
+
+// urlSchemeRegex matches targets with a URL scheme such as https:, mailto: or data:.
+var urlSchemeRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*:`)
+
+// windowsAbsPathRegex matches drive-letter paths such as C:\ or C:/.
+var windowsAbsPathRegex = regexp.MustCompile(`^[A-Za-z]:[\\/]`)
+
+// ValidationIssue is one rule violation found in a package.
+type ValidationIssue struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	// File is relative to the package root; empty for issues about the package as a whole.
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
+// Location returns File with the line number appended when known.
+func (issue ValidationIssue) Location() string {
+	if issue.Line > 0 {
+		return issue.File + ":" + strconv.Itoa(issue.Line)
+	}
+	return issue.File
+}
+
+// ValidationReport is the validation result for one skill or plugin directory.
+type ValidationReport struct {
+	Kind    string `json:"kind"`
+	Path    string `json:"path"`
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
+	// Manifest is the SKILL.md or plugin.json checked, relative to Path.
+	Manifest string            `json:"manifest,omitempty"`
+	Issues   []ValidationIssue `json:"issues"`
+}
+
+// NewValidationReport returns an empty report for the package of the given kind at path.
+func NewValidationReport(kind, path string) ValidationReport {
+	return ValidationReport{Kind: kind, Path: path, Issues: []ValidationIssue{}}
+}
+
+// AddError records an issue that blocks publish.
+func (r *ValidationReport) AddError(rule, file, message string) {
+	r.Issues = append(r.Issues, ValidationIssue{Rule: rule, Severity: ValidationSeverityError, File: file, Message: message})
+}
+
+// AddWarning records an issue that is reported without blocking publish.
+func (r *ValidationReport) AddWarning(rule, file, message string) {
+	r.Issues = append(r.Issues, ValidationIssue{Rule: rule, Severity: ValidationSeverityWarning, File: file, Message: message})
+}
+
+// Downgrade reports every issue of rule as a warning, for publish flags that knowingly accept a rule's findings.
+func (r *ValidationReport) Downgrade(rule string) {
+	for idx := range r.Issues {
+		if r.Issues[idx].Rule == rule {
+			r.Issues[idx].Severity = ValidationSeverityWarning
+		}
+	}
+}
+
+// Count returns the number of issues with the given severity.
+func (r ValidationReport) Count(severity string) int {
+	count := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// ValidatePackageFiles applies the file rules to every file publish would include from sourceDir:
+// size limit, binary content, likely secrets, and absolute or broken relative links in markdown files.
+func ValidatePackageFiles(report *ValidationReport, sourceDir string) error {
+	files, _, err := CollectPublishFiles(sourceDir)
+	if err != nil {
+		return fmt.Errorf("failed to collect files in %s: %w", sourceDir, err)
+	}
+	allowlist, err := loadSecretsAllowlist(sourceDir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := validatePackageFile(report, allowlist, sourceDir, file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validatePackageFile(report *ValidationReport, allowlist secretsAllowlist, sourceDir string, file ZipFileEntry) error {
+	relPath := filepath.ToSlash(file.RelPath)
+	absPath := filepath.Join(sourceDir, file.RelPath)
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", relPath, err)
+	}
+	// Every file is scanned, including those the size and binary rules stop below: they are published too.
+	if err := scanFileSecrets(report, allowlist, absPath, relPath); err != nil {
+		return err
+	}
+	if info.Size() > maxPublishFileSize {
+		report.AddError(ValidationRuleFileSize, relPath,
+			fmt.Sprintf("file is %s; the limit is %s", formatByteSize(info.Size()), formatByteSize(maxPublishFileSize)))
+		return nil
+	}
+	binary, err := isBinaryFile(absPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", relPath, err)
+	}
+	if binary {
+		report.AddWarning(ValidationRuleBinaryFile, relPath, "binary file; agents read package files as text")
+		return nil
+	}
+	if !isMarkdownFile(relPath) {
+		return nil
+	}
+	// #nosec G304 -- path is a file collected from the user-provided package directory.
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", relPath, err)
+	}
+	return validateMarkdownLinks(report, sourceDir, relPath, data)
+}
+
+func scanFileSecrets(report *ValidationReport, allowlist secretsAllowlist, absPath, relPath string) error {
+	// #nosec G304 -- path is a file collected from the user-provided package directory.
+	file, err := os.Open(absPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", relPath, err)
+	}
+	defer func() {
+		_ = file.Close() // read-side close after scan
+	}()
+	if err := scanSecrets(report, allowlist, relPath, file); err != nil {
+		return fmt.Errorf("failed to scan %s: %w", relPath, err)
+	}
+	return nil
+}
+
+func isBinaryFile(path string) (bool, error) {
+	// #nosec G304 -- path is a file collected from the user-provided package directory.
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		_ = file.Close() // read-side close after sniff
+	}()
+	head := make([]byte, binarySniffLength)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return false, err
+	}
+	return bytes.IndexByte(head[:n], 0) >= 0, nil
+}
+
+func isMarkdownFile(relPath string) bool {
+	switch strings.ToLower(filepath.Ext(relPath)) {
+	case ".md", ".mdx", ".markdown":
+		return true
+	}
+	return false
+}
+
+// validateMarkdownLinks checks the targets of inline links and images outside code blocks and code spans.
+func validateMarkdownLinks(report *ValidationReport, sourceDir, relPath string, data []byte) error {
+	inFence := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		line = inlineCodeRegex.ReplaceAllString(line, "")
+		for _, match := range markdownLinkRegex.FindAllStringSubmatch(line, -1) {
+			target := match[1]
+			if rule, problem := checkPackagePath(sourceDir, filepath.Dir(filepath.FromSlash(relPath)), target); rule != "" {
+				report.Issues = append(report.Issues, ValidationIssue{Rule: rule, Severity: ValidationSeverityError, File: relPath, Line: lineNo,
+					Message: fmt.Sprintf("link %q %s", target, problem)})
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// ValidateManifestPath applies the link rules to a path declared in a manifest field, such as the commands
+// directory of a plugin: it must be relative and exist under sourceDir.
+func ValidateManifestPath(report *ValidationReport, sourceDir, manifest, field, declared string) {
+	if rule, problem := checkPackagePath(sourceDir, ".", declared); rule != "" {
+		report.AddError(rule, manifest, fmt.Sprintf("'%s' path %q %s", field, declared, problem))
+	}
+}
+
+// checkPackagePath returns the violated rule and a description of the problem when target, resolved against the
+// package-relative directory baseDir, is an absolute path or a relative path that escapes the package or does not
+// exist. URLs and in-page anchors are not checked.
+func checkPackagePath(sourceDir, baseDir, target string) (rule, problem string) {
+	if isAbsolutePathTarget(target) {
+		return ValidationRuleAbsolutePath, "is an absolute path and will not resolve on other machines"
+	}
+	if strings.HasPrefix(target, "#") || urlSchemeRegex.MatchString(target) {
+		return "", ""
+	}
+	linkPath := target
+	if idx := strings.IndexAny(linkPath, "#?"); idx >= 0 {
+		linkPath = linkPath[:idx]
+	}
+	if unescaped, err := url.PathUnescape(linkPath); err == nil {
+		linkPath = unescaped
+	}
+	resolved := filepath.Join(baseDir, filepath.FromSlash(linkPath))
+	if !filepath.IsLocal(resolved) {
+		return ValidationRuleBrokenLink, "points outside the package"
+	}
+	if _, err := os.Stat(filepath.Join(sourceDir, resolved)); err != nil {
+		return ValidationRuleBrokenLink, "does not exist in the package"
+	}
+	return "", ""
+}
+
+func isAbsolutePathTarget(target string) bool {
+	return strings.HasPrefix(target, "/") || strings.HasPrefix(target, "\\") || strings.HasPrefix(target, "~/") ||
+		strings.HasPrefix(strings.ToLower(target), "file:") || windowsAbsPathRegex.MatchString(target)
+}
+
+func formatByteSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// ValidationRow is one issue in the validate table.
+type ValidationRow struct {
+	Severity string `col-name:"Severity"`
+	Rule     string `col-name:"Rule"`
+	Location string `col-name:"Location"`
+	Message  string `col-name:"Message"`
+}
+
+type validationSummaryJSON struct {
+	SchemaVersion string             `json:"schemaVersion"`
+	Results       []ValidationReport `json:"results"`
+}
+
+// PrintValidationReports renders validation reports as a table per package or as one JSON document that
+// carries ValidationSchemaVersion.
+func PrintValidationReports(entityLabel string, reports []ValidationReport, format string) error {
+	if reports == nil {
+		reports = []ValidationReport{}
+	}
+	if strings.EqualFold(format, "json") {
+		data, err := json.MarshalIndent(validationSummaryJSON{SchemaVersion: ValidationSchemaVersion, Results: reports}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal validation report: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	for _, report := range reports {
+		rows := make([]ValidationRow, 0, len(report.Issues))
+		for _, issue := range report.Issues {
+			rows = append(rows, ValidationRow{Severity: issue.Severity, Rule: issue.Rule, Location: issue.Location(), Message: issue.Message})
+		}
+		log.Info(fmt.Sprintf("%s validation for %s (%d error(s), %d warning(s)):", entityLabel, report.Path,
+			report.Count(ValidationSeverityError), report.Count(ValidationSeverityWarning)))
+		if err := coreutils.PrintTable(rows, "Issues", "No issues found", false); err != nil {
+			log.Warn("Failed to render validation report: " + err.Error())
+		}
+	}
+	return nil
+}
+
+// ValidationError returns an error when any report has errors, or warnings when strict is set; nil otherwise.
+func ValidationError(entityLabel string, reports []ValidationReport, strict bool) error {
+	errorCount, warningCount := 0, 0
+	for _, report := range reports {
+		errorCount += report.Count(ValidationSeverityError)
+		warningCount += report.Count(ValidationSeverityWarning)
+	}
+	if errorCount > 0 {
+		return fmt.Errorf("%s validation failed with %d error(s)", strings.ToLower(entityLabel), errorCount)
+	}
+	if strict && warningCount > 0 {
+		return fmt.Errorf("%s validation failed with %d warning(s) in strict mode", strings.ToLower(entityLabel), warningCount)
+	}
+	return nil
+}
+
+// CheckPublishValidation logs the warnings in report and returns an error listing its errors, if any.
+// Publish calls it before building the zip.
+func CheckPublishValidation(entityLabel string, report ValidationReport) error {
+	var errs []string
+	for _, issue := range report.Issues {
+		line := issue.Message
+		if location := issue.Location(); location != "" {
+			line = location + ": " + line
+		}
+		if issue.Severity == ValidationSeverityError {
+			errs = append(errs, fmt.Sprintf("  [%s] %s", issue.Rule, line))
+			continue
+		}
+		log.Warn(fmt.Sprintf("[%s] %s", issue.Rule, line))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s at %s failed validation:\n%s", strings.ToLower(entityLabel), report.Path, strings.Join(errs, "\n"))
+	}
+	return nil
+}
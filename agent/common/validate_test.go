@@ -0,0 +1,85 @@
+package common
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func issuesByRule(report ValidationReport) map[string][]ValidationIssue {
+	byRule := map[string][]ValidationIssue{}
+	for _, issue := range report.Issues {
+		byRule[issue.Rule] = append(byRule[issue.Rule], issue)
+	}
+	return byRule
+}
+
+func TestValidatePackageFiles(t *testing.T) {
+	restore := maxPublishFileSize
+	t.Cleanup(func() { maxPublishFileSize = restore })
+	maxPublishFileSize = 512
+
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "SKILL.md"), strings.Join([]string{
+		"See [the guide](docs/guide.md#setup) and [site](https://example.com) or [top](#usage).",
+		"Broken: [missing](docs/missing.md), [outside](../other/README.md), ![logo](/Users/me/logo.png)",
+		"```",
+		"[ignored](not/there.md)",
+		"```",
+		"Inline `[ignored](nope.md)` code.",
+	}, "\n"))
+	writeTestFile(t, filepath.Join(dir, "docs", "guide.md"), "# Guide")
+	writeTestFile(t, filepath.Join(dir, "assets", "icon.png"), "\x89PNG\x00\x00")
+	writeTestFile(t, filepath.Join(dir, "data.txt"), strings.Repeat("x", 513))
+	writeTestFile(t, filepath.Join(dir, ".git", "big"), strings.Repeat("x", 1024))
+
+	report := NewValidationReport("skill", dir)
+	require.NoError(t, ValidatePackageFiles(&report, dir))
+	byRule := issuesByRule(report)
+
+	require.Len(t, byRule[ValidationRuleBrokenLink], 2)
+	assert.Equal(t, "SKILL.md:2", byRule[ValidationRuleBrokenLink][0].Location())
+	assert.Contains(t, byRule[ValidationRuleBrokenLink][0].Message, "docs/missing.md")
+	assert.Contains(t, byRule[ValidationRuleBrokenLink][1].Message, "outside the package")
+	require.Len(t, byRule[ValidationRuleAbsolutePath], 1)
+	assert.Contains(t, byRule[ValidationRuleAbsolutePath][0].Message, "/Users/me/logo.png")
+	require.Len(t, byRule[ValidationRuleBinaryFile], 1)
+	assert.Equal(t, "assets/icon.png", byRule[ValidationRuleBinaryFile][0].File)
+	assert.Equal(t, ValidationSeverityWarning, byRule[ValidationRuleBinaryFile][0].Severity)
+	require.Len(t, byRule[ValidationRuleFileSize], 1, "excluded files are not checked")
+	assert.Equal(t, "data.txt", byRule[ValidationRuleFileSize][0].File)
+
+	assert.Equal(t, 4, report.Count(ValidationSeverityError))
+	assert.ErrorContains(t, ValidationError("Skill", []ValidationReport{report}, false), "skill validation failed with 4 error(s)")
+	err := CheckPublishValidation("Skill", report)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "[broken-link] SKILL.md:2: link \"docs/missing.md\" does not exist in the package")
+}
+
+func TestValidateManifestPath(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "commands", "run.md"), "# run")
+
+	report := NewValidationReport("plugin", dir)
+	ValidateManifestPath(&report, dir, "plugin.json", "commands", "./commands")
+	assert.Empty(t, report.Issues)
+
+	ValidateManifestPath(&report, dir, "plugin.json", "agents", "./agents")
+	ValidateManifestPath(&report, dir, "plugin.json", "hooks", `C:\hooks\hooks.json`)
+	require.Len(t, report.Issues, 2)
+	assert.Equal(t, ValidationRuleBrokenLink, report.Issues[0].Rule)
+	assert.Equal(t, `'agents' path "./agents" does not exist in the package`, report.Issues[0].Message)
+	assert.Equal(t, ValidationRuleAbsolutePath, report.Issues[1].Rule)
+}
+
+func TestValidationError_Strict(t *testing.T) {
+	report := NewValidationReport("plugin", "/work/demo")
+	report.AddWarning(ValidationRuleManifestDescription, "plugin.json", "missing 'description' field")
+
+	assert.NoError(t, ValidationError("Plugin", []ValidationReport{report}, false))
+	assert.ErrorContains(t, ValidationError("Plugin", []ValidationReport{report}, true), "1 warning(s) in strict mode")
+	assert.NoError(t, CheckPublishValidation("Plugin", report))
+}
@@ -0,0 +1,242 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Verify statuses in addition to SummaryStatusOK and SummaryStatusFailed.
+const (
+	VerifyStatusModified   = "modified"
+	VerifyStatusUnverified = "unverified"
+	VerifyEvidenceVerified = "verified"
+	VerifyEvidenceFailed   = "failed"
+)
+
+// verifyDetailMaxFiles caps how many changed paths are listed in the table detail; JSON lists them all.
+const verifyDetailMaxFiles = 5
+
+// verifyInstalledEvidence is swappable in tests.
+var verifyInstalledEvidence = VerifyPackageEvidence
+
+// VerifyRow is one row in the verify table.
+type VerifyRow struct {
+	Agent    string `json:"agent" col-name:"Agent"`
+	Name     string `json:"name" col-name:"Name"`
+	Version  string `json:"version" col-name:"Version"`
+	Path     string `json:"path" col-name:"Path"`
+	Status   string `json:"status" col-name:"Status"`
+	Evidence string `json:"evidence,omitempty" col-name:"Evidence"`
+	Detail   string `json:"detail" col-name:"Detail"`
+}
+
+// VerifyResult is the outcome of verifying one install target.
+type VerifyResult struct {
+	VerifyRow
+	Diff InventoryDiff `json:"files"`
+}
+
+// Tampered reports whether the install differs from what was published or failed verification.
+func (r VerifyResult) Tampered() bool {
+	return r.Status == VerifyStatusModified || r.Status == SummaryStatusFailed || r.Evidence == VerifyEvidenceFailed
+}
+
+// VerifyOptions configures VerifyInstalls.
+type VerifyOptions struct {
+	// ManifestFileName is the install-info manifest under .jfrog (e.g. skill-info.json).
+	ManifestFileName string
+	// CheckEvidence re-verifies evidence of the installed version in Artifactory using ServerDetails.
+	CheckEvidence bool
+	ServerDetails *config.ServerDetails
+}
+
+// VerifyTargets returns the install targets to verify: slug in every selected harness (or under --path),
+// or every CLI-managed install found there when slug is empty.
+func VerifyTargets(slug string, flags InstallFlagsResult, manifestFileName string) ([]InstallTarget, error) {
+	if slug != "" {
+		return ResolveAgentTargets(slug, flags.AbsoluteInstallBaseDir, flags.Specs, flags.ProjectDirAbs, flags.IsGlobal)
+	}
+	if flags.PathMode() {
+		slugs, err := DiscoverInstalledSlugs(flags.AbsoluteInstallBaseDir, manifestFileName)
+		if err != nil {
+			return nil, err
+		}
+		targets := make([]InstallTarget, 0, len(slugs))
+		for _, installed := range slugs {
+			target, err := BuildPathInstallTarget(installed, flags.AbsoluteInstallBaseDir)
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, target)
+		}
+		return targets, nil
+	}
+	var targets []InstallTarget
+	for _, spec := range flags.Specs {
+		installDir, err := ResolveAgentInstallDir(spec, flags.ProjectDirAbs, flags.IsGlobal)
+		if err != nil {
+			return nil, err
+		}
+		slugs, err := DiscoverInstalledSlugs(installDir, manifestFileName)
+		if err != nil {
+			return nil, err
+		}
+		for _, installed := range slugs {
+			resolved, err := ResolveAgentTargets(installed, "", []AgentSpec{spec}, flags.ProjectDirAbs, flags.IsGlobal)
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, resolved...)
+		}
+	}
+	return targets, nil
+}
+
+// VerifyInstalls re-hashes each install target and compares it with the file inventory recorded at install time.
+func VerifyInstalls(targets []InstallTarget, opts VerifyOptions) []VerifyResult {
+	results := make([]VerifyResult, 0, len(targets))
+	for _, target := range targets {
+		results = append(results, verifyInstall(target, opts))
+	}
+	return results
+}
+
+func verifyInstall(target InstallTarget, opts VerifyOptions) VerifyResult {
+	result := VerifyResult{VerifyRow: VerifyRow{Agent: target.Agent.Name, Path: target.DestinationDir}}
+	manifest, err := ReadInstallInfoManifest(target.DestinationDir, opts.ManifestFileName)
+	if err != nil {
+		result.Status, result.Detail = SummaryStatusFailed, err.Error()
+		return result
+	}
+	if manifest == nil {
+		result.Status, result.Detail = SummaryStatusSkipped, "not installed by the JFrog CLI"
+		return result
+	}
+	result.Name, result.Version = manifest.Slug, manifest.InstalledVersion
+
+	inventory, err := ReadFileInventory(target.DestinationDir)
+	switch {
+	case err != nil:
+		result.Status, result.Detail = SummaryStatusFailed, err.Error()
+	case inventory == nil:
+		result.Status, result.Detail = VerifyStatusUnverified, "no file inventory recorded; reinstall to enable verification"
+	default:
+		diff, diffErr := diffInstall(target.DestinationDir, manifest.StorePath, inventory)
+		if diffErr != nil {
+			result.Status, result.Detail = SummaryStatusFailed, diffErr.Error()
+			break
+		}
+		result.Diff = diff
+		if diff.Clean() {
+			result.Status, result.Detail = SummaryStatusOK, fmt.Sprintf("%d files match", len(inventory.Files))
+		} else {
+			result.Status, result.Detail = VerifyStatusModified, describeInventoryDiff(diff)
+		}
+	}
+
+	if opts.CheckEvidence {
+		if err := verifyInstalledEvidence(opts.ServerDetails, manifest.Repo, manifest.Slug, manifest.InstalledVersion); err != nil {
+			result.Evidence = VerifyEvidenceFailed
+			result.Detail += "; evidence: " + err.Error()
+		} else {
+			result.Evidence = VerifyEvidenceVerified
+		}
+	}
+	return result
+}
+
+// diffInstall compares an install with its inventory. Linked installs are checked in the store they link to;
+// package entries whose link no longer points there are reported as modified and other entries as added.
+func diffInstall(installDir, storeDir string, inventory *FileInventory) (InventoryDiff, error) {
+	if storeDir == "" {
+		return DiffFileInventory(installDir, inventory)
+	}
+	diff, err := DiffFileInventory(storeDir, inventory)
+	if err != nil {
+		return InventoryDiff{}, err
+	}
+	relinked, extra, err := StoreLinkProblems(storeDir, installDir)
+	if err != nil {
+		return InventoryDiff{}, err
+	}
+	diff.Modified = append(diff.Modified, relinked...)
+	diff.Added = append(diff.Added, extra...)
+	sort.Strings(diff.Modified)
+	sort.Strings(diff.Added)
+	return diff, nil
+}
+
+// describeInventoryDiff summarizes a diff, e.g. "1 modified, 1 added: SKILL.md, notes.md".
+func describeInventoryDiff(diff InventoryDiff) string {
+	var counts, paths []string
+	for _, group := range []struct {
+		label string
+		paths []string
+	}{
+		{"modified", diff.Modified},
+		{"added", diff.Added},
+		{"missing", diff.Missing},
+	} {
+		if len(group.paths) == 0 {
+			continue
+		}
+		counts = append(counts, fmt.Sprintf("%d %s", len(group.paths), group.label))
+		paths = append(paths, group.paths...)
+	}
+	if len(paths) > verifyDetailMaxFiles {
+		paths = append(paths[:verifyDetailMaxFiles], fmt.Sprintf("and %d more", len(paths)-verifyDetailMaxFiles))
+	}
+	return strings.Join(counts, ", ") + ": " + strings.Join(paths, ", ")
+}
+
+type verifySummaryJSON struct {
+	Results []VerifyResult `json:"results"`
+}
+
+// PrintVerifySummary renders verify results as a table or JSON (JSON includes every changed path).
+func PrintVerifySummary(entityLabel string, results []VerifyResult, format string) error {
+	if strings.EqualFold(format, "json") {
+		data, err := json.MarshalIndent(verifySummaryJSON{Results: results}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal verify summary: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	rows := make([]VerifyRow, 0, len(results))
+	for _, result := range results {
+		rows = append(rows, result.VerifyRow)
+	}
+	log.Info(entityLabel + " verification summary:")
+	if err := coreutils.PrintTable(rows, "Verified", "No installed "+strings.ToLower(entityLabel)+"s found", false); err != nil {
+		log.Warn("Failed to render verify summary: " + err.Error())
+	}
+	return nil
+}
+
+// VerifyError returns an error naming how many installs failed verification, or nil when none did.
+// Installs without a recorded inventory are reported with a warning but do not fail the run.
+func VerifyError(entityLabel string, results []VerifyResult) error {
+	tampered, unverified := 0, 0
+	for _, result := range results {
+		switch {
+		case result.Tampered():
+			tampered++
+		case result.Status == VerifyStatusUnverified:
+			unverified++
+		}
+	}
+	if unverified > 0 {
+		log.Warn(fmt.Sprintf("%d %s install(s) have no file inventory and could not be verified; reinstall them to record one", unverified, strings.ToLower(entityLabel)))
+	}
+	if tampered > 0 {
+		return fmt.Errorf("%d %s install(s) failed verification", tampered, strings.ToLower(entityLabel))
+	}
+	return nil
+}
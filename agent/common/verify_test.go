@@ -0,0 +1,107 @@
+package common
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testVerifyManifest = "skill-info.json"
+
+// installForVerify writes a package with its install manifest and, when withInventory is set, its file inventory.
+func installForVerify(t *testing.T, baseDir, slug string, withInventory bool) string {
+	t.Helper()
+	installDir := filepath.Join(baseDir, slug)
+	writeTestFile(t, filepath.Join(installDir, "SKILL.md"), "# "+slug)
+	require.NoError(t, WriteInstallInfoManifest(installDir, testVerifyManifest, InstallInfoManifest{
+		Repo: "skills-local", Slug: slug, InstalledVersion: "1.0.0", Scope: string(InstallScopePath), Agent: PathAgentName,
+	}))
+	if withInventory {
+		files, err := HashPackageTree(installDir)
+		require.NoError(t, err)
+		require.NoError(t, WriteFileInventory(installDir, files))
+	}
+	return installDir
+}
+
+func TestVerifyInstalls_ReportsTamperingPerInstall(t *testing.T) {
+	base := t.TempDir()
+	installForVerify(t, base, "clean", true)
+	tamperedDir := installForVerify(t, base, "tampered", true)
+	installForVerify(t, base, "legacy", false)
+	writeTestFile(t, filepath.Join(tamperedDir, "SKILL.md"), "# injected")
+
+	targets, err := VerifyTargets("", InstallFlagsResult{AbsoluteInstallBaseDir: base}, testVerifyManifest)
+	require.NoError(t, err)
+	results := VerifyInstalls(targets, VerifyOptions{ManifestFileName: testVerifyManifest})
+	require.Len(t, results, 3)
+
+	statuses := map[string]string{}
+	for _, result := range results {
+		statuses[result.Name] = result.Status
+	}
+	assert.Equal(t, map[string]string{"clean": SummaryStatusOK, "legacy": VerifyStatusUnverified, "tampered": VerifyStatusModified}, statuses)
+	assert.Equal(t, "1 modified: SKILL.md", results[2].Detail)
+	assert.Equal(t, []string{"SKILL.md"}, results[2].Diff.Modified)
+
+	err = VerifyError("Skill", results)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 skill install(s) failed verification")
+}
+
+func TestVerifyInstalls_SlugNotInstalled(t *testing.T) {
+	targets, err := VerifyTargets("missing", InstallFlagsResult{AbsoluteInstallBaseDir: t.TempDir()}, testVerifyManifest)
+	require.NoError(t, err)
+	results := VerifyInstalls(targets, VerifyOptions{ManifestFileName: testVerifyManifest})
+	require.Len(t, results, 1)
+	assert.Equal(t, SummaryStatusSkipped, results[0].Status)
+	assert.NoError(t, VerifyError("Skill", results))
+}
+
+func TestVerifyInstalls_Evidence(t *testing.T) {
+	base := t.TempDir()
+	installForVerify(t, base, "web", true)
+	orig := verifyInstalledEvidence
+	t.Cleanup(func() { verifyInstalledEvidence = orig })
+
+	var checked string
+	verifyInstalledEvidence = func(_ *config.ServerDetails, repoKey, slug, version string) error {
+		checked = repoKey + "/" + slug + "@" + version
+		return errors.New("no evidence found")
+	}
+	targets, err := VerifyTargets("web", InstallFlagsResult{AbsoluteInstallBaseDir: base}, testVerifyManifest)
+	require.NoError(t, err)
+	results := VerifyInstalls(targets, VerifyOptions{ManifestFileName: testVerifyManifest, CheckEvidence: true})
+
+	require.Len(t, results, 1)
+	assert.Equal(t, "skills-local/web@1.0.0", checked)
+	assert.Equal(t, SummaryStatusOK, results[0].Status)
+	assert.Equal(t, VerifyEvidenceFailed, results[0].Evidence)
+	assert.Contains(t, results[0].Detail, "evidence: no evidence found")
+	assert.Error(t, VerifyError("Skill", results))
+}
+
+func TestDescribeInventoryDiff_TruncatesPaths(t *testing.T) {
+	diff := InventoryDiff{Modified: []string{"a", "b", "c"}, Missing: []string{"d", "e", "f", "g"}}
+	assert.Equal(t, "3 modified, 4 missing: a, b, c, d, e, and 2 more", describeInventoryDiff(diff))
+}
+
+func TestVerifyTargets_HarnessDiscovery(t *testing.T) {
+	projectDir := t.TempDir()
+	spec := AgentSpec{Name: "cursor", Config: AgentConfig{ProjectDir: ".cursor/skills"}}
+	installDir, err := ResolveAgentInstallDir(spec, projectDir, false)
+	require.NoError(t, err)
+	installForVerify(t, installDir, "web", true)
+	require.NoError(t, os.MkdirAll(filepath.Join(installDir, "handmade"), InstallDirMode))
+
+	targets, err := VerifyTargets("", InstallFlagsResult{Specs: []AgentSpec{spec}, ProjectDirAbs: projectDir}, testVerifyManifest)
+	require.NoError(t, err)
+	require.Len(t, targets, 1)
+	assert.Equal(t, filepath.Join(installDir, "web"), targets[0].DestinationDir)
+	assert.Equal(t, InstallScopeProject, targets[0].Scope)
+}
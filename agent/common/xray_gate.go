@@ -0,0 +1,283 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	corelog "github.com/jfrog/jfrog-cli-core/v2/utils/log"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/progressbar"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	defaultXrayGateTimeout = 5 * time.Minute
+	xrayPollInterval       = 5 * time.Second
+)
+
+// XrayGateKind describes how the Xray scan gate is reached and configured for one package kind.
+type XrayGateKind struct {
+	// Label names the package kind in messages, e.g. "Skill".
+	Label string
+	// API is the Artifactory package API serving api/<API>/<repoKey>/xrayStatus.
+	API string
+	// SkipEnvVar skips the publish gate when set to true.
+	SkipEnvVar string
+	// TimeoutEnvVar overrides how long publish waits for a scan in progress (a Go duration).
+	TimeoutEnvVar string
+}
+
+// XrayGateSkills is the scan gate of skills repositories.
+var XrayGateSkills = XrayGateKind{
+	Label:         "Skill",
+	API:           "skills",
+	SkipEnvVar:    "JFROG_CLI_SKIP_SKILLS_SCAN",
+	TimeoutEnvVar: "JFROG_CLI_SKILLS_SCAN_TIMEOUT",
+}
+
+// XrayGatePlugins is the scan gate of agent plugins repositories.
+var XrayGatePlugins = XrayGateKind{
+	Label:         "Plugin",
+	API:           "agentplugins",
+	SkipEnvVar:    "JFROG_CLI_SKIP_AGENT_PLUGINS_SCAN",
+	TimeoutEnvVar: "JFROG_CLI_AGENT_PLUGINS_SCAN_TIMEOUT",
+}
+
+// lookupXrayStatus is swappable in tests.
+var lookupXrayStatus = fetchXrayStatus
+
+// XrayGateParams contains the parameters for the Xray scan gate check.
+type XrayGateParams struct {
+	Kind                XrayGateKind
+	ServerDetails       *config.ServerDetails
+	RepoKey             string
+	ArtifactPath        string // in-repo path: "slug/version/slug-version.zip"
+	Slug                string
+	Version             string
+	SkipScan            bool
+	AutoDeleteOnFailure bool
+	Quiet               bool
+}
+
+// PackageArtifactPath returns the in-repo path of the zip published for slug at version.
+func PackageArtifactPath(slug, version string) string {
+	return fmt.Sprintf("%s/%s/%s-%s.zip", slug, version, slug, version)
+}
+
+// CheckXrayGate calls the Artifactory Xray gate endpoint of params.Kind after publish.
+// It polls until a terminal status is reached, then acts based on the result.
+// Returns an error only if the scan detects malicious content (BLOCKED).
+func CheckXrayGate(params XrayGateParams) error {
+	if params.SkipScan || IsEnvTrue(params.Kind.SkipEnvVar) {
+		log.Info("Xray scan check skipped.")
+		return nil
+	}
+
+	status, err := lookupXrayStatus(params.ServerDetails, params.Kind, params.RepoKey, params.ArtifactPath)
+	if err != nil {
+		log.Warn("Xray gate check failed:", err.Error())
+		return nil
+	}
+
+	switch status {
+	case services.SkillXrayStatusNotInEntitlement:
+		log.Debug("Xray entitlement not active. Skipping scan gate.")
+		return nil
+	case services.SkillXrayStatusDisabledForRepo:
+		log.Info(fmt.Sprintf("Xray scanning is disabled for repository '%s'. Skipping scan.", params.RepoKey))
+		return nil
+	case services.SkillXrayStatusApproved:
+		logScanPassed(params)
+		return nil
+	case services.SkillXrayStatusBlocked:
+		return handleBlocked(params)
+	case services.SkillXrayStatusScanInProgress:
+		return pollUntilDone(params)
+	default:
+		log.Warn(fmt.Sprintf("Unknown Xray gate status: %s. Skipping scan gate.", status))
+		return nil
+	}
+}
+
+func pollUntilDone(params XrayGateParams) error {
+	log.Info("Scanning for malicious content...")
+
+	timeout := resolveTimeout(params.Kind)
+	ticker := time.NewTicker(xrayPollInterval)
+	defer ticker.Stop()
+	deadline := time.After(timeout)
+	pollCount := 0
+	startTime := time.Now()
+
+	// Use a spinner only for interactive terminals that are not in quiet mode.
+	// NewBarsMng() redirects logs to a file, so we must not call it in quiet/CI mode.
+	useSpinner := false
+	var mng *progressbar.ProgressBarMng
+	var spinner interface{ Abort(bool) }
+	if !params.Quiet && !IsNonInteractive() {
+		var shouldInit bool
+		mng, shouldInit, _ = progressbar.NewBarsMng()
+		if shouldInit && mng != nil {
+			useSpinner = true
+			mng.GetBarsWg().Add(1)
+			spinner = mng.NewUpdatableHeadlineBarWithSpinner(func() string {
+				elapsed := time.Since(startTime).Truncate(time.Second)
+				return fmt.Sprintf(" Scanning for malicious content... (%s elapsed, %d polls)", elapsed, pollCount)
+			})
+		}
+	}
+
+	stopSpinner := func() {
+		if !useSpinner {
+			return
+		}
+		useSpinner = false
+		spinner.Abort(true)
+		mng.GetBarsWg().Done()
+		time.Sleep(progressbar.ProgressRefreshRate)
+		if logFile := mng.GetLogFile(); logFile != nil {
+			_ = corelog.CloseLogFile(logFile)
+		}
+	}
+	defer stopSpinner()
+
+	for {
+		select {
+		case <-deadline:
+			stopSpinner()
+			log.Warn(fmt.Sprintf("Xray scan did not complete within %s after %d polls. The scan may still be in progress on the server.", timeout, pollCount))
+			return nil
+		case <-ticker.C:
+			pollCount++
+			if !useSpinner {
+				log.Debug(fmt.Sprintf("Xray scan poll attempt %d...", pollCount))
+			}
+			status, err := lookupXrayStatus(params.ServerDetails, params.Kind, params.RepoKey, params.ArtifactPath)
+			if err != nil {
+				log.Debug("Poll error (will retry):", err.Error())
+				continue
+			}
+			switch status {
+			case services.SkillXrayStatusApproved:
+				stopSpinner()
+				logScanPassed(params)
+				return nil
+			case services.SkillXrayStatusBlocked:
+				stopSpinner()
+				return handleBlocked(params)
+			case services.SkillXrayStatusScanInProgress:
+				continue
+			default:
+				stopSpinner()
+				log.Warn(fmt.Sprintf("Unexpected Xray gate status during polling: %s", status))
+				return nil
+			}
+		}
+	}
+}
+
+func logScanPassed(params XrayGateParams) {
+	log.Info(fmt.Sprintf("[SUCCESS] %s \"%s\" v%s passed security scan.", params.Kind.Label, params.Slug, params.Version))
+}
+
+func handleBlocked(params XrayGateParams) error {
+	label := strings.ToLower(params.Kind.Label)
+	log.Error(fmt.Sprintf("[VIOLATION] %s \"%s\" v%s identified as malicious.", params.Kind.Label, params.Slug, params.Version))
+	if params.AutoDeleteOnFailure {
+		deletePath := fmt.Sprintf("%s/%s/%s/", params.RepoKey, params.Slug, params.Version)
+		if err := DeleteVersion(params.ServerDetails, params.RepoKey, params.Slug, params.Version); err != nil {
+			log.Error(fmt.Sprintf("Failed to delete malicious %s artifact '%s' from '%s': %s", label, deletePath, params.RepoKey, err.Error()))
+		} else {
+			log.Info(fmt.Sprintf("Malicious artifact deleted: %s", deletePath))
+		}
+	}
+	return fmt.Errorf("%s %q v%s was blocked by Xray security scan", label, params.Slug, params.Version)
+}
+
+// XrayStatus returns the Xray gate status of a published package version without waiting for a scan to finish.
+func XrayStatus(serverDetails *config.ServerDetails, kind XrayGateKind, repoKey, slug, version string) (string, error) {
+	return lookupXrayStatus(serverDetails, kind, repoKey, PackageArtifactPath(slug, version))
+}
+
+// CheckXrayInstallGate refuses to install a version that Xray has blocked or is still scanning, unless
+// skipCheck is set. When the status cannot be read, the install proceeds with a warning, as publish does.
+func CheckXrayInstallGate(serverDetails *config.ServerDetails, kind XrayGateKind, repoKey, slug, version string, skipCheck bool) error {
+	label := strings.ToLower(kind.Label)
+	status, err := XrayStatus(serverDetails, kind, repoKey, slug, version)
+	if err != nil {
+		log.Warn(fmt.Sprintf("Could not read the Xray scan status of %s '%s' v%s: %s", label, slug, version, err.Error()))
+		return nil
+	}
+	var refusal string
+	switch status {
+	case services.SkillXrayStatusBlocked:
+		refusal = fmt.Sprintf("%s '%s' v%s is blocked by Xray security scan", label, slug, version)
+	case services.SkillXrayStatusScanInProgress:
+		refusal = fmt.Sprintf("the Xray security scan of %s '%s' v%s is still in progress; retry once it completes", label, slug, version)
+	default:
+		return nil
+	}
+	if skipCheck {
+		log.Warn(refusal + ". Installing anyway because --skip-scan-check is set.")
+		return nil
+	}
+	return fmt.Errorf("%s. Use --skip-scan-check to install it anyway", refusal)
+}
+
+// fetchXrayStatus reads the gate status from api/<kind.API>/<repoKey>/xrayStatus.
+func fetchXrayStatus(serverDetails *config.ServerDetails, kind XrayGateKind, repoKey, artifactPath string) (string, error) {
+	sm, err := utils.CreateServiceManager(serverDetails, 3, 0, false)
+	if err != nil {
+		return "", fmt.Errorf("could not create service manager for Xray gate check: %w", err)
+	}
+	return fetchXrayStatusWith(sm, kind, repoKey, artifactPath)
+}
+
+// fetchXrayStatusWith reads the Xray gate status of artifactPath through sm, so callers that look up many
+// artifacts can share one services manager.
+func fetchXrayStatusWith(sm artifactory.ArtifactoryServicesManager, kind XrayGateKind, repoKey, artifactPath string) (string, error) {
+	if kind.API == XrayGateSkills.API {
+		resp, err := sm.GetSkillXrayStatus(repoKey, artifactPath)
+		if err != nil {
+			return "", err
+		}
+		return resp.Status, nil
+	}
+	artURL := clientutils.AddTrailingSlashIfNeeded(sm.GetConfig().GetServiceDetails().GetUrl())
+	statusURL := fmt.Sprintf("%sapi/%s/%s/xrayStatus?path=%s", artURL, kind.API, repoKey, url.QueryEscape(artifactPath))
+	log.Debug("Xray status request:", statusURL)
+	httpDetails := sm.GetConfig().GetServiceDetails().CreateHttpClientDetails()
+	resp, body, _, err := sm.Client().SendGet(statusURL, true, &httpDetails)
+	if err != nil {
+		return "", err
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return "", err
+	}
+	var result services.SkillXrayStatusResponse
+	if err = json.Unmarshal(body, &result); err != nil {
+		return "", errorutils.CheckErrorf("failed to parse xray status response: %s", err.Error())
+	}
+	return result.Status, nil
+}
+
+func resolveTimeout(kind XrayGateKind) time.Duration {
+	if v := os.Getenv(kind.TimeoutEnvVar); v != "" {
+		d, err := time.ParseDuration(v)
+		if err == nil && d > 0 {
+			return d
+		}
+		log.Warn(fmt.Sprintf("Invalid %s value '%s', using default %s", kind.TimeoutEnvVar, v, defaultXrayGateTimeout))
+	}
+	return defaultXrayGateTimeout
+}
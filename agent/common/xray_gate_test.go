@@ -0,0 +1,119 @@
+package common
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func stubXrayStatus(t *testing.T, status string, err error) *[]string {
+	t.Helper()
+	restore := lookupXrayStatus
+	t.Cleanup(func() { lookupXrayStatus = restore })
+	var requested []string
+	lookupXrayStatus = func(_ *config.ServerDetails, kind XrayGateKind, repoKey, artifactPath string) (string, error) {
+		requested = append(requested, kind.API+":"+repoKey+"/"+artifactPath)
+		return status, err
+	}
+	return &requested
+}
+
+func TestCheckXrayGate_SkipScan(t *testing.T) {
+	err := CheckXrayGate(XrayGateParams{Kind: XrayGateSkills, SkipScan: true})
+	assert.NoError(t, err)
+}
+
+func TestCheckXrayGate_SkipViaEnv(t *testing.T) {
+	t.Setenv(XrayGateSkills.SkipEnvVar, "true")
+	err := CheckXrayGate(XrayGateParams{Kind: XrayGateSkills})
+	assert.NoError(t, err)
+}
+
+func TestCheckXrayGate_PluginBlocked(t *testing.T) {
+	requested := stubXrayStatus(t, services.SkillXrayStatusBlocked, nil)
+	err := CheckXrayGate(XrayGateParams{
+		Kind:         XrayGatePlugins,
+		RepoKey:      "plugins-local",
+		ArtifactPath: PackageArtifactPath("lint", "1.0.0"),
+		Slug:         "lint",
+		Version:      "1.0.0",
+	})
+	assert.EqualError(t, err, `plugin "lint" v1.0.0 was blocked by Xray security scan`)
+	assert.Equal(t, []string{"agentplugins:plugins-local/lint/1.0.0/lint-1.0.0.zip"}, *requested)
+}
+
+func TestCheckXrayGate_StatusErrorDoesNotFail(t *testing.T) {
+	stubXrayStatus(t, "", errors.New("connection refused"))
+	assert.NoError(t, CheckXrayGate(XrayGateParams{Kind: XrayGatePlugins}))
+}
+
+func TestResolveTimeout_Default(t *testing.T) {
+	t.Setenv(XrayGateSkills.TimeoutEnvVar, "")
+	d := resolveTimeout(XrayGateSkills)
+	assert.Equal(t, defaultXrayGateTimeout, d)
+}
+
+func TestResolveTimeout_Custom(t *testing.T) {
+	t.Setenv(XrayGatePlugins.TimeoutEnvVar, "10m")
+	d := resolveTimeout(XrayGatePlugins)
+	assert.Equal(t, 10*time.Minute, d)
+}
+
+func TestResolveTimeout_Invalid(t *testing.T) {
+	t.Setenv(XrayGateSkills.TimeoutEnvVar, "not-a-duration")
+	d := resolveTimeout(XrayGateSkills)
+	assert.Equal(t, defaultXrayGateTimeout, d, "invalid value should fall back to default")
+}
+
+func TestHandleBlocked_NoAutoDelete(t *testing.T) {
+	params := XrayGateParams{
+		Kind:                XrayGateSkills,
+		Slug:                "test-skill",
+		Version:             "1.0.0",
+		AutoDeleteOnFailure: false,
+	}
+	err := handleBlocked(params)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "blocked by Xray security scan")
+}
+
+func TestCheckXrayInstallGate(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    string
+		statusErr error
+		skip      bool
+		wantErr   string
+	}{
+		{name: "approved", status: services.SkillXrayStatusApproved},
+		{name: "not entitled", status: services.SkillXrayStatusNotInEntitlement},
+		{name: "blocked", status: services.SkillXrayStatusBlocked, wantErr: "plugin 'lint' v1.0.0 is blocked by Xray security scan. Use --skip-scan-check"},
+		{name: "in progress", status: services.SkillXrayStatusScanInProgress, wantErr: "still in progress"},
+		{name: "blocked with override", status: services.SkillXrayStatusBlocked, skip: true},
+		{name: "status unavailable", statusErr: errors.New("404 not found")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubXrayStatus(t, tt.status, tt.statusErr)
+			err := CheckXrayInstallGate(nil, XrayGatePlugins, "plugins-local", "lint", "1.0.0", tt.skip)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestXrayStatusConstants(t *testing.T) {
+	// Verify constants match expected values from the API spec.
+	assert.Equal(t, "NOT_IN_ENTITLEMENT", services.SkillXrayStatusNotInEntitlement)
+	assert.Equal(t, "XRAY_DISABLED_FOR_REPO", services.SkillXrayStatusDisabledForRepo)
+	assert.Equal(t, "SCAN_IN_PROGRESS", services.SkillXrayStatusScanInProgress)
+	assert.Equal(t, "BLOCKED", services.SkillXrayStatusBlocked)
+	assert.Equal(t, "APPROVED", services.SkillXrayStatusApproved)
+}
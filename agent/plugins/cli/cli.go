@@ -1,12 +1,22 @@
 package cli
 
 import (
+	"github.com/jfrog/jfrog-cli-artifactory/agent/plugins/commands/bundle"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/plugins/commands/convert"
 	"github.com/jfrog/jfrog-cli-artifactory/agent/plugins/commands/delete"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/plugins/commands/deprecate"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/plugins/commands/diff"
 	"github.com/jfrog/jfrog-cli-artifactory/agent/plugins/commands/install"
 	"github.com/jfrog/jfrog-cli-artifactory/agent/plugins/commands/list"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/plugins/commands/marketplace"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/plugins/commands/outdated"
 	"github.com/jfrog/jfrog-cli-artifactory/agent/plugins/commands/publish"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/plugins/commands/rollback"
 	"github.com/jfrog/jfrog-cli-artifactory/agent/plugins/commands/search"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/plugins/commands/sync"
 	"github.com/jfrog/jfrog-cli-artifactory/agent/plugins/commands/update"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/plugins/commands/validate"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/plugins/commands/verify"
 	"github.com/jfrog/jfrog-cli-artifactory/cliutils/flagkit"
 	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
 )
@@ -41,6 +51,27 @@ func GetSubCommands() []components.Command {
 			Arguments:   getDeleteArguments(),
 			Action:      delete.RunDelete,
 		},
+		{
+			Name:        "deprecate",
+			Flags:       flagkit.GetCommandFlags(flagkit.AgentPluginsDeprecate),
+			Description: "Mark a published agent plugin version as deprecated, or remove the mark with --undo.",
+			Arguments:   getDeprecateArguments(),
+			Action:      deprecate.RunDeprecate,
+		},
+		{
+			Name:        "diff",
+			Flags:       flagkit.GetCommandFlags(flagkit.AgentPluginsDiff),
+			Description: "Show what changed between two versions of a agent plugin, or between the installed copy and a version.",
+			Arguments:   getDiffArguments(),
+			Action:      diff.RunDiff,
+		},
+		{
+			Name:        "convert",
+			Flags:       flagkit.GetCommandFlags(flagkit.AgentPluginsConvert),
+			Description: "Write a copy of a local agent plugin in the layout another harness loads (claude, cursor, codex, or copilot), reporting components that could not be translated.",
+			Arguments:   getConvertArguments(),
+			Action:      convert.RunConvert,
+		},
 		{
 			Name:        "list",
 			Flags:       flagkit.GetCommandFlags(flagkit.AgentPluginsList),
@@ -54,6 +85,69 @@ func GetSubCommands() []components.Command {
 			Arguments:   getSearchArguments(),
 			Action:      search.RunSearch,
 		},
+		{
+			Name:        "sync",
+			Flags:       flagkit.GetCommandFlags(flagkit.AgentPluginsSync),
+			Description: "Install, update, and optionally prune agent plugins to match the project manifest.",
+			Action:      sync.RunSync,
+		},
+		{
+			Name:        "verify",
+			Flags:       flagkit.GetCommandFlags(flagkit.AgentPluginsVerify),
+			Description: "Check installed agent plugins for files modified, added, or removed since install.",
+			Arguments:   getVerifyArguments(),
+			Action:      verify.RunVerify,
+		},
+		{
+			Name:        "export",
+			Flags:       flagkit.GetCommandFlags(flagkit.AgentPluginsExport),
+			Description: "Write agent plugins and their evidence to a bundle archive for air-gapped environments.",
+			Arguments:   getExportArguments(),
+			Action:      bundle.RunExport,
+		},
+		{
+			Name:        "import",
+			Flags:       flagkit.GetCommandFlags(flagkit.AgentPluginsImport),
+			Description: "Install agent plugins from a bundle archive, or publish them to another repository.",
+			Arguments:   getImportArguments(),
+			Action:      bundle.RunImport,
+		},
+		{
+			Name:        "rollback",
+			Flags:       flagkit.GetCommandFlags(flagkit.AgentPluginsRollback),
+			Description: "Restore the agent plugin version installed before the last update.",
+			Arguments:   getRollbackArguments(),
+			Action:      rollback.RunRollback,
+		},
+		{
+			Name:        "outdated",
+			Flags:       flagkit.GetCommandFlags(flagkit.AgentPluginsOutdated),
+			Description: "Report installed agent plugins in every harness with current, wanted, and latest versions, without changing anything.",
+			Action:      outdated.RunOutdated,
+		},
+		{
+			Name:        "validate",
+			Flags:       flagkit.GetCommandFlags(flagkit.AgentPluginsValidate),
+			Description: "Check agent plugins against the publish rules (manifest fields, file sizes, binaries, likely secrets, absolute paths, broken links) without contacting the server.",
+			Arguments:   getValidateArguments(),
+			Action:      validate.RunValidate,
+		},
+		{
+			Name:        "marketplace",
+			Description: "Agent plugin marketplace commands.",
+			Subcommands: getMarketplaceSubCommands(),
+		},
+	}
+}
+
+func getMarketplaceSubCommands() []components.Command {
+	return []components.Command{
+		{
+			Name:        "generate",
+			Flags:       flagkit.GetCommandFlags(flagkit.AgentPluginsMarketplaceGenerate),
+			Description: "Generate <harness>-marketplace.json from the latest plugin versions in the repository and upload it, or report drift with --check.",
+			Action:      marketplace.RunGenerate,
+		},
 	}
 }
 
@@ -79,7 +173,7 @@ func getInstallArguments() []components.Argument {
 	return []components.Argument{
 		{
 			Name:        "slug",
-			Description: "Agent plugin slug to install.",
+			Description: "Agent plugin slug to install. Optional with --frozen, which installs every pinned plugin.",
 		},
 	}
 }
@@ -92,3 +186,79 @@ func getDeleteArguments() []components.Argument {
 		},
 	}
 }
+
+func getDeprecateArguments() []components.Argument {
+	return []components.Argument{
+		{
+			Name:        "slug",
+			Description: "Agent plugin slug to deprecate.",
+		},
+	}
+}
+
+func getDiffArguments() []components.Argument {
+	return []components.Argument{
+		{
+			Name:        "slug",
+			Description: "Plugin slug to compare.",
+		},
+		{
+			Name:        "versions",
+			Description: "The version to compare from and the version to compare to, or one version with --installed.",
+		},
+	}
+}
+
+func getVerifyArguments() []components.Argument {
+	return []components.Argument{
+		{
+			Name:        "slug",
+			Description: "Agent plugin slug to verify. Optional; verifies every installed plugin when omitted.",
+		},
+	}
+}
+
+func getExportArguments() []components.Argument {
+	return []components.Argument{
+		{
+			Name:        "slugs",
+			Description: "Agent plugin slugs to export, each optionally with @<version> or @<range> (default: latest).",
+		},
+	}
+}
+
+func getImportArguments() []components.Argument {
+	return []components.Argument{
+		{
+			Name:        "bundle",
+			Description: "Path to a bundle archive written by export.",
+		},
+	}
+}
+
+func getRollbackArguments() []components.Argument {
+	return []components.Argument{
+		{
+			Name:        "slug",
+			Description: "Agent plugin slug to roll back.",
+		},
+	}
+}
+
+func getConvertArguments() []components.Argument {
+	return []components.Argument{
+		{
+			Name:        "path",
+			Description: "Path to the agent plugin folder to convert.",
+		},
+	}
+}
+
+func getValidateArguments() []components.Argument {
+	return []components.Argument{
+		{
+			Name:        "path",
+			Description: "Path to an agent plugin folder. Several paths may be given.",
+		},
+	}
+}
@@ -0,0 +1,98 @@
+package bundle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/plugins/commands/install"
+	plugincommon "github.com/jfrog/jfrog-cli-artifactory/agent/plugins/common"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// RunImport is the CLI action for `jf agent plugins import`.
+// It installs the plugins of an export bundle without reaching Artifactory, or re-publishes them with --publish.
+func RunImport(c *components.Context) error {
+	if c.GetNumberOfArgs() != 1 {
+		return fmt.Errorf("usage: jf agent plugins import <bundle> ((--harness <name[,name...]> | --auto-harness) [--global] [--project-dir <dir>] | --path <dir> | --publish [--repo <repo>]) [--public-keys <file[,file...]>] [--format <table|json>]")
+	}
+	publish := c.GetBoolFlagValue("publish")
+	if publish && (c.GetStringFlagValue(agentcommon.InstallHarnessFlag) != "" || c.GetStringFlagValue(agentcommon.InstallPathFlag) != "") {
+		return fmt.Errorf("--publish cannot be combined with --harness or --path")
+	}
+	format := "table"
+	if c.GetStringFlagValue("format") != "" {
+		format = c.GetStringFlagValue("format")
+	}
+
+	tmpDir, err := os.MkdirTemp("", "plugin-import-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer func() {
+		// Best-effort cleanup of import temp dir.
+		_ = os.RemoveAll(tmpDir)
+	}()
+	bundle, err := agentcommon.OpenBundle(c.GetArgumentAt(0), filepath.Join(tmpDir, "bundle"))
+	if err != nil {
+		return err
+	}
+
+	if publish {
+		serverDetails, err := agentcommon.GetServerDetails(c)
+		if err != nil {
+			return err
+		}
+		repoKey, err := agentcommon.ResolveRepo(serverDetails, c.GetStringFlagValue("repo"), agentcommon.IsQuiet(c), plugincommon.RepoOptions())
+		if err != nil {
+			return err
+		}
+		results := agentcommon.PublishBundle(serverDetails, repoKey, bundle, agentcommon.LockKindPlugin)
+		return agentcommon.PrintBundlePublishSummary("Plugin", results, format)
+	}
+
+	flags, err := agentcommon.ValidateInstallFlags(c, plugincommon.Agents, agentcommon.PluginsAgentsKey, plugincommon.RegistryHelp)
+	if err != nil {
+		return err
+	}
+	evidenceKeys, err := agentcommon.LoadEvidenceKeys(strings.Split(c.GetStringFlagValue("public-keys"), ","))
+	if err != nil {
+		return err
+	}
+	packages := bundle.Packages(agentcommon.LockKindPlugin)
+	if len(packages) == 0 {
+		log.Info("The bundle contains no plugins.")
+		return nil
+	}
+	// Bundled installs apply only the policy in agent-config.json, read once for the whole bundle.
+	policyLoader := agentcommon.NewInstallPolicyLoader(nil)
+	var failed []string
+	for _, pkg := range packages {
+		// Dependencies cannot be resolved offline; export them alongside the plugins that need them.
+		cmd := install.NewInstallCommand().
+			SetRepoKey(pkg.Repo).
+			SetSlug(pkg.Slug).
+			SetVersion(pkg.Version).
+			SetFormat(format).
+			SetQuiet(agentcommon.IsQuiet(c)).
+			SetNoDeps(true).
+			SetBundledZip(bundle.File(pkg.Zip), bundle.HasVerifiedEvidence(pkg, evidenceKeys)).
+			SetPolicyLoader(policyLoader)
+		if flags.PathMode() {
+			cmd.SetInstallPath(flags.AbsoluteInstallBaseDir)
+		} else {
+			cmd.SetAgents(flags.Specs).SetGlobal(flags.IsGlobal).SetProjectDir(flags.ProjectDirAbs)
+		}
+		if err := cmd.Run(); err != nil {
+			log.Error(fmt.Sprintf("Import of plugin '%s' failed: %s", pkg.Slug, err.Error()))
+			failed = append(failed, pkg.Slug)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("import failed for plugin(s): %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
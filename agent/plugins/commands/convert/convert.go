@@ -0,0 +1,55 @@
+package convert
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	plugincommon "github.com/jfrog/jfrog-cli-artifactory/agent/plugins/common"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+)
+
+// RunConvert is the CLI action for `jf agent plugins convert <path> --to <harness>`.
+// It writes a copy of the plugin in the layout the target harness loads and reports the components that could
+// not be translated. The source directory is left untouched.
+func RunConvert(c *components.Context) error {
+	to := strings.ToLower(strings.TrimSpace(c.GetStringFlagValue("to")))
+	if c.GetNumberOfArgs() != 1 || to == "" {
+		return fmt.Errorf("usage: jf agent plugins convert <path> --to <%s> [--output <dir>] [--format <table|json>]", strings.Join(plugincommon.KnownPluginLayouts(), "|"))
+	}
+	format := "table"
+	if c.GetStringFlagValue("format") != "" {
+		format = c.GetStringFlagValue("format")
+	}
+	arg := c.GetArgumentAt(0)
+	srcDir, err := filepath.Abs(arg)
+	if err != nil {
+		return fmt.Errorf("invalid plugin path: %w", err)
+	}
+	info, err := os.Stat(srcDir)
+	if err != nil || !info.IsDir() {
+		return fmt.Errorf("plugin path '%s' is not a valid directory", arg)
+	}
+
+	output := strings.TrimSpace(c.GetStringFlagValue("output"))
+	if output == "" {
+		output = srcDir + "-" + to
+	}
+	dstDir, err := filepath.Abs(output)
+	if err != nil {
+		return fmt.Errorf("invalid output path: %w", err)
+	}
+	if dstDir == srcDir {
+		return fmt.Errorf("output directory must differ from the plugin directory")
+	}
+	if entries, err := os.ReadDir(dstDir); err == nil && len(entries) > 0 {
+		return fmt.Errorf("output directory '%s' is not empty", dstDir)
+	}
+
+	report, err := plugincommon.ConvertPlugin(srcDir, dstDir, to)
+	if err != nil {
+		return err
+	}
+	return plugincommon.PrintConversionReport(report, format)
+}
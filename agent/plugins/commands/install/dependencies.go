@@ -0,0 +1,165 @@
+package install
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+	plugincommon "github.com/jfrog/jfrog-cli-artifactory/agent/plugins/common"
+	skillinstall "github.com/jfrog/jfrog-cli-artifactory/agent/skills/commands/install"
+	skillpublish "github.com/jfrog/jfrog-cli-artifactory/agent/skills/commands/publish"
+	skillcommon "github.com/jfrog/jfrog-cli-artifactory/agent/skills/common"
+)
+
+// resolveSkillVersion is swappable in tests.
+var resolveSkillVersion = skillcommon.ResolveSkillVersion
+
+// resolveSkillsRepo is swappable in tests.
+var resolveSkillsRepo = agentcommon.ResolveRepo
+
+// installDependencies resolves the skills and plugins declared in the extracted plugin's plugin.json
+// and installs them into the same harnesses. It returns one summary row per dependency target.
+func (ic *InstallCommand) installDependencies(unzipDir, tmpDir string) ([]agentcommon.SummaryRow, error) {
+	direct, err := readPluginDependencies(unzipDir)
+	if err != nil {
+		return nil, fmt.Errorf("plugin '%s' version '%s': %w", ic.slug, ic.version, err)
+	}
+	if len(direct) == 0 {
+		return nil, nil
+	}
+	installer := plugincommon.NewDependencyInstaller(filepath.Join(tmpDir, "dependencies"), ic.policy, map[string]plugincommon.DependencyKind{
+		agentcommon.LockKindPlugin: ic.pluginDependencyKind(),
+		agentcommon.LockKindSkill:  ic.skillDependencyKind(),
+	})
+	resolved, err := installer.Resolve(agentcommon.LockKindPlugin, ic.slug, ic.version, direct)
+	if err != nil {
+		return nil, fmt.Errorf("plugin '%s' version '%s': %w", ic.slug, ic.version, err)
+	}
+	return installer.Install(resolved), nil
+}
+
+func readPluginDependencies(unzipDir string) ([]agentcommon.PackageDependency, error) {
+	meta, err := plugincommon.ReadPluginMeta(unzipDir)
+	if err != nil {
+		if errors.Is(err, plugincommon.ErrPluginManifestNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return meta.PackageDependencies()
+}
+
+// dependencyCommand installs a plugin dependency with the root install's repository, scope, harnesses, policy, and Xray gate.
+func (ic *InstallCommand) dependencyCommand(slug, version string) *InstallCommand {
+	return &InstallCommand{
+		serverDetails: ic.serverDetails,
+		repoKey:       ic.repoKey,
+		slug:          slug,
+		version:       version,
+		agents:        ic.agents,
+		scope:         ic.scope,
+		projectDir:    ic.projectDir,
+		installPath:   ic.installPath,
+		format:        ic.format,
+		quiet:         ic.quiet,
+		noCache:       ic.noCache,
+		skipScanCheck: ic.skipScanCheck,
+		policy:        ic.policy,
+		policyLoader:  ic.policyLoader,
+	}
+}
+
+func (ic *InstallCommand) pluginDependencyKind() plugincommon.DependencyKind {
+	return plugincommon.DependencyKind{
+		ResolveVersion: func(slug, requested string) (string, error) {
+			return resolvePluginVersion(ic.serverDetails, ic.repoKey, slug, requested, ic.quiet)
+		},
+		NewPackage: func(slug, version string) plugincommon.DependencyPackage {
+			return ic.dependencyCommand(slug, version)
+		},
+		ReadDependencies: readPluginDependencies,
+		Targets: func(slug string) ([]plugincommon.AgentTarget, error) {
+			return ic.dependencyCommand(slug, "").resolveAgentTargetDirectories()
+		},
+	}
+}
+
+// skillDependencyKind installs skill dependencies from the skills repository into the skills
+// directories of the harnesses the plugin is installed for (e.g. plugin harness "claude" -> skills "claude-code").
+func (ic *InstallCommand) skillDependencyKind() plugincommon.DependencyKind {
+	if ic.installPath != "" {
+		unsupported := func(string, string) (string, error) {
+			return "", fmt.Errorf("--path installs cannot place skill dependencies; install them with 'jf agent skills install' or pass --no-deps")
+		}
+		return plugincommon.DependencyKind{ResolveVersion: unsupported}
+	}
+	skills := &skillDependencies{root: ic}
+	return plugincommon.DependencyKind{
+		ResolveVersion: func(slug, requested string) (string, error) {
+			if err := skills.prepare(); err != nil {
+				return "", err
+			}
+			return resolveSkillVersion(ic.serverDetails, skills.repoKey, slug, requested, ic.quiet)
+		},
+		NewPackage: func(slug, version string) plugincommon.DependencyPackage {
+			return skillinstall.NewInstallCommand().
+				SetServerDetails(ic.serverDetails).
+				SetRepoKey(skills.repoKey).
+				SetSlug(slug).
+				SetVersion(version).
+				SetAgents(skills.agents).
+				SetGlobal(skills.isGlobal()).
+				SetProjectDir(ic.projectDir).
+				SetQuiet(ic.quiet).
+				SetNoCache(ic.noCache).
+				SetPolicyLoader(ic.policyLoader)
+		},
+		ReadDependencies: func(unzipDir string) ([]agentcommon.PackageDependency, error) {
+			meta, err := skillpublish.ParseSkillMeta(unzipDir)
+			if err != nil {
+				return nil, err
+			}
+			return meta.Dependencies, nil
+		},
+		Targets: func(slug string) ([]plugincommon.AgentTarget, error) {
+			return agentcommon.ResolveAgentTargets(slug, "", skills.agents, ic.projectDir, skills.isGlobal())
+		},
+	}
+}
+
+// skillDependencies holds the skills repository and harnesses, resolved on the first skill dependency.
+type skillDependencies struct {
+	root    *InstallCommand
+	repoKey string
+	agents  []skillcommon.AgentSpec
+}
+
+func (sd *skillDependencies) isGlobal() bool {
+	return sd.root.scope == agentcommon.InstallScopeGlobal
+}
+
+func (sd *skillDependencies) prepare() error {
+	if sd.repoKey != "" {
+		return nil
+	}
+	registry, err := agentcommon.LoadAgentRegistry(skillcommon.Agents, agentcommon.SkillsAgentsKey)
+	if err != nil {
+		return err
+	}
+	agents := make([]skillcommon.AgentSpec, 0, len(sd.root.agents))
+	for _, agent := range sd.root.agents {
+		spec, err := agentcommon.ResolveAgent(registry, plugincommon.SkillHarnessFor(agent.Name), skillcommon.RegistryHelp)
+		if err != nil {
+			return fmt.Errorf("plugin harness '%s' has no skills harness for skill dependencies: %w", agent.Name, err)
+		}
+		agents = append(agents, spec)
+	}
+	repoKey, err := resolveSkillsRepo(sd.root.serverDetails, "", sd.root.quiet, skillcommon.RepoOptions())
+	if err != nil {
+		return err
+	}
+	sd.agents = agents
+	sd.repoKey = repoKey
+	return nil
+}
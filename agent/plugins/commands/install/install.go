@@ -9,9 +9,9 @@ import (
 
 	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
 	plugincommon "github.com/jfrog/jfrog-cli-artifactory/agent/plugins/common"
+	pluginsCommon "github.com/jfrog/jfrog-cli-core/v2/plugins/common"
 	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
-	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 )
 
@@ -32,6 +32,28 @@ type InstallCommand struct {
 	installPath string
 	format      string
 	quiet       bool
+	// frozen installs exactly the version pinned in the lockfile and fails on a checksum mismatch.
+	frozen         bool
+	expectedSHA256 string
+	// zipSHA256 is the digest of the downloaded zip, recorded in the lockfile after install.
+	zipSHA256 string
+	// constraint is the version range recorded in the install manifest so update stays within it.
+	constraint string
+	// noDeps skips the skills and plugins declared under "dependencies" in plugin.json.
+	noDeps bool
+	// bundledZip is a zip taken from an export bundle; it replaces version resolution, the download,
+	// and the Artifactory evidence check.
+	bundledZip      string
+	bundledEvidence bool
+	// noCache downloads the zip without reading or writing the local package cache.
+	noCache bool
+	// skipScanCheck installs versions that Xray reports as blocked or still being scanned.
+	skipScanCheck bool
+	// convert installs a copy converted to the layout of each target harness the plugin was not published for.
+	convert bool
+	// policy is the install policy of repoKey, read through policyLoader by loadPolicy.
+	policy       *agentcommon.InstallPolicy
+	policyLoader *agentcommon.InstallPolicyLoader
 }
 
 func NewInstallCommand() *InstallCommand {
@@ -96,6 +118,62 @@ func (ic *InstallCommand) SetInstallPath(installPath string) *InstallCommand {
 	return ic
 }
 
+// SetFrozen installs the version pinned in the lockfile instead of resolving one.
+func (ic *InstallCommand) SetFrozen(frozen bool) *InstallCommand {
+	ic.frozen = frozen
+	return ic
+}
+
+// SetConstraint records the version range the package is installed with. Run sets it
+// automatically when --version is a range; update and sync pass it explicitly.
+func (ic *InstallCommand) SetConstraint(constraint string) *InstallCommand {
+	ic.constraint = constraint
+	return ic
+}
+
+// SetNoDeps installs only the plugin itself, without its declared dependencies.
+func (ic *InstallCommand) SetNoDeps(noDeps bool) *InstallCommand {
+	ic.noDeps = noDeps
+	return ic
+}
+
+// SetNoCache always downloads the package zip instead of using the local package cache.
+func (ic *InstallCommand) SetNoCache(noCache bool) *InstallCommand {
+	ic.noCache = noCache
+	return ic
+}
+
+// SetSkipScanCheck installs the version even when its Xray scan status is BLOCKED or SCAN_IN_PROGRESS.
+func (ic *InstallCommand) SetSkipScanCheck(skip bool) *InstallCommand {
+	ic.skipScanCheck = skip
+	return ic
+}
+
+// SetConvert converts the plugin to the layout of each target harness it was not published for.
+func (ic *InstallCommand) SetConvert(convert bool) *InstallCommand {
+	ic.convert = convert
+	return ic
+}
+
+// SetPolicyLoader shares the install policy of a CLI run, so it is read once however many commands the run creates.
+func (ic *InstallCommand) SetPolicyLoader(loader *agentcommon.InstallPolicyLoader) *InstallCommand {
+	ic.policyLoader = loader
+	return ic
+}
+
+// SetBundledZip installs the exact version set with SetVersion from a zip in an extracted export bundle.
+// hasEvidence reports whether the bundled evidence verified against the public keys the import trusts.
+func (ic *InstallCommand) SetBundledZip(zipPath string, hasEvidence bool) *InstallCommand {
+	ic.bundledZip = zipPath
+	ic.bundledEvidence = hasEvidence
+	return ic
+}
+
+// ZipSHA256 returns the SHA-256 of the zip fetched by FetchAndExtractTo.
+func (ic *InstallCommand) ZipSHA256() string {
+	return ic.zipSHA256
+}
+
 func (ic *InstallCommand) ServerDetails() (*config.ServerDetails, error) {
 	return ic.serverDetails, nil
 }
@@ -105,25 +183,83 @@ func (ic *InstallCommand) CommandName() string {
 }
 
 func (ic *InstallCommand) Run() error {
+	fetched, err := ic.fetch()
+	defer fetched.cleanup()
+	if err != nil {
+		if agentcommon.IsPolicyViolation(err) {
+			rows := append(fetched.policyRows, agentcommon.FailedSyncRows(fetched.targets, err)...)
+			_ = agentcommon.PrintInstallSummary("Plugin", ic.slug, ic.version, rows, ic.format)
+		}
+		return err
+	}
+	return ic.apply(fetched)
+}
+
+// fetchedInstall is a resolved and extracted package waiting to be copied to its targets.
+type fetchedInstall struct {
+	targets  []plugincommon.AgentTarget
+	tmpDir   string
+	unzipDir string
+	// policyRows are failed rows for targets whose harness the install policy does not allow.
+	policyRows []agentcommon.SummaryRow
+}
+
+// cleanup removes the temp dir holding the downloaded and extracted package.
+func (f fetchedInstall) cleanup() {
+	if f.tmpDir != "" {
+		// Best-effort cleanup of install temp dir.
+		_ = os.RemoveAll(f.tmpDir)
+	}
+}
+
+// fetch resolves targets and version, then downloads, checks, and extracts the plugin. It only touches
+// its own temp dir, so multi-package installs run it on parallel workers.
+func (ic *InstallCommand) fetch() (fetchedInstall, error) {
+	var fetched fetchedInstall
 	if ic.installPath == "" && len(ic.agents) == 0 {
-		return fmt.Errorf("--harness is required unless --path is set")
+		return fetched, fmt.Errorf("--harness is required unless --path is set")
 	}
 
 	installTargets, err := ic.resolveAgentTargetDirectories()
 	if err != nil {
-		return err
+		return fetched, err
+	}
+	if len(installTargets) == 0 {
+		return fetched, fmt.Errorf("no install directory was resolved for plugin '%s'; check --harness and --path", ic.slug)
 	}
+	fetched.targets = installTargets
 
-	resolvedVersion, err := ic.resolveVersion()
-	if err != nil {
-		return err
+	if agentcommon.IsVersionRange(ic.version) {
+		ic.constraint = strings.TrimSpace(ic.version)
+	}
+	switch {
+	case ic.frozen:
+		if err := ic.applyLockedPin(installTargets); err != nil {
+			return fetched, err
+		}
+	case ic.bundledZip != "":
+		// Bundled installs take the exact version recorded in the bundle.
+	default:
+		resolvedVersion, err := ic.resolveVersion()
+		if err != nil {
+			return fetched, err
+		}
+		ic.version = resolvedVersion
 	}
-	ic.version = resolvedVersion
 
 	if err := agentcommon.ValidateSemver(ic.version); err != nil {
-		return err
+		return fetched, err
 	}
 
+	if err := ic.loadPolicy(); err != nil {
+		return fetched, err
+	}
+	fetched.targets, fetched.policyRows = ic.policy.AllowedTargets(installTargets)
+	if len(fetched.targets) == 0 {
+		return fetched, fmt.Errorf("no selected harness may install plugin '%s': %w", ic.slug, ic.policy.CheckTarget(installTargets[0]))
+	}
+	installTargets = fetched.targets
+
 	if ic.installPath != "" {
 		log.Info(fmt.Sprintf("Installing plugin '%s' version '%s' to %s", ic.slug, ic.version, installTargets[0].DestinationDir))
 	} else {
@@ -132,20 +268,38 @@ func (ic *InstallCommand) Run() error {
 
 	tmpDir, err := os.MkdirTemp("", "plugin-install-*")
 	if err != nil {
-		return fmt.Errorf("failed to create temp dir: %w", err)
+		return fetched, fmt.Errorf("failed to create temp dir: %w", err)
 	}
-	defer func() {
-		// Best-effort cleanup of install temp dir.
-		_ = os.RemoveAll(tmpDir)
-	}()
+	fetched.tmpDir = tmpDir
+	if fetched.unzipDir, err = ic.FetchAndExtractTo(tmpDir); err != nil {
+		return fetched, err
+	}
+	return fetched, nil
+}
 
-	unzipDir, err := ic.FetchAndExtractTo(tmpDir)
-	if err != nil {
-		return err
+// apply installs dependencies, copies the fetched plugin to its targets, records the lockfile, and prints the summary.
+func (ic *InstallCommand) apply(fetched fetchedInstall) error {
+	installTargets, tmpDir, unzipDir := fetched.targets, fetched.tmpDir, fetched.unzipDir
+	var err error
+	var dependencyRows []agentcommon.SummaryRow
+	switch {
+	case ic.noDeps:
+	case ic.frozen:
+		log.Debug("--frozen installs only the pinned plugin; its dependencies are pinned as separate lockfile entries")
+	default:
+		if dependencyRows, err = ic.installDependencies(unzipDir, tmpDir); err != nil {
+			return err
+		}
 	}
 
 	results := ic.CopyExtractedToTargets(unzipDir, installTargets)
+	if !ic.frozen {
+		if err := ic.RecordLockfile(results); err != nil {
+			return err
+		}
+	}
 
+	results = append(append(dependencyRows, fetched.policyRows...), results...)
 	if err := agentcommon.PrintInstallSummary("Plugin", ic.slug, ic.version, results, ic.format); err != nil {
 		return err
 	}
@@ -200,56 +354,230 @@ func (ic *InstallCommand) resolveVersionFromMarketplaces() (string, error) {
 	return resolved, nil
 }
 
-// FetchAndExtractTo downloads the plugin zip into tmpDir, extracts it, and runs evidence checks.
-// The returned unzipDir is under tmpDir; callers must keep tmpDir until copies finish.
+// FetchAndExtractTo enforces the install policy, checks the Xray scan status, downloads the plugin zip into tmpDir,
+// runs evidence checks, and extracts it. The returned unzipDir is under tmpDir; callers must keep tmpDir until
+// copies finish.
 func (ic *InstallCommand) FetchAndExtractTo(tmpDir string) (string, error) {
-	zipPath, err := ic.downloadZip(tmpDir)
-	if err != nil {
-		return "", fmt.Errorf("download failed: %w", err)
+	if err := ic.enforcePackagePolicy(); err != nil {
+		return "", err
 	}
-	unzipDir := filepath.Join(tmpDir, "contents")
-	if err := agentcommon.UnzipFile(zipPath, unzipDir); err != nil {
-		return "", fmt.Errorf("unzip failed: %w", err)
+	var err error
+	zipPath := ic.bundledZip
+	if zipPath == "" {
+		if err = agentcommon.CheckXrayInstallGate(ic.serverDetails, agentcommon.XrayGatePlugins, ic.repoKey, ic.slug, ic.version, ic.skipScanCheck); err != nil {
+			return "", err
+		}
+		if zipPath, err = ic.downloadZip(tmpDir); err != nil {
+			return "", fmt.Errorf("download failed: %w", err)
+		}
+	}
+	if ic.zipSHA256, err = agentcommon.VerifyLockedChecksum(zipPath, ic.expectedSHA256); err != nil {
+		return "", fmt.Errorf("plugin '%s' version '%s': %w", ic.slug, ic.version, err)
 	}
 	if err := ic.handleEvidenceVerification(); err != nil {
 		return "", err
 	}
+	unzipDir := filepath.Join(tmpDir, "contents")
+	if err := agentcommon.UnzipFile(zipPath, unzipDir); err != nil {
+		return "", fmt.Errorf("unzip failed: %w", err)
+	}
 	return unzipDir, nil
 }
 
+// loadPolicy reads the install policy through the loader shared with SetPolicyLoader, or through a loader of its own
+// when none was set. Bundled installs do not reach Artifactory, so only the policy in agent-config.json applies to them.
+func (ic *InstallCommand) loadPolicy() error {
+	if ic.policyLoader == nil {
+		ic.policyLoader = agentcommon.NewInstallPolicyLoader(ic.policyServerDetails())
+	}
+	policy, err := ic.policyLoader.Load()
+	if err != nil {
+		return err
+	}
+	ic.policy = policy
+	return nil
+}
+
+// enforcePackagePolicy refuses the repository, slug, or version when the install policy does not allow them.
+func (ic *InstallCommand) enforcePackagePolicy() error {
+	if err := ic.loadPolicy(); err != nil {
+		return err
+	}
+	if err := ic.policy.CheckPackage(ic.repoKey, ic.slug); err != nil {
+		return err
+	}
+	return ic.policy.CheckXrayApproval(ic.policyServerDetails(), agentcommon.XrayGatePlugins, ic.repoKey, ic.slug, ic.version)
+}
+
+// policyServerDetails returns the server the policy checks may reach, or nil for bundled installs.
+func (ic *InstallCommand) policyServerDetails() *config.ServerDetails {
+	if ic.bundledZip != "" {
+		return nil
+	}
+	return ic.serverDetails
+}
+
+// CheckPolicyTarget returns an error when the install policy does not allow installing into target.
+func (ic *InstallCommand) CheckPolicyTarget(target plugincommon.AgentTarget) error {
+	if err := ic.loadPolicy(); err != nil {
+		return err
+	}
+	return ic.policy.CheckTarget(target)
+}
+
+// PolicyAllowedTargets splits targets into those the install policy allows and failed summary rows for the rest.
+// When the policy cannot be loaded every target fails.
+func (ic *InstallCommand) PolicyAllowedTargets(targets []plugincommon.AgentTarget) ([]plugincommon.AgentTarget, []agentcommon.SummaryRow) {
+	if err := ic.loadPolicy(); err != nil {
+		return nil, agentcommon.FailedSyncRows(targets, err)
+	}
+	return ic.policy.AllowedTargets(targets)
+}
+
 // CopyExtractedToTargets copies an unpacked plugin tree to the given resolved targets and
-// writes a plugin-info manifest per target.
+// writes a plugin-info manifest and file inventory per target. With SetConvert, targets whose harness the
+// plugin was not published for get a copy converted to their layout.
 func (ic *InstallCommand) CopyExtractedToTargets(unzipDir string, installTargets []plugincommon.AgentTarget) []agentcommon.SummaryRow {
 	results := make([]agentcommon.SummaryRow, 0, len(installTargets))
+	trees := map[string]*pluginTree{}
 	for _, target := range installTargets {
+		tree, err := ic.treeForTarget(unzipDir, target, trees)
+		if err != nil {
+			results = append(results, agentcommon.InstallFailureRow(target.Agent.Name, string(target.Scope), target.DestinationDir, err))
+			continue
+		}
 		if err := agentcommon.EnsureDestinationDir(target.DestinationDir); err != nil {
 			results = append(results, agentcommon.InstallFailureRow(target.Agent.Name, string(target.Scope), target.DestinationDir, err))
 			continue
 		}
-		if err := agentcommon.CopyDir(unzipDir, target.DestinationDir); err != nil {
+		if err := agentcommon.CopyDir(tree.dir, target.DestinationDir); err != nil {
+			results = append(results, agentcommon.InstallFailureRow(target.Agent.Name, string(target.Scope), target.DestinationDir, err))
+			continue
+		}
+		if err := ic.writePluginInfoManifest(target, tree.convertedFrom); err != nil {
 			results = append(results, agentcommon.InstallFailureRow(target.Agent.Name, string(target.Scope), target.DestinationDir, err))
 			continue
 		}
-		if err := ic.writePluginInfoManifest(target); err != nil {
+		if err := agentcommon.WriteFileInventory(target.DestinationDir, tree.inventory); err != nil {
 			results = append(results, agentcommon.InstallFailureRow(target.Agent.Name, string(target.Scope), target.DestinationDir, err))
 			continue
 		}
+		detail := agentcommon.SummaryDetailOKInstall
+		if tree.convertedFrom != "" {
+			detail = fmt.Sprintf("Converted from %s; %d component(s) not translated.", tree.convertedFrom, tree.dropped)
+		}
 		results = append(results, agentcommon.SummaryRow{
 			Agent:  target.Agent.Name,
 			Scope:  string(target.Scope),
 			Path:   target.DestinationDir,
 			Status: agentcommon.SummaryStatusOK,
-			Detail: agentcommon.SummaryDetailOKInstall,
+			Detail: detail,
 		})
 	}
 	return results
 }
 
+// pluginTree is an extracted plugin ready to be copied to targets, as published or converted for one harness.
+type pluginTree struct {
+	dir string
+	// inventory records the files as installed so verify can detect later changes on disk.
+	inventory map[string]string
+	// convertedFrom is the harness the plugin was published for when dir holds a converted copy.
+	convertedFrom string
+	dropped       int
+}
+
+// treeForTarget returns the tree to copy to target, converting unzipDir for the target harness on first use.
+// Converted trees are written next to unzipDir, so they are removed with the caller's temp dir.
+func (ic *InstallCommand) treeForTarget(unzipDir string, target plugincommon.AgentTarget, trees map[string]*pluginTree) (*pluginTree, error) {
+	harness := ""
+	needed, err := plugincommon.NeedsConversion(unzipDir, target.Agent.Name)
+	if err != nil {
+		return nil, err
+	}
+	if needed {
+		if ic.convert {
+			harness = target.Agent.Name
+		} else {
+			log.Warn(fmt.Sprintf("Plugin '%s' was not published for %s; use --convert to install a copy converted to its layout.", ic.slug, target.Agent.Name))
+		}
+	}
+	if tree, found := trees[harness]; found {
+		return tree, nil
+	}
+	tree := &pluginTree{dir: unzipDir}
+	if harness != "" {
+		if tree.dir, err = os.MkdirTemp(filepath.Dir(unzipDir), "convert-"+harness+"-*"); err != nil {
+			return nil, fmt.Errorf("failed to create temp dir: %w", err)
+		}
+		report, err := plugincommon.ConvertPlugin(unzipDir, tree.dir, harness)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert plugin '%s' for %s: %w", ic.slug, harness, err)
+		}
+		tree.convertedFrom = report.From
+		for _, item := range report.Dropped() {
+			log.Warn(fmt.Sprintf("Plugin '%s' for %s: %s (%s) was not translated: %s", ic.slug, harness, item.Component, item.Path, item.Detail))
+			tree.dropped++
+		}
+	}
+	if tree.inventory, err = agentcommon.HashPackageTree(tree.dir); err != nil {
+		return nil, err
+	}
+	trees[harness] = tree
+	return tree, nil
+}
+
+// RecordLockfile pins the installed version and zip checksum for every successful row.
+func (ic *InstallCommand) RecordLockfile(results []agentcommon.SummaryRow) error {
+	entries := agentcommon.LockEntriesForRows(agentcommon.LockKindPlugin, ic.repoKey, ic.slug, ic.version, ic.zipSHA256, results)
+	if len(entries) == 0 {
+		return nil
+	}
+	path, err := agentcommon.LockfilePath(ic.scope, ic.projectDir, ic.installPath)
+	if err != nil {
+		return err
+	}
+	if err := agentcommon.RecordLockEntries(path, entries); err != nil {
+		return fmt.Errorf("update %s: %w", agentcommon.LockfileName, err)
+	}
+	log.Debug(fmt.Sprintf("Recorded plugin '%s' version '%s' in %s", ic.slug, ic.version, path))
+	return nil
+}
+
+// applyLockedPin takes the repo, version, and checksum from the lockfile for --frozen installs.
+func (ic *InstallCommand) applyLockedPin(installTargets []plugincommon.AgentTarget) error {
+	path, err := agentcommon.LockfilePath(ic.scope, ic.projectDir, ic.installPath)
+	if err != nil {
+		return err
+	}
+	lockfile, err := agentcommon.ReadLockfile(path)
+	if err != nil {
+		return err
+	}
+	pin, err := lockfile.LockedPin(agentcommon.LockKindPlugin, ic.slug, agentcommon.TargetAgentNames(installTargets))
+	if err != nil {
+		return err
+	}
+	if ic.version != "" && !agentcommon.VersionMatchesRequest(pin.Version, ic.version) {
+		return fmt.Errorf("--version %s conflicts with version %s pinned in %s", ic.version, pin.Version, path)
+	}
+	if ic.repoKey != "" && ic.repoKey != pin.Repo {
+		return fmt.Errorf("--repo %s conflicts with repository %s pinned in %s", ic.repoKey, pin.Repo, path)
+	}
+	ic.repoKey = pin.Repo
+	ic.version = pin.Version
+	ic.expectedSHA256 = pin.SHA256
+	return nil
+}
+
 func (ic *InstallCommand) handleEvidenceVerification() error {
 	err := ic.verifyEvidence()
 	if err == nil {
 		return nil
 	}
+	if policyErr := ic.policy.CheckEvidence("plugin", ic.slug, ic.version, err); policyErr != nil {
+		return policyErr
+	}
 	if ic.quiet || agentcommon.IsNonInteractive() {
 		if agentcommon.ShouldFailOnMissingEvidenceForPlugins() {
 			return fmt.Errorf("evidence verification failed for plugin '%s': %s. %s", ic.slug, err.Error(), agentcommon.DisableQuietFailureEvidenceHintForPlugins())
@@ -258,7 +586,7 @@ func (ic *InstallCommand) handleEvidenceVerification() error {
 		return nil
 	}
 	log.Warn("Evidence verification failed:", err.Error())
-	if !coreutils.AskYesNo("The plugin is unattested. Continue with installation?", false) {
+	if !agentcommon.AskYesNo("The plugin is unattested. Continue with installation?", false) {
 		return fmt.Errorf("installation aborted by user")
 	}
 	return nil
@@ -279,7 +607,7 @@ func (ic *InstallCommand) resolveAgentTargetDirectories() ([]plugincommon.AgentT
 	return agentcommon.ResolveAgentTargets(ic.slug, "", ic.agents, ic.projectDir, isGlobal)
 }
 
-func (ic *InstallCommand) writePluginInfoManifest(target plugincommon.AgentTarget) error {
+func (ic *InstallCommand) writePluginInfoManifest(target plugincommon.AgentTarget, convertedFrom string) error {
 	manifest := agentcommon.InstallInfoManifest{
 		SchemaVersion:    agentcommon.InstallInfoManifestSchemaVersion,
 		Repo:             ic.repoKey,
@@ -287,6 +615,9 @@ func (ic *InstallCommand) writePluginInfoManifest(target plugincommon.AgentTarge
 		InstalledVersion: ic.version,
 		Scope:            string(target.Scope),
 		Agent:            target.Agent.Name,
+		Constraint:       ic.constraint,
+		SHA256:           ic.zipSHA256,
+		ConvertedFrom:    convertedFrom,
 	}
 	if target.Scope == plugincommon.ScopeProject && ic.projectDir != "" {
 		manifest.ProjectDir = ic.projectDir
@@ -295,22 +626,40 @@ func (ic *InstallCommand) writePluginInfoManifest(target plugincommon.AgentTarge
 }
 
 func (ic *InstallCommand) downloadZip(tmpDir string) (string, error) {
-	return agentcommon.DownloadPackageZip(ic.serverDetails, ic.repoKey, ic.slug, ic.version, tmpDir, "plugin")
+	return agentcommon.FetchPackageZip(agentcommon.PackageZipFetch{
+		ServerDetails:  ic.serverDetails,
+		RepoKey:        ic.repoKey,
+		Slug:           ic.slug,
+		Version:        ic.version,
+		ArtifactKind:   "plugin",
+		ExpectedSHA256: ic.expectedSHA256,
+		NoCache:        ic.noCache,
+	}, tmpDir)
 }
 
 func (ic *InstallCommand) verifyEvidence() error {
+	if ic.bundledZip != "" {
+		if !ic.bundledEvidence {
+			return agentcommon.ErrNoBundledEvidence
+		}
+		return nil
+	}
 	return agentcommon.VerifyPackageEvidence(ic.serverDetails, ic.repoKey, ic.slug, ic.version)
 }
 
 // RunInstall is the CLI action for `jf agent plugins install`.
 func RunInstall(c *components.Context) error {
-	if c.GetNumberOfArgs() < 1 {
-		return fmt.Errorf("usage: jf agent plugins install <slug> (--harness <name[,name...]> [--global] [--project-dir <dir>] | --path <dir>) [--repo <repo>] [--version <ver>]")
+	frozen := c.GetBoolFlagValue("frozen")
+	if c.GetNumberOfArgs() < 1 && !frozen {
+		return fmt.Errorf("usage: jf agent plugins install <slug> ((--harness <name[,name...]> | --auto-harness) [--global] [--project-dir <dir>] | --path <dir>) [--repo <repo>] [--version <ver>] [--frozen [--threads <n>]] [--no-deps] [--no-cache] [--skip-scan-check] [--convert]")
 	}
 
-	slug := c.GetArgumentAt(0)
-	if err := agentcommon.ValidateSlug(slug); err != nil {
-		return err
+	slug := ""
+	if c.GetNumberOfArgs() > 0 {
+		slug = c.GetArgumentAt(0)
+		if err := agentcommon.ValidateSlug(slug); err != nil {
+			return err
+		}
 	}
 
 	flags, err := agentcommon.ValidateInstallFlags(c, plugincommon.Agents, agentcommon.PluginsAgentsKey, plugincommon.RegistryHelp)
@@ -323,9 +672,13 @@ func RunInstall(c *components.Context) error {
 		return err
 	}
 	quiet := agentcommon.IsQuiet(c)
-	repoKey, err := agentcommon.ResolveRepo(serverDetails, c.GetStringFlagValue("repo"), quiet, plugincommon.RepoOptions())
-	if err != nil {
-		return err
+	// --frozen takes the repository from the lockfile unless --repo is given explicitly.
+	repoKey := c.GetStringFlagValue("repo")
+	if !frozen {
+		repoKey, err = agentcommon.ResolveRepo(serverDetails, repoKey, quiet, plugincommon.RepoOptions())
+		if err != nil {
+			return err
+		}
 	}
 
 	version := c.GetStringFlagValue("version")
@@ -333,22 +686,78 @@ func RunInstall(c *components.Context) error {
 	if c.GetStringFlagValue("format") != "" {
 		format = c.GetStringFlagValue("format")
 	}
+	// Every package of the run, including parallel --frozen installs, shares one install policy.
+	policyLoader := agentcommon.NewInstallPolicyLoader(serverDetails)
+	newCommand := func(slug string, specs []plugincommon.AgentSpec) *InstallCommand {
+		cmd := NewInstallCommand().
+			SetServerDetails(serverDetails).
+			SetRepoKey(repoKey).
+			SetSlug(slug).
+			SetVersion(version).
+			SetFormat(format).
+			SetQuiet(quiet).
+			SetFrozen(frozen).
+			SetNoDeps(c.GetBoolFlagValue("no-deps")).
+			SetNoCache(c.GetBoolFlagValue("no-cache")).
+			SetSkipScanCheck(c.GetBoolFlagValue("skip-scan-check")).
+			SetConvert(c.GetBoolFlagValue("convert")).
+			SetPolicyLoader(policyLoader)
+		if flags.PathMode() {
+			return cmd.SetInstallPath(flags.AbsoluteInstallBaseDir)
+		}
+		return cmd.
+			SetAgents(specs).
+			SetGlobal(flags.IsGlobal).
+			SetProjectDir(flags.ProjectDirAbs)
+	}
 
-	cmd := NewInstallCommand().
-		SetServerDetails(serverDetails).
-		SetRepoKey(repoKey).
-		SetSlug(slug).
-		SetVersion(version).
-		SetFormat(format).
-		SetQuiet(quiet)
-
-	if flags.PathMode() {
-		return cmd.SetInstallPath(flags.AbsoluteInstallBaseDir).Run()
+	if slug != "" {
+		return newCommand(slug, flags.Specs).Run()
 	}
+	threads, err := pluginsCommon.GetThreadsCount(c)
+	if err != nil {
+		return err
+	}
+	return runFrozenAll(flags, threads, newCommand)
+}
 
-	return cmd.
-		SetAgents(flags.Specs).
-		SetGlobal(flags.IsGlobal).
-		SetProjectDir(flags.ProjectDirAbs).
-		Run()
+// runFrozenAll installs every plugin the lockfile pins for the selected harnesses (`install --frozen` without a slug).
+func runFrozenAll(flags agentcommon.InstallFlagsResult, threads int, newCommand func(string, []plugincommon.AgentSpec) *InstallCommand) error {
+	lockPath, err := flags.LockfilePath()
+	if err != nil {
+		return err
+	}
+	lockfile, err := agentcommon.ReadLockfile(lockPath)
+	if err != nil {
+		return err
+	}
+	harnesses := flags.HarnessNames()
+	slugs := lockfile.Slugs(agentcommon.LockKindPlugin, harnesses)
+	if len(slugs) == 0 {
+		log.Info(fmt.Sprintf("No plugins are pinned in %s for harness(es) %s.", lockPath, strings.Join(harnesses, ", ")))
+		return nil
+	}
+	// Downloads run on parallel workers; copies into the harness directories happen one package at a time.
+	commands := make([]*InstallCommand, len(slugs))
+	fetched := make([]fetchedInstall, len(slugs))
+	errs := make([]error, len(slugs))
+	var failed []string
+	agentcommon.RunPackagePipeline(len(slugs), threads, func(i int) {
+		locked := lockfile.LockedHarnesses(agentcommon.LockKindPlugin, slugs[i], harnesses)
+		commands[i] = newCommand(slugs[i], agentcommon.FilterAgentSpecs(flags.Specs, locked))
+		fetched[i], errs[i] = commands[i].fetch()
+	}, func(i int) {
+		defer fetched[i].cleanup()
+		if errs[i] == nil {
+			errs[i] = commands[i].apply(fetched[i])
+		}
+		if errs[i] != nil {
+			log.Error(fmt.Sprintf("Frozen install of plugin '%s' failed: %s", slugs[i], errs[i].Error()))
+			failed = append(failed, slugs[i])
+		}
+	})
+	if len(failed) > 0 {
+		return fmt.Errorf("frozen install failed for plugin(s): %s", strings.Join(failed, ", "))
+	}
+	return nil
 }
@@ -129,3 +129,52 @@ func TestRun_MissingHarnessAndPath(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "--harness is required")
 }
+
+func TestApplyLockedPin_UsesLockfile(t *testing.T) {
+	projectRoot := t.TempDir()
+	lockPath, err := agentcommon.LockfilePath(agentcommon.InstallScopeProject, projectRoot, "")
+	require.NoError(t, err)
+	require.NoError(t, agentcommon.RecordLockEntries(lockPath, []agentcommon.LockEntry{
+		{Kind: agentcommon.LockKindPlugin, Slug: "my-plugin", Repo: "plugins-repo", Version: "1.4.0", SHA256: "abc", Harness: "claude"},
+	}))
+
+	ic := NewInstallCommand().SetSlug("my-plugin").SetProjectDir(projectRoot).SetFrozen(true)
+	targets := []plugincommon.AgentTarget{{Agent: plugincommon.AgentSpec{Name: "claude"}, Scope: plugincommon.ScopeProject}}
+	require.NoError(t, ic.applyLockedPin(targets))
+	assert.Equal(t, "plugins-repo", ic.repoKey)
+	assert.Equal(t, "1.4.0", ic.version)
+	assert.Equal(t, "abc", ic.expectedSHA256)
+
+	conflicting := NewInstallCommand().SetSlug("my-plugin").SetProjectDir(projectRoot).SetVersion("2.0.0")
+	err = conflicting.applyLockedPin(targets)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "conflicts with version 1.4.0")
+}
+
+func TestRecordLockfile_WritesSuccessfulTargets(t *testing.T) {
+	projectRoot := t.TempDir()
+	ic := NewInstallCommand().SetRepoKey("plugins-repo").SetSlug("my-plugin").SetVersion("1.2.3").SetProjectDir(projectRoot)
+	ic.zipSHA256 = "feed"
+
+	require.NoError(t, ic.RecordLockfile([]agentcommon.SummaryRow{
+		{Agent: "claude", Status: agentcommon.SummaryStatusOK},
+		{Agent: "cursor", Status: agentcommon.SummaryStatusFailed},
+	}))
+
+	lockfile, err := agentcommon.ReadLockfile(filepath.Join(projectRoot, ".jfrog", agentcommon.LockfileName))
+	require.NoError(t, err)
+	require.Len(t, lockfile.Entries, 1)
+	assert.Equal(t, agentcommon.LockEntry{
+		Kind: agentcommon.LockKindPlugin, Slug: "my-plugin", Repo: "plugins-repo", Version: "1.2.3", SHA256: "feed", Harness: "claude",
+	}, lockfile.Entries[0])
+}
+
+func TestDependencyCommand_KeepsRootOptions(t *testing.T) {
+	root := NewInstallCommand().SetRepoKey("plugins-local").SetNoCache(true).SetSkipScanCheck(true)
+	dep := root.dependencyCommand("helper", "1.0.0")
+	assert.Equal(t, "helper", dep.slug)
+	assert.Equal(t, "1.0.0", dep.version)
+	assert.Equal(t, "plugins-local", dep.repoKey)
+	assert.True(t, dep.noCache)
+	assert.True(t, dep.skipScanCheck, "dependencies pass the Xray gate the root install was asked to skip")
+}
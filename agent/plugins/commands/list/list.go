@@ -33,6 +33,8 @@ const (
 	listCheckStatusBehind  = "behind"
 	listCheckStatusCurrent = "current"
 	listCheckStatusAhead   = "ahead"
+	// listCheckStatusDeprecated marks an installed version that was deprecated after it was installed.
+	listCheckStatusDeprecated = "deprecated"
 )
 
 // repoListRow is one row for registry mode (jf agent plugins list --repo).
@@ -321,13 +323,24 @@ func (lc *ListCommand) fillUpdateStatus(row *localListRow) {
 		row.Status = listCheckStatusUnknown
 		return
 	}
-	latest, err := pluginscommon.ResolveLatestPluginVersion(lc.serverDetails, row.Repo, row.Name)
+	versions, err := pluginscommon.ListPluginVersions(lc.serverDetails, row.Repo, row.Name)
+	if err != nil || len(versions) == 0 {
+		row.RegistryLatest = emDash
+		row.Status = listCheckStatusUnknown
+		return
+	}
+	deprecated := agentcommon.ListDeprecatedVersions(lc.serverDetails, row.Repo, row.Name)
+	latest, err := agentcommon.LatestActiveVersion(row.Name, versions, deprecated)
 	if err != nil {
 		row.RegistryLatest = emDash
 		row.Status = listCheckStatusUnknown
 		return
 	}
 	row.RegistryLatest = latest
+	if _, isDeprecated := deprecated.Lookup(row.Name, row.Version); isDeprecated {
+		row.Status = listCheckStatusDeprecated
+		return
+	}
 	cmp, err := agentcommon.CompareSemver(row.Version, latest)
 	if err != nil {
 		row.Status = listCheckStatusUnknown
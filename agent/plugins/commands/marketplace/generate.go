@@ -0,0 +1,160 @@
+package marketplace
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+	plugincommon "github.com/jfrog/jfrog-cli-artifactory/agent/plugins/common"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+type GenerateCommand struct {
+	serverDetails *config.ServerDetails
+	repoKey       string
+	harness       string
+	check         bool
+	dryRun        bool
+	format        string
+}
+
+func NewGenerateCommand() *GenerateCommand {
+	return &GenerateCommand{format: "table"}
+}
+
+func (gc *GenerateCommand) SetServerDetails(details *config.ServerDetails) *GenerateCommand {
+	gc.serverDetails = details
+	return gc
+}
+
+func (gc *GenerateCommand) SetRepoKey(repoKey string) *GenerateCommand {
+	gc.repoKey = repoKey
+	return gc
+}
+
+func (gc *GenerateCommand) SetHarness(harness string) *GenerateCommand {
+	gc.harness = harness
+	return gc
+}
+
+// SetCheck reports drift between the marketplace file and the repository instead of uploading a new file.
+func (gc *GenerateCommand) SetCheck(check bool) *GenerateCommand {
+	gc.check = check
+	return gc
+}
+
+// SetDryRun prints the generated file instead of uploading it.
+func (gc *GenerateCommand) SetDryRun(dryRun bool) *GenerateCommand {
+	gc.dryRun = dryRun
+	return gc
+}
+
+func (gc *GenerateCommand) SetFormat(format string) *GenerateCommand {
+	gc.format = format
+	return gc
+}
+
+func (gc *GenerateCommand) ServerDetails() (*config.ServerDetails, error) {
+	return gc.serverDetails, nil
+}
+
+func (gc *GenerateCommand) CommandName() string { return "agent_plugins_marketplace_generate" }
+
+func (gc *GenerateCommand) Run() error {
+	fileName := plugincommon.MarketplaceFileName(gc.harness)
+	existing, err := plugincommon.ReadMarketplace(gc.serverDetails, gc.repoKey, gc.harness)
+	if err != nil {
+		if !errors.Is(err, plugincommon.ErrMarketplaceNotFound) {
+			return err
+		}
+		log.Debug(fmt.Sprintf("%s does not exist in repository '%s' yet.", fileName, gc.repoKey))
+	}
+	plugins, err := plugincommon.ListMarketplacePlugins(gc.serverDetails, gc.repoKey)
+	if err != nil {
+		return err
+	}
+
+	if gc.check {
+		rows, err := plugincommon.MarketplaceDrift(existing, plugins)
+		if err != nil {
+			return fmt.Errorf("%s: %w", fileName, err)
+		}
+		if err := printDrift(fileName, rows, gc.format); err != nil {
+			return err
+		}
+		return plugincommon.MarketplaceDriftError(gc.harness, gc.repoKey, rows)
+	}
+
+	data, err := plugincommon.GenerateMarketplace(existing, gc.repoKey, gc.harness, plugins)
+	if err != nil {
+		return err
+	}
+	if gc.dryRun {
+		fmt.Print(string(data))
+		return nil
+	}
+	if err := plugincommon.UploadMarketplace(gc.serverDetails, gc.repoKey, gc.harness, data); err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Uploaded %s listing %d plugin(s) to repository '%s'.", fileName, len(plugins), gc.repoKey))
+	return nil
+}
+
+type driftJSON struct {
+	Results []plugincommon.MarketplaceDriftRow `json:"results"`
+}
+
+func printDrift(fileName string, rows []plugincommon.MarketplaceDriftRow, format string) error {
+	if strings.EqualFold(format, "json") {
+		data, err := json.MarshalIndent(driftJSON{Results: rows}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal marketplace drift: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	log.Info(fileName + " compared with the repository:")
+	if err := coreutils.PrintTable(rows, "Plugins", "No plugins found", false); err != nil {
+		log.Warn("Failed to render marketplace drift: " + err.Error())
+	}
+	return nil
+}
+
+// RunGenerate is the CLI action for `jf agent plugins marketplace generate --harness <name>`.
+// It lists the latest version of every plugin in the repository in <harness>-marketplace.json and uploads it,
+// or with --check reports where the current file differs from the repository.
+func RunGenerate(c *components.Context) error {
+	harness := strings.TrimSpace(c.GetStringFlagValue("harness"))
+	if c.GetNumberOfArgs() > 0 || harness == "" {
+		return fmt.Errorf("usage: jf agent plugins marketplace generate --harness <name> [--repo <repo>] [--check [--format <table|json>]] [--dry-run]")
+	}
+	if strings.Contains(harness, ",") {
+		return fmt.Errorf("--harness takes a single harness name, got '%s'", harness)
+	}
+	serverDetails, err := agentcommon.GetServerDetails(c)
+	if err != nil {
+		return err
+	}
+	quiet := agentcommon.IsQuiet(c)
+	repoKey, err := agentcommon.ResolveRepo(serverDetails, c.GetStringFlagValue("repo"), quiet, plugincommon.RepoOptions())
+	if err != nil {
+		return err
+	}
+	format := "table"
+	if c.GetStringFlagValue("format") != "" {
+		format = c.GetStringFlagValue("format")
+	}
+	return NewGenerateCommand().
+		SetServerDetails(serverDetails).
+		SetRepoKey(repoKey).
+		SetHarness(harness).
+		SetCheck(c.GetBoolFlagValue("check")).
+		SetDryRun(c.GetBoolFlagValue("dry-run")).
+		SetFormat(format).
+		Run()
+}
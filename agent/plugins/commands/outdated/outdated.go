@@ -0,0 +1,52 @@
+package outdated
+
+import (
+	"fmt"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+	plugincommon "github.com/jfrog/jfrog-cli-artifactory/agent/plugins/common"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+)
+
+// RunOutdated is the CLI action for `jf agent plugins outdated`.
+// It reports installed plugins in every harness whose repository has a newer version, without changing anything.
+func RunOutdated(c *components.Context) error {
+	if c.GetNumberOfArgs() > 0 {
+		return fmt.Errorf("usage: jf agent plugins outdated [--projects <dir[,dir...]>] [--format <table|json>]")
+	}
+	projectDirs, err := agentcommon.ParseOutdatedProjectDirs(c.GetStringFlagValue("projects"))
+	if err != nil {
+		return err
+	}
+	format := "table"
+	if c.GetStringFlagValue("format") != "" {
+		format = c.GetStringFlagValue("format")
+	}
+	registry, err := agentcommon.LoadAgentRegistry(plugincommon.Agents, agentcommon.PluginsAgentsKey)
+	if err != nil {
+		return err
+	}
+	serverDetails, err := agentcommon.GetServerDetails(c)
+	if err != nil {
+		return err
+	}
+
+	rows, err := agentcommon.CollectOutdated(registry, agentcommon.OutdatedOptions{
+		ServerDetails:    serverDetails,
+		ManifestFileName: plugincommon.PluginInfoManifestFile,
+		ProjectDirs:      projectDirs,
+		ListVersions: func(repoKey, slug string) ([]string, error) {
+			return plugincommon.ListPluginVersions(serverDetails, repoKey, slug)
+		},
+		XrayStatus: func(repoKey, slug, version string) (string, error) {
+			return agentcommon.XrayStatus(serverDetails, agentcommon.XrayGatePlugins, repoKey, slug, version)
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := agentcommon.PrintOutdatedSummary("Plugin", rows, format); err != nil {
+		return err
+	}
+	return agentcommon.OutdatedError("Plugin", rows)
+}
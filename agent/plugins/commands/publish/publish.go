@@ -24,14 +24,17 @@ import (
 var packageVersionExists = common.PackageVersionExists
 
 type PublishCommand struct {
-	serverDetails      *config.ServerDetails
-	repoKey            string
-	pluginDir          string
-	version            string
-	signingKey         string
-	keyAlias           string
-	quiet              bool
-	buildConfiguration *build.BuildConfiguration
+	serverDetails       *config.ServerDetails
+	repoKey             string
+	pluginDir           string
+	version             string
+	signingKey          string
+	keyAlias            string
+	quiet               bool
+	skipScan            bool
+	skipSecretScan      bool
+	autoDeleteOnFailure bool
+	buildConfiguration  *build.BuildConfiguration
 }
 
 func NewPublishCommand() *PublishCommand {
@@ -73,6 +76,21 @@ func (pc *PublishCommand) SetQuiet(quiet bool) *PublishCommand {
 	return pc
 }
 
+func (pc *PublishCommand) SetSkipScan(skip bool) *PublishCommand {
+	pc.skipScan = skip
+	return pc
+}
+
+func (pc *PublishCommand) SetSkipSecretScan(skip bool) *PublishCommand {
+	pc.skipSecretScan = skip
+	return pc
+}
+
+func (pc *PublishCommand) SetAutoDeleteOnFailure(autoDelete bool) *PublishCommand {
+	pc.autoDeleteOnFailure = autoDelete
+	return pc
+}
+
 func (pc *PublishCommand) SetBuildConfiguration(buildConfig *build.BuildConfiguration) *PublishCommand {
 	pc.buildConfiguration = buildConfig
 	return pc
@@ -85,6 +103,16 @@ func (pc *PublishCommand) ServerDetails() (*config.ServerDetails, error) {
 func (pc *PublishCommand) CommandName() string { return "agent_plugins_publish" }
 
 func (pc *PublishCommand) Run() error {
+	report, err := plugincommon.ValidatePlugin(pc.pluginDir)
+	if err != nil {
+		return err
+	}
+	if pc.skipSecretScan {
+		report.Downgrade(common.ValidationRuleSecret)
+	}
+	if err := common.CheckPublishValidation("Plugin", report); err != nil {
+		return err
+	}
 	meta, err := plugincommon.ValidateAndResolvePluginMeta(pc.pluginDir, pc.version)
 	if err != nil {
 		return err
@@ -150,6 +178,21 @@ func (pc *PublishCommand) Run() error {
 	subjectRepoPath := fmt.Sprintf("%s/%s/%s/%s", pc.repoKey, slug, version, filepath.Base(zipPath))
 	pc.attachEvidence(slug, version, sha256Hex, subjectRepoPath)
 
+	// Post-publish Xray scan gate check
+	if err := common.CheckXrayGate(common.XrayGateParams{
+		Kind:                common.XrayGatePlugins,
+		ServerDetails:       pc.serverDetails,
+		RepoKey:             pc.repoKey,
+		ArtifactPath:        common.PackageArtifactPath(slug, version),
+		Slug:                slug,
+		Version:             version,
+		SkipScan:            pc.skipScan,
+		AutoDeleteOnFailure: pc.autoDeleteOnFailure,
+		Quiet:               pc.quiet,
+	}); err != nil {
+		return err
+	}
+
 	log.Info(fmt.Sprintf("Plugin '%s' version '%s' published successfully.", slug, version))
 	return nil
 }
@@ -340,6 +383,9 @@ func RunPublish(commandContext *components.Context) error {
 		SetSigningKey(commandContext.GetStringFlagValue("signing-key")).
 		SetKeyAlias(commandContext.GetStringFlagValue("key-alias")).
 		SetQuiet(quiet).
+		SetSkipScan(commandContext.GetBoolFlagValue("skip-scan")).
+		SetSkipSecretScan(commandContext.GetBoolFlagValue("skip-secret-scan")).
+		SetAutoDeleteOnFailure(commandContext.GetBoolFlagValue("auto-delete-on-failure")).
 		SetBuildConfiguration(buildConfig)
 
 	return cmd.Run()
@@ -0,0 +1,47 @@
+package rollback
+
+import (
+	"fmt"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+	plugincommon "github.com/jfrog/jfrog-cli-artifactory/agent/plugins/common"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+)
+
+// RunRollback is the CLI action for `jf agent plugins rollback`.
+// It restores the version each selected install had before its last update.
+func RunRollback(c *components.Context) error {
+	if c.GetNumberOfArgs() != 1 {
+		return fmt.Errorf("usage: jf agent plugins rollback <slug> (--harness <name[,name...]> [--global] [--project-dir <dir>] | --path <dir>) [--format <table|json>]")
+	}
+	slug := c.GetArgumentAt(0)
+	if err := agentcommon.ValidateSlug(slug); err != nil {
+		return err
+	}
+
+	flags, err := agentcommon.ValidateInstallFlags(c, plugincommon.Agents, agentcommon.PluginsAgentsKey, plugincommon.RegistryHelp)
+	if err != nil {
+		return err
+	}
+	format := "table"
+	if c.GetStringFlagValue("format") != "" {
+		format = c.GetStringFlagValue("format")
+	}
+
+	targets, err := agentcommon.ResolveAgentTargets(slug, flags.AbsoluteInstallBaseDir, flags.Specs, flags.ProjectDirAbs, flags.IsGlobal)
+	if err != nil {
+		return err
+	}
+	results := agentcommon.RollbackInstalls(targets, plugincommon.PluginInfoManifestFile)
+	if err := agentcommon.PrintRollbackSummary("Plugin", slug, results, format); err != nil {
+		return err
+	}
+	lockPath, err := flags.LockfilePath()
+	if err != nil {
+		return err
+	}
+	if err := agentcommon.RecordRollbackLockEntries(lockPath, agentcommon.LockKindPlugin, results); err != nil {
+		return err
+	}
+	return agentcommon.RollbackError("Plugin", slug, results)
+}
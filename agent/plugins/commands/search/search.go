@@ -16,6 +16,7 @@ type SearchCommand struct {
 	query         string
 	repoKey       string
 	format        string
+	filters       agentcommon.SearchFilters
 }
 
 func (sc *SearchCommand) SetServerDetails(details *config.ServerDetails) *SearchCommand {
@@ -38,6 +39,11 @@ func (sc *SearchCommand) SetFormat(format string) *SearchCommand {
 	return sc
 }
 
+func (sc *SearchCommand) SetFilters(filters agentcommon.SearchFilters) *SearchCommand {
+	sc.filters = filters
+	return sc
+}
+
 func (sc *SearchCommand) Run() error {
 	rows, err := agentcommon.SearchRowsByProperty(sc.serverDetails, agentcommon.PropertySearchOptions{
 		NamePropertyKey: plugincommon.SearchNamePropertyKey,
@@ -47,6 +53,10 @@ func (sc *SearchCommand) Run() error {
 	if err != nil {
 		return fmt.Errorf("plugin search failed: %w", err)
 	}
+	rows, err = agentcommon.ApplySearchFilters(sc.serverDetails, rows, sc.filters)
+	if err != nil {
+		return fmt.Errorf("plugin search failed: %w", err)
+	}
 	return agentcommon.PrintSearchResults(rows, agentcommon.PrintSearchResultsOptions{
 		Query:           sc.query,
 		Format:          sc.format,
@@ -59,7 +69,9 @@ func (sc *SearchCommand) Run() error {
 // RunSearch is the CLI action for `jf agent plugins search`.
 func RunSearch(c *components.Context) error {
 	if c.GetNumberOfArgs() < 1 {
-		return fmt.Errorf("usage: jf agent plugins search <query> [--repo <repo>] [--format json]")
+		return fmt.Errorf("usage: jf agent plugins search <query> [--repo <repo>] [--format table|json|csv] " +
+			"[--tag <tags>] [--author <author>] [--harness <name>] [--min-version <version>] [--has-evidence] [--xray-status <status>] " +
+			"[--sort-by name|version|repository] [--sort-order asc|desc] [--limit N] [--offset N] [--threads N]")
 	}
 
 	query := strings.TrimSpace(c.GetArgumentAt(0))
@@ -82,11 +94,17 @@ func RunSearch(c *components.Context) error {
 		format = c.GetStringFlagValue("format")
 	}
 
+	filters, err := agentcommon.ParseSearchFilters(c, agentcommon.XrayGatePlugins)
+	if err != nil {
+		return err
+	}
+
 	cmd := &SearchCommand{}
 	cmd.SetServerDetails(serverDetails).
 		SetQuery(query).
 		SetRepoKey(repoKey).
-		SetFormat(format)
+		SetFormat(format).
+		SetFilters(filters)
 
 	return cmd.Run()
 }
@@ -0,0 +1,251 @@
+package sync
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/plugins/commands/install"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/plugins/commands/update"
+	plugincommon "github.com/jfrog/jfrog-cli-artifactory/agent/plugins/common"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// resolvePluginVersion is swappable in tests.
+var resolvePluginVersion = plugincommon.ResolvePluginVersion
+
+// applyPluginTargets is swappable in tests.
+var applyPluginTargets = (*syncCommand).applyTargets
+
+type syncCommand struct {
+	serverDetails *config.ServerDetails
+	repoFlag      string
+	projectDir    string
+	manifestPath  string
+	prune         bool
+	dryRun        bool
+	noCache       bool
+	skipScanCheck bool
+	quiet         bool
+	format        string
+	// policyLoader reads the install policy once for every package in the manifest.
+	policyLoader *agentcommon.InstallPolicyLoader
+}
+
+// declaredPlugin is one manifest entry with its harnesses resolved to install targets.
+type declaredPlugin struct {
+	pkg     agentcommon.ManifestPackage
+	repoKey string
+	targets []plugincommon.AgentTarget
+}
+
+// constraint returns the declared version when it is a range, so updates stay within it.
+func (d declaredPlugin) constraint() string {
+	if agentcommon.IsVersionRange(d.pkg.Version) {
+		return d.pkg.Version
+	}
+	return ""
+}
+
+// RunSync is the CLI action for `jf agent plugins sync`.
+func RunSync(c *components.Context) error {
+	if c.GetNumberOfArgs() > 0 {
+		return fmt.Errorf("usage: jf agent plugins sync [--manifest <file>] [--project-dir <dir>] [--repo <repo>] [--prune] [--dry-run] [--no-cache] [--skip-scan-check] [--format <table|json>]")
+	}
+	projectDir, err := agentcommon.ResolveInstallProjectDir(strings.TrimSpace(c.GetStringFlagValue("project-dir")), false)
+	if err != nil {
+		return err
+	}
+	manifestPath := strings.TrimSpace(c.GetStringFlagValue("manifest"))
+	if manifestPath == "" {
+		manifestPath = agentcommon.ProjectManifestPath(projectDir)
+	}
+	serverDetails, err := agentcommon.GetServerDetails(c)
+	if err != nil {
+		return err
+	}
+	format := "table"
+	if c.GetStringFlagValue("format") != "" {
+		format = c.GetStringFlagValue("format")
+	}
+	sc := &syncCommand{
+		serverDetails: serverDetails,
+		repoFlag:      c.GetStringFlagValue("repo"),
+		projectDir:    projectDir,
+		manifestPath:  manifestPath,
+		prune:         c.GetBoolFlagValue("prune"),
+		dryRun:        c.GetBoolFlagValue("dry-run"),
+		noCache:       c.GetBoolFlagValue("no-cache"),
+		skipScanCheck: c.GetBoolFlagValue("skip-scan-check"),
+		quiet:         agentcommon.IsQuiet(c),
+		format:        format,
+		policyLoader:  agentcommon.NewInstallPolicyLoader(serverDetails),
+	}
+	return sc.run()
+}
+
+// run installs missing plugins, updates drifted ones, and with --prune removes undeclared installs.
+func (sc *syncCommand) run() error {
+	manifest, err := agentcommon.ReadProjectManifest(sc.manifestPath)
+	if err != nil {
+		return err
+	}
+	if len(manifest.Plugins) == 0 && !sc.prune {
+		log.Info(fmt.Sprintf("No plugins declared in %s; nothing to sync.", sc.manifestPath))
+		return nil
+	}
+	declared, err := sc.resolveDeclaredPlugins(manifest.Plugins)
+	if err != nil {
+		return err
+	}
+
+	combined := make([]agentcommon.UpdateAllSummaryRow, 0)
+	for _, plugin := range declared {
+		version, rows := sc.syncPlugin(plugin)
+		combined = agentcommon.AppendUpdateAllSummaryRows(combined, plugin.pkg.Slug, version, rows)
+	}
+	if sc.prune {
+		pruned, err := sc.pruneUndeclared(manifest.Plugins)
+		if err != nil {
+			return err
+		}
+		combined = append(combined, pruned...)
+	}
+
+	if len(combined) == 0 {
+		log.Info("All declared plugins are in sync.")
+		return nil
+	}
+	if err := agentcommon.PrintSyncSummary("Plugin", combined, sc.format); err != nil {
+		return err
+	}
+	return agentcommon.SyncFailureError(combined)
+}
+
+// resolveDeclaredPlugins validates every harness and repository up front so a typo in the
+// manifest fails before anything is installed.
+func (sc *syncCommand) resolveDeclaredPlugins(packages []agentcommon.ManifestPackage) ([]declaredPlugin, error) {
+	registry, err := agentcommon.LoadAgentRegistry(plugincommon.Agents, agentcommon.PluginsAgentsKey)
+	if err != nil {
+		return nil, err
+	}
+	defaultRepo := ""
+	declared := make([]declaredPlugin, 0, len(packages))
+	for _, pkg := range packages {
+		specs := make([]plugincommon.AgentSpec, 0, len(pkg.Harnesses))
+		for _, name := range pkg.Harnesses {
+			spec, err := agentcommon.ResolveAgent(registry, name, plugincommon.RegistryHelp)
+			if err != nil {
+				return nil, fmt.Errorf("plugin '%s': %w", pkg.Slug, err)
+			}
+			specs = append(specs, spec)
+		}
+		targets, err := agentcommon.ResolveAgentTargets(pkg.Slug, "", specs, sc.projectDir, false)
+		if err != nil {
+			return nil, err
+		}
+		repoKey := pkg.Repo
+		if repoKey == "" {
+			if defaultRepo == "" {
+				if defaultRepo, err = agentcommon.ResolveRepo(sc.serverDetails, sc.repoFlag, sc.quiet, plugincommon.RepoOptions()); err != nil {
+					return nil, err
+				}
+			}
+			repoKey = defaultRepo
+		}
+		declared = append(declared, declaredPlugin{pkg: pkg, repoKey: repoKey, targets: targets})
+	}
+	return declared, nil
+}
+
+// syncPlugin brings every target of one declared plugin to the manifest version.
+// Resolve and download failures are reported as failed rows so the remaining plugins still sync.
+func (sc *syncCommand) syncPlugin(plugin declaredPlugin) (string, []agentcommon.SummaryRow) {
+	version, err := resolvePluginVersion(sc.serverDetails, plugin.repoKey, plugin.pkg.Slug, plugin.pkg.Version, true)
+	if err != nil {
+		log.Warn(fmt.Sprintf("Skipping plugin '%s': could not resolve version: %s", plugin.pkg.Slug, err.Error()))
+		return "", agentcommon.FailedSyncRows(plugin.targets, err)
+	}
+	plans := agentcommon.PlanSyncTargets(plugin.targets, version, plugincommon.ReadInstalledPluginVersion)
+	rows, toInstall, toUpdate := agentcommon.SplitSyncPlans("Plugin", plugin.pkg.Slug, version, plans, sc.dryRun)
+	if len(toInstall) == 0 && len(toUpdate) == 0 {
+		return version, rows
+	}
+	return version, append(rows, applyPluginTargets(sc, plugin, version, toInstall, toUpdate)...)
+}
+
+// applyTargets downloads the plugin once, installs it where it is missing, and updates drifted targets.
+// Targets whose harness the install policy does not allow fail without being touched.
+func (sc *syncCommand) applyTargets(plugin declaredPlugin, version string, toInstall, toUpdate []plugincommon.AgentTarget) []agentcommon.SummaryRow {
+	cmd := install.NewInstallCommand().
+		SetServerDetails(sc.serverDetails).
+		SetRepoKey(plugin.repoKey).
+		SetSlug(plugin.pkg.Slug).
+		SetVersion(version).
+		SetConstraint(plugin.constraint()).
+		SetQuiet(sc.quiet).
+		SetProjectDir(sc.projectDir).
+		SetGlobal(false).
+		SetNoCache(sc.noCache).
+		SetSkipScanCheck(sc.skipScanCheck).
+		SetPolicyLoader(sc.policyLoader)
+
+	toInstall, policyRows := cmd.PolicyAllowedTargets(toInstall)
+	toUpdate, updateRows := cmd.PolicyAllowedTargets(toUpdate)
+	policyRows = append(policyRows, updateRows...)
+	pending := append(append([]plugincommon.AgentTarget{}, toInstall...), toUpdate...)
+	if len(pending) == 0 {
+		return policyRows
+	}
+
+	tmpDir, err := os.MkdirTemp("", "plugin-sync-*")
+	if err != nil {
+		return append(policyRows, agentcommon.FailedSyncRows(pending, fmt.Errorf("failed to create temp dir: %w", err))...)
+	}
+	defer func() {
+		// Best-effort cleanup of sync temp dir.
+		_ = os.RemoveAll(tmpDir)
+	}()
+
+	unzipDir, err := cmd.FetchAndExtractTo(tmpDir)
+	if err != nil {
+		log.Warn(fmt.Sprintf("Skipping plugin '%s': %s", plugin.pkg.Slug, err.Error()))
+		return append(policyRows, agentcommon.FailedSyncRows(pending, err)...)
+	}
+	rows := cmd.CopyExtractedToTargets(unzipDir, toInstall)
+	rows = append(rows, update.UpdateTargets(unzipDir, cmd, toUpdate)...)
+	if err := cmd.RecordLockfile(rows); err != nil {
+		log.Warn(fmt.Sprintf("Plugin '%s' was synced but the lockfile was not: %s", plugin.pkg.Slug, err.Error()))
+	}
+	return append(policyRows, rows...)
+}
+
+// pruneUndeclared removes CLI-managed plugins that the manifest does not declare for a harness.
+// Only harnesses named somewhere in the plugins section are scanned.
+func (sc *syncCommand) pruneUndeclared(packages []agentcommon.ManifestPackage) ([]agentcommon.UpdateAllSummaryRow, error) {
+	registry, err := agentcommon.LoadAgentRegistry(plugincommon.Agents, agentcommon.PluginsAgentsKey)
+	if err != nil {
+		return nil, err
+	}
+	declaredByHarness := agentcommon.DeclaredSlugsByHarness(packages)
+	specs := make([]plugincommon.AgentSpec, 0, len(declaredByHarness))
+	for _, name := range agentcommon.SortedHarnesses(declaredByHarness) {
+		spec, err := agentcommon.ResolveAgent(registry, name, plugincommon.RegistryHelp)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	undeclared, err := agentcommon.FindUndeclaredInstalls(specs, sc.projectDir, plugincommon.PluginInfoManifestFile, declaredByHarness)
+	if err != nil {
+		return nil, err
+	}
+	lockPath, err := agentcommon.LockfilePath(agentcommon.InstallScopeProject, sc.projectDir, "")
+	if err != nil {
+		return nil, err
+	}
+	return agentcommon.PruneInstalls("Plugin", agentcommon.LockKindPlugin, lockPath, undeclared, sc.dryRun), nil
+}
@@ -0,0 +1,63 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/common/testutil"
+	plugincommon "github.com/jfrog/jfrog-cli-artifactory/agent/plugins/common"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func installPlugin(t *testing.T, projectDir, slug, version string) string {
+	t.Helper()
+	dir := filepath.Join(projectDir, ".claude", "plugins", slug)
+	require.NoError(t, agentcommon.WriteInstallInfoManifest(dir, plugincommon.PluginInfoManifestFile, plugincommon.PluginInfoManifest{
+		Repo: "plugins", Slug: slug, InstalledVersion: version, Scope: "project", Agent: "claude",
+	}))
+	return dir
+}
+
+func TestSync_UpdatesDriftedAndPrunes(t *testing.T) {
+	testutil.WithJfrogHome(t)
+	projectDir := t.TempDir()
+	installPlugin(t, projectDir, "review", "1.0.0")
+	stale := installPlugin(t, projectDir, "stale", "1.0.0")
+	manifestPath := agentcommon.ProjectManifestPath(projectDir)
+	require.NoError(t, os.MkdirAll(filepath.Dir(manifestPath), 0o755))
+	require.NoError(t, os.WriteFile(manifestPath, []byte(`{"plugins": [
+		{"slug": "review", "version": "1.1.0", "harnesses": ["claude"]},
+		{"slug": "lint", "harnesses": ["claude"]}
+	]}`), 0o644))
+
+	origResolve, origApply := resolvePluginVersion, applyPluginTargets
+	t.Cleanup(func() { resolvePluginVersion, applyPluginTargets = origResolve, origApply })
+	resolvePluginVersion = func(_ *config.ServerDetails, _, slug, requested string, _ bool) (string, error) {
+		if requested == "" {
+			return "2.0.0", nil
+		}
+		return requested, nil
+	}
+	installed := map[string][]string{}
+	applyPluginTargets = func(_ *syncCommand, plugin declaredPlugin, version string, toInstall, toUpdate []plugincommon.AgentTarget) []agentcommon.SummaryRow {
+		installed[plugin.pkg.Slug+"@"+version] = append(agentcommon.TargetAgentNames(toInstall), agentcommon.TargetAgentNames(toUpdate)...)
+		return nil
+	}
+
+	sc := &syncCommand{
+		serverDetails: &config.ServerDetails{},
+		repoFlag:      "plugins",
+		projectDir:    projectDir,
+		manifestPath:  manifestPath,
+		prune:         true,
+		quiet:         true,
+		format:        "json",
+	}
+	require.NoError(t, sc.run())
+	assert.Equal(t, map[string][]string{"review@1.1.0": {"claude"}, "lint@2.0.0": {"claude"}}, installed)
+	assert.NoDirExists(t, stale)
+}
@@ -11,6 +11,7 @@ import (
 	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
 	"github.com/jfrog/jfrog-cli-artifactory/agent/plugins/commands/install"
 	plugincommon "github.com/jfrog/jfrog-cli-artifactory/agent/plugins/common"
+	pluginsCommon "github.com/jfrog/jfrog-cli-core/v2/plugins/common"
 	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
@@ -39,6 +40,9 @@ var resolvePluginVersion = plugincommon.ResolvePluginVersion
 // updateSlugAcrossTargetsFn is swappable in tests.
 var updateSlugAcrossTargetsFn = updateSlugAcrossTargets
 
+// fetchSlugUpdateFn is swappable in tests.
+var fetchSlugUpdateFn = fetchSlugUpdate
+
 type preUpdate struct {
 	agentTarget            plugincommon.AgentTarget
 	installedVersion       string
@@ -54,7 +58,7 @@ func RunUpdate(c *components.Context) error {
 		if c.GetNumberOfArgs() > 0 {
 			return fmt.Errorf("unexpected positional argument(s); use --slug to specify the plugin")
 		}
-		return fmt.Errorf("usage: jf agent plugins update --slug <slug> (--harness <name[,name...]> [--global] [--project-dir <dir>] | --path <dir>) [--repo <repo>] [--version <ver>] [--dry-run] [--force] [--format <table|json>]\n       jf agent plugins update --all --harness <name[,name...]> [--global] [--project-dir <dir>] [--repo <repo>] [--dry-run] [--force] [--format <table|json>]")
+		return fmt.Errorf("usage: jf agent plugins update --slug <slug> ((--harness <name[,name...]> | --auto-harness) [--global] [--project-dir <dir>] | --path <dir>) [--repo <repo>] [--version <ver>] [--dry-run] [--force] [--no-cache] [--skip-scan-check] [--format <table|json>]\n       jf agent plugins update --all (--harness <name[,name...]> | --auto-harness) [--global] [--project-dir <dir>] [--repo <repo>] [--dry-run] [--force] [--no-cache] [--skip-scan-check] [--threads <n>] [--format <table|json>]")
 	}
 	if all {
 		if slugFlag != "" {
@@ -64,7 +68,7 @@ func RunUpdate(c *components.Context) error {
 			return fmt.Errorf("unexpected positional argument(s); use --slug or --all")
 		}
 		if strings.TrimSpace(c.GetStringFlagValue("version")) != "" {
-			return fmt.Errorf("--all cannot be combined with --version; it always updates to the latest version")
+			return fmt.Errorf("--all cannot be combined with --version; it updates each plugin to the latest version allowed by the range it was installed with")
 		}
 		if strings.TrimSpace(c.GetStringFlagValue("path")) != "" {
 			return fmt.Errorf("--all cannot be combined with --path; --path targets a single install directory")
@@ -79,7 +83,7 @@ func RunUpdate(c *components.Context) error {
 		return fmt.Errorf("--all requires --harness; --path is not supported")
 	}
 	if all && len(opts.flags.Specs) == 0 {
-		return fmt.Errorf("--all requires --harness <name[,name...]>")
+		return fmt.Errorf("--all requires --harness <name[,name...]> or --auto-harness")
 	}
 
 	if all {
@@ -105,8 +109,16 @@ type update struct {
 	flags         agentcommon.InstallFlagsResult
 	dryRun        bool
 	force         bool
+	noCache       bool
+	skipScanCheck bool
 	format        string
 	quiet         bool
+	// constraint is the version range recorded in the install manifest of updated targets.
+	constraint string
+	// threads is how many plugin groups update --all fetches and updates in parallel.
+	threads int
+	// policyLoader reads the install policy once for all plugins and parallel workers of the run.
+	policyLoader *agentcommon.InstallPolicyLoader
 }
 
 func newUpdate(c *components.Context) (update, error) {
@@ -123,6 +135,10 @@ func newUpdate(c *components.Context) (update, error) {
 	if err != nil {
 		return update{}, err
 	}
+	threads, err := pluginsCommon.GetThreadsCount(c)
+	if err != nil {
+		return update{}, err
+	}
 	format := "table"
 	if c.GetStringFlagValue("format") != "" {
 		format = c.GetStringFlagValue("format")
@@ -133,8 +149,12 @@ func newUpdate(c *components.Context) (update, error) {
 		flags:         flags,
 		dryRun:        c.GetBoolFlagValue("dry-run"),
 		force:         c.GetBoolFlagValue("force"),
+		noCache:       c.GetBoolFlagValue("no-cache"),
+		skipScanCheck: c.GetBoolFlagValue("skip-scan-check"),
 		format:        format,
 		quiet:         quiet,
+		threads:       threads,
+		policyLoader:  agentcommon.NewInstallPolicyLoader(serverDetails),
 	}, nil
 }
 
@@ -145,17 +165,24 @@ func runUpdateOnSlug(opts update, slug, requestedVersion string) error {
 		return err
 	}
 
-	targetVersion, err := resolveTargetVersion(opts.serverDetails, opts.repoKey, slug, requestedVersion, opts.quiet)
-	if err != nil {
-		return err
-	}
+	// Without --version each target stays within the range it was installed with.
+	var results []agentcommon.SummaryRow
+	for _, group := range agentcommon.GroupTargetsByConstraint(targets, plugincommon.PluginInfoManifestFile, requestedVersion) {
+		targetVersion, err := resolveTargetVersion(opts.serverDetails, opts.repoKey, slug, group.Requested, opts.quiet)
+		if err != nil {
+			return err
+		}
 
-	results, err := updateSlugAcrossTargetsFn(opts, slug, targetVersion, targets)
-	if err != nil {
-		return err
-	}
-	if err := agentcommon.PrintInstallSummary("Plugin", slug, targetVersion, results, opts.format); err != nil {
-		return err
+		groupOpts := opts
+		groupOpts.constraint = group.Constraint
+		groupResults, err := updateSlugAcrossTargetsFn(groupOpts, slug, targetVersion, group.Targets)
+		if err != nil {
+			return err
+		}
+		if err := agentcommon.PrintInstallSummary("Plugin", slug, targetVersion, groupResults, opts.format); err != nil {
+			return err
+		}
+		results = append(results, groupResults...)
 	}
 	return finalError(results)
 }
@@ -179,7 +206,8 @@ func confirmUpdateAll(opts update) error {
 	return nil
 }
 
-// runUpdateAll enumerates every installed plugin under each --harness and updates each to its latest version.
+// runUpdateAll enumerates every installed plugin under each --harness and updates each to its latest version
+// (or the newest version within the range it was installed with).
 func runUpdateAll(opts update) error {
 	slugOrder, slugToTargets, err := discoverInstalledPluginTargets(opts.flags)
 	if err != nil {
@@ -225,40 +253,83 @@ func discoverInstalledPluginTargets(flags agentcommon.InstallFlagsResult) ([]str
 	return slugOrder, slugToTargets, nil
 }
 
+// updateAllJob is one slug and constraint group of an update --all run.
+type updateAllJob struct {
+	slug          string
+	group         agentcommon.ConstraintGroup
+	targetVersion string
+	// fetched is set by run when the group was fetched; record applies it.
+	fetched    *slugUpdate
+	results    []agentcommon.SummaryRow
+	resolveErr error
+}
+
 // applyUpdateAllForSlugs resolves latest version per slug, updates targets, and builds combined summary rows.
+// Groups are resolved and fetched on up to opts.threads workers; install directories and the lockfile are
+// updated one group at a time, in slug order.
 // Resolve and download failures for one slug are logged and recorded as failed rows; remaining slugs still run.
 func applyUpdateAllForSlugs(opts update, slugOrder []string, slugToTargets map[string][]plugincommon.AgentTarget,
 ) ([]agentcommon.UpdateAllSummaryRow, updateAllOutcome) {
-	combined := make([]agentcommon.UpdateAllSummaryRow, 0)
-	var outcome updateAllOutcome
+	var jobs []*updateAllJob
 	for _, slug := range slugOrder {
-		targetVersion, err := resolveLatestPluginVersion(opts.serverDetails, opts.repoKey, slug)
-		if err != nil {
-			if outcome.firstResolveErr == nil {
-				outcome.firstResolveErr = err
-			}
-			log.Warn(fmt.Sprintf("Skipping plugin '%s': could not resolve latest version: %s", slug, err.Error()))
-			results := failedRowsForTargets(slugToTargets[slug], err.Error())
-			combined = agentcommon.AppendUpdateAllSummaryRows(combined, slug, "", results)
-			outcome.updatedSlugCount++
-			_, slugFailed := tallySummaryRows(results)
-			outcome.anyFailed = outcome.anyFailed || slugFailed
-			continue
+		// Targets installed with a version range are updated to the newest version that still satisfies it.
+		for _, group := range agentcommon.GroupTargetsByConstraint(slugToTargets[slug], plugincommon.PluginInfoManifestFile, "") {
+			jobs = append(jobs, &updateAllJob{slug: slug, group: group})
 		}
-		results, err := updateSlugAcrossTargetsFn(opts, slug, targetVersion, slugToTargets[slug])
-		if err != nil {
-			log.Warn(fmt.Sprintf("Skipping plugin '%s': download failed: %s", slug, err.Error()))
-			results = failedRowsForTargets(slugToTargets[slug], err.Error())
-		}
-		combined = agentcommon.AppendUpdateAllSummaryRows(combined, slug, targetVersion, results)
-		outcome.updatedSlugCount++
-		slugOK, slugFailed := tallySummaryRows(results)
-		outcome.anyOK = outcome.anyOK || slugOK
-		outcome.anyFailed = outcome.anyFailed || slugFailed
 	}
+	combined := make([]agentcommon.UpdateAllSummaryRow, 0)
+	var outcome updateAllOutcome
+	agentcommon.RunPackagePipeline(len(jobs), opts.threads, func(i int) {
+		jobs[i].run(opts)
+	}, func(i int) {
+		combined = jobs[i].record(combined, &outcome)
+	})
 	return combined, outcome
 }
 
+// run resolves the group's target version and fetches it. It does not touch install directories or the lockfile.
+func (job *updateAllJob) run(opts update) {
+	opts.constraint = job.group.Constraint
+	targetVersion, err := resolveUpdateAllVersion(opts, job.slug, job.group.Requested)
+	if err != nil {
+		log.Warn(fmt.Sprintf("Skipping plugin '%s': could not resolve latest version: %s", job.slug, err.Error()))
+		job.resolveErr = err
+		job.results = failedRowsForTargets(job.group.Targets, err.Error())
+		return
+	}
+	job.targetVersion = targetVersion
+	fetched, err := fetchSlugUpdateFn(opts, job.slug, targetVersion, job.group.Targets)
+	if err != nil {
+		log.Warn(fmt.Sprintf("Skipping plugin '%s': download failed: %s", job.slug, err.Error()))
+		job.results = failedRowsForTargets(job.group.Targets, err.Error())
+		return
+	}
+	job.fetched = fetched
+}
+
+// record applies the fetched update, appends the job's rows to combined, and folds them into outcome.
+func (job *updateAllJob) record(combined []agentcommon.UpdateAllSummaryRow, outcome *updateAllOutcome) []agentcommon.UpdateAllSummaryRow {
+	if job.fetched != nil {
+		job.results = job.fetched.apply()
+	}
+	if job.resolveErr != nil && outcome.firstResolveErr == nil {
+		outcome.firstResolveErr = job.resolveErr
+	}
+	outcome.updatedSlugCount++
+	slugOK, slugFailed := tallySummaryRows(job.results)
+	outcome.anyOK = outcome.anyOK || slugOK
+	outcome.anyFailed = outcome.anyFailed || slugFailed
+	return agentcommon.AppendUpdateAllSummaryRows(combined, job.slug, job.targetVersion, job.results)
+}
+
+// resolveUpdateAllVersion picks the latest version, or the newest one satisfying a recorded constraint.
+func resolveUpdateAllVersion(opts update, slug, requested string) (string, error) {
+	if requested == "" {
+		return resolveLatestPluginVersion(opts.serverDetails, opts.repoKey, slug)
+	}
+	return resolvePluginVersion(opts.serverDetails, opts.repoKey, slug, requested, true)
+}
+
 func failedRowsForTargets(targets []plugincommon.AgentTarget, detail string) []agentcommon.SummaryRow {
 	rows := make([]agentcommon.SummaryRow, 0, len(targets))
 	for _, target := range targets {
@@ -302,46 +373,108 @@ func resolveTargetVersion(serverDetails *config.ServerDetails, repoKey, slug, re
 // Returns the per-target summary rows. Targets that are not installed or already at the
 // target version are reported without performing a download.
 func updateSlugAcrossTargets(opts update, slug, targetVersion string, targets []plugincommon.AgentTarget) ([]agentcommon.SummaryRow, error) {
+	fetched, err := fetchSlugUpdate(opts, slug, targetVersion, targets)
+	if err != nil {
+		return nil, err
+	}
+	return fetched.apply(), nil
+}
+
+// slugUpdate is a slug fetched for update: rows for the targets that are reported without a download, and the
+// extracted tree for the targets apply replaces.
+type slugUpdate struct {
+	slug       string
+	results    []agentcommon.SummaryRow
+	updatable  []preUpdate
+	installCmd *install.InstallCommand
+	tmpDir     string
+	unzipDir   string
+}
+
+// fetchSlugUpdate checks the targets and the install policy, then downloads and extracts the slug into a temp dir.
+// It only reads install directories, so update --all runs it on parallel workers.
+func fetchSlugUpdate(opts update, slug, targetVersion string, targets []plugincommon.AgentTarget) (*slugUpdate, error) {
 	checks := preUpdateTargets(targets, targetVersion, opts.force, opts.quiet)
 	results, updatable := initialResultsAndUpdatable(checks, targetVersion)
+	fetched := &slugUpdate{slug: slug, results: results}
 
 	if opts.dryRun {
 		logDryRun(slug, targetVersion, checks)
-		return results, nil
+		return fetched, nil
 	}
 	if len(updatable) == 0 {
-		return results, nil
-	}
-
-	tmpDir, err := os.MkdirTemp("", "plugin-update-*")
-	if err != nil {
-		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+		return fetched, nil
 	}
-	defer func() {
-		// Best-effort teardown of per-slug temp dir after copies finish or fail.
-		if removeErr := os.RemoveAll(tmpDir); removeErr != nil {
-			log.Warn(fmt.Sprintf("Could not remove plugin update temp dir %s: %s", tmpDir, removeErr.Error()))
-		}
-	}()
 
 	installCmd := install.NewInstallCommand().
 		SetServerDetails(opts.serverDetails).
 		SetRepoKey(opts.repoKey).
 		SetSlug(slug).
 		SetVersion(targetVersion).
+		SetConstraint(opts.constraint).
 		SetQuiet(opts.quiet).
 		SetProjectDir(opts.flags.ProjectDirAbs).
-		SetGlobal(opts.flags.IsGlobal)
+		SetGlobal(opts.flags.IsGlobal).
+		SetInstallPath(opts.flags.AbsoluteInstallBaseDir).
+		SetNoCache(opts.noCache).
+		SetSkipScanCheck(opts.skipScanCheck).
+		SetPolicyLoader(opts.policyLoader)
 
+	allowed := make([]preUpdate, 0, len(updatable))
+	for _, preUpdateCheck := range updatable {
+		if err := installCmd.CheckPolicyTarget(preUpdateCheck.agentTarget); err != nil {
+			fetched.results = append(fetched.results, summaryRowFor(preUpdateCheck.agentTarget, agentcommon.SummaryStatusFailed, err.Error()))
+			continue
+		}
+		allowed = append(allowed, preUpdateCheck)
+	}
+	if len(allowed) == 0 {
+		return fetched, nil
+	}
+
+	tmpDir, err := os.MkdirTemp("", "plugin-update-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
 	unzipDir, err := installCmd.FetchAndExtractTo(tmpDir)
 	if err != nil {
-		return nil, err
+		removeUpdateTmpDir(tmpDir)
+		if !agentcommon.IsPolicyViolation(err) {
+			return nil, err
+		}
+		for _, preUpdateCheck := range allowed {
+			fetched.results = append(fetched.results, summaryRowFor(preUpdateCheck.agentTarget, agentcommon.SummaryStatusFailed, err.Error()))
+		}
+		return fetched, nil
 	}
+	fetched.updatable, fetched.installCmd, fetched.tmpDir, fetched.unzipDir = allowed, installCmd, tmpDir, unzipDir
+	return fetched, nil
+}
 
-	for _, preUpdateCheck := range updatable {
-		results = append(results, updatePlugin(unzipDir, installCmd, preUpdateCheck))
+// apply replaces each updatable target with the fetched tree, records the lockfile, and removes the temp dir.
+// It moves install directories and writes the lockfile, so callers run it on one goroutine at a time.
+func (su *slugUpdate) apply() []agentcommon.SummaryRow {
+	if su.tmpDir != "" {
+		// Best-effort teardown of per-slug temp dir after copies finish or fail.
+		defer removeUpdateTmpDir(su.tmpDir)
+	}
+	results := su.results
+	if len(su.updatable) == 0 {
+		return results
+	}
+	for _, preUpdateCheck := range su.updatable {
+		results = append(results, updatePlugin(su.unzipDir, su.installCmd, preUpdateCheck))
+	}
+	if err := su.installCmd.RecordLockfile(results); err != nil {
+		log.Warn(fmt.Sprintf("Plugin '%s' was updated but the lockfile was not: %s", su.slug, err.Error()))
+	}
+	return results
+}
+
+func removeUpdateTmpDir(tmpDir string) {
+	if err := os.RemoveAll(tmpDir); err != nil {
+		log.Warn(fmt.Sprintf("Could not remove plugin update temp dir %s: %s", tmpDir, err.Error()))
 	}
-	return results, nil
 }
 
 func preUpdateTargets(targets []plugincommon.AgentTarget, targetVersion string, force, quiet bool) []preUpdate {
@@ -413,10 +546,22 @@ func logDryRun(slug, targetVersion string, checks []preUpdate) {
 	}
 }
 
+// UpdateTargets replaces each installed target with the already-fetched tree in unzipDir,
+// restoring the previous install when a copy fails. Used by plugins sync for drifted targets.
+func UpdateTargets(unzipDir string, installCommand *install.InstallCommand, targets []plugincommon.AgentTarget) []agentcommon.SummaryRow {
+	results := make([]agentcommon.SummaryRow, 0, len(targets))
+	for _, agentTarget := range targets {
+		results = append(results, updatePlugin(unzipDir, installCommand, preUpdate{agentTarget: agentTarget}))
+	}
+	return results
+}
+
 // updatePlugin updates a single install target using the already-fetched tree in unzipDir.
-// On success the backup is deleted; on copy failure applyPluginUpdateCopy restores the backup first.
+// On success the backup is kept for rollback; on copy failure applyPluginUpdateCopy restores the backup first.
+// Targets installed with --convert are converted again.
 func updatePlugin(unzipDir string, installCommand *install.InstallCommand, check preUpdate) agentcommon.SummaryRow {
 	agentTarget := check.agentTarget
+	installCommand.SetConvert(wasConvertedInstall(agentTarget.DestinationDir))
 	backupPath, err := createPluginBackupForUpdate(agentTarget)
 	if err != nil {
 		return summaryRowFor(agentTarget, agentcommon.SummaryStatusFailed, err.Error())
@@ -425,10 +570,16 @@ func updatePlugin(unzipDir string, installCommand *install.InstallCommand, check
 	if row.Status != agentcommon.SummaryStatusOK {
 		return row
 	}
-	removePluginUpdateBackup(backupPath, filepath.Dir(agentTarget.DestinationDir))
+	keepPluginUpdateBackup(backupPath, agentTarget.DestinationDir)
 	return summaryRowFor(agentTarget, agentcommon.SummaryStatusOK, agentcommon.SummaryDetailOKInstall)
 }
 
+// wasConvertedInstall reports whether the plugin at pluginDir was installed as a copy converted to its harness.
+func wasConvertedInstall(pluginDir string) bool {
+	manifest, err := agentcommon.ReadInstallInfoManifest(pluginDir, plugincommon.PluginInfoManifestFile)
+	return err == nil && manifest != nil && manifest.ConvertedFrom != ""
+}
+
 // createPluginBackupForUpdate reserves a backup path and renames the live install directory aside.
 func createPluginBackupForUpdate(agentTarget plugincommon.AgentTarget) (string, error) {
 	slugBase := filepath.Base(agentTarget.DestinationDir)
@@ -476,13 +627,17 @@ func applyPluginUpdateCopy(unzipDir string, installCommand *install.InstallComma
 	return row
 }
 
-// removePluginUpdateBackup deletes the backup tree after a successful update.
-func removePluginUpdateBackup(backupPath, parent string) {
-	if err := os.RemoveAll(backupPath); err != nil {
-		log.Warn(fmt.Sprintf("Update succeeded but previous copy at %s could not be deleted: %s", backupPath, err.Error()))
-		return
+// keepPluginUpdateBackup moves the backup tree under the new install's .jfrog/previous after a successful update,
+// so that rollback can restore it, and removes the backup root once it is empty.
+func keepPluginUpdateBackup(backupPath, installDir string) {
+	if err := agentcommon.KeepPreviousInstall(backupPath, installDir); err != nil {
+		log.Warn(fmt.Sprintf("Update succeeded but the previous version could not be kept for rollback: %s", err.Error()))
+		if err := os.RemoveAll(backupPath); err != nil {
+			log.Warn(fmt.Sprintf("Previous copy at %s could not be deleted: %s", backupPath, err.Error()))
+			return
+		}
 	}
-	backupRoot := filepath.Join(parent, pluginBackupDirName)
+	backupRoot := filepath.Join(filepath.Dir(installDir), pluginBackupDirName)
 	if err := os.Remove(backupRoot); err != nil && !os.IsNotExist(err) {
 		log.Warn(fmt.Sprintf("Could not remove empty %s directory at %s: %s", pluginBackupDirName, backupRoot, err.Error()))
 	}
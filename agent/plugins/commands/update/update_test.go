@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
 	"github.com/jfrog/jfrog-cli-artifactory/agent/plugins/commands/install"
@@ -159,6 +160,28 @@ func TestUpdateOnePlugin_SuccessRemovesBackup(t *testing.T) {
 	assert.Contains(t, string(data), "2.0.0")
 }
 
+func TestUpdateOnePlugin_SuccessKeepsPreviousVersion(t *testing.T) {
+	dir := pluginDir(t, `{"name":"web","version":"1.0.0"}`)
+	check := preUpdate{
+		agentTarget: plugincommon.AgentTarget{
+			Agent:          plugincommon.AgentSpec{Name: "claude"},
+			Scope:          plugincommon.ScopeProject,
+			DestinationDir: dir,
+		},
+		installedVersion: "1.0.0",
+	}
+
+	src := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(src, "plugin.json"), []byte(`{"name":"web","version":"2.0.0"}`), agentcommon.DefaultFileMode))
+
+	row := updatePlugin(src, install.NewInstallCommand().SetSlug("web").SetVersion("2.0.0").SetRepoKey("r"), check)
+	require.Equal(t, agentcommon.SummaryStatusOK, row.Status)
+
+	data, err := os.ReadFile(filepath.Join(agentcommon.PreviousInstallDir(dir), "plugin.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "1.0.0", "the replaced version is kept for rollback")
+}
+
 func TestResolveTargetVersion_ExplicitUsedDirectly(t *testing.T) {
 	restore := resolvePluginVersion
 	resolvePluginVersion = func(_ *config.ServerDetails, repoKey, slug, requested string, quiet bool) (string, error) {
@@ -301,22 +324,22 @@ func TestApplyUpdateAllForSlugs_ContinuesOnResolveError(t *testing.T) {
 
 func TestApplyUpdateAllForSlugs_ContinuesOnDownloadError(t *testing.T) {
 	oldResolve := resolveLatestPluginVersion
-	oldUpdate := updateSlugAcrossTargetsFn
+	oldFetch := fetchSlugUpdateFn
 	defer func() {
 		resolveLatestPluginVersion = oldResolve
-		updateSlugAcrossTargetsFn = oldUpdate
+		fetchSlugUpdateFn = oldFetch
 	}()
 
 	resolveLatestPluginVersion = func(*config.ServerDetails, string, string) (string, error) {
 		return "2.0.0", nil
 	}
-	updateSlugAcrossTargetsFn = func(opts update, slug, targetVersion string, targets []plugincommon.AgentTarget) ([]agentcommon.SummaryRow, error) {
+	fetchSlugUpdateFn = func(opts update, slug, targetVersion string, targets []plugincommon.AgentTarget) (*slugUpdate, error) {
 		if slug == "bad" {
 			return nil, errors.New("download failed")
 		}
-		return []agentcommon.SummaryRow{
+		return &slugUpdate{slug: slug, results: []agentcommon.SummaryRow{
 			{Agent: targets[0].Agent.Name, Scope: string(targets[0].Scope), Path: targets[0].DestinationDir, Status: agentcommon.SummaryStatusOK},
-		}, nil
+		}}, nil
 	}
 
 	target := plugincommon.AgentTarget{
@@ -338,6 +361,71 @@ func TestApplyUpdateAllForSlugs_ContinuesOnDownloadError(t *testing.T) {
 	assert.Equal(t, 2, outcome.updatedSlugCount)
 }
 
+func TestApplyUpdateAllForSlugs_StaysWithinRecordedConstraint(t *testing.T) {
+	oldLatest, oldResolve, oldFetch := resolveLatestPluginVersion, resolvePluginVersion, fetchSlugUpdateFn
+	t.Cleanup(func() {
+		resolveLatestPluginVersion, resolvePluginVersion, fetchSlugUpdateFn = oldLatest, oldResolve, oldFetch
+	})
+	resolveLatestPluginVersion = func(*config.ServerDetails, string, string) (string, error) {
+		return "2.0.0", nil
+	}
+	resolvePluginVersion = func(_ *config.ServerDetails, _, _, requested string, _ bool) (string, error) {
+		assert.Equal(t, "^1.2", requested)
+		return "1.9.0", nil
+	}
+	constraints := map[string]string{}
+	fetchSlugUpdateFn = func(opts update, slug, targetVersion string, targets []plugincommon.AgentTarget) (*slugUpdate, error) {
+		constraints[targets[0].Agent.Name+"@"+targetVersion] = opts.constraint
+		return &slugUpdate{slug: slug}, nil
+	}
+
+	root := t.TempDir()
+	ranged := plugincommon.AgentTarget{Agent: plugincommon.AgentSpec{Name: "claude"}, DestinationDir: filepath.Join(root, "claude", "review")}
+	latest := plugincommon.AgentTarget{Agent: plugincommon.AgentSpec{Name: "cursor"}, DestinationDir: filepath.Join(root, "cursor", "review")}
+	require.NoError(t, agentcommon.WriteInstallInfoManifest(ranged.DestinationDir, plugincommon.PluginInfoManifestFile, plugincommon.PluginInfoManifest{
+		Slug: "review", InstalledVersion: "1.2.0", Constraint: "^1.2",
+	}))
+
+	opts := update{serverDetails: &config.ServerDetails{}, repoKey: "repo"}
+	applyUpdateAllForSlugs(opts, []string{"review"}, map[string][]plugincommon.AgentTarget{"review": {ranged, latest}})
+	assert.Equal(t, map[string]string{"claude@1.9.0": "^1.2", "cursor@2.0.0": ""}, constraints)
+}
+
+func TestApplyUpdateAllForSlugs_ParallelKeepsSlugOrder(t *testing.T) {
+	oldLatest, oldFetch := resolveLatestPluginVersion, fetchSlugUpdateFn
+	t.Cleanup(func() {
+		resolveLatestPluginVersion, fetchSlugUpdateFn = oldLatest, oldFetch
+	})
+	resolveLatestPluginVersion = func(_ *config.ServerDetails, _, slug string) (string, error) {
+		if slug == "c" {
+			return "", errors.New("no versions")
+		}
+		return "2.0.0", nil
+	}
+	fetchSlugUpdateFn = func(_ update, slug, _ string, targets []plugincommon.AgentTarget) (*slugUpdate, error) {
+		// Earlier slugs finish last.
+		time.Sleep(time.Duration(4-len(slug)) * 5 * time.Millisecond)
+		return &slugUpdate{slug: slug, results: []agentcommon.SummaryRow{{Agent: targets[0].Agent.Name, Path: targets[0].DestinationDir, Status: agentcommon.SummaryStatusOK}}}, nil
+	}
+
+	slugs := []string{"a", "bb", "c", "ddd"}
+	slugToTargets := map[string][]plugincommon.AgentTarget{}
+	for _, slug := range slugs {
+		slugToTargets[slug] = []plugincommon.AgentTarget{{Agent: plugincommon.AgentSpec{Name: "cursor"}, DestinationDir: filepath.Join(t.TempDir(), slug)}}
+	}
+	opts := update{serverDetails: &config.ServerDetails{}, repoKey: "repo", threads: 4}
+	combined, outcome := applyUpdateAllForSlugs(opts, slugs, slugToTargets)
+
+	require.Len(t, combined, 4)
+	for i, slug := range slugs {
+		assert.Equal(t, slug, combined[i].Name)
+	}
+	assert.Equal(t, agentcommon.SummaryStatusFailed, combined[2].Status)
+	assert.True(t, outcome.anyOK)
+	assert.True(t, outcome.anyFailed)
+	assert.Equal(t, 4, outcome.updatedSlugCount)
+}
+
 func TestCreatePluginBackupForUpdate_MissingInstallDir(t *testing.T) {
 	target := plugincommon.AgentTarget{
 		Agent:          plugincommon.AgentSpec{Name: "cursor"},
@@ -0,0 +1,45 @@
+package validate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+	plugincommon "github.com/jfrog/jfrog-cli-artifactory/agent/plugins/common"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+)
+
+// RunValidate is the CLI action for `jf agent plugins validate <path> [<path>...]`.
+// It applies the checks publish runs before zipping, without contacting the server, so the same rules can run
+// in pre-commit hooks and CI. It fails when any plugin has errors, or warnings with --strict.
+func RunValidate(c *components.Context) error {
+	if c.GetNumberOfArgs() < 1 {
+		return fmt.Errorf("usage: jf agent plugins validate <path> [<path>...] [--strict] [--format <table|json>]")
+	}
+	format := "table"
+	if c.GetStringFlagValue("format") != "" {
+		format = c.GetStringFlagValue("format")
+	}
+	reports := make([]agentcommon.ValidationReport, 0, c.GetNumberOfArgs())
+	for argIndex := 0; argIndex < c.GetNumberOfArgs(); argIndex++ {
+		arg := c.GetArgumentAt(argIndex)
+		pluginDir, err := filepath.Abs(arg)
+		if err != nil {
+			return fmt.Errorf("invalid plugin path: %w", err)
+		}
+		info, err := os.Stat(pluginDir)
+		if err != nil || !info.IsDir() {
+			return fmt.Errorf("plugin path '%s' is not a valid directory", arg)
+		}
+		report, err := plugincommon.ValidatePlugin(pluginDir)
+		if err != nil {
+			return err
+		}
+		reports = append(reports, report)
+	}
+	if err := agentcommon.PrintValidationReports("Plugin", reports, format); err != nil {
+		return err
+	}
+	return agentcommon.ValidationError("Plugin", reports, c.GetBoolFlagValue("strict"))
+}
@@ -0,0 +1,52 @@
+package verify
+
+import (
+	"fmt"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+	plugincommon "github.com/jfrog/jfrog-cli-artifactory/agent/plugins/common"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+)
+
+// RunVerify is the CLI action for `jf agent plugins verify`.
+// It re-hashes installed plugins and reports files modified, added, or removed since install.
+func RunVerify(c *components.Context) error {
+	if c.GetNumberOfArgs() > 1 {
+		return fmt.Errorf("usage: jf agent plugins verify [<slug>] (--harness <name[,name...]> [--global] [--project-dir <dir>] | --path <dir>) [--evidence] [--format <table|json>]")
+	}
+	slug := ""
+	if c.GetNumberOfArgs() == 1 {
+		slug = c.GetArgumentAt(0)
+		if err := agentcommon.ValidateSlug(slug); err != nil {
+			return err
+		}
+	}
+
+	flags, err := agentcommon.ValidateInstallFlags(c, plugincommon.Agents, agentcommon.PluginsAgentsKey, plugincommon.RegistryHelp)
+	if err != nil {
+		return err
+	}
+	opts := agentcommon.VerifyOptions{ManifestFileName: plugincommon.PluginInfoManifestFile}
+	if c.GetBoolFlagValue("evidence") {
+		serverDetails, err := agentcommon.GetServerDetails(c)
+		if err != nil {
+			return err
+		}
+		opts.CheckEvidence = true
+		opts.ServerDetails = serverDetails
+	}
+	format := "table"
+	if c.GetStringFlagValue("format") != "" {
+		format = c.GetStringFlagValue("format")
+	}
+
+	targets, err := agentcommon.VerifyTargets(slug, flags, plugincommon.PluginInfoManifestFile)
+	if err != nil {
+		return err
+	}
+	results := agentcommon.VerifyInstalls(targets, opts)
+	if err := agentcommon.PrintVerifySummary("Plugin", results, format); err != nil {
+		return err
+	}
+	return agentcommon.VerifyError("Plugin", results)
+}
@@ -11,9 +11,9 @@ type AgentSpec = agentcommon.AgentSpec
 // Agents is the hardcoded set of agents currently supported by `jf agent plugins`.
 // User overrides come from agent-config.json -> "plugins-agents".
 var Agents = map[string]AgentConfig{
-	"claude": {GlobalDir: "~/.claude/plugins", ProjectDir: ".claude/plugins"},
-	"cursor": {GlobalDir: "~/.cursor/plugins", ProjectDir: ".cursor/plugins"},
-	"codex":  {GlobalDir: "~/.codex/plugins", ProjectDir: ".codex/plugins"},
+	"claude": {GlobalDir: "~/.claude/plugins", ProjectDir: ".claude/plugins", DetectPaths: []string{".claude", "CLAUDE.md", "~/.claude"}},
+	"cursor": {GlobalDir: "~/.cursor/plugins", ProjectDir: ".cursor/plugins", DetectPaths: []string{".cursor", ".cursorrules", "~/.cursor"}},
+	"codex":  {GlobalDir: "~/.codex/plugins", ProjectDir: ".codex/plugins", DetectPaths: []string{".codex", "~/.codex"}},
 }
 
 // RegistryHelp configures agent-config.json help text for plugins harness resolution.
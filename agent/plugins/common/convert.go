@@ -0,0 +1,255 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Conversion statuses of one plugin component.
+const (
+	ConvertStatusConverted = "converted"
+	ConvertStatusDropped   = "dropped"
+)
+
+// hooksComponent is the manifest field whose configuration is specific to each harness.
+const hooksComponent = "hooks"
+
+// PluginLayout is where a harness reads a plugin manifest and which manifestPathFields components it loads.
+type PluginLayout struct {
+	ManifestPath string
+	Components   []string
+}
+
+// PluginLayouts maps harness names to the plugin layout they load. Plugins are converted between these layouts.
+var PluginLayouts = map[string]PluginLayout{
+	"claude":  {ManifestPath: ".claude-plugin/" + manifestFileName, Components: []string{"commands", "agents", "skills", "hooks", "mcpServers", "lspServers", "outputStyles"}},
+	"cursor":  {ManifestPath: ".cursor-plugin/" + manifestFileName, Components: []string{"commands", "agents", "skills", "hooks", "mcpServers"}},
+	"codex":   {ManifestPath: ".codex-plugin/" + manifestFileName, Components: []string{"skills", "mcpServers"}},
+	"copilot": {ManifestPath: ".github/plugin/" + manifestFileName, Components: []string{"commands", "agents", "skills", "hooks", "mcpServers"}},
+}
+
+// defaultComponentPaths are the plugin-relative paths harnesses load a component from when the manifest does not
+// declare it.
+var defaultComponentPaths = map[string]string{
+	"commands":     "commands",
+	"agents":       "agents",
+	"skills":       "skills",
+	"hooks":        "hooks/hooks.json",
+	"mcpServers":   ".mcp.json",
+	"lspServers":   ".lsp.json",
+	"outputStyles": "output-styles",
+}
+
+// ConversionItem is what happened to one component of a converted plugin.
+type ConversionItem struct {
+	Component string `json:"component" col-name:"Component"`
+	Path      string `json:"path,omitempty" col-name:"Path"`
+	Status    string `json:"status" col-name:"Status"`
+	Detail    string `json:"detail,omitempty" col-name:"Detail"`
+}
+
+// ConversionReport describes the conversion of one plugin to the layout of another harness.
+type ConversionReport struct {
+	Name string `json:"name"`
+	// From is the harness the plugin was written for; empty when its manifest is not in a harness-specific location.
+	From     string           `json:"from,omitempty"`
+	To       string           `json:"to"`
+	Manifest string           `json:"manifest"`
+	Items    []ConversionItem `json:"items"`
+}
+
+// Dropped returns the components that could not be translated to the target harness.
+func (r ConversionReport) Dropped() []ConversionItem {
+	var dropped []ConversionItem
+	for _, item := range r.Items {
+		if item.Status == ConvertStatusDropped {
+			dropped = append(dropped, item)
+		}
+	}
+	return dropped
+}
+
+// KnownPluginLayouts returns the harness names in PluginLayouts, sorted.
+func KnownPluginLayouts() []string {
+	names := make([]string, 0, len(PluginLayouts))
+	for name := range PluginLayouts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PluginManifestHarness returns the harness whose layout holds the primary manifest of the plugin at pluginRoot, or
+// "" when the manifest is in a location shared by all harnesses, such as plugin.json at the root.
+func PluginManifestHarness(pluginRoot string) (string, error) {
+	relativePath, _, err := findPrimaryPluginManifest(pluginRoot)
+	if err != nil {
+		return "", err
+	}
+	return harnessForManifestPath(relativePath), nil
+}
+
+func harnessForManifestPath(relativePath string) string {
+	relativePath = filepath.ToSlash(relativePath)
+	for _, name := range KnownPluginLayouts() {
+		if PluginLayouts[name].ManifestPath == relativePath {
+			return name
+		}
+	}
+	return ""
+}
+
+// NeedsConversion reports whether harness to must get a converted copy of the plugin at pluginRoot: the plugin
+// was written for another harness and does not also ship a manifest in the layout of to.
+func NeedsConversion(pluginRoot, to string) (bool, error) {
+	layout, known := PluginLayouts[to]
+	if !known {
+		return false, nil
+	}
+	if _, err := os.Stat(filepath.Join(pluginRoot, filepath.FromSlash(layout.ManifestPath))); err == nil {
+		return false, nil
+	}
+	from, err := PluginManifestHarness(pluginRoot)
+	if err != nil {
+		return false, err
+	}
+	return from != "" && from != to, nil
+}
+
+// ConvertPlugin writes the plugin at srcDir to dstDir in the layout harness to loads: the manifest moves to the
+// target manifest path, and components the target does not load are left out of the manifest and the tree.
+// Hooks are dropped when the harnesses differ, since each harness names hook events and configures them in its
+// own way. Every component is listed in the report with whether it was converted.
+func ConvertPlugin(srcDir, dstDir, to string) (ConversionReport, error) {
+	layout, known := PluginLayouts[to]
+	if !known {
+		return ConversionReport{}, fmt.Errorf("no plugin layout is known for harness '%s' (known: %s)", to, strings.Join(KnownPluginLayouts(), ", "))
+	}
+	relativePath, meta, err := findPrimaryPluginManifest(srcDir)
+	if err != nil {
+		return ConversionReport{}, err
+	}
+	relativePath = filepath.ToSlash(relativePath)
+	report := ConversionReport{Name: meta.Name, From: harnessForManifestPath(relativePath), To: to, Manifest: layout.ManifestPath}
+
+	// #nosec G304 -- path is constructed by joining a user-provided directory with a fixed allowlist.
+	data, err := os.ReadFile(filepath.Join(srcDir, filepath.FromSlash(relativePath)))
+	if err != nil {
+		return report, fmt.Errorf("failed to read %s: %w", relativePath, err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return report, fmt.Errorf("failed to parse %s: %w", relativePath, err)
+	}
+
+	excluded := []string{relativePath}
+	for _, component := range manifestPathFields {
+		raw, declared := fields[component]
+		paths := manifestFieldPaths(raw)
+		if !declared {
+			defaultPath := defaultComponentPaths[component]
+			if _, err := os.Stat(filepath.Join(srcDir, filepath.FromSlash(defaultPath))); err != nil {
+				continue
+			}
+			paths = []string{defaultPath}
+		}
+		item := ConversionItem{Component: component, Path: strings.Join(paths, ", "), Status: ConvertStatusConverted}
+		switch {
+		case !slices.Contains(layout.Components, component):
+			item.Status, item.Detail = ConvertStatusDropped, fmt.Sprintf("%s plugins do not support %s", to, component)
+		case component == hooksComponent && report.From != to:
+			item.Status, item.Detail = ConvertStatusDropped, "hook events and configuration differ between harnesses; port the hooks by hand"
+		}
+		if item.Status == ConvertStatusDropped {
+			delete(fields, component)
+			for _, componentPath := range paths {
+				excluded = append(excluded, path.Clean(componentPath))
+			}
+		}
+		report.Items = append(report.Items, item)
+	}
+
+	if err := copyPluginTree(srcDir, dstDir, excluded); err != nil {
+		return report, err
+	}
+	converted, err := json.MarshalIndent(fields, "", manifestJSONIndent)
+	if err != nil {
+		return report, fmt.Errorf("failed to marshal %s: %w", layout.ManifestPath, err)
+	}
+	manifestPath := filepath.Join(dstDir, filepath.FromSlash(layout.ManifestPath))
+	if err := os.MkdirAll(filepath.Dir(manifestPath), agentcommon.InstallDirMode); err != nil {
+		return report, err
+	}
+	// #nosec G306 -- the manifest is written into a user-owned output or install directory.
+	if err := os.WriteFile(manifestPath, append(converted, '\n'), agentcommon.InstallManifestFileMode); err != nil {
+		return report, fmt.Errorf("failed to write %s: %w", layout.ManifestPath, err)
+	}
+	return report, nil
+}
+
+// copyPluginTree copies the files under srcDir to dstDir, leaving out the .jfrog directory and the plugin-relative
+// paths in excluded. Directories are created for the files copied, so none is left empty by an exclusion.
+func copyPluginTree(srcDir, dstDir string, excluded []string) error {
+	if err := os.MkdirAll(dstDir, agentcommon.InstallDirMode); err != nil {
+		return err
+	}
+	return filepath.WalkDir(srcDir, func(fullPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(srcDir, fullPath)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+		if relPath == ".jfrog" || slices.Contains(excluded, relPath) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		destPath := filepath.Join(dstDir, filepath.FromSlash(relPath))
+		if err := os.MkdirAll(filepath.Dir(destPath), agentcommon.InstallDirMode); err != nil {
+			return err
+		}
+		return agentcommon.CopyFile(fullPath, destPath)
+	})
+}
+
+// PrintConversionReport prints the components of a converted plugin as a table, or the report as JSON.
+func PrintConversionReport(report ConversionReport, format string) error {
+	if strings.EqualFold(format, "json") {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal conversion report: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	from := report.From
+	if from == "" {
+		from = "the shared plugin.json layout"
+	}
+	log.Info(fmt.Sprintf("Plugin '%s' converted from %s to %s (%s):", report.Name, from, report.To, report.Manifest))
+	if err := coreutils.PrintTable(report.Items, "Components", "No components found", false); err != nil {
+		return err
+	}
+	if dropped := len(report.Dropped()); dropped > 0 {
+		log.Warn(fmt.Sprintf("%d component(s) could not be translated to %s; see the table above.", dropped, report.To))
+	}
+	return nil
+}
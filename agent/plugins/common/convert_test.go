@@ -0,0 +1,121 @@
+package common
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+)
+
+func writeConvertTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for relPath, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(relPath))
+		if err := os.MkdirAll(filepath.Dir(path), agentcommon.DefaultDirMode); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), agentcommon.PrivateFileMode); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	return dir
+}
+
+func TestConvertPlugin(t *testing.T) {
+	src := writeConvertTree(t, map[string]string{
+		".claude-plugin/plugin.json": `{"name": "demo", "version": "1.0.0", "commands": "./commands", "lspServers": {"go": {"command": "gopls"}}}`,
+		"commands/review.md":         "# Review",
+		"skills/web/SKILL.md":        "# Web",
+		"hooks/hooks.json":           "{}",
+		".jfrog/plugin-info.json":    "{}",
+	})
+	dst := filepath.Join(t.TempDir(), "demo-codex")
+
+	report, err := ConvertPlugin(src, dst, "codex")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Name != "demo" || report.From != "claude" || report.Manifest != ".codex-plugin/plugin.json" {
+		t.Fatalf("unexpected report identity %+v", report)
+	}
+	want := []struct{ component, status string }{
+		{"commands", ConvertStatusDropped},
+		{"skills", ConvertStatusConverted},
+		{"hooks", ConvertStatusDropped},
+		{"lspServers", ConvertStatusDropped},
+	}
+	if len(report.Items) != len(want) {
+		t.Fatalf("expected %d items, got %+v", len(want), report.Items)
+	}
+	for i, item := range report.Items {
+		if item.Component != want[i].component || item.Status != want[i].status {
+			t.Errorf("item %d: expected %s %s, got %+v", i, want[i].component, want[i].status, item)
+		}
+	}
+	if len(report.Dropped()) != 3 {
+		t.Errorf("expected 3 dropped components, got %+v", report.Dropped())
+	}
+
+	data, err := os.ReadFile(filepath.Join(dst, ".codex-plugin", "plugin.json"))
+	if err != nil {
+		t.Fatalf("read converted manifest: %v", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("parse converted manifest: %v", err)
+	}
+	if fields["name"] != "demo" || fields["commands"] != nil || fields["lspServers"] != nil {
+		t.Errorf("unexpected converted manifest %s", data)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "skills", "web", "SKILL.md")); err != nil {
+		t.Errorf("expected skills to be copied: %v", err)
+	}
+	for _, relPath := range []string{".claude-plugin", "commands", "hooks", ".jfrog"} {
+		if _, err := os.Stat(filepath.Join(dst, relPath)); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be left out, got %v", relPath, err)
+		}
+	}
+}
+
+func TestConvertPlugin_UnknownHarness(t *testing.T) {
+	src := writeConvertTree(t, map[string]string{"plugin.json": `{"name": "demo", "version": "1.0.0"}`})
+	if _, err := ConvertPlugin(src, t.TempDir(), "windsurf"); err == nil {
+		t.Fatal("expected an error for a harness without a plugin layout")
+	}
+}
+
+func TestNeedsConversion(t *testing.T) {
+	claudePlugin := writeConvertTree(t, map[string]string{".claude-plugin/plugin.json": `{"name": "demo", "version": "1.0.0"}`})
+	bothPlugin := writeConvertTree(t, map[string]string{
+		".claude-plugin/plugin.json": `{"name": "demo", "version": "1.0.0"}`,
+		".cursor-plugin/plugin.json": `{"name": "demo", "version": "1.0.0"}`,
+	})
+	sharedPlugin := writeConvertTree(t, map[string]string{"plugin.json": `{"name": "demo", "version": "1.0.0"}`})
+
+	tests := []struct {
+		name string
+		dir  string
+		to   string
+		want bool
+	}{
+		{name: "same harness", dir: claudePlugin, to: "claude", want: false},
+		{name: "other harness", dir: claudePlugin, to: "cursor", want: true},
+		{name: "ships target manifest", dir: bothPlugin, to: "cursor", want: false},
+		{name: "shared manifest", dir: sharedPlugin, to: "codex", want: false},
+		{name: "unknown harness", dir: claudePlugin, to: "windsurf", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NeedsConversion(tt.dir, tt.to)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,335 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// skillHarnessAliases maps plugin harness names to the skills harness of the same tool where the names differ.
+var skillHarnessAliases = map[string]string{
+	"claude": "claude-code",
+}
+
+// SkillHarnessFor returns the skills harness that receives skill dependencies of a plugin installed for pluginHarness.
+func SkillHarnessFor(pluginHarness string) string {
+	if alias, ok := skillHarnessAliases[pluginHarness]; ok {
+		return alias
+	}
+	return pluginHarness
+}
+
+// DependencySource looks up versions and declared dependencies while the dependency graph is walked.
+type DependencySource interface {
+	// ResolveVersion picks the version to install for a dependency request.
+	ResolveVersion(dependency agentcommon.PackageDependency) (string, error)
+	// Dependencies returns the dependencies declared by one version of a package.
+	Dependencies(kind, slug, version string) ([]agentcommon.PackageDependency, error)
+}
+
+// ResolvedDependency is one package of the transitive dependency set.
+type ResolvedDependency struct {
+	Kind    string
+	Slug    string
+	Version string
+	// RequiredBy lists the packages that declared this dependency, e.g. "plugin 'review' 1.0.0 (^1.2)".
+	RequiredBy []string
+}
+
+type dependencyKey struct {
+	kind string
+	slug string
+}
+
+func (k dependencyKey) String() string {
+	return fmt.Sprintf("%s '%s'", k.kind, k.slug)
+}
+
+type dependencyResolver struct {
+	source   DependencySource
+	resolved map[dependencyKey]*ResolvedDependency
+	// narrowed holds, per package, the intersection of the requests its dependents made in the previous walk.
+	narrowed map[dependencyKey]string
+	// requests collects the version request of every dependent reached in this walk, per package.
+	requests map[dependencyKey][]string
+	// conflict is the first dependent whose request the already resolved version did not satisfy.
+	conflict error
+	// stack is the current path from the root package, used for cycle detection.
+	stack []dependencyKey
+	order []dependencyKey
+}
+
+// ResolveDependencies walks the dependencies of a root package depth-first and returns the transitive set in
+// install order (every package after its own dependencies), excluding the root. When dependents request different
+// ranges of the same slug, the walk is repeated with the version picked from the intersection of their ranges.
+// The ranges are rebuilt from the dependents of the latest walk only, so a dependent that was replaced by another
+// version no longer constrains the packages it required. It fails on a dependency cycle and when no version
+// satisfies every dependent.
+func ResolveDependencies(rootKind, rootSlug, rootVersion string, direct []agentcommon.PackageDependency, source DependencySource) ([]ResolvedDependency, error) {
+	narrowed := map[dependencyKey]string{}
+	attempted := map[string]bool{narrowedFingerprint(narrowed): true}
+	for {
+		resolver := &dependencyResolver{
+			source:   source,
+			resolved: map[dependencyKey]*ResolvedDependency{},
+			narrowed: narrowed,
+			requests: map[dependencyKey][]string{},
+		}
+		if err := resolver.visit(dependencyKey{kind: rootKind, slug: rootSlug}, rootVersion, direct); err != nil {
+			return nil, err
+		}
+		if resolver.conflict == nil {
+			ordered := make([]ResolvedDependency, 0, len(resolver.order))
+			for _, key := range resolver.order {
+				ordered = append(ordered, *resolver.resolved[key])
+			}
+			return ordered, nil
+		}
+		next, ok := resolver.nextNarrowed()
+		if !ok || attempted[narrowedFingerprint(next)] {
+			// No version satisfies every dependent, or the walk would repeat an earlier attempt.
+			return nil, resolver.conflict
+		}
+		attempted[narrowedFingerprint(next)] = true
+		narrowed = next
+	}
+}
+
+func (r *dependencyResolver) visit(key dependencyKey, version string, dependencies []agentcommon.PackageDependency) error {
+	r.stack = append(r.stack, key)
+	defer func() { r.stack = r.stack[:len(r.stack)-1] }()
+
+	for _, dependency := range dependencies {
+		depKey := dependencyKey{kind: dependency.Kind, slug: dependency.Slug}
+		requiredBy := fmt.Sprintf("%s %s", key, version)
+		if dependency.Version != "" {
+			requiredBy += " (" + dependency.Version + ")"
+		}
+		if r.onStack(depKey) {
+			return fmt.Errorf("dependency cycle: %s", r.cyclePath(depKey))
+		}
+		r.requests[depKey] = append(r.requests[depKey], dependency.Version)
+		if existing, found := r.resolved[depKey]; found {
+			if !agentcommon.VersionMatchesRequest(existing.Version, dependency.Version) && r.conflict == nil {
+				r.conflict = fmt.Errorf("conflicting versions of %s: %s needs %s, but %s already resolved it to %s; declare versions that every dependent accepts",
+					depKey, requiredBy, dependency.Version, strings.Join(existing.RequiredBy, ", "), existing.Version)
+			}
+			existing.RequiredBy = append(existing.RequiredBy, requiredBy)
+			continue
+		}
+
+		request := dependency.Version
+		if narrowed, found := r.narrowed[depKey]; found {
+			// A range narrowed for dependents of the previous walk that no longer fits is left out; the
+			// requests of this walk decide the next attempt.
+			if intersection, err := agentcommon.IntersectVersionRequests(narrowed, request); err == nil {
+				request = intersection
+			}
+		}
+		depVersion, err := r.source.ResolveVersion(agentcommon.PackageDependency{Kind: dependency.Kind, Slug: dependency.Slug, Version: request})
+		if err != nil {
+			return fmt.Errorf("resolve %s required by %s: %w", depKey, requiredBy, err)
+		}
+		transitive, err := r.source.Dependencies(dependency.Kind, dependency.Slug, depVersion)
+		if err != nil {
+			return fmt.Errorf("read dependencies of %s %s: %w", depKey, depVersion, err)
+		}
+		r.resolved[depKey] = &ResolvedDependency{Kind: dependency.Kind, Slug: dependency.Slug, Version: depVersion, RequiredBy: []string{requiredBy}}
+		if err := r.visit(depKey, depVersion, transitive); err != nil {
+			return err
+		}
+		r.order = append(r.order, depKey)
+	}
+	return nil
+}
+
+// nextNarrowed intersects the requests of every package required by more than one dependent in this walk.
+// It reports false when some package has no version that satisfies all of its dependents.
+func (r *dependencyResolver) nextNarrowed() (map[dependencyKey]string, bool) {
+	next := map[dependencyKey]string{}
+	for key, requests := range r.requests {
+		if len(requests) < 2 {
+			continue
+		}
+		combined := requests[0]
+		for _, request := range requests[1:] {
+			var err error
+			if combined, err = agentcommon.IntersectVersionRequests(combined, request); err != nil {
+				return nil, false
+			}
+		}
+		if _, err := r.source.ResolveVersion(agentcommon.PackageDependency{Kind: key.kind, Slug: key.slug, Version: combined}); err != nil {
+			return nil, false
+		}
+		next[key] = combined
+	}
+	return next, true
+}
+
+// narrowedFingerprint renders narrowed ranges in a stable order so repeated attempts can be detected.
+func narrowedFingerprint(narrowed map[dependencyKey]string) string {
+	entries := make([]string, 0, len(narrowed))
+	for key, request := range narrowed {
+		entries = append(entries, key.String()+"="+request)
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ";")
+}
+
+func (r *dependencyResolver) onStack(key dependencyKey) bool {
+	for _, entry := range r.stack {
+		if entry == key {
+			return true
+		}
+	}
+	return false
+}
+
+// cyclePath renders the stack from the first occurrence of key back to key: a -> b -> a.
+func (r *dependencyResolver) cyclePath(key dependencyKey) string {
+	var parts []string
+	for i := len(r.stack) - 1; i >= 0; i-- {
+		parts = append([]string{r.stack[i].String()}, parts...)
+		if r.stack[i] == key {
+			break
+		}
+	}
+	return strings.Join(append(parts, key.String()), " -> ")
+}
+
+// DependencyPackage is the part of a skills or plugins install command used to install a dependency.
+type DependencyPackage interface {
+	FetchAndExtractTo(tmpDir string) (string, error)
+	CopyExtractedToTargets(unzipDir string, targets []AgentTarget) []agentcommon.SummaryRow
+	RecordLockfile(results []agentcommon.SummaryRow) error
+}
+
+// DependencyKind supplies version resolution, install commands, and targets for one package kind.
+type DependencyKind struct {
+	ResolveVersion func(slug, requested string) (string, error)
+	NewPackage     func(slug, version string) DependencyPackage
+	// ReadDependencies reads the dependencies declared by an extracted package.
+	ReadDependencies func(unzipDir string) ([]agentcommon.PackageDependency, error)
+	// Targets returns where the dependency is installed, matching the harnesses of the root package.
+	Targets func(slug string) ([]AgentTarget, error)
+}
+
+// DependencyInstaller resolves and installs the transitive dependencies of a package.
+// Each dependency is downloaded once: while the graph is resolved its metadata is read
+// from the extracted archive, which is then copied to the targets by Install.
+type DependencyInstaller struct {
+	kinds  map[string]DependencyKind
+	tmpDir string
+	// policy is the install policy of the root package; dependencies are installed only into the harnesses it allows.
+	policy  *agentcommon.InstallPolicy
+	fetched map[fetchedKey]fetchedDependency
+}
+
+// fetchedKey identifies one downloaded version; a restarted walk may fetch several versions of a package.
+type fetchedKey struct {
+	dependencyKey
+	version string
+}
+
+type fetchedDependency struct {
+	pkg      DependencyPackage
+	unzipDir string
+}
+
+// NewDependencyInstaller creates an installer that extracts dependencies under tmpDir and installs them into the
+// targets policy allows. A nil policy allows every target.
+func NewDependencyInstaller(tmpDir string, policy *agentcommon.InstallPolicy, kinds map[string]DependencyKind) *DependencyInstaller {
+	return &DependencyInstaller{kinds: kinds, tmpDir: tmpDir, policy: policy, fetched: map[fetchedKey]fetchedDependency{}}
+}
+
+func (di *DependencyInstaller) kind(kind string) (DependencyKind, error) {
+	dependencyKind, ok := di.kinds[kind]
+	if !ok {
+		return DependencyKind{}, fmt.Errorf("%s dependencies are not supported here", kind)
+	}
+	return dependencyKind, nil
+}
+
+// ResolveVersion implements DependencySource.
+func (di *DependencyInstaller) ResolveVersion(dependency agentcommon.PackageDependency) (string, error) {
+	dependencyKind, err := di.kind(dependency.Kind)
+	if err != nil {
+		return "", err
+	}
+	return dependencyKind.ResolveVersion(dependency.Slug, dependency.Version)
+}
+
+// Dependencies implements DependencySource by downloading and extracting the package.
+func (di *DependencyInstaller) Dependencies(kind, slug, version string) ([]agentcommon.PackageDependency, error) {
+	dependencyKind, err := di.kind(kind)
+	if err != nil {
+		return nil, err
+	}
+	key := fetchedKey{dependencyKey: dependencyKey{kind: kind, slug: slug}, version: version}
+	if fetched, found := di.fetched[key]; found {
+		// The walk restarted after a version request was narrowed; reuse the archive.
+		return dependencyKind.ReadDependencies(fetched.unzipDir)
+	}
+	extractDir := filepath.Join(di.tmpDir, kind+"-"+slug)
+	for fetched := range di.fetched {
+		if fetched.dependencyKey == key.dependencyKey {
+			extractDir += "-" + version
+			break
+		}
+	}
+	if err := os.MkdirAll(extractDir, agentcommon.InstallDirMode); err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	pkg := dependencyKind.NewPackage(slug, version)
+	unzipDir, err := pkg.FetchAndExtractTo(extractDir)
+	if err != nil {
+		return nil, err
+	}
+	di.fetched[key] = fetchedDependency{pkg: pkg, unzipDir: unzipDir}
+	return dependencyKind.ReadDependencies(unzipDir)
+}
+
+// Resolve walks the dependency graph of the root package; see ResolveDependencies.
+func (di *DependencyInstaller) Resolve(rootKind, rootSlug, rootVersion string, direct []agentcommon.PackageDependency) ([]ResolvedDependency, error) {
+	return ResolveDependencies(rootKind, rootSlug, rootVersion, direct, di)
+}
+
+// Install copies resolved dependencies to the targets the install policy allows and records them in the lockfile.
+// Targets the policy blocks become failed rows. Rows carry the dependency in their detail so they can share the
+// root package's install summary.
+func (di *DependencyInstaller) Install(resolved []ResolvedDependency) []agentcommon.SummaryRow {
+	var rows []agentcommon.SummaryRow
+	for _, dependency := range resolved {
+		fetched := di.fetched[fetchedKey{dependencyKey: dependencyKey{kind: dependency.Kind, slug: dependency.Slug}, version: dependency.Version}]
+		targets, err := di.kinds[dependency.Kind].Targets(dependency.Slug)
+		if err != nil {
+			log.Warn(fmt.Sprintf("Skipping %s '%s': %s", dependency.Kind, dependency.Slug, err.Error()))
+			rows = append(rows, agentcommon.SummaryRow{Status: agentcommon.SummaryStatusFailed, Detail: dependencyDetail(dependency, err.Error())})
+			continue
+		}
+		targets, policyRows := di.policy.AllowedTargets(targets)
+		var results []agentcommon.SummaryRow
+		if len(targets) > 0 {
+			log.Info(fmt.Sprintf("Installing dependency %s '%s' version '%s' (required by %s)",
+				dependency.Kind, dependency.Slug, dependency.Version, strings.Join(dependency.RequiredBy, ", ")))
+			results = fetched.pkg.CopyExtractedToTargets(fetched.unzipDir, targets)
+			if err := fetched.pkg.RecordLockfile(results); err != nil {
+				log.Warn(fmt.Sprintf("Dependency %s '%s' was installed but the lockfile was not updated: %s", dependency.Kind, dependency.Slug, err.Error()))
+			}
+		}
+		for _, row := range append(policyRows, results...) {
+			row.Detail = dependencyDetail(dependency, row.Detail)
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+func dependencyDetail(dependency ResolvedDependency, detail string) string {
+	return fmt.Sprintf("dependency %s '%s' %s: %s", dependency.Kind, dependency.Slug, dependency.Version, detail)
+}
@@ -0,0 +1,251 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/common/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDependencySource serves versions and dependency lists from memory.
+type fakeDependencySource struct {
+	versions     map[string][]string
+	dependencies map[string][]agentcommon.PackageDependency
+}
+
+func (f fakeDependencySource) ResolveVersion(dependency agentcommon.PackageDependency) (string, error) {
+	return agentcommon.SelectPackageVersion(agentcommon.SelectPackageVersionOpts{
+		Available: f.versions[dependency.Kind+"/"+dependency.Slug],
+		Requested: dependency.Version,
+		RepoKey:   "repo",
+		Quiet:     true,
+	})
+}
+
+func (f fakeDependencySource) Dependencies(kind, slug, version string) ([]agentcommon.PackageDependency, error) {
+	return f.dependencies[kind+"/"+slug+"@"+version], nil
+}
+
+func skillDep(slug, version string) agentcommon.PackageDependency {
+	return agentcommon.PackageDependency{Kind: agentcommon.LockKindSkill, Slug: slug, Version: version}
+}
+
+func pluginDep(slug, version string) agentcommon.PackageDependency {
+	return agentcommon.PackageDependency{Kind: agentcommon.LockKindPlugin, Slug: slug, Version: version}
+}
+
+func TestResolveDependencies_TransitiveInstallOrder(t *testing.T) {
+	source := fakeDependencySource{
+		versions: map[string][]string{
+			"plugin/lint":      {"2.0.0", "2.1.0"},
+			"skill/web-search": {"1.2.0", "1.4.0", "2.0.0"},
+			"skill/fetch":      {"1.0.0"},
+		},
+		dependencies: map[string][]agentcommon.PackageDependency{
+			"plugin/lint@2.1.0":      {skillDep("web-search", "~1.4.0")},
+			"skill/web-search@1.4.0": {skillDep("fetch", "")},
+		},
+	}
+	resolved, err := ResolveDependencies(agentcommon.LockKindPlugin, "review", "1.0.0",
+		[]agentcommon.PackageDependency{pluginDep("lint", "^2"), skillDep("web-search", "^1.2")}, source)
+	require.NoError(t, err)
+
+	var order []string
+	for _, dependency := range resolved {
+		order = append(order, dependency.Kind+"/"+dependency.Slug+"@"+dependency.Version)
+	}
+	assert.Equal(t, []string{"skill/fetch@1.0.0", "skill/web-search@1.4.0", "plugin/lint@2.1.0"}, order)
+	assert.Equal(t, []string{"plugin 'lint' 2.1.0 (~1.4.0)", "plugin 'review' 1.0.0 (^1.2)"}, resolved[1].RequiredBy)
+}
+
+func TestResolveDependencies_Conflict(t *testing.T) {
+	source := fakeDependencySource{
+		versions: map[string][]string{
+			"plugin/lint":      {"2.0.0"},
+			"skill/web-search": {"1.4.0", "2.0.0"},
+		},
+		dependencies: map[string][]agentcommon.PackageDependency{
+			"plugin/lint@2.0.0": {skillDep("web-search", "^2")},
+		},
+	}
+	_, err := ResolveDependencies(agentcommon.LockKindPlugin, "review", "1.0.0",
+		[]agentcommon.PackageDependency{skillDep("web-search", "~1.4.0"), pluginDep("lint", "")}, source)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "conflicting versions of skill 'web-search'")
+	assert.Contains(t, err.Error(), "plugin 'lint' 2.0.0 (^2) needs ^2")
+	assert.Contains(t, err.Error(), "already resolved it to 1.4.0")
+}
+
+func TestResolveDependencies_IntersectsRanges(t *testing.T) {
+	source := fakeDependencySource{
+		versions: map[string][]string{
+			"plugin/lint":      {"2.0.0"},
+			"skill/web-search": {"1.7.0", "1.9.0"},
+		},
+		dependencies: map[string][]agentcommon.PackageDependency{
+			"plugin/lint@2.0.0": {skillDep("web-search", "<1.8")},
+		},
+	}
+	resolved, err := ResolveDependencies(agentcommon.LockKindPlugin, "review", "1.0.0",
+		[]agentcommon.PackageDependency{skillDep("web-search", "^1"), pluginDep("lint", "")}, source)
+	require.NoError(t, err)
+
+	require.Len(t, resolved, 2)
+	assert.Equal(t, "web-search", resolved[0].Slug)
+	assert.Equal(t, "1.7.0", resolved[0].Version)
+	assert.Equal(t, []string{"plugin 'review' 1.0.0 (^1)", "plugin 'lint' 2.0.0 (<1.8)"}, resolved[0].RequiredBy)
+}
+
+func TestResolveDependencies_RebuildsRangesOfReplacedDependents(t *testing.T) {
+	// lint 2.0.0 narrows web-search to <1.5 before fmt narrows lint to ^1. lint 1.0.0 needs web-search >=2,
+	// which only resolves once the range from the replaced lint 2.0.0 is dropped.
+	source := fakeDependencySource{
+		versions: map[string][]string{
+			"plugin/lint":      {"1.0.0", "2.0.0"},
+			"plugin/fmt":       {"1.0.0"},
+			"skill/web-search": {"1.4.0", "2.0.0"},
+		},
+		dependencies: map[string][]agentcommon.PackageDependency{
+			"plugin/lint@2.0.0": {skillDep("web-search", "<1.5")},
+			"plugin/lint@1.0.0": {skillDep("web-search", ">=2")},
+			"plugin/fmt@1.0.0":  {pluginDep("lint", "^1")},
+		},
+	}
+	resolved, err := ResolveDependencies(agentcommon.LockKindPlugin, "review", "1.0.0",
+		[]agentcommon.PackageDependency{skillDep("web-search", ""), pluginDep("lint", ""), pluginDep("fmt", "^1")}, source)
+	require.NoError(t, err)
+
+	var versions []string
+	for _, dependency := range resolved {
+		versions = append(versions, dependency.Kind+"/"+dependency.Slug+"@"+dependency.Version)
+	}
+	assert.Equal(t, []string{"skill/web-search@2.0.0", "plugin/lint@1.0.0", "plugin/fmt@1.0.0"}, versions)
+}
+
+func TestResolveDependencies_Cycle(t *testing.T) {
+	source := fakeDependencySource{
+		versions: map[string][]string{
+			"skill/a": {"1.0.0"},
+			"skill/b": {"1.0.0"},
+		},
+		dependencies: map[string][]agentcommon.PackageDependency{
+			"skill/a@1.0.0": {skillDep("b", "")},
+			"skill/b@1.0.0": {skillDep("root", "")},
+		},
+	}
+	_, err := ResolveDependencies(agentcommon.LockKindSkill, "root", "1.0.0", []agentcommon.PackageDependency{skillDep("a", "")}, source)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dependency cycle: skill 'root' -> skill 'a' -> skill 'b' -> skill 'root'")
+}
+
+// fakeDependencyPackage writes a marker file instead of downloading a zip.
+type fakeDependencyPackage struct {
+	slug     string
+	recorded *[]string
+}
+
+func (f fakeDependencyPackage) FetchAndExtractTo(tmpDir string) (string, error) {
+	return tmpDir, nil
+}
+
+func (f fakeDependencyPackage) CopyExtractedToTargets(_ string, targets []AgentTarget) []agentcommon.SummaryRow {
+	var rows []agentcommon.SummaryRow
+	for _, target := range targets {
+		rows = append(rows, agentcommon.SummaryRow{Agent: target.Agent.Name, Path: target.DestinationDir, Status: agentcommon.SummaryStatusOK, Detail: "installed"})
+	}
+	return rows
+}
+
+func (f fakeDependencyPackage) RecordLockfile([]agentcommon.SummaryRow) error {
+	*f.recorded = append(*f.recorded, f.slug)
+	return nil
+}
+
+func TestDependencyInstaller_FetchesOnceAndInstallsInOrder(t *testing.T) {
+	tmpDir := t.TempDir()
+	var fetched, recorded []string
+	installer := NewDependencyInstaller(tmpDir, nil, map[string]DependencyKind{
+		agentcommon.LockKindSkill: {
+			ResolveVersion: func(slug, requested string) (string, error) { return "1.0.0", nil },
+			NewPackage: func(slug, version string) DependencyPackage {
+				fetched = append(fetched, slug+"@"+version)
+				return fakeDependencyPackage{slug: slug, recorded: &recorded}
+			},
+			ReadDependencies: func(unzipDir string) ([]agentcommon.PackageDependency, error) {
+				if filepath.Base(unzipDir) == "skill-a" {
+					return []agentcommon.PackageDependency{skillDep("b", "")}, nil
+				}
+				return nil, nil
+			},
+			Targets: func(slug string) ([]AgentTarget, error) {
+				return []AgentTarget{{Agent: AgentSpec{Name: "claude-code"}, DestinationDir: filepath.Join(tmpDir, "skills", slug)}}, nil
+			},
+		},
+	})
+
+	resolved, err := installer.Resolve(agentcommon.LockKindPlugin, "review", "1.0.0", []agentcommon.PackageDependency{skillDep("a", ""), skillDep("b", "")})
+	require.NoError(t, err)
+	rows := installer.Install(resolved)
+
+	assert.Equal(t, []string{"a@1.0.0", "b@1.0.0"}, fetched)
+	assert.Equal(t, []string{"b", "a"}, recorded)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "dependency skill 'b' 1.0.0: installed", rows[0].Detail)
+	assert.Equal(t, "claude-code", rows[1].Agent)
+	_, statErr := os.Stat(filepath.Join(tmpDir, "skill-a"))
+	assert.NoError(t, statErr)
+}
+
+func TestDependencyInstaller_FiltersTargetsByPolicy(t *testing.T) {
+	home := testutil.WithJfrogHome(t)
+	testutil.WriteAgentConfig(t, home, `{"install-policy": {"allowHarnesses": ["cursor"]}}`)
+	policy, err := agentcommon.LoadInstallPolicy(nil)
+	require.NoError(t, err)
+
+	tmpDir := t.TempDir()
+	var recorded []string
+	installer := NewDependencyInstaller(tmpDir, policy, map[string]DependencyKind{
+		agentcommon.LockKindSkill: {
+			ResolveVersion: func(slug, requested string) (string, error) { return "1.0.0", nil },
+			NewPackage: func(slug, version string) DependencyPackage {
+				return fakeDependencyPackage{slug: slug, recorded: &recorded}
+			},
+			ReadDependencies: func(string) ([]agentcommon.PackageDependency, error) { return nil, nil },
+			Targets: func(slug string) ([]AgentTarget, error) {
+				return []AgentTarget{
+					{Agent: AgentSpec{Name: "claude-code"}, DestinationDir: filepath.Join(tmpDir, "claude", slug)},
+					{Agent: AgentSpec{Name: "cursor"}, DestinationDir: filepath.Join(tmpDir, "cursor", slug)},
+				}, nil
+			},
+		},
+	})
+
+	resolved, err := installer.Resolve(agentcommon.LockKindPlugin, "review", "1.0.0", []agentcommon.PackageDependency{skillDep("web", "")})
+	require.NoError(t, err)
+	rows := installer.Install(resolved)
+
+	require.Len(t, rows, 2)
+	assert.Equal(t, "claude-code", rows[0].Agent)
+	assert.Equal(t, agentcommon.SummaryStatusFailed, rows[0].Status)
+	assert.Contains(t, rows[0].Detail, "dependency skill 'web' 1.0.0: blocked by install policy")
+	assert.Contains(t, rows[0].Detail, "harness 'claude-code' is not in the allowed list")
+	assert.Equal(t, "cursor", rows[1].Agent)
+	assert.Equal(t, agentcommon.SummaryStatusOK, rows[1].Status)
+	assert.Equal(t, []string{"web"}, recorded)
+}
+
+func TestDependencyInstaller_UnsupportedKind(t *testing.T) {
+	installer := NewDependencyInstaller(t.TempDir(), nil, map[string]DependencyKind{})
+	_, err := installer.Resolve(agentcommon.LockKindSkill, "root", "1.0.0", []agentcommon.PackageDependency{pluginDep("lint", "")})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "plugin dependencies are not supported")
+}
+
+func TestSkillHarnessFor(t *testing.T) {
+	assert.Equal(t, "claude-code", SkillHarnessFor("claude"))
+	assert.Equal(t, "cursor", SkillHarnessFor("cursor"))
+}
@@ -0,0 +1,252 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Drift statuses reported by MarketplaceDrift.
+const (
+	MarketplaceDriftOK       = "ok"
+	MarketplaceDriftMissing  = "missing"  // published in the repository, not listed in the file
+	MarketplaceDriftOutdated = "outdated" // listed with another version than the latest in the repository
+	MarketplaceDriftChanged  = "changed"  // listed with the latest version, but its source URL (e.g. a relative one) or SHA differ
+	MarketplaceDriftRemoved  = "removed"  // listed in the file, no longer published in the repository
+)
+
+// defaultMarketplaceSourceKind is the "source" kind of generated entries for harnesses not in marketplaceSourceKinds.
+const defaultMarketplaceSourceKind = "zip"
+
+// marketplaceSourceKinds is the "source" kind each harness expects for a plugin zip.
+var marketplaceSourceKinds = map[string]string{
+	"claude": "url",
+}
+
+// MarketplacePlugin is the latest published version of one plugin, as a generated marketplace lists it.
+type MarketplacePlugin struct {
+	Name    string
+	Version string
+	// URL is the absolute URL of the version's zip, so harnesses reading the marketplace can fetch it.
+	URL string
+	// SHA is the SHA-256 of the zip.
+	SHA string
+}
+
+// MarketplaceDriftRow compares one plugin in <harness>-marketplace.json with the repository.
+type MarketplaceDriftRow struct {
+	Plugin      string `json:"plugin" col-name:"Plugin"`
+	FileVersion string `json:"fileVersion" col-name:"Marketplace"`
+	RepoVersion string `json:"repositoryVersion" col-name:"Repository"`
+	Status      string `json:"status" col-name:"Status"`
+}
+
+// ListMarketplacePlugins returns the latest version of every plugin in repoKey with the checksum of its zip,
+// sorted by name. Plugins without a published version are skipped.
+func ListMarketplacePlugins(serverDetails *config.ServerDetails, repoKey string) ([]MarketplacePlugin, error) {
+	items, err := ListPlugins(serverDetails, repoKey, 0)
+	if err != nil {
+		return nil, err
+	}
+	serviceManager, err := utils.CreateServiceManager(serverDetails, 3, 0, false)
+	if err != nil {
+		return nil, err
+	}
+	plugins := make([]MarketplacePlugin, 0, len(items))
+	for _, item := range items {
+		if item.LatestVersion == "" {
+			continue
+		}
+		zipPath := agentcommon.PackageArtifactPath(item.Slug, item.LatestVersion)
+		info, err := serviceManager.FileInfo(fmt.Sprintf("%s/%s", repoKey, zipPath))
+		if err != nil {
+			log.Warn(fmt.Sprintf("Skipping plugin '%s': could not read %s: %s", item.Slug, zipPath, err.Error()))
+			continue
+		}
+		plugins = append(plugins, MarketplacePlugin{
+			Name:    item.Slug,
+			Version: item.LatestVersion,
+			URL:     marketplaceSourceURL(serverDetails, repoKey, zipPath),
+			SHA:     info.Checksums.Sha256,
+		})
+	}
+	return plugins, nil
+}
+
+// ReadMarketplace downloads <harness>-marketplace.json from the repository root and returns its content.
+// When the file is absent, the error is ErrMarketplaceNotFound (compare with errors.Is).
+func ReadMarketplace(serverDetails *config.ServerDetails, repoKey, harness string) ([]byte, error) {
+	path, cleanup, err := downloadMarketplace(serverDetails, repoKey, harness)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	// #nosec G304 -- path is the marketplace.json we just downloaded into our temp dir.
+	return os.ReadFile(path)
+}
+
+// UploadMarketplace uploads data as <harness>-marketplace.json to the root of repoKey, replacing any existing file.
+func UploadMarketplace(serverDetails *config.ServerDetails, repoKey, harness string, data []byte) error {
+	tmpDir, err := os.MkdirTemp("", "plugin-marketplace-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir for marketplace: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	fileName := MarketplaceFileName(harness)
+	path := filepath.Join(tmpDir, fileName)
+	if err := os.WriteFile(path, data, agentcommon.PrivateFileMode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", fileName, err)
+	}
+	if _, err := agentcommon.UploadPublishArtifact(serverDetails, path, repoKey+"/", false, nil); err != nil {
+		return fmt.Errorf("upload %s failed: %w", fileName, err)
+	}
+	return nil
+}
+
+// GenerateMarketplace returns <harness>-marketplace.json listing plugins. Fields that existing (the current
+// file, or nil) sets beyond name, version and source are kept, so curated descriptions and authors survive
+// regeneration; plugins no longer published are dropped. defaultName names a marketplace that has no name yet.
+func GenerateMarketplace(existing []byte, defaultName, harness string, plugins []MarketplacePlugin) ([]byte, error) {
+	doc := map[string]any{}
+	if len(bytes.TrimSpace(existing)) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(existing))
+		decoder.UseNumber()
+		if err := decoder.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", MarketplaceFileName(harness), err)
+		}
+	}
+	if name, _ := doc["name"].(string); strings.TrimSpace(name) == "" {
+		doc["name"] = defaultName
+	}
+
+	previous := map[string]map[string]any{}
+	if list, ok := doc["plugins"].([]any); ok {
+		for _, item := range list {
+			entry, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			if name, ok := entry["name"].(string); ok {
+				previous[strings.ToLower(strings.TrimSpace(name))] = entry
+			}
+		}
+	}
+
+	sourceKind := marketplaceSourceKind(harness)
+	sorted := sortedMarketplacePlugins(plugins)
+	entries := make([]any, 0, len(sorted))
+	for _, plugin := range sorted {
+		entry := previous[strings.ToLower(plugin.Name)]
+		if entry == nil {
+			entry = map[string]any{}
+		}
+		source, _ := entry["source"].(map[string]any)
+		if source == nil {
+			source = map[string]any{"source": sourceKind}
+		}
+		source["url"] = plugin.URL
+		if plugin.SHA != "" {
+			source["sha"] = plugin.SHA
+		} else {
+			delete(source, "sha")
+		}
+		entry["name"] = plugin.Name
+		entry["version"] = plugin.Version
+		entry["source"] = source
+		entries = append(entries, entry)
+	}
+	doc["plugins"] = entries
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", MarketplaceFileName(harness), err)
+	}
+	return append(data, '\n'), nil
+}
+
+// MarketplaceDrift compares the current <harness>-marketplace.json (nil when absent) with the plugins
+// published in the repository. Rows are sorted by plugin name.
+func MarketplaceDrift(existing []byte, plugins []MarketplacePlugin) ([]MarketplaceDriftRow, error) {
+	var current marketplace
+	if len(bytes.TrimSpace(existing)) > 0 {
+		if err := json.Unmarshal(existing, &current); err != nil {
+			return nil, fmt.Errorf("parse marketplace: %w", err)
+		}
+	}
+
+	rows := make([]MarketplaceDriftRow, 0, len(plugins))
+	published := map[string]bool{}
+	for _, plugin := range plugins {
+		published[strings.ToLower(plugin.Name)] = true
+		row := MarketplaceDriftRow{Plugin: plugin.Name, RepoVersion: plugin.Version}
+		entry, ok := findEntry(&current, plugin.Name)
+		switch {
+		case !ok:
+			row.Status = MarketplaceDriftMissing
+		case strings.TrimSpace(entry.Version) != plugin.Version:
+			row.FileVersion = entry.Version
+			row.Status = MarketplaceDriftOutdated
+		case entry.Source == nil || entry.Source.URL != plugin.URL || (plugin.SHA != "" && entry.Source.SHA != plugin.SHA):
+			row.FileVersion = entry.Version
+			row.Status = MarketplaceDriftChanged
+		default:
+			row.FileVersion = entry.Version
+			row.Status = MarketplaceDriftOK
+		}
+		rows = append(rows, row)
+	}
+	for _, entry := range current.Plugins {
+		if !published[strings.ToLower(strings.TrimSpace(entry.Name))] {
+			rows = append(rows, MarketplaceDriftRow{Plugin: entry.Name, FileVersion: entry.Version, Status: MarketplaceDriftRemoved})
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return strings.ToLower(rows[i].Plugin) < strings.ToLower(rows[j].Plugin)
+	})
+	return rows, nil
+}
+
+// MarketplaceDriftError returns an error naming how many plugins differ from the repository, or nil when none.
+func MarketplaceDriftError(harness, repoKey string, rows []MarketplaceDriftRow) error {
+	drifted := 0
+	for _, row := range rows {
+		if row.Status != MarketplaceDriftOK {
+			drifted++
+		}
+	}
+	if drifted == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d plugin(s) in %s differ from repository '%s'; run 'jf agent plugins marketplace generate --harness %s' to update it",
+		drifted, MarketplaceFileName(harness), repoKey, harness)
+}
+
+// marketplaceSourceURL returns the absolute Artifactory URL of zipPath in repoKey.
+func marketplaceSourceURL(serverDetails *config.ServerDetails, repoKey, zipPath string) string {
+	return strings.TrimSuffix(serverDetails.GetArtifactoryUrl(), "/") + "/" + repoKey + "/" + zipPath
+}
+
+func marketplaceSourceKind(harness string) string {
+	if kind, ok := marketplaceSourceKinds[strings.ToLower(strings.TrimSpace(harness))]; ok {
+		return kind
+	}
+	return defaultMarketplaceSourceKind
+}
+
+func sortedMarketplacePlugins(plugins []MarketplacePlugin) []MarketplacePlugin {
+	sorted := append([]MarketplacePlugin(nil), plugins...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return strings.ToLower(sorted[i].Name) < strings.ToLower(sorted[j].Name)
+	})
+	return sorted
+}
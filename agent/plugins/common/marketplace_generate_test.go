@@ -0,0 +1,134 @@
+package common
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var repositoryPlugins = []MarketplacePlugin{
+	{Name: "dummy-plugin-beta", Version: "1.1.0", URL: "https://example.jfrog.io/artifactory/plugins-local/dummy-plugin-beta/1.1.0/dummy-plugin-beta-1.1.0.zip", SHA: "b110"},
+	{Name: "dummy-plugin-alpha", Version: "1.0.2", URL: "https://example.jfrog.io/artifactory/plugins-local/dummy-plugin-alpha/1.0.2/dummy-plugin-alpha-1.0.2.zip", SHA: "a102"},
+	{Name: "dummy-plugin-epsilon", Version: "0.1.0", URL: "https://example.jfrog.io/artifactory/plugins-local/dummy-plugin-epsilon/0.1.0/dummy-plugin-epsilon-0.1.0.zip", SHA: "e010"},
+}
+
+func TestGenerateMarketplace_New(t *testing.T) {
+	data, err := GenerateMarketplace(nil, "plugins-local", "claude", repositoryPlugins[:1])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"name": "plugins-local",
+		"plugins": [{
+			"name": "dummy-plugin-beta",
+			"version": "1.1.0",
+			"source": {"source": "url", "url": "https://example.jfrog.io/artifactory/plugins-local/dummy-plugin-beta/1.1.0/dummy-plugin-beta-1.1.0.zip", "sha": "b110"}
+		}]
+	}`, string(data))
+
+	data, err = GenerateMarketplace(nil, "plugins-local", "cursor", repositoryPlugins[:1])
+	require.NoError(t, err)
+	parsed := parseMarketplaceBytes(t, data)
+	assert.Equal(t, "zip", parsed.Plugins[0].Source.Source)
+}
+
+func TestGenerateMarketplace_KeepsCuratedFields(t *testing.T) {
+	existing, err := os.ReadFile(filepath.Join("testdata", "claude-marketplace.json"))
+	require.NoError(t, err)
+
+	data, err := GenerateMarketplace(existing, "plugins-local", "claude", repositoryPlugins)
+	require.NoError(t, err)
+
+	var doc struct {
+		Name  string `json:"name"`
+		Owner struct {
+			Name string `json:"name"`
+		} `json:"owner"`
+		Plugins []struct {
+			Name        string       `json:"name"`
+			Version     string       `json:"version"`
+			Description string       `json:"description"`
+			Source      pluginSource `json:"source"`
+		} `json:"plugins"`
+	}
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, "test-marketplace", doc.Name)
+	assert.Equal(t, "test-owner", doc.Owner.Name)
+	require.Len(t, doc.Plugins, 3)
+
+	assert.Equal(t, "dummy-plugin-alpha", doc.Plugins[0].Name)
+	assert.Equal(t, "First dummy plugin for marketplace parser tests.", doc.Plugins[0].Description)
+	assert.Equal(t, "a102", doc.Plugins[0].Source.SHA)
+
+	assert.Equal(t, "dummy-plugin-beta", doc.Plugins[1].Name)
+	assert.Equal(t, "1.1.0", doc.Plugins[1].Version)
+	assert.Equal(t, "Second dummy plugin for marketplace parser tests.", doc.Plugins[1].Description)
+	assert.Equal(t, pluginSource{Source: "url", URL: "https://example.jfrog.io/artifactory/plugins-local/dummy-plugin-beta/1.1.0/dummy-plugin-beta-1.1.0.zip", SHA: "b110"}, doc.Plugins[1].Source)
+
+	assert.Equal(t, "dummy-plugin-epsilon", doc.Plugins[2].Name)
+	assert.Empty(t, doc.Plugins[2].Description)
+}
+
+func TestGenerateMarketplace_InvalidExisting(t *testing.T) {
+	_, err := GenerateMarketplace([]byte("{not json"), "plugins-local", "claude", repositoryPlugins)
+	assert.ErrorContains(t, err, "parse claude-marketplace.json")
+}
+
+func TestMarketplaceDrift(t *testing.T) {
+	existing, err := os.ReadFile(filepath.Join("testdata", "claude-marketplace.json"))
+	require.NoError(t, err)
+	repository := []MarketplacePlugin{
+		{Name: "dummy-plugin-alpha", Version: "1.0.2", URL: "https://example.jfrog.io/artifactory/plugins-local/dummy-plugin-alpha/1.0.2/dummy-plugin-alpha-1.0.2.zip", SHA: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"},
+		{Name: "dummy-plugin-gamma", Version: "2.0.0", URL: "https://example.jfrog.io/artifactory/plugins-local/dummy-plugin-gamma/2.0.0/dummy-plugin-gamma-2.0.0.zip"},
+	}
+
+	rows, err := MarketplaceDrift(existing, repository)
+	require.NoError(t, err)
+	assert.Equal(t, []MarketplaceDriftRow{
+		{Plugin: "dummy-plugin-alpha", FileVersion: "1.0.2", RepoVersion: "1.0.2", Status: MarketplaceDriftOK},
+		{Plugin: "dummy-plugin-beta", FileVersion: "1.0.1", Status: MarketplaceDriftRemoved},
+		{Plugin: "dummy-plugin-gamma", RepoVersion: "2.0.0", Status: MarketplaceDriftMissing},
+	}, rows)
+	assert.EqualError(t, MarketplaceDriftError("claude", "plugins-local", rows),
+		"2 plugin(s) in claude-marketplace.json differ from repository 'plugins-local'; run 'jf agent plugins marketplace generate --harness claude' to update it")
+
+	repository[0].SHA = "cccc"
+	repository = append(repository, MarketplacePlugin{Name: "dummy-plugin-beta", Version: "1.1.0", URL: "https://example.jfrog.io/artifactory/plugins-local/dummy-plugin-beta/1.1.0/dummy-plugin-beta-1.1.0.zip"})
+	rows, err = MarketplaceDrift(existing, repository)
+	require.NoError(t, err)
+	assert.Equal(t, MarketplaceDriftChanged, rows[0].Status)
+	assert.Equal(t, MarketplaceDriftOutdated, rows[1].Status)
+
+	// Entries listing a repository-relative source URL are regenerated with the absolute one.
+	relative := []byte(`{"plugins": [{"name": "dummy-plugin-gamma", "version": "2.0.0", "source": {"source": "url", "url": "dummy-plugin-gamma/2.0.0/dummy-plugin-gamma-2.0.0.zip"}}]}`)
+	rows, err = MarketplaceDrift(relative, repository[1:2])
+	require.NoError(t, err)
+	assert.Equal(t, MarketplaceDriftChanged, rows[0].Status)
+}
+
+func TestMarketplaceSourceURL(t *testing.T) {
+	for _, artifactoryURL := range []string{"https://example.jfrog.io/artifactory/", "https://example.jfrog.io/artifactory"} {
+		serverDetails := &config.ServerDetails{ArtifactoryUrl: artifactoryURL}
+		assert.Equal(t, "https://example.jfrog.io/artifactory/plugins-local/web/1.0.0/web-1.0.0.zip",
+			marketplaceSourceURL(serverDetails, "plugins-local", "web/1.0.0/web-1.0.0.zip"))
+	}
+}
+
+func TestMarketplaceDrift_GeneratedFileIsInSync(t *testing.T) {
+	data, err := GenerateMarketplace(nil, "plugins-local", "cursor", repositoryPlugins)
+	require.NoError(t, err)
+	rows, err := MarketplaceDrift(data, repositoryPlugins)
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.NoError(t, MarketplaceDriftError("cursor", "plugins-local", rows))
+}
+
+func parseMarketplaceBytes(t *testing.T, data []byte) marketplace {
+	t.Helper()
+	var parsed marketplace
+	require.NoError(t, json.Unmarshal(data, &parsed))
+	return parsed
+}
@@ -93,10 +93,45 @@ type PluginMeta struct {
 	Name        string `json:"name"`
 	Version     string `json:"version,omitempty"`
 	Description string `json:"description,omitempty"`
+	// Dependencies declares skills and plugins installed with this plugin; see PackageDependencies.
+	Dependencies json.RawMessage `json:"dependencies,omitempty"`
 	// ManifestVersion is the consensus version from plugin.json files only (before --version).
 	ManifestVersion string `json:"-"`
 }
 
+// pluginDependencies is the "dependencies" object of plugin.json: slug -> version request per kind.
+//
+// Example:
+//
+//	"dependencies": {
+//	  "skills": {"web-search": "^1.2"},
+//	  "plugins": {"lint": "~2.0.0"}
+//	}
+type pluginDependencies struct {
+	Skills  map[string]string `json:"skills,omitempty"`
+	Plugins map[string]string `json:"plugins,omitempty"`
+}
+
+// PackageDependencies parses the "dependencies" object: skills first, then plugins, each sorted by slug.
+func (meta PluginMeta) PackageDependencies() ([]agentcommon.PackageDependency, error) {
+	if len(meta.Dependencies) == 0 {
+		return nil, nil
+	}
+	var declared pluginDependencies
+	if err := json.Unmarshal(meta.Dependencies, &declared); err != nil {
+		return nil, fmt.Errorf("invalid 'dependencies': expected {\"skills\": {...}, \"plugins\": {...}}: %w", err)
+	}
+	skills, err := agentcommon.ParseDependencyMap(agentcommon.LockKindSkill, declared.Skills)
+	if err != nil {
+		return nil, err
+	}
+	plugins, err := agentcommon.ParseDependencyMap(agentcommon.LockKindPlugin, declared.Plugins)
+	if err != nil {
+		return nil, err
+	}
+	return append(skills, plugins...), nil
+}
+
 // findPrimaryPluginManifest returns the first plugin.json found under pluginRoot,
 // searching loadPluginManifestPaths() in order.
 func findPrimaryPluginManifest(pluginRoot string) (relativePath string, meta PluginMeta, err error) {
@@ -177,6 +212,9 @@ func ValidateAndResolvePluginMeta(pluginRoot, versionFlag string) (PluginMeta, e
 	if meta.Name == "" {
 		return PluginMeta{}, fmt.Errorf("%s is missing required 'name' field", relativePath)
 	}
+	if _, err := meta.PackageDependencies(); err != nil {
+		return PluginMeta{}, fmt.Errorf("%s: %w", relativePath, err)
+	}
 
 	manifestVersion := strings.TrimSpace(meta.Version)
 	resolvedVersion := strings.TrimSpace(versionFlag)
@@ -190,6 +228,7 @@ func ValidateAndResolvePluginMeta(pluginRoot, versionFlag string) (PluginMeta, e
 	return PluginMeta{
 		Name:            meta.Name,
 		Version:         resolvedVersion,
+		Dependencies:    meta.Dependencies,
 		ManifestVersion: manifestVersion,
 	}, nil
 }
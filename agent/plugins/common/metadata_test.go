@@ -369,3 +369,49 @@ func TestUpdatePluginManifestVersions_SkipsWhenNoManifestVersion(t *testing.T) {
 		t.Fatalf("expected no version field inserted, got %s", string(data))
 	}
 }
+
+func TestValidateAndResolvePluginMeta_Dependencies(t *testing.T) {
+	dir := t.TempDir()
+	manifest := `{"name": "review", "version": "1.0.0", "dependencies": {
+		"skills": {"web-search": "^1.2", "summarize": "latest"},
+		"plugins": {"lint": "~2.0.0"}
+	}}`
+	if err := os.WriteFile(filepath.Join(dir, "plugin.json"), []byte(manifest), agentcommon.PrivateFileMode); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	meta, err := ValidateAndResolvePluginMeta(dir, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dependencies, err := meta.PackageDependencies()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []agentcommon.PackageDependency{
+		{Kind: agentcommon.LockKindSkill, Slug: "summarize"},
+		{Kind: agentcommon.LockKindSkill, Slug: "web-search", Version: "^1.2"},
+		{Kind: agentcommon.LockKindPlugin, Slug: "lint", Version: "~2.0.0"},
+	}
+	if len(dependencies) != len(expected) {
+		t.Fatalf("expected %d dependencies, got %+v", len(expected), dependencies)
+	}
+	for i := range expected {
+		if dependencies[i] != expected[i] {
+			t.Fatalf("dependency %d: expected %+v, got %+v", i, expected[i], dependencies[i])
+		}
+	}
+}
+
+func TestValidateAndResolvePluginMeta_InvalidDependencies(t *testing.T) {
+	dir := t.TempDir()
+	manifest := `{"name": "review", "dependencies": {"skills": {"web-search": "not a version"}}}`
+	if err := os.WriteFile(filepath.Join(dir, "plugin.json"), []byte(manifest), agentcommon.PrivateFileMode); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	_, err := ValidateAndResolvePluginMeta(dir, "")
+	if err == nil || !strings.Contains(err.Error(), "web-search") {
+		t.Fatalf("expected invalid dependency error, got %v", err)
+	}
+}
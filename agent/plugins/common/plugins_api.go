@@ -5,6 +5,7 @@ import (
 	"sort"
 	"strings"
 
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-client-go/utils/log"
@@ -17,7 +18,7 @@ type PluginListItem struct {
 }
 
 // ListPlugins lists all plugin slugs in repoKey by reading the root folder's children,
-// resolves the latest version for each, sorts by name, and applies limit.
+// resolves the latest version for each that is not deprecated, sorts by name, and applies limit.
 func ListPlugins(serverDetails *config.ServerDetails, repoKey string, limit int) ([]PluginListItem, error) {
 	if serverDetails == nil {
 		return nil, fmt.Errorf("server details are required to list plugins")
@@ -36,6 +37,7 @@ func ListPlugins(serverDetails *config.ServerDetails, repoKey string, limit int)
 		return nil, fmt.Errorf("failed to list plugins in repository '%s': %w", repoKey, err)
 	}
 
+	deprecated := agentcommon.ListDeprecatedVersions(serverDetails, repoKey, "")
 	pluginEntries := make([]PluginListItem, 0, len(info.Children))
 	for _, child := range info.Children {
 		if !child.Folder {
@@ -45,7 +47,7 @@ func ListPlugins(serverDetails *config.ServerDetails, repoKey string, limit int)
 		if slug == "" || strings.HasPrefix(slug, ".") {
 			continue
 		}
-		latest, err := ResolveLatestPluginVersion(serverDetails, repoKey, slug)
+		latest, err := resolveLatestPluginVersion(serverDetails, repoKey, slug, deprecated)
 		if err != nil {
 			log.Warn(fmt.Sprintf("Could not resolve latest version for plugin '%s': %s", slug, err.Error()))
 			latest = ""
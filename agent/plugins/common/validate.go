@@ -0,0 +1,89 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+)
+
+// manifestPathFields are the plugin.json fields that may point at component files or directories in the plugin.
+// Each holds a path or a list of paths; inline object values are not paths and are not checked.
+var manifestPathFields = []string{"commands", "agents", "skills", "hooks", "mcpServers", "lspServers", "outputStyles"}
+
+// ValidatePlugin checks the primary plugin.json and the files of the plugin at pluginRoot against the
+// agentcommon.ValidationSchemaVersion rules. Issues are reported in the returned report; the error is
+// reserved for failures to read the plugin directory.
+func ValidatePlugin(pluginRoot string) (agentcommon.ValidationReport, error) {
+	report := agentcommon.NewValidationReport("plugin", pluginRoot)
+	relativePath, meta, err := findPrimaryPluginManifest(pluginRoot)
+	switch {
+	case errors.Is(err, ErrPluginManifestNotFound):
+		report.AddError(agentcommon.ValidationRuleManifestSyntax, "",
+			fmt.Sprintf("no plugin.json found; add search paths under %q in %s", agentcommon.PluginManifestPathsKey, agentcommon.AgentConfigPathForDisplay()))
+	case err != nil:
+		report.AddError(agentcommon.ValidationRuleManifestSyntax, "", err.Error())
+	default:
+		manifest := filepath.ToSlash(relativePath)
+		report.Manifest = manifest
+		if err := validatePluginMeta(&report, pluginRoot, manifest, meta); err != nil {
+			return report, err
+		}
+	}
+	if err := agentcommon.ValidatePackageFiles(&report, pluginRoot); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+func validatePluginMeta(report *agentcommon.ValidationReport, pluginRoot, manifest string, meta PluginMeta) error {
+	report.Name, report.Version = meta.Name, meta.Version
+	if meta.Name == "" {
+		report.AddError(agentcommon.ValidationRuleManifestName, manifest, "missing required 'name' field")
+	} else if err := agentcommon.ValidateSlug(meta.Name); err != nil {
+		report.AddError(agentcommon.ValidationRuleManifestName, manifest, err.Error())
+	}
+	if meta.Description == "" {
+		report.AddWarning(agentcommon.ValidationRuleManifestDescription, manifest, "missing 'description' field; it is shown in marketplaces and search")
+	}
+	if meta.Version != "" {
+		if err := agentcommon.ValidateSemver(meta.Version); err != nil {
+			report.AddError(agentcommon.ValidationRuleManifestVersion, manifest, err.Error())
+		}
+	}
+	if _, err := meta.PackageDependencies(); err != nil {
+		report.AddError(agentcommon.ValidationRuleManifestDependencies, manifest, err.Error())
+	}
+
+	// #nosec G304 -- path is constructed by joining a user-provided directory with a fixed allowlist.
+	data, err := os.ReadFile(filepath.Join(pluginRoot, manifest))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", manifest, err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", manifest, err)
+	}
+	for _, field := range manifestPathFields {
+		for _, declared := range manifestFieldPaths(fields[field]) {
+			agentcommon.ValidateManifestPath(report, pluginRoot, manifest, field, declared)
+		}
+	}
+	return nil
+}
+
+// manifestFieldPaths returns the paths held by a manifest field that is a string or a list of strings.
+func manifestFieldPaths(raw json.RawMessage) []string {
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return []string{single}
+	}
+	var list []string
+	if err := json.Unmarshal(raw, &list); err == nil {
+		return list
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+)
+
+func TestValidatePlugin(t *testing.T) {
+	dir := t.TempDir()
+	manifest := `{
+		"name": "demo",
+		"version": "2.0",
+		"commands": "./commands",
+		"agents": ["./agents/reviewer.md", "/opt/agents/planner.md"],
+		"dependencies": {"skills": {"Web": "^1.0"}},
+		"mcpServers": {"demo": {"command": "node"}}
+	}`
+	if err := os.MkdirAll(filepath.Join(dir, ".claude-plugin"), agentcommon.DefaultDirMode); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".claude-plugin", "plugin.json"), []byte(manifest), agentcommon.PrivateFileMode); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "commands"), agentcommon.DefaultDirMode); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	report, err := ValidatePlugin(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Manifest != ".claude-plugin/plugin.json" || report.Name != "demo" {
+		t.Fatalf("unexpected report identity %+v", report)
+	}
+	want := []struct{ rule, severity string }{
+		{agentcommon.ValidationRuleManifestDescription, agentcommon.ValidationSeverityWarning},
+		{agentcommon.ValidationRuleManifestVersion, agentcommon.ValidationSeverityError},
+		{agentcommon.ValidationRuleManifestDependencies, agentcommon.ValidationSeverityError},
+		{agentcommon.ValidationRuleBrokenLink, agentcommon.ValidationSeverityError},
+		{agentcommon.ValidationRuleAbsolutePath, agentcommon.ValidationSeverityError},
+	}
+	if len(report.Issues) != len(want) {
+		t.Fatalf("expected %d issues, got %+v", len(want), report.Issues)
+	}
+	for i, issue := range report.Issues {
+		if issue.Rule != want[i].rule || issue.Severity != want[i].severity {
+			t.Errorf("issue %d: expected %s %s, got %+v", i, want[i].severity, want[i].rule, issue)
+		}
+	}
+}
+
+func TestValidatePlugin_NoManifest(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Demo"), agentcommon.PrivateFileMode); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	report, err := ValidatePlugin(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Issues) != 1 || report.Issues[0].Rule != agentcommon.ValidationRuleManifestSyntax {
+		t.Fatalf("expected a single manifest-syntax issue, got %+v", report.Issues)
+	}
+}
@@ -9,9 +9,9 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 )
 
-// listPluginVersions returns the version folders published under <repoKey>/<slug>/ using
+// ListPluginVersions returns the version folders published under <repoKey>/<slug>/ using
 // the generic Artifactory storage API. Folder children that are not directories are skipped.
-func listPluginVersions(serverDetails *config.ServerDetails, repoKey, slug string) ([]string, error) {
+func ListPluginVersions(serverDetails *config.ServerDetails, repoKey, slug string) ([]string, error) {
 	if serverDetails == nil {
 		return nil, fmt.Errorf("server details are required to list plugin versions")
 	}
@@ -43,38 +43,49 @@ func listPluginVersions(serverDetails *config.ServerDetails, repoKey, slug strin
 	return versions, nil
 }
 
-// ResolveLatestPluginVersion returns the greatest semver from listPluginVersions.
+// ResolveLatestPluginVersion returns the greatest semver from ListPluginVersions that is not deprecated.
 func ResolveLatestPluginVersion(serverDetails *config.ServerDetails, repoKey, slug string) (string, error) {
-	versions, err := listPluginVersions(serverDetails, repoKey, slug)
+	return resolveLatestPluginVersion(serverDetails, repoKey, slug, agentcommon.ListDeprecatedVersions(serverDetails, repoKey, slug))
+}
+
+func resolveLatestPluginVersion(serverDetails *config.ServerDetails, repoKey, slug string, deprecated agentcommon.DeprecatedVersions) (string, error) {
+	versions, err := ListPluginVersions(serverDetails, repoKey, slug)
 	if err != nil {
 		return "", fmt.Errorf("failed to list versions for plugin '%s': %w", slug, err)
 	}
 	if len(versions) == 0 {
 		return "", fmt.Errorf("plugin '%s' has no versions in repository '%s'", slug, repoKey)
 	}
-	return agentcommon.LatestVersion(versions)
+	return agentcommon.LatestActiveVersion(slug, versions, deprecated)
 }
 
-// ResolvePluginVersion lists remote versions then applies SelectPackageVersion rules.
+// ResolvePluginVersion lists remote versions then applies SelectPackageVersion rules, skipping deprecated versions
+// unless one is requested explicitly.
 // Used by install and update when --version is set or when resolving latest from Artifactory.
 func ResolvePluginVersion(serverDetails *config.ServerDetails, repoKey, slug, requested string, quiet bool) (string, error) {
 	requested = strings.TrimSpace(requested)
-	if requested != "" && requested != "latest" {
-		if err := agentcommon.ValidateSemver(requested); err != nil {
-			return "", err
-		}
+	if err := agentcommon.ValidateVersionRequest(requested); err != nil {
+		return "", err
 	}
-	versions, err := listPluginVersions(serverDetails, repoKey, slug)
+	versions, err := ListPluginVersions(serverDetails, repoKey, slug)
 	if err != nil {
 		if agentcommon.IsHTTPNotFound(err) {
 			return "", fmt.Errorf("plugin '%s' not found in repository '%s'", slug, repoKey)
 		}
 		return "", fmt.Errorf("failed to list versions: %w", err)
 	}
-	return agentcommon.SelectPackageVersion(agentcommon.SelectPackageVersionOpts{
-		Available: versions,
-		Requested: requested,
-		RepoKey:   repoKey,
-		Quiet:     quiet,
+	deprecated := agentcommon.ListDeprecatedVersions(serverDetails, repoKey, slug)
+	version, err := agentcommon.SelectPackageVersion(agentcommon.SelectPackageVersionOpts{
+		Available:  versions,
+		Requested:  requested,
+		RepoKey:    repoKey,
+		Quiet:      quiet,
+		Slug:       slug,
+		Deprecated: deprecated,
 	})
+	if err != nil {
+		return "", err
+	}
+	agentcommon.WarnIfDeprecated("Plugin", slug, version, deprecated)
+	return version, nil
 }
@@ -1,12 +1,21 @@
 package cli
 
 import (
+	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/commands/bundle"
 	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/commands/delete"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/commands/deprecate"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/commands/diff"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/commands/gc"
 	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/commands/install"
 	skillslist "github.com/jfrog/jfrog-cli-artifactory/agent/skills/commands/list"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/commands/outdated"
 	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/commands/publish"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/commands/rollback"
 	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/commands/search"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/commands/sync"
 	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/commands/update"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/commands/validate"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/commands/verify"
 	"github.com/jfrog/jfrog-cli-artifactory/cliutils/flagkit"
 	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
 )
@@ -55,6 +64,73 @@ func GetSubCommands() []components.Command {
 			Arguments:   getDeleteArguments(),
 			Action:      delete.RunDelete,
 		},
+		{
+			Name:        "deprecate",
+			Flags:       flagkit.GetCommandFlags(flagkit.SkillsDeprecate),
+			Description: "Mark a published skill version as deprecated, or remove the mark with --undo.",
+			Arguments:   getDeprecateArguments(),
+			Action:      deprecate.RunDeprecate,
+		},
+		{
+			Name:        "diff",
+			Flags:       flagkit.GetCommandFlags(flagkit.SkillsDiff),
+			Description: "Show what changed between two versions of a skill, or between the installed copy and a version.",
+			Arguments:   getDiffArguments(),
+			Action:      diff.RunDiff,
+		},
+		{
+			Name:        "sync",
+			Flags:       flagkit.GetCommandFlags(flagkit.SkillsSync),
+			Description: "Install, update, and optionally prune skills to match the project manifest.",
+			Action:      sync.RunSync,
+		},
+		{
+			Name:        "verify",
+			Flags:       flagkit.GetCommandFlags(flagkit.SkillsVerify),
+			Description: "Check installed skills for files modified, added, or removed since install.",
+			Arguments:   getVerifyArguments(),
+			Action:      verify.RunVerify,
+		},
+		{
+			Name:        "export",
+			Flags:       flagkit.GetCommandFlags(flagkit.SkillsExport),
+			Description: "Write skills and their evidence to a bundle archive for air-gapped environments.",
+			Arguments:   getExportArguments(),
+			Action:      bundle.RunExport,
+		},
+		{
+			Name:        "import",
+			Flags:       flagkit.GetCommandFlags(flagkit.SkillsImport),
+			Description: "Install skills from a bundle archive, or publish them to another repository.",
+			Arguments:   getImportArguments(),
+			Action:      bundle.RunImport,
+		},
+		{
+			Name:        "gc",
+			Flags:       flagkit.GetCommandFlags(flagkit.SkillsGC),
+			Description: "Remove skill versions from the local package store that no linked install uses.",
+			Action:      gc.RunGC,
+		},
+		{
+			Name:        "rollback",
+			Flags:       flagkit.GetCommandFlags(flagkit.SkillsRollback),
+			Description: "Restore the skill version installed before the last update.",
+			Arguments:   getRollbackArguments(),
+			Action:      rollback.RunRollback,
+		},
+		{
+			Name:        "outdated",
+			Flags:       flagkit.GetCommandFlags(flagkit.SkillsOutdated),
+			Description: "Report installed skills in every harness with current, wanted, and latest versions, without changing anything.",
+			Action:      outdated.RunOutdated,
+		},
+		{
+			Name:        "validate",
+			Flags:       flagkit.GetCommandFlags(flagkit.SkillsValidate),
+			Description: "Check skills against the publish rules (manifest fields, file sizes, binaries, likely secrets, absolute paths, broken links) without contacting the server.",
+			Arguments:   getValidateArguments(),
+			Action:      validate.RunValidate,
+		},
 	}
 }
 
@@ -80,7 +156,7 @@ func getInstallArguments() []components.Argument {
 	return []components.Argument{
 		{
 			Name:        "slug",
-			Description: "Skill slug to install.",
+			Description: "Skill slug to install. Optional with --frozen, which installs every pinned skill.",
 		},
 	}
 }
@@ -102,3 +178,70 @@ func getDeleteArguments() []components.Argument {
 		},
 	}
 }
+
+func getDeprecateArguments() []components.Argument {
+	return []components.Argument{
+		{
+			Name:        "slug",
+			Description: "Skill slug to deprecate.",
+		},
+	}
+}
+
+func getDiffArguments() []components.Argument {
+	return []components.Argument{
+		{
+			Name:        "slug",
+			Description: "Skill slug to compare.",
+		},
+		{
+			Name:        "versions",
+			Description: "The version to compare from and the version to compare to, or one version with --installed.",
+		},
+	}
+}
+
+func getVerifyArguments() []components.Argument {
+	return []components.Argument{
+		{
+			Name:        "slug",
+			Description: "Skill slug to verify. Optional; verifies every installed skill when omitted.",
+		},
+	}
+}
+
+func getExportArguments() []components.Argument {
+	return []components.Argument{
+		{
+			Name:        "slugs",
+			Description: "Skill slugs to export, each optionally with @<version> or @<range> (default: latest).",
+		},
+	}
+}
+
+func getImportArguments() []components.Argument {
+	return []components.Argument{
+		{
+			Name:        "bundle",
+			Description: "Path to a bundle archive written by export.",
+		},
+	}
+}
+
+func getRollbackArguments() []components.Argument {
+	return []components.Argument{
+		{
+			Name:        "slug",
+			Description: "Skill slug to roll back.",
+		},
+	}
+}
+
+func getValidateArguments() []components.Argument {
+	return []components.Argument{
+		{
+			Name:        "path",
+			Description: "Path to a skill folder containing SKILL.md. Several paths may be given.",
+		},
+	}
+}
@@ -0,0 +1,54 @@
+package bundle
+
+import (
+	"fmt"
+	"strings"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/common"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// RunExport is the CLI action for `jf agent skills export`.
+// It writes the selected skill zips, their SHA-256 values, and their evidence into one bundle archive.
+func RunExport(c *components.Context) error {
+	outputPath := strings.TrimSpace(c.GetStringFlagValue("output"))
+	if c.GetNumberOfArgs() < 1 || outputPath == "" {
+		return fmt.Errorf("usage: jf agent skills export <slug>[@<version>] [<slug>[@<version>]...] --output <file> [--repo <repo>]")
+	}
+	requests := make([]agentcommon.PackageDependency, 0, c.GetNumberOfArgs())
+	for _, arg := range c.Arguments {
+		request, err := agentcommon.ParseDependencySpec(agentcommon.LockKindSkill, arg)
+		if err != nil {
+			return err
+		}
+		requests = append(requests, request)
+	}
+
+	serverDetails, err := agentcommon.GetServerDetails(c)
+	if err != nil {
+		return err
+	}
+	quiet := agentcommon.IsQuiet(c)
+	repoKey, err := agentcommon.ResolveRepo(serverDetails, c.GetStringFlagValue("repo"), quiet, common.RepoOptions())
+	if err != nil {
+		return err
+	}
+
+	packages, err := agentcommon.ExportPackages(agentcommon.ExportOptions{
+		ServerDetails: serverDetails,
+		RepoKey:       repoKey,
+		Kind:          agentcommon.LockKindSkill,
+		Requests:      requests,
+		ResolveVersion: func(slug, requested string) (string, error) {
+			return common.ResolveSkillVersion(serverDetails, repoKey, slug, requested, quiet)
+		},
+		OutputPath: outputPath,
+	})
+	if err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Exported %d skill(s) to %s", len(packages), outputPath))
+	return nil
+}
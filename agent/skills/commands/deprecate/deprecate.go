@@ -0,0 +1,113 @@
+package deprecate
+
+import (
+	"fmt"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/common"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+)
+
+// DeprecateCommand marks a published skill version as deprecated, or removes the mark.
+type DeprecateCommand struct {
+	serverDetails *config.ServerDetails
+	repoKey       string
+	slug          string
+	version       string
+	reason        string
+	replacement   string
+	undo          bool
+}
+
+func NewDeprecateCommand() *DeprecateCommand {
+	return &DeprecateCommand{}
+}
+
+func (dc *DeprecateCommand) SetServerDetails(details *config.ServerDetails) *DeprecateCommand {
+	dc.serverDetails = details
+	return dc
+}
+
+func (dc *DeprecateCommand) SetRepoKey(repoKey string) *DeprecateCommand {
+	dc.repoKey = repoKey
+	return dc
+}
+
+func (dc *DeprecateCommand) SetSlug(slug string) *DeprecateCommand {
+	dc.slug = slug
+	return dc
+}
+
+func (dc *DeprecateCommand) SetVersion(version string) *DeprecateCommand {
+	dc.version = version
+	return dc
+}
+
+func (dc *DeprecateCommand) SetReason(reason string) *DeprecateCommand {
+	dc.reason = reason
+	return dc
+}
+
+// SetReplacement names the published version users should move to.
+func (dc *DeprecateCommand) SetReplacement(replacement string) *DeprecateCommand {
+	dc.replacement = replacement
+	return dc
+}
+
+// SetUndo removes the deprecation instead of setting it.
+func (dc *DeprecateCommand) SetUndo(undo bool) *DeprecateCommand {
+	dc.undo = undo
+	return dc
+}
+
+func (dc *DeprecateCommand) ServerDetails() (*config.ServerDetails, error) {
+	return dc.serverDetails, nil
+}
+
+func (dc *DeprecateCommand) CommandName() string {
+	return "skills_deprecate"
+}
+
+func (dc *DeprecateCommand) Run() error {
+	return agentcommon.RunDeprecation(agentcommon.DeprecationRequest{
+		EntityLabel:   "Skill",
+		ServerDetails: dc.serverDetails,
+		RepoKey:       dc.repoKey,
+		Slug:          dc.slug,
+		Version:       dc.version,
+		Deprecation:   agentcommon.Deprecation{Reason: dc.reason, Replacement: dc.replacement},
+		Undo:          dc.undo,
+	})
+}
+
+// RunDeprecate is the CLI action for `jf agent skills deprecate`.
+// Deprecated versions stay installable when pinned, but latest and range resolution, search and list skip them.
+func RunDeprecate(c *components.Context) error {
+	if c.GetNumberOfArgs() != 1 {
+		return fmt.Errorf("usage: jf agent skills deprecate <slug> --version <version> --reason <text> [--replacement <version>] [--repo <repo>]\n" +
+			"       jf agent skills deprecate <slug> --version <version> --undo [--repo <repo>]")
+	}
+
+	slug := c.GetArgumentAt(0)
+
+	serverDetails, err := agentcommon.GetServerDetails(c)
+	if err != nil {
+		return err
+	}
+
+	repoKey, err := agentcommon.ResolveRepo(serverDetails, c.GetStringFlagValue("repo"), agentcommon.IsQuiet(c), common.RepoOptions())
+	if err != nil {
+		return err
+	}
+
+	return NewDeprecateCommand().
+		SetServerDetails(serverDetails).
+		SetRepoKey(repoKey).
+		SetSlug(slug).
+		SetVersion(c.GetStringFlagValue("version")).
+		SetReason(c.GetStringFlagValue("reason")).
+		SetReplacement(c.GetStringFlagValue("replacement")).
+		SetUndo(c.GetBoolFlagValue("undo")).
+		Run()
+}
@@ -0,0 +1,73 @@
+package diff
+
+import (
+	"fmt"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/common"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+)
+
+// RunDiff is the CLI action for `jf agent skills diff`.
+// It prints what changes between two published versions of a skill, or between the installed skill and a version.
+func RunDiff(c *components.Context) error {
+	installed := c.GetBoolFlagValue("installed")
+	wantArgs := 3
+	if installed {
+		wantArgs = 2
+	}
+	if c.GetNumberOfArgs() != wantArgs {
+		return fmt.Errorf("usage: jf agent skills diff <slug> (<from-version> <to-version> | <version> --installed (--harness <name[,name...]> [--global] [--project-dir <dir>] | --path <dir>)) [--repo <repo>] [--no-cache] [--format <table|json>]")
+	}
+	slug := c.GetArgumentAt(0)
+	if err := agentcommon.ValidateSlug(slug); err != nil {
+		return err
+	}
+
+	request := agentcommon.PackageDiffRequest{
+		Slug:             slug,
+		ArtifactKind:     "skill",
+		EntityLabel:      "Skill",
+		ManifestFileName: common.SkillInfoManifestFile,
+		NoCache:          c.GetBoolFlagValue("no-cache"),
+		Format:           "table",
+	}
+	if c.GetStringFlagValue("format") != "" {
+		request.Format = c.GetStringFlagValue("format")
+	}
+	if installed {
+		flags, err := agentcommon.ValidateInstallFlags(c, common.Agents, agentcommon.SkillsAgentsKey, common.RegistryHelp)
+		if err != nil {
+			return err
+		}
+		if request.Installed, err = agentcommon.ResolveAgentTargets(slug, flags.AbsoluteInstallBaseDir, flags.Specs, flags.ProjectDirAbs, flags.IsGlobal); err != nil {
+			return err
+		}
+	}
+
+	serverDetails, err := agentcommon.GetServerDetails(c)
+	if err != nil {
+		return err
+	}
+	quiet := agentcommon.IsQuiet(c)
+	repoKey, err := agentcommon.ResolveRepo(serverDetails, c.GetStringFlagValue("repo"), quiet, common.RepoOptions())
+	if err != nil {
+		return err
+	}
+	request.ServerDetails, request.RepoKey = serverDetails, repoKey
+
+	versions := make([]string, 0, 2)
+	for idx := 1; idx < c.GetNumberOfArgs(); idx++ {
+		version, err := common.ResolveSkillVersion(serverDetails, repoKey, slug, c.GetArgumentAt(idx), quiet)
+		if err != nil {
+			return err
+		}
+		versions = append(versions, version)
+	}
+	if installed {
+		request.ToVersion = versions[0]
+	} else {
+		request.FromVersion, request.ToVersion = versions[0], versions[1]
+	}
+	return agentcommon.RunPackageDiff(request)
+}
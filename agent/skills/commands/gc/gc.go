@@ -0,0 +1,26 @@
+package gc
+
+import (
+	"fmt"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/common"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+)
+
+// RunGC is the CLI action for `jf agent skills gc`.
+// It removes skill versions from the package store that no --link install references any more.
+func RunGC(c *components.Context) error {
+	if c.GetNumberOfArgs() > 0 {
+		return fmt.Errorf("usage: jf agent skills gc [--dry-run] [--format <table|json>]")
+	}
+	format := "table"
+	if c.GetStringFlagValue("format") != "" {
+		format = c.GetStringFlagValue("format")
+	}
+	rows, err := agentcommon.GCStore(common.SkillInfoManifestFile, c.GetBoolFlagValue("dry-run"))
+	if err != nil {
+		return err
+	}
+	return agentcommon.PrintStoreGCSummary(rows, format)
+}
@@ -0,0 +1,78 @@
+package install
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+	plugincommon "github.com/jfrog/jfrog-cli-artifactory/agent/plugins/common"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/commands/publish"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/common"
+)
+
+// resolveSkillVersion is swappable in tests.
+var resolveSkillVersion = common.ResolveSkillVersion
+
+// installDependencies resolves the skills listed in the extracted SKILL.md and installs them
+// into the same targets. It returns one summary row per dependency target.
+func (ic *InstallCommand) installDependencies(unzipDir, tmpDir string) ([]agentcommon.SummaryRow, error) {
+	direct, err := readSkillDependencies(unzipDir)
+	if err != nil {
+		return nil, fmt.Errorf("skill '%s' version '%s': %w", ic.slug, ic.version, err)
+	}
+	if len(direct) == 0 {
+		return nil, nil
+	}
+	installer := plugincommon.NewDependencyInstaller(filepath.Join(tmpDir, "dependencies"), ic.policy, map[string]plugincommon.DependencyKind{
+		agentcommon.LockKindSkill: {
+			ResolveVersion: func(slug, requested string) (string, error) {
+				return resolveSkillVersion(ic.serverDetails, ic.repoKey, slug, requested, ic.quiet)
+			},
+			NewPackage: func(slug, version string) plugincommon.DependencyPackage {
+				return ic.dependencyCommand(slug, version)
+			},
+			ReadDependencies: readSkillDependencies,
+			Targets: func(slug string) ([]common.AgentTarget, error) {
+				return ic.dependencyCommand(slug, "").resolveAgentTargetDirectories()
+			},
+		},
+	})
+	resolved, err := installer.Resolve(agentcommon.LockKindSkill, ic.slug, ic.version, direct)
+	if err != nil {
+		return nil, fmt.Errorf("skill '%s' version '%s': %w", ic.slug, ic.version, err)
+	}
+	return installer.Install(resolved), nil
+}
+
+func readSkillDependencies(unzipDir string) ([]agentcommon.PackageDependency, error) {
+	meta, err := publish.ParseSkillMeta(unzipDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return meta.Dependencies, nil
+}
+
+// dependencyCommand installs a skill dependency with the root install's repository, scope, harnesses, and policy.
+func (ic *InstallCommand) dependencyCommand(slug, version string) *InstallCommand {
+	return &InstallCommand{
+		serverDetails: ic.serverDetails,
+		repoKey:       ic.repoKey,
+		slug:          slug,
+		version:       version,
+		agents:        ic.agents,
+		scope:         ic.scope,
+		projectDir:    ic.projectDir,
+		installPath:   ic.installPath,
+		format:        ic.format,
+		quiet:         ic.quiet,
+		noCache:       ic.noCache,
+		link:          ic.link,
+		policy:        ic.policy,
+		policyLoader:  ic.policyLoader,
+	}
+}
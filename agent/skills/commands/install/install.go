@@ -9,13 +9,16 @@ import (
 	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
 	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/common"
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	pluginsCommon "github.com/jfrog/jfrog-cli-core/v2/plugins/common"
 	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
-	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-client-go/artifactory/services"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 )
 
+// downloadPackageZip is swappable in tests.
+var downloadPackageZip = (*InstallCommand).downloadZip
+
 // InstallCommand installs a skill for configured agents or legacy --path (update).
 type InstallCommand struct {
 	serverDetails *config.ServerDetails
@@ -33,6 +36,26 @@ type InstallCommand struct {
 	// explicitTargets, when set, overrides resolveAgentTargetDirectories (used by skills update).
 	explicitTargets []common.AgentTarget
 	suppressSummary bool
+	// frozen installs exactly the version pinned in the lockfile and fails on a checksum mismatch.
+	frozen         bool
+	expectedSHA256 string
+	// zipSHA256 is the digest of the downloaded zip, recorded in the lockfile after install.
+	zipSHA256 string
+	// constraint is the version range recorded in the install manifest so update stays within it.
+	constraint string
+	// noDeps skips the skills listed under "dependencies" in SKILL.md.
+	noDeps bool
+	// bundledZip is a zip taken from an export bundle; it replaces version resolution, the download,
+	// and the Artifactory evidence check.
+	bundledZip      string
+	bundledEvidence bool
+	// noCache downloads the zip without reading or writing the local package cache.
+	noCache bool
+	// link installs into the shared package store and links each target to it instead of copying.
+	link bool
+	// policy is the install policy of repoKey, read through policyLoader by loadPolicy.
+	policy       *agentcommon.InstallPolicy
+	policyLoader *agentcommon.InstallPolicyLoader
 }
 
 func NewInstallCommand() *InstallCommand {
@@ -109,6 +132,61 @@ func (ic *InstallCommand) SetSuppressSummary(suppress bool) *InstallCommand {
 	return ic
 }
 
+// SetFrozen installs the version pinned in the lockfile instead of resolving one.
+func (ic *InstallCommand) SetFrozen(frozen bool) *InstallCommand {
+	ic.frozen = frozen
+	return ic
+}
+
+// SetConstraint records the version range the package is installed with. Run sets it
+// automatically when --version is a range; update and sync pass it explicitly.
+func (ic *InstallCommand) SetConstraint(constraint string) *InstallCommand {
+	ic.constraint = constraint
+	return ic
+}
+
+// SetNoDeps installs only the skill itself, without its declared dependencies.
+func (ic *InstallCommand) SetNoDeps(noDeps bool) *InstallCommand {
+	ic.noDeps = noDeps
+	return ic
+}
+
+// SetNoCache always downloads the package zip instead of using the local package cache.
+func (ic *InstallCommand) SetNoCache(noCache bool) *InstallCommand {
+	ic.noCache = noCache
+	return ic
+}
+
+// SetLink extracts the skill once into the shared package store and links each target to it.
+func (ic *InstallCommand) SetLink(link bool) *InstallCommand {
+	ic.link = link
+	return ic
+}
+
+// Link reports whether targets are linked to the shared package store (--link).
+func (ic *InstallCommand) Link() bool {
+	return ic.link
+}
+
+// SetPolicyLoader shares the install policy of a CLI run, so it is read once however many commands the run creates.
+func (ic *InstallCommand) SetPolicyLoader(loader *agentcommon.InstallPolicyLoader) *InstallCommand {
+	ic.policyLoader = loader
+	return ic
+}
+
+// SetBundledZip installs the exact version set with SetVersion from a zip in an extracted export bundle.
+// hasEvidence reports whether the bundled evidence verified against the public keys the import trusts.
+func (ic *InstallCommand) SetBundledZip(zipPath string, hasEvidence bool) *InstallCommand {
+	ic.bundledZip = zipPath
+	ic.bundledEvidence = hasEvidence
+	return ic
+}
+
+// ZipSHA256 returns the SHA-256 of the zip fetched by FetchAndExtractTo.
+func (ic *InstallCommand) ZipSHA256() string {
+	return ic.zipSHA256
+}
+
 func (ic *InstallCommand) ServerDetails() (*config.ServerDetails, error) {
 	return ic.serverDetails, nil
 }
@@ -118,20 +196,78 @@ func (ic *InstallCommand) CommandName() string {
 }
 
 func (ic *InstallCommand) Run() error {
+	fetched, err := ic.fetch()
+	defer fetched.cleanup()
+	if err != nil {
+		if agentcommon.IsPolicyViolation(err) && !ic.suppressSummary {
+			rows := append(fetched.policyRows, agentcommon.FailedSyncRows(fetched.targets, err)...)
+			_ = agentcommon.PrintInstallSummary("Skill", ic.slug, ic.version, rows, ic.format)
+		}
+		return err
+	}
+	return ic.apply(fetched)
+}
+
+// fetchedInstall is a resolved and extracted package waiting to be copied to its targets.
+type fetchedInstall struct {
+	targets  []common.AgentTarget
+	tmpDir   string
+	unzipDir string
+	// policyRows are failed rows for targets whose harness the install policy does not allow.
+	policyRows []agentcommon.SummaryRow
+}
+
+// cleanup removes the temp dir holding the downloaded and extracted package.
+func (f fetchedInstall) cleanup() {
+	if f.tmpDir != "" {
+		// Best-effort cleanup of install temp dir.
+		_ = os.RemoveAll(f.tmpDir)
+	}
+}
+
+// fetch resolves targets and version, then downloads, checks, and extracts the skill. It only touches
+// its own temp dir, so multi-package installs run it on parallel workers.
+func (ic *InstallCommand) fetch() (fetchedInstall, error) {
+	var fetched fetchedInstall
 	if ic.installPath == "" && len(ic.agents) == 0 && len(ic.explicitTargets) == 0 {
-		return fmt.Errorf("--harness is required")
+		return fetched, fmt.Errorf("--harness is required")
 	}
 
 	installTargets, err := ic.resolveAgentTargetDirectories()
 	if err != nil {
-		return err
+		return fetched, err
+	}
+	if len(installTargets) == 0 {
+		return fetched, fmt.Errorf("no install directory was resolved for skill '%s'; check --harness and --path", ic.slug)
 	}
+	fetched.targets = installTargets
 
-	resolvedVersion, err := common.ResolveSkillVersion(ic.serverDetails, ic.repoKey, ic.slug, ic.version, ic.quiet)
-	if err != nil {
-		return err
+	if agentcommon.IsVersionRange(ic.version) {
+		ic.constraint = strings.TrimSpace(ic.version)
+	}
+	switch {
+	case ic.frozen:
+		if err := ic.applyLockedPin(installTargets); err != nil {
+			return fetched, err
+		}
+	case ic.bundledZip != "":
+		// Bundled installs take the exact version recorded in the bundle.
+	default:
+		resolvedVersion, err := common.ResolveSkillVersion(ic.serverDetails, ic.repoKey, ic.slug, ic.version, ic.quiet)
+		if err != nil {
+			return fetched, err
+		}
+		ic.version = resolvedVersion
+	}
+
+	if err := ic.loadPolicy(); err != nil {
+		return fetched, err
+	}
+	fetched.targets, fetched.policyRows = ic.policy.AllowedTargets(installTargets)
+	if len(fetched.targets) == 0 {
+		return fetched, fmt.Errorf("no selected harness may install skill '%s': %w", ic.slug, ic.policy.CheckTarget(installTargets[0]))
 	}
-	ic.version = resolvedVersion
+	installTargets = fetched.targets
 
 	if ic.installPath != "" {
 		log.Info(fmt.Sprintf("Installing skill '%s' version '%s' to %s", ic.slug, ic.version, installTargets[0].DestinationDir))
@@ -141,20 +277,38 @@ func (ic *InstallCommand) Run() error {
 
 	tmpDir, err := os.MkdirTemp("", "skill-install-*")
 	if err != nil {
-		return fmt.Errorf("failed to create temp dir: %w", err)
+		return fetched, fmt.Errorf("failed to create temp dir: %w", err)
 	}
-	defer func() {
-		// Best-effort cleanup of install temp dir.
-		_ = os.RemoveAll(tmpDir)
-	}()
+	fetched.tmpDir = tmpDir
+	if fetched.unzipDir, err = ic.FetchAndExtractTo(tmpDir); err != nil {
+		return fetched, err
+	}
+	return fetched, nil
+}
 
-	unzipDir, err := ic.FetchAndExtractTo(tmpDir)
-	if err != nil {
-		return err
+// apply installs dependencies, copies the fetched skill to its targets, records the lockfile, and prints the summary.
+func (ic *InstallCommand) apply(fetched fetchedInstall) error {
+	installTargets, tmpDir, unzipDir := fetched.targets, fetched.tmpDir, fetched.unzipDir
+	var err error
+	var dependencyRows []agentcommon.SummaryRow
+	switch {
+	case ic.noDeps:
+	case ic.frozen:
+		log.Debug("--frozen installs only the pinned skill; its dependencies are pinned as separate lockfile entries")
+	default:
+		if dependencyRows, err = ic.installDependencies(unzipDir, tmpDir); err != nil {
+			return err
+		}
 	}
 
 	results := ic.CopyExtractedToTargets(unzipDir, installTargets)
+	if !ic.frozen {
+		if err := ic.RecordLockfile(results); err != nil {
+			return err
+		}
+	}
 
+	results = append(append(dependencyRows, fetched.policyRows...), results...)
 	if !ic.suppressSummary {
 		if err := agentcommon.PrintInstallSummary("Skill", ic.slug, ic.version, results, ic.format); err != nil {
 			return err
@@ -172,42 +326,115 @@ func (ic *InstallCommand) Run() error {
 	return nil
 }
 
-// FetchAndExtractTo downloads the skill zip into tmpDir, extracts it, and runs evidence checks.
-// The returned unzipDir is under tmpDir; callers must keep tmpDir until copies finish.
+// FetchAndExtractTo enforces the install policy, downloads the skill zip into tmpDir, runs evidence checks, and
+// extracts it. The returned unzipDir is under tmpDir; callers must keep tmpDir until copies finish.
 func (ic *InstallCommand) FetchAndExtractTo(tmpDir string) (unzipDir string, err error) {
-	zipPath, err := ic.downloadZip(tmpDir)
-	if err != nil {
-		if strings.Contains(err.Error(), "403") {
-			return "", ic.diagnoseDownloadForbidden(err)
+	if err := ic.enforcePackagePolicy(); err != nil {
+		return "", err
+	}
+	zipPath := ic.bundledZip
+	if zipPath == "" {
+		if zipPath, err = downloadPackageZip(ic, tmpDir); err != nil {
+			if strings.Contains(err.Error(), "403") {
+				return "", ic.diagnoseDownloadForbidden(err)
+			}
+			return "", fmt.Errorf("download failed: %w", err)
 		}
-		return "", fmt.Errorf("download failed: %w", err)
+	}
+	if ic.zipSHA256, err = agentcommon.VerifyLockedChecksum(zipPath, ic.expectedSHA256); err != nil {
+		return "", fmt.Errorf("skill '%s' version '%s': %w", ic.slug, ic.version, err)
+	}
+	if err := ic.handleEvidenceVerification(); err != nil {
+		return "", err
 	}
 
 	unzipDir = filepath.Join(tmpDir, "contents")
 	if err := agentcommon.UnzipFile(zipPath, unzipDir); err != nil {
 		return "", fmt.Errorf("unzip failed: %w", err)
 	}
+	return unzipDir, nil
+}
 
-	if err := ic.handleEvidenceVerification(); err != nil {
-		return "", err
+// loadPolicy reads the install policy through the loader shared with SetPolicyLoader, or through a loader of its own
+// when none was set. Bundled installs do not reach Artifactory, so only the policy in agent-config.json applies to them.
+func (ic *InstallCommand) loadPolicy() error {
+	if ic.policyLoader == nil {
+		ic.policyLoader = agentcommon.NewInstallPolicyLoader(ic.policyServerDetails())
 	}
-	return unzipDir, nil
+	policy, err := ic.policyLoader.Load()
+	if err != nil {
+		return err
+	}
+	ic.policy = policy
+	return nil
+}
+
+// enforcePackagePolicy refuses the repository, slug, or version when the install policy does not allow them.
+func (ic *InstallCommand) enforcePackagePolicy() error {
+	if err := ic.loadPolicy(); err != nil {
+		return err
+	}
+	if err := ic.policy.CheckPackage(ic.repoKey, ic.slug); err != nil {
+		return err
+	}
+	return ic.policy.CheckXrayApproval(ic.policyServerDetails(), agentcommon.XrayGateSkills, ic.repoKey, ic.slug, ic.version)
 }
 
-// CopyExtractedToTargets copies an unpacked skill tree to the given resolved targets and
-// writes a skill-info manifest per target.
+// policyServerDetails returns the server the policy checks may reach, or nil for bundled installs.
+func (ic *InstallCommand) policyServerDetails() *config.ServerDetails {
+	if ic.bundledZip != "" {
+		return nil
+	}
+	return ic.serverDetails
+}
+
+// CheckPolicyTarget returns an error when the install policy does not allow installing into target.
+func (ic *InstallCommand) CheckPolicyTarget(target common.AgentTarget) error {
+	if err := ic.loadPolicy(); err != nil {
+		return err
+	}
+	return ic.policy.CheckTarget(target)
+}
+
+// PolicyAllowedTargets splits targets into those the install policy allows and failed summary rows for the rest.
+// When the policy cannot be loaded every target fails.
+func (ic *InstallCommand) PolicyAllowedTargets(targets []common.AgentTarget) ([]common.AgentTarget, []agentcommon.SummaryRow) {
+	if err := ic.loadPolicy(); err != nil {
+		return nil, agentcommon.FailedSyncRows(targets, err)
+	}
+	return ic.policy.AllowedTargets(targets)
+}
+
+// CopyExtractedToTargets copies an unpacked skill tree to the given resolved targets (or links them to the
+// package store with --link) and writes a skill-info manifest and file inventory per target.
 func (ic *InstallCommand) CopyExtractedToTargets(unzipDir string, installTargets []common.AgentTarget) []agentcommon.SummaryRow {
+	return ic.InstallExtractedToTargets(unzipDir, installTargets, ic.link)
+}
+
+// InstallExtractedToTargets is CopyExtractedToTargets with the copy or link mode chosen by the caller;
+// update keeps linked installs linked.
+func (ic *InstallCommand) InstallExtractedToTargets(unzipDir string, installTargets []common.AgentTarget, link bool) []agentcommon.SummaryRow {
 	results := make([]agentcommon.SummaryRow, 0, len(installTargets))
+	// The inventory records the files as published so verify can detect later changes on disk.
+	inventory, prepareErr := agentcommon.HashPackageTree(unzipDir)
+	storeDir := ""
+	if prepareErr == nil && link {
+		storeDir, prepareErr = agentcommon.EnsureStoreVersion(ic.slug, ic.version, unzipDir, inventory)
+	}
 	for _, target := range installTargets {
-		if err := agentcommon.EnsureDestinationDir(target.DestinationDir); err != nil {
+		if prepareErr != nil {
+			results = append(results, agentcommon.InstallFailureRow(target.Agent.Name, string(target.Scope), target.DestinationDir, prepareErr))
+			continue
+		}
+		if err := agentcommon.PlacePackage(unzipDir, storeDir, target.DestinationDir, common.SkillInfoManifestFile); err != nil {
 			results = append(results, agentcommon.InstallFailureRow(target.Agent.Name, string(target.Scope), target.DestinationDir, err))
 			continue
 		}
-		if err := agentcommon.CopyDir(unzipDir, target.DestinationDir); err != nil {
+		if err := ic.writeSkillInfoManifest(target, storeDir); err != nil {
 			results = append(results, agentcommon.InstallFailureRow(target.Agent.Name, string(target.Scope), target.DestinationDir, err))
 			continue
 		}
-		if err := ic.writeSkillInfoManifest(target); err != nil {
+		if err := agentcommon.WriteFileInventory(target.DestinationDir, inventory); err != nil {
 			results = append(results, agentcommon.InstallFailureRow(target.Agent.Name, string(target.Scope), target.DestinationDir, err))
 			continue
 		}
@@ -222,11 +449,57 @@ func (ic *InstallCommand) CopyExtractedToTargets(unzipDir string, installTargets
 	return results
 }
 
+// RecordLockfile pins the installed version and zip checksum for every successful row.
+func (ic *InstallCommand) RecordLockfile(results []agentcommon.SummaryRow) error {
+	entries := agentcommon.LockEntriesForRows(agentcommon.LockKindSkill, ic.repoKey, ic.slug, ic.version, ic.zipSHA256, results)
+	if len(entries) == 0 {
+		return nil
+	}
+	path, err := agentcommon.LockfilePath(ic.scope, ic.projectDir, ic.installPath)
+	if err != nil {
+		return err
+	}
+	if err := agentcommon.RecordLockEntries(path, entries); err != nil {
+		return fmt.Errorf("update %s: %w", agentcommon.LockfileName, err)
+	}
+	log.Debug(fmt.Sprintf("Recorded skill '%s' version '%s' in %s", ic.slug, ic.version, path))
+	return nil
+}
+
+// applyLockedPin takes the repo, version, and checksum from the lockfile for --frozen installs.
+func (ic *InstallCommand) applyLockedPin(installTargets []common.AgentTarget) error {
+	path, err := agentcommon.LockfilePath(ic.scope, ic.projectDir, ic.installPath)
+	if err != nil {
+		return err
+	}
+	lockfile, err := agentcommon.ReadLockfile(path)
+	if err != nil {
+		return err
+	}
+	pin, err := lockfile.LockedPin(agentcommon.LockKindSkill, ic.slug, agentcommon.TargetAgentNames(installTargets))
+	if err != nil {
+		return err
+	}
+	if ic.version != "" && !agentcommon.VersionMatchesRequest(pin.Version, ic.version) {
+		return fmt.Errorf("--version %s conflicts with version %s pinned in %s", ic.version, pin.Version, path)
+	}
+	if ic.repoKey != "" && ic.repoKey != pin.Repo {
+		return fmt.Errorf("--repo %s conflicts with repository %s pinned in %s", ic.repoKey, pin.Repo, path)
+	}
+	ic.repoKey = pin.Repo
+	ic.version = pin.Version
+	ic.expectedSHA256 = pin.SHA256
+	return nil
+}
+
 func (ic *InstallCommand) handleEvidenceVerification() error {
 	err := ic.verifyEvidence()
 	if err == nil {
 		return nil
 	}
+	if policyErr := ic.policy.CheckEvidence("skill", ic.slug, ic.version, err); policyErr != nil {
+		return policyErr
+	}
 	if ic.quiet || agentcommon.IsNonInteractive() {
 		if agentcommon.ShouldFailOnMissingEvidenceForSkills() {
 			return fmt.Errorf("evidence verification failed for skill '%s': %s. %s", ic.slug, err.Error(), agentcommon.DisableQuietFailureEvidenceHintForSkills())
@@ -235,7 +508,7 @@ func (ic *InstallCommand) handleEvidenceVerification() error {
 		return nil
 	}
 	log.Warn("Evidence verification failed:", err.Error())
-	if !coreutils.AskYesNo("The skill is unattested. Continue with installation?", false) {
+	if !agentcommon.AskYesNo("The skill is unattested. Continue with installation?", false) {
 		return fmt.Errorf("installation aborted by user")
 	}
 	return nil
@@ -260,7 +533,7 @@ func (ic *InstallCommand) resolveAgentTargetDirectories() ([]common.AgentTarget,
 	return agentcommon.ResolveAgentTargets(ic.slug, "", ic.agents, ic.projectDir, isGlobal)
 }
 
-func (ic *InstallCommand) writeSkillInfoManifest(target common.AgentTarget) error {
+func (ic *InstallCommand) writeSkillInfoManifest(target common.AgentTarget, storeDir string) error {
 	dirName := filepath.Base(target.DestinationDir)
 	slug := ic.slug
 	if dirName != "" && dirName != slug {
@@ -273,6 +546,9 @@ func (ic *InstallCommand) writeSkillInfoManifest(target common.AgentTarget) erro
 		InstalledVersion: ic.version,
 		Scope:            string(target.Scope),
 		Agent:            target.Agent.Name,
+		Constraint:       ic.constraint,
+		StorePath:        storeDir,
+		SHA256:           ic.zipSHA256,
 	}
 	if target.Scope == common.ScopeProject && ic.projectDir != "" {
 		manifest.ProjectDir = ic.projectDir
@@ -281,7 +557,15 @@ func (ic *InstallCommand) writeSkillInfoManifest(target common.AgentTarget) erro
 }
 
 func (ic *InstallCommand) downloadZip(tmpDir string) (string, error) {
-	return agentcommon.DownloadPackageZip(ic.serverDetails, ic.repoKey, ic.slug, ic.version, tmpDir, "skill")
+	return agentcommon.FetchPackageZip(agentcommon.PackageZipFetch{
+		ServerDetails:  ic.serverDetails,
+		RepoKey:        ic.repoKey,
+		Slug:           ic.slug,
+		Version:        ic.version,
+		ArtifactKind:   "skill",
+		ExpectedSHA256: ic.expectedSHA256,
+		NoCache:        ic.noCache,
+	}, tmpDir)
 }
 
 // diagnoseDownloadForbidden checks the Xray status API when a download returns 403.
@@ -304,18 +588,28 @@ func (ic *InstallCommand) diagnoseDownloadForbidden(originalErr error) error {
 }
 
 func (ic *InstallCommand) verifyEvidence() error {
+	if ic.bundledZip != "" {
+		if !ic.bundledEvidence {
+			return agentcommon.ErrNoBundledEvidence
+		}
+		return nil
+	}
 	return agentcommon.VerifyPackageEvidence(ic.serverDetails, ic.repoKey, ic.slug, ic.version)
 }
 
 // RunInstall is the CLI action for `jf agent skills install`.
 func RunInstall(c *components.Context) error {
-	if c.GetNumberOfArgs() < 1 {
-		return fmt.Errorf("usage: jf agent skills install <slug> (--harness <name[,name...]> [--global] [--project-dir <dir>] | --path <dir>) [--repo <repo>] [--version <ver>]")
+	frozen := c.GetBoolFlagValue("frozen")
+	if c.GetNumberOfArgs() < 1 && !frozen {
+		return fmt.Errorf("usage: jf agent skills install <slug> ((--harness <name[,name...]> | --auto-harness) [--global] [--project-dir <dir>] | --path <dir>) [--repo <repo>] [--version <ver>] [--frozen [--threads <n>]] [--no-deps] [--no-cache] [--link]")
 	}
 
-	slug := c.GetArgumentAt(0)
-	if err := agentcommon.ValidateSlug(slug); err != nil {
-		return err
+	slug := ""
+	if c.GetNumberOfArgs() > 0 {
+		slug = c.GetArgumentAt(0)
+		if err := agentcommon.ValidateSlug(slug); err != nil {
+			return err
+		}
 	}
 
 	flags, err := agentcommon.ValidateInstallFlags(c, common.Agents, agentcommon.SkillsAgentsKey, common.RegistryHelp)
@@ -328,9 +622,13 @@ func RunInstall(c *components.Context) error {
 		return err
 	}
 	quiet := agentcommon.IsQuiet(c)
-	repoKey, err := agentcommon.ResolveRepo(serverDetails, c.GetStringFlagValue("repo"), quiet, common.RepoOptions())
-	if err != nil {
-		return err
+	// --frozen takes the repository from the lockfile unless --repo is given explicitly.
+	repoKey := c.GetStringFlagValue("repo")
+	if !frozen {
+		repoKey, err = agentcommon.ResolveRepo(serverDetails, repoKey, quiet, common.RepoOptions())
+		if err != nil {
+			return err
+		}
 	}
 
 	version := c.GetStringFlagValue("version")
@@ -338,27 +636,77 @@ func RunInstall(c *components.Context) error {
 	if c.GetStringFlagValue("format") != "" {
 		format = c.GetStringFlagValue("format")
 	}
-	if flags.PathMode() {
-		return NewInstallCommand().
+	// Every package of the run, including parallel --frozen installs, shares one install policy.
+	policyLoader := agentcommon.NewInstallPolicyLoader(serverDetails)
+	newCommand := func(slug string, specs []common.AgentSpec) *InstallCommand {
+		cmd := NewInstallCommand().
 			SetServerDetails(serverDetails).
 			SetRepoKey(repoKey).
 			SetSlug(slug).
 			SetVersion(version).
-			SetInstallPath(flags.AbsoluteInstallBaseDir).
 			SetFormat(format).
 			SetQuiet(quiet).
-			Run()
-	}
-
-	return NewInstallCommand().
-		SetServerDetails(serverDetails).
-		SetRepoKey(repoKey).
-		SetSlug(slug).
-		SetVersion(version).
-		SetAgents(flags.Specs).
-		SetGlobal(flags.IsGlobal).
-		SetProjectDir(flags.ProjectDirAbs).
-		SetFormat(format).
-		SetQuiet(quiet).
-		Run()
+			SetFrozen(frozen).
+			SetNoDeps(c.GetBoolFlagValue("no-deps")).
+			SetNoCache(c.GetBoolFlagValue("no-cache")).
+			SetLink(c.GetBoolFlagValue("link")).
+			SetPolicyLoader(policyLoader)
+		if flags.PathMode() {
+			return cmd.SetInstallPath(flags.AbsoluteInstallBaseDir)
+		}
+		return cmd.
+			SetAgents(specs).
+			SetGlobal(flags.IsGlobal).
+			SetProjectDir(flags.ProjectDirAbs)
+	}
+
+	if slug != "" {
+		return newCommand(slug, flags.Specs).Run()
+	}
+	threads, err := pluginsCommon.GetThreadsCount(c)
+	if err != nil {
+		return err
+	}
+	return runFrozenAll(flags, threads, newCommand)
+}
+
+// runFrozenAll installs every skill the lockfile pins for the selected harnesses (`install --frozen` without a slug).
+func runFrozenAll(flags agentcommon.InstallFlagsResult, threads int, newCommand func(string, []common.AgentSpec) *InstallCommand) error {
+	lockPath, err := flags.LockfilePath()
+	if err != nil {
+		return err
+	}
+	lockfile, err := agentcommon.ReadLockfile(lockPath)
+	if err != nil {
+		return err
+	}
+	harnesses := flags.HarnessNames()
+	slugs := lockfile.Slugs(agentcommon.LockKindSkill, harnesses)
+	if len(slugs) == 0 {
+		log.Info(fmt.Sprintf("No skills are pinned in %s for harness(es) %s.", lockPath, strings.Join(harnesses, ", ")))
+		return nil
+	}
+	// Downloads run on parallel workers; copies into the harness directories happen one package at a time.
+	commands := make([]*InstallCommand, len(slugs))
+	fetched := make([]fetchedInstall, len(slugs))
+	errs := make([]error, len(slugs))
+	var failed []string
+	agentcommon.RunPackagePipeline(len(slugs), threads, func(i int) {
+		locked := lockfile.LockedHarnesses(agentcommon.LockKindSkill, slugs[i], harnesses)
+		commands[i] = newCommand(slugs[i], agentcommon.FilterAgentSpecs(flags.Specs, locked))
+		fetched[i], errs[i] = commands[i].fetch()
+	}, func(i int) {
+		defer fetched[i].cleanup()
+		if errs[i] == nil {
+			errs[i] = commands[i].apply(fetched[i])
+		}
+		if errs[i] != nil {
+			log.Error(fmt.Sprintf("Frozen install of skill '%s' failed: %s", slugs[i], errs[i].Error()))
+			failed = append(failed, slugs[i])
+		}
+	})
+	if len(failed) > 0 {
+		return fmt.Errorf("frozen install failed for skill(s): %s", strings.Join(failed, ", "))
+	}
+	return nil
 }
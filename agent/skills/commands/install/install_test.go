@@ -1,6 +1,7 @@
 package install
 
 import (
+	"archive/zip"
 	"os"
 	"path/filepath"
 	"testing"
@@ -87,3 +88,65 @@ func TestCopyExtractedToTargets_WritesInstallManifest(t *testing.T) {
 	assert.Equal(t, "cursor", got.Agent)
 	assert.Equal(t, projectRoot, got.ProjectDir)
 }
+
+func TestApplyLockedPin_MissingHarness(t *testing.T) {
+	projectRoot := t.TempDir()
+	lockPath, err := agentcommon.LockfilePath(agentcommon.InstallScopeProject, projectRoot, "")
+	require.NoError(t, err)
+	require.NoError(t, agentcommon.RecordLockEntries(lockPath, []agentcommon.LockEntry{
+		{Kind: agentcommon.LockKindSkill, Slug: "my-skill", Repo: "skills-repo", Version: "1.0.0", SHA256: "abc", Harness: "cursor"},
+	}))
+
+	ic := NewInstallCommand().SetSlug("my-skill").SetProjectDir(projectRoot).SetFrozen(true)
+	err = ic.applyLockedPin([]common.AgentTarget{
+		{Agent: common.AgentSpec{Name: "cursor"}, Scope: common.ScopeProject},
+		{Agent: common.AgentSpec{Name: "claude-code"}, Scope: common.ScopeProject},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not locked for harness 'claude-code'")
+}
+
+func TestFetchAndExtractTo_FrozenChecksumMismatch(t *testing.T) {
+	ic := NewInstallCommand().SetSlug("my-skill").SetVersion("1.0.0")
+	ic.expectedSHA256 = "deadbeef"
+	restore := downloadPackageZip
+	downloadPackageZip = func(_ *InstallCommand, tmpDir string) (string, error) {
+		zipPath := filepath.Join(tmpDir, "my-skill-1.0.0.zip")
+		return zipPath, os.WriteFile(zipPath, []byte("not-the-pinned-zip"), 0o644)
+	}
+	t.Cleanup(func() { downloadPackageZip = restore })
+
+	_, err := ic.FetchAndExtractTo(t.TempDir())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum mismatch")
+}
+
+func TestFetchAndExtractTo_BundledZip(t *testing.T) {
+	restore := downloadPackageZip
+	downloadPackageZip = func(*InstallCommand, string) (string, error) {
+		t.Fatal("bundled installs must not download")
+		return "", nil
+	}
+	t.Cleanup(func() { downloadPackageZip = restore })
+
+	zipPath := filepath.Join(t.TempDir(), "my-skill-1.0.0.zip")
+	zipFile, err := os.Create(zipPath)
+	require.NoError(t, err)
+	zipWriter := zip.NewWriter(zipFile)
+	entry, err := zipWriter.Create("SKILL.md")
+	require.NoError(t, err)
+	_, err = entry.Write([]byte("# my-skill"))
+	require.NoError(t, err)
+	require.NoError(t, zipWriter.Close())
+	require.NoError(t, zipFile.Close())
+
+	ic := NewInstallCommand().SetSlug("my-skill").SetVersion("1.0.0").SetQuiet(true).SetBundledZip(zipPath, true)
+	unzipDir, err := ic.FetchAndExtractTo(t.TempDir())
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(unzipDir, "SKILL.md"))
+	assert.NotEmpty(t, ic.ZipSHA256())
+
+	_, err = NewInstallCommand().SetSlug("my-skill").SetVersion("1.0.0").SetQuiet(true).SetBundledZip(zipPath, false).FetchAndExtractTo(t.TempDir())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), agentcommon.ErrNoBundledEvidence.Error())
+}
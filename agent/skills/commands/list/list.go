@@ -34,6 +34,8 @@ const (
 	listCheckStatusBehind  = "behind"
 	listCheckStatusCurrent = "current"
 	listCheckStatusAhead   = "ahead"
+	// listCheckStatusDeprecated marks an installed version that was deprecated after it was installed.
+	listCheckStatusDeprecated = "deprecated"
 )
 
 // repoListRow is one row for registry mode (jf agent skills list --repo).
@@ -317,13 +319,18 @@ func (lc *ListCommand) fillUpdateStatus(row *localListRow, slug string) {
 	for versionIndex, skillVersion := range versions {
 		available[versionIndex] = skillVersion.Version
 	}
-	latest, err := agentcommon.LatestVersion(available)
+	deprecated := agentcommon.ListDeprecatedVersions(lc.serverDetails, row.Repo, slug)
+	latest, err := agentcommon.LatestActiveVersion(slug, available, deprecated)
 	if err != nil {
 		row.RegistryLatest = emDash
 		row.Status = listCheckStatusUnknown
 		return
 	}
 	row.RegistryLatest = latest
+	if _, isDeprecated := deprecated.Lookup(slug, row.Version); isDeprecated {
+		row.Status = listCheckStatusDeprecated
+		return
+	}
 	semverComparison, err := agentcommon.CompareSemver(row.Version, latest)
 	if err != nil {
 		row.Status = listCheckStatusUnknown
@@ -0,0 +1,60 @@
+package outdated
+
+import (
+	"fmt"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/common"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+)
+
+// RunOutdated is the CLI action for `jf agent skills outdated`.
+// It reports installed skills in every harness whose repository has a newer version, without changing anything.
+func RunOutdated(c *components.Context) error {
+	if c.GetNumberOfArgs() > 0 {
+		return fmt.Errorf("usage: jf agent skills outdated [--projects <dir[,dir...]>] [--format <table|json>]")
+	}
+	projectDirs, err := agentcommon.ParseOutdatedProjectDirs(c.GetStringFlagValue("projects"))
+	if err != nil {
+		return err
+	}
+	format := "table"
+	if c.GetStringFlagValue("format") != "" {
+		format = c.GetStringFlagValue("format")
+	}
+	registry, err := agentcommon.LoadAgentRegistry(common.Agents, agentcommon.SkillsAgentsKey)
+	if err != nil {
+		return err
+	}
+	serverDetails, err := agentcommon.GetServerDetails(c)
+	if err != nil {
+		return err
+	}
+
+	rows, err := agentcommon.CollectOutdated(registry, agentcommon.OutdatedOptions{
+		ServerDetails:    serverDetails,
+		ManifestFileName: common.SkillInfoManifestFile,
+		ProjectDirs:      projectDirs,
+		ListVersions: func(repoKey, slug string) ([]string, error) {
+			versions, err := common.ListVersions(serverDetails, repoKey, slug)
+			if err != nil {
+				return nil, err
+			}
+			available := make([]string, len(versions))
+			for idx, skillVersion := range versions {
+				available[idx] = skillVersion.Version
+			}
+			return available, nil
+		},
+		XrayStatus: func(repoKey, slug, version string) (string, error) {
+			return agentcommon.XrayStatus(serverDetails, agentcommon.XrayGateSkills, repoKey, slug, version)
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := agentcommon.PrintOutdatedSummary("Skill", rows, format); err != nil {
+		return err
+	}
+	return agentcommon.OutdatedError("Skill", rows)
+}
@@ -46,6 +46,7 @@ type PublishCommand struct {
 	keyAlias            string
 	quiet               bool
 	skipScan            bool
+	skipSecretScan      bool
 	autoDeleteOnFailure bool
 	buildConfiguration  *build.BuildConfiguration
 }
@@ -94,6 +95,11 @@ func (pc *PublishCommand) SetSkipScan(skip bool) *PublishCommand {
 	return pc
 }
 
+func (pc *PublishCommand) SetSkipSecretScan(skip bool) *PublishCommand {
+	pc.skipSecretScan = skip
+	return pc
+}
+
 func (pc *PublishCommand) SetAutoDeleteOnFailure(autoDelete bool) *PublishCommand {
 	pc.autoDeleteOnFailure = autoDelete
 	return pc
@@ -113,6 +119,17 @@ func (pc *PublishCommand) CommandName() string {
 }
 
 func (pc *PublishCommand) Run() error {
+	report, err := ValidateSkill(pc.skillDir)
+	if err != nil {
+		return err
+	}
+	if pc.skipSecretScan {
+		report.Downgrade(agentcommon.ValidationRuleSecret)
+	}
+	if err := agentcommon.CheckPublishValidation("Skill", report); err != nil {
+		return err
+	}
+
 	meta, err := ParseSkillMeta(pc.skillDir)
 	if err != nil {
 		return err
@@ -198,11 +215,11 @@ func (pc *PublishCommand) Run() error {
 	pc.attachEvidence(slug, version, sha256Hex)
 
 	// Post-publish Xray scan gate check
-	artifactPath := fmt.Sprintf("%s/%s/%s-%s.zip", slug, version, slug, version)
-	if err := common.CheckXrayGate(common.XrayGateParams{
+	if err := agentcommon.CheckXrayGate(agentcommon.XrayGateParams{
+		Kind:                agentcommon.XrayGateSkills,
 		ServerDetails:       pc.serverDetails,
 		RepoKey:             pc.repoKey,
-		ArtifactPath:        artifactPath,
+		ArtifactPath:        agentcommon.PackageArtifactPath(slug, version),
 		Slug:                slug,
 		Version:             version,
 		SkipScan:            pc.skipScan,
@@ -655,6 +672,7 @@ func RunPublish(c *components.Context) error {
 		SetKeyAlias(c.GetStringFlagValue("key-alias")).
 		SetQuiet(quiet).
 		SetSkipScan(c.GetBoolFlagValue("skip-scan")).
+		SetSkipSecretScan(c.GetBoolFlagValue("skip-secret-scan")).
 		SetAutoDeleteOnFailure(c.GetBoolFlagValue("auto-delete-on-failure")).
 		SetBuildConfiguration(buildConfig)
 
@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -142,6 +143,42 @@ description: |-
 	assert.Equal(t, "First line. Second line.", meta.Description)
 }
 
+func TestParseSkillMeta_Dependencies(t *testing.T) {
+	dir := t.TempDir()
+	skillMD := `---
+name: my-skill
+dependencies:
+  - web-search@^1.2
+  - "summarize"
+version: 1.0.0
+---
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(skillMD), 0644))
+
+	meta, err := ParseSkillMeta(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", meta.Version)
+	assert.Equal(t, []agentcommon.PackageDependency{
+		{Kind: agentcommon.LockKindSkill, Slug: "web-search", Version: "^1.2"},
+		{Kind: agentcommon.LockKindSkill, Slug: "summarize"},
+	}, meta.Dependencies)
+}
+
+func TestParseSkillMeta_InvalidDependency(t *testing.T) {
+	dir := t.TempDir()
+	skillMD := `---
+name: my-skill
+dependencies:
+  - Web_Search
+---
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(skillMD), 0644))
+
+	_, err := ParseSkillMeta(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "SKILL.md 'dependencies'")
+}
+
 func TestUpdateSkillMetaVersion_ReplacesExisting(t *testing.T) {
 	dir := t.TempDir()
 	skillMD := `---
@@ -6,12 +6,20 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
 )
 
 type SkillMeta struct {
 	Name        string
 	Description string
 	Version     string
+	// Dependencies lists the skills installed with this skill, declared in front matter as:
+	//
+	//	dependencies:
+	//	  - web-search@^1.2
+	//	  - summarize
+	Dependencies []agentcommon.PackageDependency
 }
 
 // ParseSkillMeta reads a SKILL.md file and extracts YAML frontmatter metadata.
@@ -78,6 +86,17 @@ func parseFrontmatter(content string) (*SkillMeta, error) {
 			value = strings.TrimSpace(strings.Join(parts, " "))
 		}
 
+		if key == "dependencies" && value == "" {
+			items, last := collectListItems(lines, i)
+			i = last
+			dependencies, err := parseSkillDependencies(items)
+			if err != nil {
+				return nil, err
+			}
+			meta.Dependencies = dependencies
+			continue
+		}
+
 		switch key {
 		case "name":
 			meta.Name = value
@@ -91,6 +110,35 @@ func parseFrontmatter(content string) (*SkillMeta, error) {
 	return meta, nil
 }
 
+// collectListItems returns the "- item" lines that follow lines[start] and the index of the last one consumed.
+func collectListItems(lines []string, start int) ([]string, int) {
+	var items []string
+	i := start
+	for i+1 < len(lines) {
+		next := strings.TrimSpace(lines[i+1])
+		if next != "" && !strings.HasPrefix(next, "-") {
+			break
+		}
+		i++
+		if item := strings.TrimSpace(strings.TrimPrefix(next, "-")); item != "" {
+			items = append(items, stripQuotes(item))
+		}
+	}
+	return items, i
+}
+
+func parseSkillDependencies(items []string) ([]agentcommon.PackageDependency, error) {
+	dependencies := make([]agentcommon.PackageDependency, 0, len(items))
+	for _, item := range items {
+		dependency, err := agentcommon.ParseDependencySpec(agentcommon.LockKindSkill, item)
+		if err != nil {
+			return nil, fmt.Errorf("SKILL.md 'dependencies': %w", err)
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	return dependencies, nil
+}
+
 func stripQuotes(s string) string {
 	if len(s) >= 2 {
 		if (s[0] == '"' && s[len(s)-1] == '"') || (s[0] == '\'' && s[len(s)-1] == '\'') {
@@ -0,0 +1,64 @@
+package publish
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+)
+
+const skillMDFile = "SKILL.md"
+
+// maxSkillDescriptionLength is the longest description harnesses load into the agent context.
+const maxSkillDescriptionLength = 1024
+
+// ValidateSkill checks the SKILL.md front matter and the files of the skill at skillDir against the
+// agentcommon.ValidationSchemaVersion rules. Issues are reported in the returned report; the error is
+// reserved for failures to read the skill directory.
+func ValidateSkill(skillDir string) (agentcommon.ValidationReport, error) {
+	report := agentcommon.NewValidationReport("skill", skillDir)
+	report.Manifest = skillMDFile
+	// #nosec G304 -- path is constructed from user-provided skill directory argument
+	data, err := os.ReadFile(filepath.Join(skillDir, skillMDFile))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		report.AddError(agentcommon.ValidationRuleManifestSyntax, skillMDFile, "SKILL.md not found")
+	case err != nil:
+		return report, fmt.Errorf("failed to read SKILL.md at %s: %w", skillDir, err)
+	default:
+		validateSkillMeta(&report, string(data))
+	}
+	if err := agentcommon.ValidatePackageFiles(&report, skillDir); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+func validateSkillMeta(report *agentcommon.ValidationReport, content string) {
+	meta, err := parseFrontmatter(content)
+	if err != nil {
+		report.AddError(agentcommon.ValidationRuleManifestSyntax, skillMDFile, err.Error())
+		return
+	}
+	report.Name, report.Version = meta.Name, meta.Version
+	if meta.Name == "" {
+		report.AddError(agentcommon.ValidationRuleManifestName, skillMDFile, "missing required 'name' field in front matter")
+	} else if err := agentcommon.ValidateSlug(meta.Name); err != nil {
+		report.AddError(agentcommon.ValidationRuleManifestName, skillMDFile, err.Error())
+	}
+	switch {
+	case meta.Description == "":
+		report.AddError(agentcommon.ValidationRuleManifestDescription, skillMDFile,
+			"missing 'description' field in front matter; harnesses use it to decide when to load the skill")
+	case len(meta.Description) > maxSkillDescriptionLength:
+		report.AddWarning(agentcommon.ValidationRuleManifestDescription, skillMDFile,
+			fmt.Sprintf("description is %d characters; harnesses may truncate it past %d", len(meta.Description), maxSkillDescriptionLength))
+	}
+	if meta.Version != "" {
+		if err := agentcommon.ValidateSemver(meta.Version); err != nil {
+			report.AddError(agentcommon.ValidationRuleManifestVersion, skillMDFile, err.Error())
+		}
+	}
+}
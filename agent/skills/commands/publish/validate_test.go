@@ -0,0 +1,67 @@
+package publish
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSkill(t *testing.T) {
+	dir := t.TempDir()
+	skillMD := `---
+name: My_Skill
+version: 1.0
+---
+
+Read [the reference](reference.md).
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(skillMD), 0644))
+
+	report, err := ValidateSkill(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "My_Skill", report.Name)
+	rules := make([]string, 0, len(report.Issues))
+	for _, issue := range report.Issues {
+		assert.Equal(t, agentcommon.ValidationSeverityError, issue.Severity)
+		rules = append(rules, issue.Rule)
+	}
+	assert.Equal(t, []string{
+		agentcommon.ValidationRuleManifestName,
+		agentcommon.ValidationRuleManifestDescription,
+		agentcommon.ValidationRuleManifestVersion,
+		agentcommon.ValidationRuleBrokenLink,
+	}, rules)
+}
+
+func TestValidateSkill_Valid(t *testing.T) {
+	dir := t.TempDir()
+	skillMD := `---
+name: my-skill
+description: Summarizes web pages.
+version: 1.0.0
+---
+
+Read [the reference](reference.md).
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(skillMD), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "reference.md"), []byte("# Reference"), 0644))
+
+	report, err := ValidateSkill(dir)
+	require.NoError(t, err)
+	assert.Empty(t, report.Issues)
+	assert.Equal(t, "1.0.0", report.Version)
+}
+
+func TestValidateSkill_MissingSkillMD(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Readme"), 0644))
+
+	report, err := ValidateSkill(dir)
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 1)
+	assert.Equal(t, agentcommon.ValidationRuleManifestSyntax, report.Issues[0].Rule)
+}
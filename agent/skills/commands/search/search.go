@@ -18,8 +18,16 @@ type SearchCommand struct {
 	repoKey       string
 	format        string
 	propSearch    bool
+	filters       agentcommon.SearchFilters
 }
 
+const (
+	// skillsAPISearchLimit is how many hits the Skills API returns per repository.
+	skillsAPISearchLimit = 50
+	// filteredSearchLimit is used instead when filters may drop hits or paging goes past the first results.
+	filteredSearchLimit = 1000
+)
+
 func (sc *SearchCommand) SetServerDetails(details *config.ServerDetails) *SearchCommand {
 	sc.serverDetails = details
 	return sc
@@ -45,6 +53,11 @@ func (sc *SearchCommand) SetPropSearch(prop bool) *SearchCommand {
 	return sc
 }
 
+func (sc *SearchCommand) SetFilters(filters agentcommon.SearchFilters) *SearchCommand {
+	sc.filters = filters
+	return sc
+}
+
 func (sc *SearchCommand) Run() error {
 	if sc.propSearch {
 		return sc.runPropSearch()
@@ -68,11 +81,15 @@ func (sc *SearchCommand) runSkillsAPISearch() error {
 		log.Debug(fmt.Sprintf("Discovered %d skills repositories: %v", len(repos), repos))
 	}
 
+	limit := skillsAPISearchLimit
+	if sc.filters.Active() {
+		limit = filteredSearchLimit
+	}
 	var results []agentcommon.SearchResultRow
 	var failedRepos []string
 	var firstErr error
 	for _, repo := range repos {
-		items, err := common.SearchSkills(sc.serverDetails, repo, sc.query, 50)
+		items, err := common.SearchSkills(sc.serverDetails, repo, sc.query, limit)
 		if err != nil {
 			log.Warn(fmt.Sprintf("Skills search failed for repo '%s': %s", repo, err.Error()))
 			failedRepos = append(failedRepos, repo)
@@ -96,7 +113,7 @@ func (sc *SearchCommand) runSkillsAPISearch() error {
 			sc.query, strings.Join(failedRepos, ", "), firstErr)
 	}
 
-	return sc.printResults(results)
+	return sc.printResults(agentcommon.FilterDeprecatedSearchRows(sc.serverDetails, results))
 }
 
 func (sc *SearchCommand) runPropSearch() error {
@@ -112,6 +129,10 @@ func (sc *SearchCommand) runPropSearch() error {
 }
 
 func (sc *SearchCommand) printResults(results []agentcommon.SearchResultRow) error {
+	results, err := agentcommon.ApplySearchFilters(sc.serverDetails, results, sc.filters)
+	if err != nil {
+		return fmt.Errorf("skills search failed: %w", err)
+	}
 	return agentcommon.PrintSearchResults(results, agentcommon.PrintSearchResultsOptions{
 		Query:           sc.query,
 		Format:          sc.format,
@@ -124,7 +145,9 @@ func (sc *SearchCommand) printResults(results []agentcommon.SearchResultRow) err
 // RunSearch is the CLI action for `jf agent skills search`.
 func RunSearch(c *components.Context) error {
 	if c.GetNumberOfArgs() < 1 {
-		return fmt.Errorf("usage: jf agent skills search <query> [--repo <repo>] [--format json] [--prop]")
+		return fmt.Errorf("usage: jf agent skills search <query> [--repo <repo>] [--format table|json|csv] [--prop] " +
+			"[--tag <tags>] [--author <author>] [--harness <name>] [--min-version <version>] [--has-evidence] [--xray-status <status>] " +
+			"[--sort-by name|version|repository] [--sort-order asc|desc] [--limit N] [--offset N] [--threads N]")
 	}
 
 	query := strings.TrimSpace(c.GetArgumentAt(0))
@@ -142,12 +165,18 @@ func RunSearch(c *components.Context) error {
 		format = c.GetStringFlagValue("format")
 	}
 
+	filters, err := agentcommon.ParseSearchFilters(c, agentcommon.XrayGateSkills)
+	if err != nil {
+		return err
+	}
+
 	cmd := &SearchCommand{}
 	cmd.SetServerDetails(serverDetails).
 		SetQuery(query).
 		SetRepoKey(c.GetStringFlagValue("repo")).
 		SetFormat(format).
-		SetPropSearch(c.GetBoolFlagValue("prop"))
+		SetPropSearch(c.GetBoolFlagValue("prop")).
+		SetFilters(filters)
 
 	return cmd.Run()
 }
@@ -0,0 +1,253 @@
+package sync
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/commands/install"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/commands/publish"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/commands/update"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/common"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// resolveSkillVersion is swappable in tests.
+var resolveSkillVersion = common.ResolveSkillVersion
+
+// applySkillTargets is swappable in tests.
+var applySkillTargets = (*syncCommand).applyTargets
+
+type syncCommand struct {
+	serverDetails *config.ServerDetails
+	repoFlag      string
+	projectDir    string
+	manifestPath  string
+	prune         bool
+	dryRun        bool
+	noCache       bool
+	link          bool
+	quiet         bool
+	format        string
+	// policyLoader reads the install policy once for every package in the manifest.
+	policyLoader *agentcommon.InstallPolicyLoader
+}
+
+// declaredSkill is one manifest entry with its harnesses resolved to install targets.
+type declaredSkill struct {
+	pkg     agentcommon.ManifestPackage
+	repoKey string
+	targets []common.AgentTarget
+}
+
+// constraint returns the declared version when it is a range, so updates stay within it.
+func (d declaredSkill) constraint() string {
+	if agentcommon.IsVersionRange(d.pkg.Version) {
+		return d.pkg.Version
+	}
+	return ""
+}
+
+// RunSync is the CLI action for `jf agent skills sync`.
+func RunSync(c *components.Context) error {
+	if c.GetNumberOfArgs() > 0 {
+		return fmt.Errorf("usage: jf agent skills sync [--manifest <file>] [--project-dir <dir>] [--repo <repo>] [--prune] [--dry-run] [--no-cache] [--link] [--format <table|json>]")
+	}
+	projectDir, err := agentcommon.ResolveInstallProjectDir(strings.TrimSpace(c.GetStringFlagValue("project-dir")), false)
+	if err != nil {
+		return err
+	}
+	manifestPath := strings.TrimSpace(c.GetStringFlagValue("manifest"))
+	if manifestPath == "" {
+		manifestPath = agentcommon.ProjectManifestPath(projectDir)
+	}
+	serverDetails, err := agentcommon.GetServerDetails(c)
+	if err != nil {
+		return err
+	}
+	format := "table"
+	if c.GetStringFlagValue("format") != "" {
+		format = c.GetStringFlagValue("format")
+	}
+	sc := &syncCommand{
+		serverDetails: serverDetails,
+		repoFlag:      c.GetStringFlagValue("repo"),
+		projectDir:    projectDir,
+		manifestPath:  manifestPath,
+		prune:         c.GetBoolFlagValue("prune"),
+		dryRun:        c.GetBoolFlagValue("dry-run"),
+		noCache:       c.GetBoolFlagValue("no-cache"),
+		link:          c.GetBoolFlagValue("link"),
+		quiet:         agentcommon.IsQuiet(c),
+		format:        format,
+		policyLoader:  agentcommon.NewInstallPolicyLoader(serverDetails),
+	}
+	return sc.run()
+}
+
+// run installs missing skills, updates drifted ones, and with --prune removes undeclared installs.
+func (sc *syncCommand) run() error {
+	manifest, err := agentcommon.ReadProjectManifest(sc.manifestPath)
+	if err != nil {
+		return err
+	}
+	if len(manifest.Skills) == 0 && !sc.prune {
+		log.Info(fmt.Sprintf("No skills declared in %s; nothing to sync.", sc.manifestPath))
+		return nil
+	}
+	declared, err := sc.resolveDeclaredSkills(manifest.Skills)
+	if err != nil {
+		return err
+	}
+
+	combined := make([]agentcommon.UpdateAllSummaryRow, 0)
+	for _, skill := range declared {
+		version, rows := sc.syncSkill(skill)
+		combined = agentcommon.AppendUpdateAllSummaryRows(combined, skill.pkg.Slug, version, rows)
+	}
+	if sc.prune {
+		pruned, err := sc.pruneUndeclared(manifest.Skills)
+		if err != nil {
+			return err
+		}
+		combined = append(combined, pruned...)
+	}
+
+	if len(combined) == 0 {
+		log.Info("All declared skills are in sync.")
+		return nil
+	}
+	if err := agentcommon.PrintSyncSummary("Skill", combined, sc.format); err != nil {
+		return err
+	}
+	return agentcommon.SyncFailureError(combined)
+}
+
+// resolveDeclaredSkills validates every harness and repository up front so a typo in the
+// manifest fails before anything is installed.
+func (sc *syncCommand) resolveDeclaredSkills(packages []agentcommon.ManifestPackage) ([]declaredSkill, error) {
+	registry, err := agentcommon.LoadAgentRegistry(common.Agents, agentcommon.SkillsAgentsKey)
+	if err != nil {
+		return nil, err
+	}
+	defaultRepo := ""
+	declared := make([]declaredSkill, 0, len(packages))
+	for _, pkg := range packages {
+		specs := make([]common.AgentSpec, 0, len(pkg.Harnesses))
+		for _, name := range pkg.Harnesses {
+			spec, err := agentcommon.ResolveAgent(registry, name, common.RegistryHelp)
+			if err != nil {
+				return nil, fmt.Errorf("skill '%s': %w", pkg.Slug, err)
+			}
+			specs = append(specs, spec)
+		}
+		targets, err := agentcommon.ResolveAgentTargets(pkg.Slug, "", specs, sc.projectDir, false)
+		if err != nil {
+			return nil, err
+		}
+		repoKey := pkg.Repo
+		if repoKey == "" {
+			if defaultRepo == "" {
+				if defaultRepo, err = agentcommon.ResolveRepo(sc.serverDetails, sc.repoFlag, sc.quiet, common.RepoOptions()); err != nil {
+					return nil, err
+				}
+			}
+			repoKey = defaultRepo
+		}
+		declared = append(declared, declaredSkill{pkg: pkg, repoKey: repoKey, targets: targets})
+	}
+	return declared, nil
+}
+
+// syncSkill brings every target of one declared skill to the manifest version.
+// Resolve and download failures are reported as failed rows so the remaining skills still sync.
+func (sc *syncCommand) syncSkill(skill declaredSkill) (string, []agentcommon.SummaryRow) {
+	version, err := resolveSkillVersion(sc.serverDetails, skill.repoKey, skill.pkg.Slug, skill.pkg.Version, true)
+	if err != nil {
+		log.Warn(fmt.Sprintf("Skipping skill '%s': could not resolve version: %s", skill.pkg.Slug, err.Error()))
+		return "", agentcommon.FailedSyncRows(skill.targets, err)
+	}
+	plans := agentcommon.PlanSyncTargets(skill.targets, version, publish.ReadInstalledSkillVersion)
+	rows, toInstall, toUpdate := agentcommon.SplitSyncPlans("Skill", skill.pkg.Slug, version, plans, sc.dryRun)
+	if len(toInstall) == 0 && len(toUpdate) == 0 {
+		return version, rows
+	}
+	return version, append(rows, applySkillTargets(sc, skill, version, toInstall, toUpdate)...)
+}
+
+// applyTargets downloads the skill once, installs it where it is missing, and updates drifted targets.
+// Targets whose harness the install policy does not allow fail without being touched.
+func (sc *syncCommand) applyTargets(skill declaredSkill, version string, toInstall, toUpdate []common.AgentTarget) []agentcommon.SummaryRow {
+	cmd := install.NewInstallCommand().
+		SetServerDetails(sc.serverDetails).
+		SetRepoKey(skill.repoKey).
+		SetSlug(skill.pkg.Slug).
+		SetVersion(version).
+		SetConstraint(skill.constraint()).
+		SetQuiet(sc.quiet).
+		SetSuppressSummary(true).
+		SetProjectDir(sc.projectDir).
+		SetGlobal(false).
+		SetNoCache(sc.noCache).
+		SetLink(sc.link).
+		SetPolicyLoader(sc.policyLoader)
+
+	toInstall, policyRows := cmd.PolicyAllowedTargets(toInstall)
+	toUpdate, updateRows := cmd.PolicyAllowedTargets(toUpdate)
+	policyRows = append(policyRows, updateRows...)
+	pending := append(append([]common.AgentTarget{}, toInstall...), toUpdate...)
+	if len(pending) == 0 {
+		return policyRows
+	}
+
+	tmpDir, err := os.MkdirTemp("", "skill-sync-*")
+	if err != nil {
+		return append(policyRows, agentcommon.FailedSyncRows(pending, fmt.Errorf("failed to create temp dir: %w", err))...)
+	}
+	defer func() {
+		// Best-effort cleanup of sync temp dir.
+		_ = os.RemoveAll(tmpDir)
+	}()
+
+	unzipDir, err := cmd.FetchAndExtractTo(tmpDir)
+	if err != nil {
+		log.Warn(fmt.Sprintf("Skipping skill '%s': %s", skill.pkg.Slug, err.Error()))
+		return append(policyRows, agentcommon.FailedSyncRows(pending, err)...)
+	}
+	rows := cmd.CopyExtractedToTargets(unzipDir, toInstall)
+	rows = append(rows, update.UpdateTargets(unzipDir, cmd, toUpdate)...)
+	if err := cmd.RecordLockfile(rows); err != nil {
+		log.Warn(fmt.Sprintf("Skill '%s' was synced but the lockfile was not: %s", skill.pkg.Slug, err.Error()))
+	}
+	return append(policyRows, rows...)
+}
+
+// pruneUndeclared removes CLI-managed skills that the manifest does not declare for a harness.
+// Only harnesses named somewhere in the skills section are scanned.
+func (sc *syncCommand) pruneUndeclared(packages []agentcommon.ManifestPackage) ([]agentcommon.UpdateAllSummaryRow, error) {
+	registry, err := agentcommon.LoadAgentRegistry(common.Agents, agentcommon.SkillsAgentsKey)
+	if err != nil {
+		return nil, err
+	}
+	declaredByHarness := agentcommon.DeclaredSlugsByHarness(packages)
+	specs := make([]common.AgentSpec, 0, len(declaredByHarness))
+	for _, name := range agentcommon.SortedHarnesses(declaredByHarness) {
+		spec, err := agentcommon.ResolveAgent(registry, name, common.RegistryHelp)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	undeclared, err := agentcommon.FindUndeclaredInstalls(specs, sc.projectDir, common.SkillInfoManifestFile, declaredByHarness)
+	if err != nil {
+		return nil, err
+	}
+	lockPath, err := agentcommon.LockfilePath(agentcommon.InstallScopeProject, sc.projectDir, "")
+	if err != nil {
+		return nil, err
+	}
+	return agentcommon.PruneInstalls("Skill", agentcommon.LockKindSkill, lockPath, undeclared, sc.dryRun), nil
+}
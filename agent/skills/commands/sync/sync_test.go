@@ -0,0 +1,114 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/common/testutil"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/common"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeManifest(t *testing.T, projectDir, body string) string {
+	t.Helper()
+	path := agentcommon.ProjectManifestPath(projectDir)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(body), 0o644))
+	return path
+}
+
+func installSkill(t *testing.T, projectDir, slug, version string) string {
+	t.Helper()
+	dir := filepath.Join(projectDir, ".cursor", "skills", slug)
+	require.NoError(t, agentcommon.WriteInstallInfoManifest(dir, common.SkillInfoManifestFile, common.SkillInfoManifest{
+		Repo: "skills", Slug: slug, InstalledVersion: version, Scope: "project", Agent: "cursor",
+	}))
+	return dir
+}
+
+func stubSync(t *testing.T, versions map[string]string) *[]string {
+	t.Helper()
+	origResolve, origApply := resolveSkillVersion, applySkillTargets
+	t.Cleanup(func() { resolveSkillVersion, applySkillTargets = origResolve, origApply })
+	resolveSkillVersion = func(_ *config.ServerDetails, _, slug, _ string, _ bool) (string, error) {
+		return versions[slug], nil
+	}
+	var applied []string
+	applySkillTargets = func(_ *syncCommand, skill declaredSkill, _ string, toInstall, toUpdate []common.AgentTarget) []agentcommon.SummaryRow {
+		var rows []agentcommon.SummaryRow
+		for _, target := range append(toInstall, toUpdate...) {
+			applied = append(applied, skill.pkg.Slug+"@"+target.Agent.Name)
+			rows = append(rows, agentcommon.SummaryRow{Agent: target.Agent.Name, Path: target.DestinationDir, Status: agentcommon.SummaryStatusOK})
+		}
+		return rows
+	}
+	return &applied
+}
+
+func newTestSync(t *testing.T, projectDir, manifestPath string) *syncCommand {
+	t.Helper()
+	testutil.WithJfrogHome(t)
+	return &syncCommand{
+		serverDetails: &config.ServerDetails{},
+		repoFlag:      "skills",
+		projectDir:    projectDir,
+		manifestPath:  manifestPath,
+		quiet:         true,
+		format:        "json",
+	}
+}
+
+func TestSync_InstallsMissingAndUpdatesDrifted(t *testing.T) {
+	projectDir := t.TempDir()
+	installSkill(t, projectDir, "current", "1.0.0")
+	installSkill(t, projectDir, "drifted", "1.0.0")
+	manifestPath := writeManifest(t, projectDir, `{"schemaVersion": 1, "skills": [
+		{"slug": "current", "version": "1.0.0", "harnesses": ["cursor"]},
+		{"slug": "drifted", "version": "2.0.0", "harnesses": ["cursor"]},
+		{"slug": "missing", "harnesses": ["cursor"]}
+	]}`)
+	applied := stubSync(t, map[string]string{"current": "1.0.0", "drifted": "2.0.0", "missing": "3.0.0"})
+
+	require.NoError(t, newTestSync(t, projectDir, manifestPath).run())
+	assert.Equal(t, []string{"drifted@cursor", "missing@cursor"}, *applied)
+}
+
+func TestSync_DryRunAppliesNothing(t *testing.T) {
+	projectDir := t.TempDir()
+	manifestPath := writeManifest(t, projectDir, `{"skills": [{"slug": "missing", "harnesses": ["cursor"]}]}`)
+	applied := stubSync(t, map[string]string{"missing": "1.0.0"})
+
+	sc := newTestSync(t, projectDir, manifestPath)
+	sc.dryRun = true
+	require.NoError(t, sc.run())
+	assert.Empty(t, *applied)
+}
+
+func TestSync_PruneRemovesUndeclared(t *testing.T) {
+	projectDir := t.TempDir()
+	kept := installSkill(t, projectDir, "kept", "1.0.0")
+	stale := installSkill(t, projectDir, "stale", "1.0.0")
+	manifestPath := writeManifest(t, projectDir, `{"skills": [{"slug": "kept", "version": "1.0.0", "harnesses": ["cursor"]}]}`)
+	stubSync(t, map[string]string{"kept": "1.0.0"})
+
+	sc := newTestSync(t, projectDir, manifestPath)
+	sc.prune = true
+	require.NoError(t, sc.run())
+	assert.DirExists(t, kept)
+	assert.NoDirExists(t, stale)
+}
+
+func TestSync_UnknownHarnessFailsBeforeInstalling(t *testing.T) {
+	projectDir := t.TempDir()
+	manifestPath := writeManifest(t, projectDir, `{"skills": [{"slug": "web", "harnesses": ["no-such-harness"]}]}`)
+	applied := stubSync(t, map[string]string{"web": "1.0.0"})
+
+	err := newTestSync(t, projectDir, manifestPath).run()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "skill 'web'")
+	assert.Empty(t, *applied)
+}
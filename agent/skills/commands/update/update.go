@@ -12,6 +12,7 @@ import (
 	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/commands/publish"
 	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/common"
 	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 )
 
@@ -28,7 +29,7 @@ type preUpdate struct {
 // RunUpdate is the CLI action for `jf agent skills update`.
 func RunUpdate(c *components.Context) error {
 	if c.GetNumberOfArgs() < 1 {
-		return fmt.Errorf("usage: jf agent skills update <slug> (--harness <name[,name...]> [--global] [--project-dir <dir>] | --path <dir>) [--repo <repo>] [--version <ver>] [--dry-run] [--force] [--format <table|json>]")
+		return fmt.Errorf("usage: jf agent skills update <slug> ((--harness <name[,name...]> | --auto-harness) [--global] [--project-dir <dir>] | --path <dir>) [--repo <repo>] [--version <ver>] [--dry-run] [--force] [--no-cache] [--link] [--format <table|json>]")
 	}
 
 	slug := c.GetArgumentAt(0)
@@ -64,57 +65,119 @@ func RunUpdate(c *components.Context) error {
 		return err
 	}
 
-	targetVersion, err := common.ResolveSkillVersion(serverDetails, repoKey, slug, requestedVersion, quiet)
+	run := skillUpdate{
+		serverDetails: serverDetails,
+		repoKey:       repoKey,
+		slug:          slug,
+		flags:         flags,
+		dryRun:        dryRun,
+		force:         force,
+		noCache:       c.GetBoolFlagValue("no-cache"),
+		link:          c.GetBoolFlagValue("link"),
+		quiet:         quiet,
+		format:        format,
+		policyLoader:  agentcommon.NewInstallPolicyLoader(serverDetails),
+	}
+	// Without --version each target stays within the range it was installed with.
+	var results []agentcommon.SummaryRow
+	for _, group := range agentcommon.GroupTargetsByConstraint(targets, common.SkillInfoManifestFile, requestedVersion) {
+		groupResults, err := run.updateGroup(group)
+		if err != nil {
+			return err
+		}
+		results = append(results, groupResults...)
+	}
+	if dryRun {
+		return nil
+	}
+	return finalError(results)
+}
+
+type skillUpdate struct {
+	serverDetails *config.ServerDetails
+	repoKey       string
+	slug          string
+	flags         agentcommon.InstallFlagsResult
+	dryRun        bool
+	force         bool
+	noCache       bool
+	link          bool
+	quiet         bool
+	format        string
+	// policyLoader reads the install policy once for all version groups.
+	policyLoader *agentcommon.InstallPolicyLoader
+}
+
+// updateGroup resolves one version for targets that share a version request, updates them, and prints their summary.
+func (run skillUpdate) updateGroup(group agentcommon.ConstraintGroup) ([]agentcommon.SummaryRow, error) {
+	targetVersion, err := common.ResolveSkillVersion(run.serverDetails, run.repoKey, run.slug, group.Requested, run.quiet)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	checks := preUpdateTargets(targets, targetVersion, force, quiet)
+	checks := preUpdateTargets(group.Targets, targetVersion, run.force, run.quiet)
 	results, updatable := initialResultsAndUpdatable(checks, targetVersion)
 
-	if dryRun {
-		logDryRun(slug, targetVersion, checks)
-		return agentcommon.PrintInstallSummary("Skill", slug, targetVersion, results, format)
+	if run.dryRun {
+		logDryRun(run.slug, targetVersion, checks)
+		return results, agentcommon.PrintInstallSummary("Skill", run.slug, targetVersion, results, run.format)
 	}
 
-	if len(updatable) == 0 {
-		if err := agentcommon.PrintInstallSummary("Skill", slug, targetVersion, results, format); err != nil {
-			return err
+	cmd := install.NewInstallCommand().
+		SetServerDetails(run.serverDetails).
+		SetRepoKey(run.repoKey).
+		SetSlug(run.slug).
+		SetVersion(targetVersion).
+		SetConstraint(group.Constraint).
+		SetQuiet(run.quiet).
+		SetSuppressSummary(true).
+		SetProjectDir(run.flags.ProjectDirAbs).
+		SetGlobal(run.flags.IsGlobal).
+		SetInstallPath(run.flags.AbsoluteInstallBaseDir).
+		SetNoCache(run.noCache).
+		SetLink(run.link).
+		SetPolicyLoader(run.policyLoader)
+
+	allowed := make([]preUpdate, 0, len(updatable))
+	for _, preUpdateCheck := range updatable {
+		if err := cmd.CheckPolicyTarget(preUpdateCheck.agentTarget); err != nil {
+			results = append(results, summaryRowFor(preUpdateCheck.agentTarget, agentcommon.SummaryStatusFailed, err.Error()))
+			continue
 		}
-		return finalError(results)
+		allowed = append(allowed, preUpdateCheck)
+	}
+	updatable = allowed
+	if len(updatable) == 0 {
+		return results, agentcommon.PrintInstallSummary("Skill", run.slug, targetVersion, results, run.format)
 	}
 
 	tmpDir, err := os.MkdirTemp("", "skill-update-*")
 	if err != nil {
-		return fmt.Errorf("failed to create temp dir: %w", err)
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
 	}
 	defer func() {
 		_ = os.RemoveAll(tmpDir)
 	}()
 
-	cmd := install.NewInstallCommand().
-		SetServerDetails(serverDetails).
-		SetRepoKey(repoKey).
-		SetSlug(slug).
-		SetVersion(targetVersion).
-		SetQuiet(quiet).
-		SetSuppressSummary(true).
-		SetProjectDir(flags.ProjectDirAbs).
-		SetGlobal(flags.IsGlobal)
-
 	unzipDir, err := cmd.FetchAndExtractTo(tmpDir)
 	if err != nil {
-		return err
+		if !agentcommon.IsPolicyViolation(err) {
+			return nil, err
+		}
+		for _, preUpdateCheck := range updatable {
+			results = append(results, summaryRowFor(preUpdateCheck.agentTarget, agentcommon.SummaryStatusFailed, err.Error()))
+		}
+		return results, agentcommon.PrintInstallSummary("Skill", run.slug, targetVersion, results, run.format)
 	}
 
 	for _, preUpdateCheck := range updatable {
 		results = append(results, updateOneSkill(unzipDir, cmd, preUpdateCheck))
 	}
-
-	if err := agentcommon.PrintInstallSummary("Skill", slug, targetVersion, results, format); err != nil {
-		return err
+	if err := cmd.RecordLockfile(results); err != nil {
+		log.Warn(fmt.Sprintf("Skill '%s' was updated but the lockfile was not: %s", run.slug, err.Error()))
 	}
-	return finalError(results)
+
+	return results, agentcommon.PrintInstallSummary("Skill", run.slug, targetVersion, results, run.format)
 }
 
 func preUpdateTargets(targets []common.AgentTarget, targetVersion string, force, quiet bool) []preUpdate {
@@ -187,12 +250,28 @@ func logDryRun(slug, targetVersion string, checks []preUpdate) {
 	}
 }
 
+// UpdateTargets replaces each installed target with the already-fetched tree in unzipDir,
+// restoring the previous install when a copy fails. Used by skills sync for drifted targets.
+func UpdateTargets(unzipDir string, installCommand *install.InstallCommand, targets []common.AgentTarget) []agentcommon.SummaryRow {
+	results := make([]agentcommon.SummaryRow, 0, len(targets))
+	for _, agentTarget := range targets {
+		results = append(results, updateOneSkill(unzipDir, installCommand, preUpdate{agentTarget: agentTarget}))
+	}
+	return results
+}
+
 // updateOneSkill updates a single install target using the already-fetched tree in unzipDir:
-// it renames the live install aside, copies from unzipDir, restores the backup on failure, then removes the backup on success.
+// it renames the live install aside, copies from unzipDir, restores the backup on failure, and on success keeps the
+// backup under .jfrog/previous for rollback.
+// Installs linked to the package store stay linked and are re-pointed to the new store version.
 func updateOneSkill(unzipDir string, installCommand *install.InstallCommand, check preUpdate) agentcommon.SummaryRow {
 	agentTarget := check.agentTarget
 	slugBase := filepath.Base(agentTarget.DestinationDir)
 	parent := filepath.Dir(agentTarget.DestinationDir)
+	link := installCommand.Link()
+	if manifest, err := agentcommon.ReadInstallInfoManifest(agentTarget.DestinationDir, common.SkillInfoManifestFile); err == nil && manifest != nil && manifest.StorePath != "" {
+		link = true
+	}
 
 	backupPath, err := reserveUpdateBackupPath(parent, slugBase)
 	if err != nil {
@@ -202,7 +281,7 @@ func updateOneSkill(unzipDir string, installCommand *install.InstallCommand, che
 		return summaryRowFor(agentTarget, agentcommon.SummaryStatusFailed, fmt.Sprintf("could not move current skill aside for update: %s", err.Error()))
 	}
 
-	rows := installCommand.CopyExtractedToTargets(unzipDir, []common.AgentTarget{agentTarget})
+	rows := installCommand.InstallExtractedToTargets(unzipDir, []common.AgentTarget{agentTarget}, link)
 	if len(rows) != 1 {
 		_ = os.RemoveAll(agentTarget.DestinationDir)
 		if restoreErr := os.Rename(backupPath, agentTarget.DestinationDir); restoreErr != nil {
@@ -219,12 +298,14 @@ func updateOneSkill(unzipDir string, installCommand *install.InstallCommand, che
 		return row
 	}
 
-	if err := os.RemoveAll(backupPath); err != nil {
-		log.Warn(fmt.Sprintf("Update succeeded but previous copy at %s could not be deleted: %s", backupPath, err.Error()))
-	} else {
-		backupRoot := filepath.Join(parent, skillBackupDirName)
-		_ = os.Remove(backupRoot)
+	if err := agentcommon.KeepPreviousInstall(backupPath, agentTarget.DestinationDir); err != nil {
+		log.Warn(fmt.Sprintf("Update succeeded but the previous version could not be kept for rollback: %s", err.Error()))
+		if err := os.RemoveAll(backupPath); err != nil {
+			log.Warn(fmt.Sprintf("Previous copy at %s could not be deleted: %s", backupPath, err.Error()))
+		}
 	}
+	backupRoot := filepath.Join(parent, skillBackupDirName)
+	_ = os.Remove(backupRoot)
 
 	return summaryRowFor(agentTarget, agentcommon.SummaryStatusOK, agentcommon.SummaryDetailOKInstall)
 }
@@ -157,6 +157,28 @@ func TestUpdateOneSkill_SuccessRemovesBackup(t *testing.T) {
 	assert.Contains(t, string(data), "2.0.0")
 }
 
+func TestUpdateOneSkill_SuccessKeepsPreviousVersion(t *testing.T) {
+	dir := skillDir(t, "---\nname: web\nversion: 1.0.0\n---\n")
+	check := preUpdate{
+		agentTarget: common.AgentTarget{
+			Agent:          common.AgentSpec{Name: "cursor"},
+			Scope:          common.ScopeProject,
+			DestinationDir: dir,
+		},
+		installedVersion: "1.0.0",
+	}
+
+	src := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(src, "SKILL.md"), []byte("---\nname: web\nversion: 2.0.0\n---\n"), 0o644))
+
+	row := updateOneSkill(src, install.NewInstallCommand(), check)
+	require.Equal(t, agentcommon.SummaryStatusOK, row.Status)
+
+	data, err := os.ReadFile(filepath.Join(agentcommon.PreviousInstallDir(dir), "SKILL.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "1.0.0", "the replaced version is kept for rollback")
+}
+
 func skillDir(t *testing.T, skillMD string) string {
 	t.Helper()
 	base := t.TempDir()
@@ -0,0 +1,45 @@
+package validate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/commands/publish"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+)
+
+// RunValidate is the CLI action for `jf agent skills validate <path> [<path>...]`.
+// It applies the checks publish runs before zipping, without contacting the server, so the same rules can run
+// in pre-commit hooks and CI. It fails when any skill has errors, or warnings with --strict.
+func RunValidate(c *components.Context) error {
+	if c.GetNumberOfArgs() < 1 {
+		return fmt.Errorf("usage: jf agent skills validate <path> [<path>...] [--strict] [--format <table|json>]")
+	}
+	format := "table"
+	if c.GetStringFlagValue("format") != "" {
+		format = c.GetStringFlagValue("format")
+	}
+	reports := make([]agentcommon.ValidationReport, 0, c.GetNumberOfArgs())
+	for argIndex := 0; argIndex < c.GetNumberOfArgs(); argIndex++ {
+		arg := c.GetArgumentAt(argIndex)
+		skillDir, err := filepath.Abs(arg)
+		if err != nil {
+			return fmt.Errorf("invalid skill path: %w", err)
+		}
+		info, err := os.Stat(skillDir)
+		if err != nil || !info.IsDir() {
+			return fmt.Errorf("skill path '%s' is not a valid directory", arg)
+		}
+		report, err := publish.ValidateSkill(skillDir)
+		if err != nil {
+			return err
+		}
+		reports = append(reports, report)
+	}
+	if err := agentcommon.PrintValidationReports("Skill", reports, format); err != nil {
+		return err
+	}
+	return agentcommon.ValidationError("Skill", reports, c.GetBoolFlagValue("strict"))
+}
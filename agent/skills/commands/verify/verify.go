@@ -0,0 +1,52 @@
+package verify
+
+import (
+	"fmt"
+
+	agentcommon "github.com/jfrog/jfrog-cli-artifactory/agent/common"
+	"github.com/jfrog/jfrog-cli-artifactory/agent/skills/common"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+)
+
+// RunVerify is the CLI action for `jf agent skills verify`.
+// It re-hashes installed skills and reports files modified, added, or removed since install.
+func RunVerify(c *components.Context) error {
+	if c.GetNumberOfArgs() > 1 {
+		return fmt.Errorf("usage: jf agent skills verify [<slug>] (--harness <name[,name...]> [--global] [--project-dir <dir>] | --path <dir>) [--evidence] [--format <table|json>]")
+	}
+	slug := ""
+	if c.GetNumberOfArgs() == 1 {
+		slug = c.GetArgumentAt(0)
+		if err := agentcommon.ValidateSlug(slug); err != nil {
+			return err
+		}
+	}
+
+	flags, err := agentcommon.ValidateInstallFlags(c, common.Agents, agentcommon.SkillsAgentsKey, common.RegistryHelp)
+	if err != nil {
+		return err
+	}
+	opts := agentcommon.VerifyOptions{ManifestFileName: common.SkillInfoManifestFile}
+	if c.GetBoolFlagValue("evidence") {
+		serverDetails, err := agentcommon.GetServerDetails(c)
+		if err != nil {
+			return err
+		}
+		opts.CheckEvidence = true
+		opts.ServerDetails = serverDetails
+	}
+	format := "table"
+	if c.GetStringFlagValue("format") != "" {
+		format = c.GetStringFlagValue("format")
+	}
+
+	targets, err := agentcommon.VerifyTargets(slug, flags, common.SkillInfoManifestFile)
+	if err != nil {
+		return err
+	}
+	results := agentcommon.VerifyInstalls(targets, opts)
+	if err := agentcommon.PrintVerifySummary("Skill", results, format); err != nil {
+		return err
+	}
+	return agentcommon.VerifyError("Skill", results)
+}
@@ -12,12 +12,12 @@ type AgentSpec = agentcommon.AgentSpec
 
 // Agents is built-in defaults; merged with ~/.jfrog/agents/agent-config.json.
 var Agents = map[string]AgentConfig{
-	"claude-code":    {GlobalDir: "~/.claude/skills", ProjectDir: ".claude/skills"},
-	"cursor":         {GlobalDir: "~/.cursor/skills", ProjectDir: ".cursor/skills"},
-	"github-copilot": {GlobalDir: "~/.copilot/skills", ProjectDir: ".github/skills"},
-	"windsurf":       {GlobalDir: "~/.codeium/windsurf/skills", ProjectDir: ".windsurf/skills"},
-	"codex":          {GlobalDir: "~/.codex/skills", ProjectDir: ".codex/skills"},
-	"cross-agent":    {GlobalDir: "~/.agents/skills", ProjectDir: ".agents/skills"},
+	"claude-code":    {GlobalDir: "~/.claude/skills", ProjectDir: ".claude/skills", DetectPaths: []string{".claude", "CLAUDE.md", "~/.claude"}},
+	"cursor":         {GlobalDir: "~/.cursor/skills", ProjectDir: ".cursor/skills", DetectPaths: []string{".cursor", ".cursorrules", "~/.cursor"}},
+	"github-copilot": {GlobalDir: "~/.copilot/skills", ProjectDir: ".github/skills", DetectPaths: []string{".github/copilot-instructions.md", "~/.copilot"}},
+	"windsurf":       {GlobalDir: "~/.codeium/windsurf/skills", ProjectDir: ".windsurf/skills", DetectPaths: []string{".windsurf", ".windsurfrules", "~/.codeium/windsurf"}},
+	"codex":          {GlobalDir: "~/.codex/skills", ProjectDir: ".codex/skills", DetectPaths: []string{".codex", "~/.codex"}},
+	"cross-agent":    {GlobalDir: "~/.agents/skills", ProjectDir: ".agents/skills", DetectPaths: []string{".agents", "AGENTS.md", "~/.agents"}},
 }
 
 // RegistryHelp configures agent-config.json help text for skills harness resolution.
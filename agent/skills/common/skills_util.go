@@ -7,7 +7,8 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 )
 
-// ResolveSkillVersion lists remote versions then applies SelectPackageVersion rules.
+// ResolveSkillVersion lists remote versions then applies SelectPackageVersion rules, skipping deprecated versions
+// unless one is requested explicitly.
 func ResolveSkillVersion(serverDetails *config.ServerDetails, repoKey, slug, requested string, quiet bool) (string, error) {
 	versions, err := ListVersions(serverDetails, repoKey, slug)
 	if err != nil {
@@ -20,10 +21,23 @@ func ResolveSkillVersion(serverDetails *config.ServerDetails, repoKey, slug, req
 	for idx, skillVersion := range versions {
 		available[idx] = skillVersion.Version
 	}
-	return agentcommon.SelectPackageVersion(agentcommon.SelectPackageVersionOpts{
-		Available: available,
-		Requested: requested,
-		RepoKey:   repoKey,
-		Quiet:     quiet,
+	deprecated := agentcommon.ListDeprecatedVersions(serverDetails, repoKey, slug)
+	version, err := agentcommon.SelectPackageVersion(agentcommon.SelectPackageVersionOpts{
+		Available:  available,
+		Requested:  requested,
+		RepoKey:    repoKey,
+		Quiet:      quiet,
+		Slug:       slug,
+		Deprecated: deprecated,
 	})
+	if err != nil {
+		return "", err
+	}
+	agentcommon.WarnIfDeprecated("Skill", slug, version, deprecated)
+	return version, nil
+}
+
+// DeleteSkillVersion deletes the entire version directory for a skill.
+func DeleteSkillVersion(serverDetails *config.ServerDetails, repoKey, slug, version string) error {
+	return agentcommon.DeleteVersion(serverDetails, repoKey, slug, version)
 }
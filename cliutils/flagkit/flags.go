@@ -504,20 +504,43 @@ const (
 	AddSources               = "add"
 
 	// Skills commands keys
-	SkillsPublish = "skills-publish"
-	SkillsInstall = "skills-install"
-	SkillsUpdate  = "skills-update"
-	SkillsSearch  = "skills-search"
-	SkillsDelete  = "skills-delete"
-	SkillsList    = "skills-list"
+	SkillsPublish  = "skills-publish"
+	SkillsInstall  = "skills-install"
+	SkillsUpdate   = "skills-update"
+	SkillsSearch   = "skills-search"
+	SkillsDelete   = "skills-delete"
+	SkillsList     = "skills-list"
+	SkillsSync     = "skills-sync"
+	SkillsVerify   = "skills-verify"
+	SkillsExport   = "skills-export"
+	SkillsImport   = "skills-import"
+	SkillsGC       = "skills-gc"
+	SkillsRollback = "skills-rollback"
+	SkillsOutdated = "skills-outdated"
+	SkillsValidate = "skills-validate"
 
 	// Agent plugin commands keys
-	AgentPluginsPublish = "agent-plugins-publish"
-	AgentPluginsInstall = "agent-plugins-install"
-	AgentPluginsUpdate  = "agent-plugins-update"
-	AgentPluginsDelete  = "agent-plugins-delete"
-	AgentPluginsList    = "agent-plugins-list"
-	AgentPluginsSearch  = "agent-plugins-search"
+	AgentPluginsPublish  = "agent-plugins-publish"
+	AgentPluginsInstall  = "agent-plugins-install"
+	AgentPluginsUpdate   = "agent-plugins-update"
+	AgentPluginsDelete   = "agent-plugins-delete"
+	AgentPluginsList     = "agent-plugins-list"
+	AgentPluginsSearch   = "agent-plugins-search"
+	AgentPluginsSync     = "agent-plugins-sync"
+	AgentPluginsVerify   = "agent-plugins-verify"
+	AgentPluginsExport   = "agent-plugins-export"
+	AgentPluginsImport   = "agent-plugins-import"
+	AgentPluginsRollback = "agent-plugins-rollback"
+	AgentPluginsOutdated = "agent-plugins-outdated"
+	AgentPluginsValidate = "agent-plugins-validate"
+
+	AgentPluginsMarketplaceGenerate = "agent-plugins-marketplace-generate"
+
+	SkillsDeprecate       = "skills-deprecate"
+	AgentPluginsDeprecate = "agent-plugins-deprecate"
+	SkillsDiff            = "skills-diff"
+	AgentPluginsDiff      = "agent-plugins-diff"
+	AgentPluginsConvert   = "agent-plugins-convert"
 
 	// Agent namespace-specific flags (shared by skills and agent-plugins commands)
 	version    = "version"
@@ -534,6 +557,7 @@ const (
 	agentFormat         = "agent-" + Format
 	skipScan            = "skip-scan"
 	autoDeleteOnFailure = "auto-delete-on-failure"
+	skipScanCheck       = "skip-scan-check"
 	harness             = "harness"
 	projectDir          = "project-dir"
 	agentGlobal         = "agent-global"
@@ -542,6 +566,39 @@ const (
 	agentSortOrder      = "agent-" + sortOrder
 	agentCheckUpdates   = "agent-check-updates"
 	checkUpdates        = "check-updates"
+	frozen              = "frozen"
+	noDeps              = "no-deps"
+	syncManifest        = "manifest"
+	syncPrune           = "prune"
+	verifyEvidence      = "evidence"
+	bundleOutput        = "output"
+	bundlePublish       = "publish"
+	bundlePublicKeys    = "public-keys"
+	noCache             = "no-cache"
+	link                = "link"
+	outdatedProjects    = "projects"
+	validateStrict      = "strict"
+	skipSecretScan      = "skip-secret-scan"
+	marketplaceCheck    = "check"
+	deprecateReason     = "reason"
+	replacementVersion  = "replacement"
+	deprecateUndo       = "undo"
+	autoHarness         = "auto-harness"
+	diffInstalled       = "installed"
+	searchFormat        = "search-" + Format
+	searchTag           = "search-tag"
+	searchAuthor        = "search-author"
+	searchHarness       = "search-harness"
+	searchMinVersion    = "search-min-version"
+	searchEvidence      = "search-has-evidence"
+	searchXrayStatus    = "search-xray-status"
+	searchSortBy        = "search-" + sortBy
+	searchSortOrder     = "search-" + sortOrder
+	searchLimit         = "search-" + limit
+	searchOffset        = "search-" + offset
+	convertTo           = "convert-to"
+	convertOutput       = "convert-output"
+	installConvert      = "convert"
 )
 
 var commandFlags = map[string][]string{
@@ -883,18 +940,18 @@ var commandFlags = map[string][]string{
 		Format, OrderBy, FilterBy, OrderAsc, Limit, Offset, Includes, Project,
 	},
 	SkillsPublish: {
-		url, user, password, accessToken, serverId, repo, version, signingKey, keyAlias, agentQuiet, skipScan, autoDeleteOnFailure,
+		url, user, password, accessToken, serverId, repo, version, signingKey, keyAlias, agentQuiet, skipScan, autoDeleteOnFailure, skipSecretScan,
 		BuildName, BuildNumber, module,
 	},
 	AgentPluginsPublish: {
-		url, user, password, accessToken, serverId, repo, version, signingKey, keyAlias, agentQuiet,
+		url, user, password, accessToken, serverId, repo, version, signingKey, keyAlias, agentQuiet, skipScan, autoDeleteOnFailure, skipSecretScan,
 		BuildName, BuildNumber, module,
 	},
 	AgentPluginsInstall: {
-		url, user, password, accessToken, serverId, repo, version, harness, projectDir, agentGlobal, installPath, agentFormat, agentQuiet,
+		url, user, password, accessToken, serverId, repo, version, harness, autoHarness, projectDir, agentGlobal, installPath, agentFormat, agentQuiet, frozen, noDeps, noCache, skipScanCheck, installConvert, threads,
 	},
 	AgentPluginsUpdate: {
-		url, user, password, accessToken, serverId, repo, version, harness, projectDir, agentGlobal, installPath, agentFormat, agentQuiet, dryRun, agentForce, agentAll, agentSlug,
+		url, user, password, accessToken, serverId, repo, version, harness, autoHarness, projectDir, agentGlobal, installPath, agentFormat, agentQuiet, dryRun, agentForce, agentAll, agentSlug, noCache, skipScanCheck, threads,
 	},
 	AgentPluginsDelete: {
 		url, user, password, accessToken, serverId, repo, version, dryRun,
@@ -903,23 +960,86 @@ var commandFlags = map[string][]string{
 		url, user, password, accessToken, serverId, repo, harness, projectDir, agentGlobal, agentFormat, agentLimit, agentSortBy, agentSortOrder, agentCheckUpdates,
 	},
 	AgentPluginsSearch: {
-		url, user, password, accessToken, serverId, repo, agentFormat,
+		url, user, password, accessToken, serverId, repo, searchFormat, searchTag, searchAuthor, searchHarness, searchMinVersion, searchEvidence, searchXrayStatus, searchSortBy, searchSortOrder, searchLimit, searchOffset, threads,
+	},
+	AgentPluginsSync: {
+		url, user, password, accessToken, serverId, repo, projectDir, syncManifest, syncPrune, dryRun, agentFormat, agentQuiet, noCache, skipScanCheck,
+	},
+	AgentPluginsVerify: {
+		url, user, password, accessToken, serverId, harness, projectDir, agentGlobal, installPath, agentFormat, verifyEvidence,
+	},
+	AgentPluginsExport: {
+		url, user, password, accessToken, serverId, repo, bundleOutput, agentQuiet,
+	},
+	AgentPluginsImport: {
+		url, user, password, accessToken, serverId, repo, harness, autoHarness, projectDir, agentGlobal, installPath, bundlePublish, bundlePublicKeys, agentFormat, agentQuiet,
+	},
+	AgentPluginsRollback: {
+		harness, projectDir, agentGlobal, installPath, agentFormat,
+	},
+	AgentPluginsOutdated: {
+		url, user, password, accessToken, serverId, outdatedProjects, agentFormat,
+	},
+	AgentPluginsValidate: {
+		validateStrict, agentFormat,
+	},
+	AgentPluginsDeprecate: {
+		url, user, password, accessToken, serverId, repo, version, deprecateReason, replacementVersion, deprecateUndo, agentQuiet,
+	},
+	AgentPluginsDiff: {
+		url, user, password, accessToken, serverId, repo, diffInstalled, harness, projectDir, agentGlobal, installPath, noCache, agentFormat, agentQuiet,
+	},
+	AgentPluginsConvert: {
+		convertTo, convertOutput, agentFormat,
+	},
+	AgentPluginsMarketplaceGenerate: {
+		url, user, password, accessToken, serverId, repo, harness, marketplaceCheck, dryRun, agentFormat, agentQuiet,
 	},
 	SkillsInstall: {
-		url, user, password, accessToken, serverId, repo, version, harness, projectDir, agentGlobal, installPath, agentFormat, agentQuiet,
+		url, user, password, accessToken, serverId, repo, version, harness, autoHarness, projectDir, agentGlobal, installPath, agentFormat, agentQuiet, frozen, noDeps, noCache, threads, link,
 	},
 	SkillsUpdate: {
-		url, user, password, accessToken, serverId, repo, version, harness, projectDir, agentGlobal, installPath, agentFormat, agentQuiet, dryRun, agentForce,
+		url, user, password, accessToken, serverId, repo, version, harness, autoHarness, projectDir, agentGlobal, installPath, agentFormat, agentQuiet, dryRun, agentForce, noCache, link,
 	},
 	SkillsDelete: {
 		url, user, password, accessToken, serverId, repo, version, dryRun,
 	},
 	SkillsSearch: {
-		url, user, password, accessToken, serverId, repo, agentFormat, propSearch,
+		url, user, password, accessToken, serverId, repo, searchFormat, propSearch, searchTag, searchAuthor, searchHarness, searchMinVersion, searchEvidence, searchXrayStatus, searchSortBy, searchSortOrder, searchLimit, searchOffset, threads,
 	},
 	SkillsList: {
 		url, user, password, accessToken, serverId, repo, harness, projectDir, agentGlobal, agentFormat, agentLimit, agentSortBy, agentSortOrder, agentCheckUpdates,
 	},
+	SkillsSync: {
+		url, user, password, accessToken, serverId, repo, projectDir, syncManifest, syncPrune, dryRun, agentFormat, agentQuiet, noCache, link,
+	},
+	SkillsVerify: {
+		url, user, password, accessToken, serverId, harness, projectDir, agentGlobal, installPath, agentFormat, verifyEvidence,
+	},
+	SkillsExport: {
+		url, user, password, accessToken, serverId, repo, bundleOutput, agentQuiet,
+	},
+	SkillsImport: {
+		url, user, password, accessToken, serverId, repo, harness, autoHarness, projectDir, agentGlobal, installPath, bundlePublish, bundlePublicKeys, agentFormat, agentQuiet,
+	},
+	SkillsGC: {
+		dryRun, agentFormat,
+	},
+	SkillsRollback: {
+		harness, projectDir, agentGlobal, installPath, agentFormat,
+	},
+	SkillsOutdated: {
+		url, user, password, accessToken, serverId, outdatedProjects, agentFormat,
+	},
+	SkillsValidate: {
+		validateStrict, agentFormat,
+	},
+	SkillsDeprecate: {
+		url, user, password, accessToken, serverId, repo, version, deprecateReason, replacementVersion, deprecateUndo, agentQuiet,
+	},
+	SkillsDiff: {
+		url, user, password, accessToken, serverId, repo, diffInstalled, harness, projectDir, agentGlobal, installPath, noCache, agentFormat, agentQuiet,
+	},
 }
 
 var flagsMap = map[string]components.Flag{
@@ -1223,7 +1343,7 @@ var flagsMap = map[string]components.Flag{
 
 	// Agent namespace-specific flags (shared by skills and agent-plugins commands)
 	repo:       components.NewStringFlag(repo, "Repository key in Artifactory.", components.SetMandatoryFalse()),
-	version:    components.NewStringFlag(version, "Package version (semver, e.g. 1.2.0) or \"latest\".", components.SetMandatoryFalse()),
+	version:    components.NewStringFlag(version, "Package version (semver, e.g. 1.2.0) or \"latest\". Install and update also accept npm-style ranges such as ^1.2, ~1.4.0, '>=2 <3', or 1.x.", components.SetMandatoryFalse()),
 	agentQuiet: components.NewBoolFlag(quiet, "[Default: $CI] Set to true to skip interactive prompts.", components.WithBoolDefaultValueFalse()),
 
 	// Skills-specific flags
@@ -1235,8 +1355,9 @@ var flagsMap = map[string]components.Flag{
 	keyAlias:            components.NewStringFlag(keyAlias, "Alias for the signing key. Overrides EVD_KEY_ALIAS env var.", components.SetMandatoryFalse()),
 	agentFormat:         components.NewStringFlag(Format, "Output format: \"table\" (default) or \"json\".", components.SetMandatoryFalse()),
 	propSearch:          components.NewBoolFlag(propSearch, "Use Artifactory property search (skill.name) instead of Skills API search.", components.WithBoolDefaultValueFalse()),
-	skipScan:            components.NewBoolFlag(skipScan, "Skip Xray security scan after publish. Can also be set via JFROG_CLI_SKIP_SKILLS_SCAN=true.", components.WithBoolDefaultValueFalse()),
+	skipScan:            components.NewBoolFlag(skipScan, "Skip Xray security scan after publish. Can also be set via JFROG_CLI_SKIP_SKILLS_SCAN=true for skills or JFROG_CLI_SKIP_AGENT_PLUGINS_SCAN=true for plugins.", components.WithBoolDefaultValueFalse()),
 	autoDeleteOnFailure: components.NewBoolFlag(autoDeleteOnFailure, "Automatically delete the artifact if Xray scan identifies it as malicious.", components.WithBoolDefaultValueFalse()),
+	skipScanCheck:       components.NewBoolFlag(skipScanCheck, "Install even if Xray reports the plugin version as blocked or still being scanned.", components.WithBoolDefaultValueFalse()),
 	harness:             components.NewStringFlag(harness, "Comma-separated harness names for install or update; a single name for list (e.g. cursor, claude-code). Resolved from ~/.jfrog/agents/agent-config.json first, then built-in fallbacks.", components.SetMandatoryFalse()),
 	projectDir:          components.NewStringFlag(projectDir, "Project root directory combined with each agent's project path from config. Default: current directory when --global is not set. Mutually exclusive with --global.", components.SetMandatoryFalse()),
 	agentGlobal:         components.NewBoolFlag(global, "Install, update, or list under each agent's global directory from config instead of under the project root. Mutually exclusive with --project-dir.", components.WithBoolDefaultValueFalse()),
@@ -1244,6 +1365,39 @@ var flagsMap = map[string]components.Flag{
 	agentSortBy:         components.NewStringFlag(sortBy, "Field to sort by. With --repo: updated (default), downloads. With --harness: name (default, only option).", components.SetMandatoryFalse()),
 	agentSortOrder:      components.NewStringFlag(sortOrder, "Sort order for --harness. Supported: asc (default), desc. Not supported with --repo.", components.SetMandatoryFalse()),
 	agentCheckUpdates:   components.NewBoolFlag(checkUpdates, "With --harness only: compare installed skills to the registry (requires jf config server). Adds registry latest and status columns. Not supported with --repo.", components.WithBoolDefaultValueFalse()),
+	syncManifest:        components.NewStringFlag(syncManifest, "Path to the project manifest that declares skills and plugins per harness. Default: <project-dir>/.jfrog/agents.json.", components.SetMandatoryFalse()),
+	syncPrune:           components.NewBoolFlag(syncPrune, "Remove installs made by JFrog CLI that the manifest does not declare for a harness it lists.", components.WithBoolDefaultValueFalse()),
+	frozen:              components.NewBoolFlag(frozen, "Install exactly the versions pinned in agents-lock.json and fail if a downloaded zip checksum differs. Without a slug, installs every package pinned for the selected harnesses.", components.WithBoolDefaultValueFalse()),
+	noDeps:              components.NewBoolFlag(noDeps, "Install only the requested package, without the skills and plugins it declares as dependencies.", components.WithBoolDefaultValueFalse()),
+	link:                components.NewBoolFlag(link, "Extract each skill version once into ~/.jfrog/agents/store and symlink the harness directories to it instead of copying. Update re-points the links; 'jf agent skills gc' removes versions nothing links to.", components.WithBoolDefaultValueFalse()),
+	noCache:             components.NewBoolFlag(noCache, "Download package zips from Artifactory instead of using the local package cache under ~/.jfrog/agents/cache.", components.WithBoolDefaultValueFalse()),
+	bundleOutput:        components.NewStringFlag(bundleOutput, "Path of the bundle archive to write, e.g. agents-bundle.zip.", components.SetMandatoryFalse()),
+	bundlePublish:       components.NewBoolFlag(bundlePublish, "Upload the bundled packages to --repo on the configured server instead of installing them.", components.WithBoolDefaultValueFalse()),
+	bundlePublicKeys:    components.NewStringFlag(bundlePublicKeys, "Comma-separated public key files trusted to have signed the bundled evidence. Without a key that verifies it, a bundled package counts as having no evidence.", components.SetMandatoryFalse()),
+	verifyEvidence:      components.NewBoolFlag(verifyEvidence, "Also re-verify the evidence of each installed version in Artifactory (requires jf config server).", components.WithBoolDefaultValueFalse()),
+	outdatedProjects:    components.NewStringFlag(outdatedProjects, "Comma-separated project root directories to scan in addition to each harness's global directory. Default: current directory.", components.SetMandatoryFalse()),
+	validateStrict:      components.NewBoolFlag(validateStrict, "Fail on validation warnings as well as errors.", components.WithBoolDefaultValueFalse()),
+	skipSecretScan:      components.NewBoolFlag(skipSecretScan, "Publish even if likely secrets are found; findings are reported as warnings. Prefer listing false positives in .jfrog/secrets-allowlist.", components.WithBoolDefaultValueFalse()),
+	marketplaceCheck:    components.NewBoolFlag(marketplaceCheck, "Report plugins whose entry in the marketplace file differs from the repository, and fail if any do, instead of uploading a new file.", components.WithBoolDefaultValueFalse()),
+	deprecateReason:     components.NewStringFlag(deprecateReason, "Why the version is deprecated. Shown when it is installed explicitly and in outdated reports.", components.SetMandatoryFalse()),
+	replacementVersion:  components.NewStringFlag(replacementVersion, "Published version to use instead of the deprecated one.", components.SetMandatoryFalse()),
+	deprecateUndo:       components.NewBoolFlag(deprecateUndo, "Remove the deprecation from the version.", components.WithBoolDefaultValueFalse()),
+	diffInstalled:       components.NewBoolFlag(diffInstalled, "Compare the installed copy in the selected harnesses or --path with the given version instead of comparing two versions.", components.WithBoolDefaultValueFalse()),
+	autoHarness:         components.NewBoolFlag(autoHarness, "Without --harness, use every harness detected on this machine (by its directories or config files, e.g. .claude or CLAUDE.md) instead of asking. Also works in non-interactive runs.", components.WithBoolDefaultValueFalse()),
+	searchFormat:        components.NewStringFlag(Format, "Output format: \"table\" (default), \"json\", or \"csv\".", components.SetMandatoryFalse()),
+	searchTag:           components.NewStringFlag("tag", "Comma-separated tags; only results whose agent.tags property holds all of them are shown.", components.SetMandatoryFalse()),
+	searchAuthor:        components.NewStringFlag("author", "Only show results whose agent.author property matches, ignoring case.", components.SetMandatoryFalse()),
+	searchHarness:       components.NewStringFlag(harness, "Only show results compatible with this harness: those whose agent.harnesses property lists it, or that list no harnesses.", components.SetMandatoryFalse()),
+	searchMinVersion:    components.NewStringFlag("min-version", "Only show results at or above this semver version.", components.SetMandatoryFalse()),
+	searchEvidence:      components.NewBoolFlag("has-evidence", "Only show results whose evidence verifies in Artifactory.", components.WithBoolDefaultValueFalse()),
+	searchXrayStatus:    components.NewStringFlag("xray-status", "Only show results with this Xray status, e.g. APPROVED, BLOCKED, or SCAN_IN_PROGRESS.", components.SetMandatoryFalse()),
+	searchSortBy:        components.NewStringFlag(sortBy, "Sort results by name, version, or repository. Default: the order returned by the server.", components.SetMandatoryFalse()),
+	searchSortOrder:     components.NewStringFlag(sortOrder, "Sort order for --sort-by: asc (default) or desc.", components.SetMandatoryFalse()),
+	searchLimit:         components.NewStringFlag(limit, "Maximum number of results to show, after filtering and sorting.", components.SetMandatoryFalse()),
+	searchOffset:        components.NewStringFlag(offset, "Number of filtered and sorted results to skip before showing results.", components.SetMandatoryFalse()),
+	convertTo:           components.NewStringFlag("to", "[Mandatory] Harness whose plugin layout to convert to: claude, cursor, codex, or copilot.", components.SetMandatoryTrue()),
+	convertOutput:       components.NewStringFlag("output", "Directory to write the converted plugin to. Must not exist or be empty. Default: <path>-<harness>.", components.SetMandatoryFalse()),
+	installConvert:      components.NewBoolFlag(installConvert, "Install a copy converted to the layout of each selected harness the plugin was not published for. Components the harness cannot load, such as hooks, are left out with a warning.", components.WithBoolDefaultValueFalse()),
 }
 
 func GetCommandFlags(cmdKey string) []components.Flag {
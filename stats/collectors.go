@@ -0,0 +1,513 @@
+package stats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/access/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/jpd"
+	lifecycleServices "github.com/jfrog/jfrog-client-go/lifecycle/services"
+	"github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	// defaultCollectorTimeout bounds a collector that sets no Timeout of its own.
+	defaultCollectorTimeout = 60 * time.Second
+	// storageCollectorTimeout is longer, as storage info is slow to compute on large instances.
+	storageCollectorTimeout = 2 * time.Minute
+	// storageTotalRepoKey is the row of storage info that sums all repositories.
+	storageTotalRepoKey = "TOTAL"
+	// replicationStatusThreads bounds the concurrent per-repository replication status calls.
+	replicationStatusThreads = 8
+	// releaseBundleStatusThreads bounds the concurrent per-bundle version searches of the OpenMetrics exposition.
+	releaseBundleStatusThreads = 8
+	// releaseBundleVersionsPageSize is the number of versions requested per release bundle version search.
+	releaseBundleVersionsPageSize = 1000
+)
+
+// Collector gathers one section of the platform stats.
+type Collector struct {
+	// Name selects the collector with --collectors and keys its result.
+	Name string
+	// Product names the section in errors, as in jpd.GenericError.
+	Product     string
+	Description string
+	// Timeout bounds the collector; zero means defaultCollectorTimeout. SetCollectorTimeout overrides it.
+	Timeout time.Duration
+	// OptIn collectors run only when named with --collectors, as they are costly or need admin privileges.
+	OptIn bool
+	// Collect returns the section, or a *jpd.GenericError when it could not be collected. ctx is cancelled when
+	// the timeout passes; the collector should stop issuing API calls then. Collect must not modify sa, which
+	// is read by the run that abandoned it.
+	Collect func(ctx context.Context, sa *ArtifactoryStats) interface{}
+}
+
+// collectorRegistry holds the collectors in the order their sections are printed. Only rt, jpd, rb and project run
+// by default; the others are opt-in.
+var collectorRegistry = []Collector{
+	{Name: "rt", Product: "ARTIFACTORY", Description: "Artifact and binary counts, storage and repository types.", Timeout: storageCollectorTimeout, Collect: singleCall((*ArtifactoryStats).GetArtifactoryStats)},
+	{Name: "jpd", Product: "JPDs", Description: "JFrog Platform Deployments and their health.", Collect: singleCall((*ArtifactoryStats).GetJPDsStats)},
+	{Name: "rb", Product: "RELEASE-BUNDLES", Description: "Release bundles.", Collect: (*ArtifactoryStats).collectReleaseBundlesStats},
+	{Name: "project", Product: "PROJECTS", Description: "Projects.", Collect: singleCall((*ArtifactoryStats).GetProjectsStats)},
+	{Name: "storage", Product: "STORAGE", Description: "Storage used by each repository.", Timeout: storageCollectorTimeout, OptIn: true, Collect: singleCall((*ArtifactoryStats).GetRepositoryStorageStats)},
+	{Name: "top-repos", Product: "TOP-REPOSITORIES", Description: "The largest repositories, up to the display limit.", Timeout: storageCollectorTimeout, OptIn: true, Collect: singleCall((*ArtifactoryStats).GetLargestRepositoriesStats)},
+	{Name: "builds", Product: "BUILDS", Description: "Builds published to Artifactory.", OptIn: true, Collect: (*ArtifactoryStats).collectBuildsStats},
+	{Name: "replication", Product: "REPLICATION", Description: "Replication status of each replicated repository.", OptIn: true, Collect: (*ArtifactoryStats).collectReplicationStats},
+}
+
+// singleCall adapts a collector that makes its API calls through the services manager, which takes no context.
+// Such a collector is abandoned rather than cancelled on timeout: its goroutine and request run until the
+// server answers or the HTTP client gives up, and only its result is dropped.
+func singleCall(collect func(sa *ArtifactoryStats) interface{}) func(context.Context, *ArtifactoryStats) interface{} {
+	return func(_ context.Context, sa *ArtifactoryStats) interface{} {
+		return collect(sa)
+	}
+}
+
+// RegisterCollector adds a collector after the built-in ones. Its name must be unique.
+func RegisterCollector(collector Collector) error {
+	for _, registered := range collectorRegistry {
+		if registered.Name == collector.Name {
+			return fmt.Errorf("stats collector '%s' is already registered", collector.Name)
+		}
+	}
+	collectorRegistry = append(collectorRegistry, collector)
+	return nil
+}
+
+// KnownCollectors returns the names of the registered collectors, in print order.
+func KnownCollectors() []string {
+	names := make([]string, 0, len(collectorRegistry))
+	for _, collector := range collectorRegistry {
+		names = append(names, collector.Name)
+	}
+	return names
+}
+
+// selectedCollectors returns the collectors named with SetCollectors, or those that are not opt-in when none are named.
+func (sa *ArtifactoryStats) selectedCollectors() ([]Collector, error) {
+	if len(sa.Collectors) == 0 {
+		defaults := make([]Collector, 0, len(collectorRegistry))
+		for _, collector := range collectorRegistry {
+			if !collector.OptIn {
+				defaults = append(defaults, collector)
+			}
+		}
+		return defaults, nil
+	}
+	selected := make([]Collector, 0, len(sa.Collectors))
+	for _, collector := range collectorRegistry {
+		for _, name := range sa.Collectors {
+			if strings.EqualFold(strings.TrimSpace(name), collector.Name) {
+				selected = append(selected, collector)
+				break
+			}
+		}
+	}
+	for _, name := range sa.Collectors {
+		if !isKnownCollector(strings.TrimSpace(name)) {
+			return nil, fmt.Errorf("unknown stats collector '%s' (known: %s)", name, strings.Join(KnownCollectors(), ", "))
+		}
+	}
+	return selected, nil
+}
+
+func isKnownCollector(name string) bool {
+	for _, collector := range collectorRegistry {
+		if strings.EqualFold(name, collector.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// collectStats runs the selected collectors concurrently and returns their results by collector name.
+// A collector that fails or times out yields a *jpd.GenericError, so one slow endpoint does not block the rest.
+func (sa *ArtifactoryStats) collectStats() (map[string]interface{}, error) {
+	collectors, err := sa.selectedCollectors()
+	if err != nil {
+		return nil, err
+	}
+	sa.storage.Store(&storageInfoCache{})
+	results := make(map[string]interface{}, len(collectors))
+	var resultsMu sync.Mutex
+	var wg sync.WaitGroup
+	for _, collector := range collectors {
+		wg.Add(1)
+		go func(collector Collector) {
+			defer wg.Done()
+			result := sa.runCollector(collector)
+			resultsMu.Lock()
+			results[collector.Name] = result
+			resultsMu.Unlock()
+		}(collector)
+	}
+	wg.Wait()
+	// The Artifactory summary shows the project count, which only the project collector knows.
+	if projects, ok := results["project"].([]services.Project); ok {
+		sa.ProjectCount = len(projects)
+		if summary, ok := results["rt"].(*ArtifactoryStatsSummary); ok {
+			summary.ProjectsCount = len(projects)
+		}
+	}
+	return results, nil
+}
+
+// runCollector runs collector, giving up once its timeout passes; its result is dropped. The context of an abandoned
+// collector is cancelled, so collectors that check it make no further API calls. Calls already in flight, and those
+// of singleCall collectors, are not interrupted.
+func (sa *ArtifactoryStats) runCollector(collector Collector) interface{} {
+	timeout := collector.Timeout
+	if sa.CollectorTimeout > 0 {
+		timeout = sa.CollectorTimeout
+	}
+	if timeout <= 0 {
+		timeout = defaultCollectorTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	done := make(chan interface{}, 1)
+	go func() {
+		done <- collector.Collect(ctx, sa)
+	}()
+	select {
+	case result := <-done:
+		return result
+	case <-ctx.Done():
+		return jpd.NewGenericError(collector.Product, fmt.Errorf("timed out after %s", timeout))
+	}
+}
+
+// storageInfoCache shares one storage info call between the collectors of a run.
+type storageInfoCache struct {
+	once sync.Once
+	info *clientutils.StorageInfo
+	err  error
+}
+
+// getStorageInfo returns the storage info of the current run, fetching it on first use. Collectors abandoned by
+// an earlier run keep the cache they started with.
+func (sa *ArtifactoryStats) getStorageInfo() (*clientutils.StorageInfo, error) {
+	cache := sa.storage.Load()
+	if cache == nil {
+		return sa.ServicesManager.GetStorageInfo()
+	}
+	cache.once.Do(func() {
+		cache.info, cache.err = sa.ServicesManager.GetStorageInfo()
+	})
+	return cache.info, cache.err
+}
+
+// RepositoryStorage is the storage used by one repository.
+type RepositoryStorage struct {
+	RepoKey        string `json:"repoKey" display:"Repository"`
+	RepoType       string `json:"repoType" display:"Type"`
+	PackageType    string `json:"packageType,omitempty" display:"Package Type"`
+	FilesCount     int64  `json:"filesCount" display:"Files"`
+	UsedSpaceBytes int64  `json:"usedSpaceBytes"`
+	UsedSpace      string `json:"usedSpace" display:"Used Space"`
+	Percentage     string `json:"percentage,omitempty" display:"Percentage"`
+}
+
+// RepositoryStorageBreakdown is the storage used by every repository, sorted by repository key.
+type RepositoryStorageBreakdown struct {
+	Repositories []RepositoryStorage `json:"repositories"`
+}
+
+// LargestRepositories is the repositories using the most storage, largest first.
+type LargestRepositories struct {
+	Repositories []RepositoryStorage `json:"repositories"`
+}
+
+// BuildSummary is one build published to Artifactory.
+type BuildSummary struct {
+	Name        string `json:"name" display:"Name"`
+	LastStarted string `json:"lastStarted,omitempty" display:"Last Started"`
+}
+
+// BuildsStats is the builds published to Artifactory.
+type BuildsStats struct {
+	BuildsCount int            `json:"buildsCount"`
+	Builds      []BuildSummary `json:"builds"`
+}
+
+// ReplicationStatus is the replication of one repository and the outcome of its last run.
+type ReplicationStatus struct {
+	RepoKey       string `json:"repoKey" display:"Repository"`
+	URL           string `json:"url,omitempty" display:"Target"`
+	Enabled       bool   `json:"enabled" display:"Enabled"`
+	Status        string `json:"status,omitempty" display:"Status"`
+	LastCompleted string `json:"lastCompleted,omitempty" display:"Last Completed"`
+}
+
+// ReplicationStats is the replication status of every replicated repository.
+type ReplicationStats struct {
+	Replications []ReplicationStatus `json:"replications"`
+}
+
+func (sa *ArtifactoryStats) repositoryStorage() ([]RepositoryStorage, error) {
+	storageInfo, err := sa.getStorageInfo()
+	if err != nil {
+		return nil, err
+	}
+	repositories := make([]RepositoryStorage, 0, len(storageInfo.RepositoriesSummaryList))
+	for _, summary := range storageInfo.RepositoriesSummaryList {
+		if summary.RepoKey == storageTotalRepoKey {
+			continue
+		}
+		filesCount, _ := summary.FilesCount.Int64()
+		usedSpaceBytes, _ := summary.UsedSpaceInBytes.Int64()
+		repositories = append(repositories, RepositoryStorage{
+			RepoKey:        summary.RepoKey,
+			RepoType:       summary.RepoType,
+			PackageType:    summary.PackageType,
+			FilesCount:     filesCount,
+			UsedSpaceBytes: usedSpaceBytes,
+			UsedSpace:      summary.UsedSpace,
+			Percentage:     summary.Percentage,
+		})
+	}
+	return repositories, nil
+}
+
+// usedSpaceInBytes returns the exact storage used by all repositories: the TOTAL row of storage info, or the sum of
+// the repository rows when there is none. It returns nil when storage info has no byte counts, as on old servers.
+func usedSpaceInBytes(storageInfo *clientutils.StorageInfo) *int64 {
+	var sum int64
+	found := false
+	for _, summary := range storageInfo.RepositoriesSummaryList {
+		usedSpace, err := summary.UsedSpaceInBytes.Int64()
+		if err != nil {
+			continue
+		}
+		if summary.RepoKey == storageTotalRepoKey {
+			return &usedSpace
+		}
+		sum += usedSpace
+		found = true
+	}
+	if !found {
+		return nil
+	}
+	return &sum
+}
+
+func (sa *ArtifactoryStats) GetRepositoryStorageStats() interface{} {
+	repositories, err := sa.repositoryStorage()
+	if err != nil {
+		return jpd.NewGenericError("STORAGE", fmt.Errorf("failed to call STORAGE API: %w", err))
+	}
+	sort.Slice(repositories, func(i, j int) bool { return repositories[i].RepoKey < repositories[j].RepoKey })
+	return &RepositoryStorageBreakdown{Repositories: repositories}
+}
+
+func (sa *ArtifactoryStats) GetLargestRepositoriesStats() interface{} {
+	repositories, err := sa.repositoryStorage()
+	if err != nil {
+		return jpd.NewGenericError("TOP-REPOSITORIES", fmt.Errorf("failed to call STORAGE API: %w", err))
+	}
+	sort.SliceStable(repositories, func(i, j int) bool { return repositories[i].UsedSpaceBytes > repositories[j].UsedSpaceBytes })
+	if sa.DisplayLimit > 0 && len(repositories) > sa.DisplayLimit {
+		repositories = repositories[:sa.DisplayLimit]
+	}
+	return &LargestRepositories{Repositories: repositories}
+}
+
+func (sa *ArtifactoryStats) GetBuildsStats() interface{} {
+	return sa.collectBuildsStats(context.Background())
+}
+
+func (sa *ArtifactoryStats) collectBuildsStats(ctx context.Context) interface{} {
+	var response struct {
+		Builds []struct {
+			URI         string `json:"uri"`
+			LastStarted string `json:"lastStarted"`
+		} `json:"builds"`
+	}
+	status, err := sa.getArtifactoryJSON(ctx, "api/build", &response)
+	if status == http.StatusNotFound {
+		// Artifactory answers 404 when no build was ever published.
+		return &BuildsStats{Builds: []BuildSummary{}}
+	}
+	if err != nil {
+		return jpd.NewGenericError("BUILDS", fmt.Errorf("failed to call BUILDS API: %w", err))
+	}
+	builds := make([]BuildSummary, 0, len(response.Builds))
+	for _, build := range response.Builds {
+		builds = append(builds, BuildSummary{Name: strings.TrimPrefix(build.URI, "/"), LastStarted: build.LastStarted})
+	}
+	sort.Slice(builds, func(i, j int) bool { return builds[i].Name < builds[j].Name })
+	return &BuildsStats{BuildsCount: len(builds), Builds: builds}
+}
+
+// collectReleaseBundlesStats lists the release bundles. The OpenMetrics exposition also counts their versions by
+// status, which takes a version search per bundle, so plain output does not pay for it.
+func (sa *ArtifactoryStats) collectReleaseBundlesStats(ctx context.Context) interface{} {
+	result := sa.GetReleaseBundlesStats()
+	releaseBundles, ok := result.(*ReleaseBundleResponse)
+	if !ok || (sa.Format != openMetricsFormat && sa.ServeAddress == "") {
+		return result
+	}
+	versionsByStatus, err := countReleaseBundleVersions(ctx, releaseBundles.ReleaseBundles, sa.LifecycleServiceManager.ReleaseBundlesSearchVersions)
+	if err != nil {
+		// The bundles are still reported; only the breakdown by status is left out.
+		log.Warn("Failed to count release bundle versions by status:", err.Error())
+		return releaseBundles
+	}
+	releaseBundles.VersionsByStatus = versionsByStatus
+	return releaseBundles
+}
+
+// countReleaseBundleVersions counts the versions of every bundle by status, searching up to
+// releaseBundleStatusThreads bundles at a time. It fails if any search fails, so a partial count is never reported.
+func countReleaseBundleVersions(ctx context.Context, bundles []ReleaseBundleInfo, search func(string, lifecycleServices.GetSearchOptionalQueryParams) (lifecycleServices.ReleaseBundleVersionsResponse, error)) (map[string]int, error) {
+	counts := make(map[string]int)
+	var countsMu sync.Mutex
+	var firstErr error
+	bundlesCh := make(chan ReleaseBundleInfo)
+	var wg sync.WaitGroup
+	for range min(releaseBundleStatusThreads, len(bundles)) {
+		wg.Go(func() {
+			for bundle := range bundlesCh {
+				bundleCounts, err := searchReleaseBundleVersions(ctx, bundle, search)
+				countsMu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = fmt.Errorf("release bundle '%s': %w", bundle.ReleaseBundleName, err)
+				}
+				for status, count := range bundleCounts {
+					counts[status] += count
+				}
+				countsMu.Unlock()
+			}
+		})
+	}
+	for _, bundle := range bundles {
+		bundlesCh <- bundle
+	}
+	close(bundlesCh)
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return counts, nil
+}
+
+// searchReleaseBundleVersions counts the versions of one bundle by status, page by page. It makes no call once ctx
+// is done.
+func searchReleaseBundleVersions(ctx context.Context, bundle ReleaseBundleInfo, search func(string, lifecycleServices.GetSearchOptionalQueryParams) (lifecycleServices.ReleaseBundleVersionsResponse, error)) (map[string]int, error) {
+	counts := make(map[string]int)
+	params := lifecycleServices.GetSearchOptionalQueryParams{Limit: releaseBundleVersionsPageSize, Project: bundle.ProjectKey}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		response, err := search(bundle.ReleaseBundleName, params)
+		if err != nil {
+			return nil, err
+		}
+		for _, version := range response.ReleaseBundles {
+			status := strings.ToLower(version.Status)
+			if status == "" {
+				status = "unknown"
+			}
+			counts[status]++
+		}
+		params.Offset += len(response.ReleaseBundles)
+		if len(response.ReleaseBundles) == 0 || params.Offset >= response.Total {
+			return counts, nil
+		}
+	}
+}
+
+func (sa *ArtifactoryStats) GetReplicationStats() interface{} {
+	return sa.collectReplicationStats(context.Background())
+}
+
+func (sa *ArtifactoryStats) collectReplicationStats(ctx context.Context) interface{} {
+	var configs []struct {
+		RepoKey string `json:"repoKey"`
+		URL     string `json:"url"`
+		Enabled bool   `json:"enabled"`
+	}
+	if _, err := sa.getArtifactoryJSON(ctx, "api/replications", &configs); err != nil {
+		if strings.Contains(err.Error(), "401") || strings.Contains(err.Error(), "403") {
+			return jpd.NewGenericError("REPLICATION", fmt.Errorf("need admin privileges"))
+		}
+		return jpd.NewGenericError("REPLICATION", fmt.Errorf("failed to call REPLICATION API: %w", err))
+	}
+	repoKeys := make([]string, 0, len(configs))
+	statuses := make(map[string]*ReplicationStatus)
+	for _, config := range configs {
+		if _, found := statuses[config.RepoKey]; !found {
+			statuses[config.RepoKey] = &ReplicationStatus{}
+			repoKeys = append(repoKeys, config.RepoKey)
+		}
+	}
+	sa.fetchReplicationStatuses(ctx, repoKeys, statuses)
+	replications := make([]ReplicationStatus, 0, len(configs))
+	for _, config := range configs {
+		status := *statuses[config.RepoKey]
+		status.RepoKey, status.URL, status.Enabled = config.RepoKey, config.URL, config.Enabled
+		replications = append(replications, status)
+	}
+	sort.SliceStable(replications, func(i, j int) bool { return replications[i].RepoKey < replications[j].RepoKey })
+	return &ReplicationStats{Replications: replications}
+}
+
+// fetchReplicationStatuses fills statuses with the last run of each repository, on up to replicationStatusThreads
+// concurrent calls. Repositories not reached before ctx is done are reported with an unknown status.
+func (sa *ArtifactoryStats) fetchReplicationStatuses(ctx context.Context, repoKeys []string, statuses map[string]*ReplicationStatus) {
+	repoKeysCh := make(chan string)
+	var wg sync.WaitGroup
+	for range min(replicationStatusThreads, len(repoKeys)) {
+		wg.Go(func() {
+			for repoKey := range repoKeysCh {
+				var response struct {
+					Status        string `json:"status"`
+					LastCompleted string `json:"lastCompleted"`
+				}
+				if _, err := sa.getArtifactoryJSON(ctx, "api/replication/"+repoKey, &response); err != nil {
+					response.Status = "unknown: " + err.Error()
+				}
+				// Each worker writes only the entry of its own repository.
+				statuses[repoKey].Status, statuses[repoKey].LastCompleted = response.Status, response.LastCompleted
+			}
+		})
+	}
+	for _, repoKey := range repoKeys {
+		repoKeysCh <- repoKey
+	}
+	close(repoKeysCh)
+	wg.Wait()
+}
+
+// getArtifactoryJSON gets an Artifactory REST API path and decodes its JSON response into target, returning the
+// HTTP status when a response was received. It makes no call once ctx is done.
+func (sa *ArtifactoryStats) getArtifactoryJSON(ctx context.Context, apiPath string, target interface{}) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	serviceDetails := sa.ServicesManager.GetConfig().GetServiceDetails()
+	httpDetails := serviceDetails.CreateHttpClientDetails()
+	artURL := utils.AddTrailingSlashIfNeeded(serviceDetails.GetUrl())
+	resp, body, _, err := sa.ServicesManager.Client().SendGet(artURL+apiPath, true, &httpDetails)
+	if err != nil {
+		return 0, err
+	}
+	if err := errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return resp.StatusCode, err
+	}
+	if err := json.Unmarshal(body, target); err != nil {
+		return resp.StatusCode, fmt.Errorf("error parsing JSON: %w", err)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,115 @@
+package stats
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/access/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/jpd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withCollectorRegistry replaces the registry for one test.
+func withCollectorRegistry(t *testing.T, collectors []Collector) {
+	t.Helper()
+	restore := collectorRegistry
+	t.Cleanup(func() { collectorRegistry = restore })
+	collectorRegistry = collectors
+}
+
+func staticCollector(name string, result interface{}) Collector {
+	return Collector{Name: name, Product: name, Collect: func(context.Context, *ArtifactoryStats) interface{} { return result }}
+}
+
+func TestSelectedCollectors(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   []string
+		want    []string
+		wantErr string
+	}{
+		{name: "defaults when none named", want: []string{"rt", "jpd", "rb", "project"}},
+		{name: "registry order", names: []string{"storage", "rt"}, want: []string{"rt", "storage"}},
+		{name: "opt-in when named", names: []string{"replication", "builds"}, want: []string{"builds", "replication"}},
+		{name: "case-insensitive", names: []string{"RT", " Top-Repos "}, want: []string{"rt", "top-repos"}},
+		{name: "unknown name", names: []string{"rt", "nope"}, wantErr: "unknown stats collector 'nope' (known: rt, jpd,"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sa := NewArtifactoryStatsCommand().SetCollectors(tt.names)
+			collectors, err := sa.selectedCollectors()
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			names := make([]string, 0, len(collectors))
+			for _, collector := range collectors {
+				names = append(names, collector.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestRunCollector_Timeout(t *testing.T) {
+	stopped := make(chan struct{})
+	slow := Collector{Name: "slow", Product: "SLOW", Collect: func(ctx context.Context, _ *ArtifactoryStats) interface{} {
+		<-ctx.Done()
+		close(stopped)
+		return nil
+	}}
+	sa := NewArtifactoryStatsCommand().SetCollectorTimeout(10 * time.Millisecond)
+
+	result := sa.runCollector(slow)
+	genericErr, ok := result.(*jpd.GenericError)
+	require.True(t, ok, "expected a *jpd.GenericError, got %T", result)
+	assert.Equal(t, "SLOW", genericErr.Product)
+	assert.EqualError(t, genericErr.Err, "timed out after 10ms")
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("the context of the abandoned collector was not cancelled")
+	}
+}
+
+func TestRegisterCollector_Duplicate(t *testing.T) {
+	withCollectorRegistry(t, append([]Collector{}, collectorRegistry...))
+
+	require.NoError(t, RegisterCollector(staticCollector("custom", nil)))
+	assert.Equal(t, "custom", KnownCollectors()[len(KnownCollectors())-1])
+	assert.EqualError(t, RegisterCollector(staticCollector("custom", nil)), "stats collector 'custom' is already registered")
+	assert.EqualError(t, RegisterCollector(staticCollector("rt", nil)), "stats collector 'rt' is already registered")
+}
+
+func TestCollectStats_ProjectCount(t *testing.T) {
+	withCollectorRegistry(t, []Collector{
+		staticCollector("rt", &ArtifactoryStatsSummary{}),
+		staticCollector("project", []services.Project{{ProjectKey: "a"}, {ProjectKey: "b"}}),
+	})
+	sa := NewArtifactoryStatsCommand()
+
+	results, err := sa.collectStats()
+	require.NoError(t, err)
+	assert.Equal(t, 2, results["rt"].(*ArtifactoryStatsSummary).ProjectsCount)
+	assert.Equal(t, 2, sa.ProjectCount)
+}
+
+func TestUsedSpaceInBytes(t *testing.T) {
+	row := func(repoKey, usedSpaceInBytes string) clientutils.RepositorySummary {
+		return clientutils.RepositorySummary{RepoKey: repoKey, UsedSpaceInBytes: json.Number(usedSpaceInBytes)}
+	}
+	total := usedSpaceInBytes(&clientutils.StorageInfo{RepositoriesSummaryList: []clientutils.RepositorySummary{row("libs", "100"), row("TOTAL", "1649267441664")}})
+	require.NotNil(t, total)
+	assert.Equal(t, int64(1649267441664), *total)
+
+	sum := usedSpaceInBytes(&clientutils.StorageInfo{RepositoriesSummaryList: []clientutils.RepositorySummary{row("libs", "100"), row("docker", "23")}})
+	require.NotNil(t, sum)
+	assert.Equal(t, int64(123), *sum)
+
+	assert.Nil(t, usedSpaceInBytes(&clientutils.StorageInfo{RepositoriesSummaryList: []clientutils.RepositorySummary{row("libs", "")}}))
+}
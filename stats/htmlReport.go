@@ -0,0 +1,323 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/access/services"
+	"github.com/jfrog/jfrog-client-go/jpd"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const htmlFormat = "html"
+
+// StatsReport is everything one stats run collected, rendered as a whole by the html format.
+type StatsReport struct {
+	ServerUrl string
+	Generated time.Time
+	// Results are the collector results by collector name.
+	Results map[string]interface{}
+	// Comparison is the change since the snapshot given with --compare, if any.
+	Comparison *StatsComparison
+}
+
+type htmlReport struct {
+	ServerUrl string
+	Generated string
+	Sections  []htmlSection
+	RawJSON   string
+}
+
+type htmlSection struct {
+	Title  string
+	Error  string
+	Tables []htmlTable
+	Charts []htmlChart
+}
+
+type htmlTable struct {
+	Title   string
+	Headers []string
+	Rows    [][]htmlCell
+	// More is how many rows were left out by the display limit.
+	More int
+}
+
+// htmlCell is a table cell; Sort, when set, is the numeric value the column sorts by.
+type htmlCell struct {
+	Text string
+	Sort string
+}
+
+type htmlChart struct {
+	Title string
+	Bars  []htmlBar
+}
+
+type htmlBar struct {
+	Label   string
+	Display string
+	Percent float64
+}
+
+// PrintHtml prints the report as a self-contained HTML page: styles, scripts and the raw JSON are inline, so the
+// page works offline.
+func (rw *GenericResultsWriter) PrintHtml() error {
+	report, ok := rw.data.(*StatsReport)
+	if !ok {
+		return fmt.Errorf("the %s format renders a whole stats report, got %T", htmlFormat, rw.data)
+	}
+	page, err := renderHTMLReport(report, rw.displayLimit)
+	if err != nil {
+		return err
+	}
+	log.Output(page)
+	return nil
+}
+
+func renderHTMLReport(report *StatsReport, displayLimit int) (string, error) {
+	rawJSON, err := json.MarshalIndent(report.Results, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	view := htmlReport{
+		ServerUrl: report.ServerUrl,
+		Generated: report.Generated.Format(time.RFC1123),
+		RawJSON:   string(rawJSON),
+	}
+	for _, collector := range collectorRegistry {
+		result, found := report.Results[collector.Name]
+		if !found {
+			continue
+		}
+		view.Sections = append(view.Sections, newHTMLSection(result, displayLimit))
+	}
+	if report.Comparison != nil {
+		view.Sections = append(view.Sections, newComparisonSection(report.Comparison))
+	}
+	var page strings.Builder
+	if err := htmlReportTemplate.Execute(&page, view); err != nil {
+		return "", fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return page.String(), nil
+}
+
+func newHTMLSection(data interface{}, displayLimit int) htmlSection {
+	switch v := data.(type) {
+	case *ArtifactoryStatsSummary:
+		repoTypeCounts := countRepositoryTypes(v)
+		repoTypes := htmlTable{Title: "Repositories by Type", Headers: []string{"Repository Type", "Count"}}
+		for _, repoType := range sortedKeys(repoTypeCounts) {
+			repoTypes.Rows = append(repoTypes.Rows, []htmlCell{{Text: repoType}, countCell(int64(repoTypeCounts[repoType]))})
+		}
+		summary := htmlTable{Headers: []string{"Metric", "Value"}, Rows: [][]htmlCell{
+			{{Text: "Total Projects"}, countCell(int64(v.ProjectsCount))},
+			{{Text: "Total Binaries"}, {Text: v.TotalBinariesCount}},
+			{{Text: "Total Binaries Size"}, {Text: v.TotalBinariesSize}},
+			{{Text: "Total Artifacts"}, {Text: v.TotalArtifactsCount}},
+			{{Text: "Total Artifacts Size"}, {Text: v.TotalArtifactsSize}},
+			{{Text: "Storage Type"}, {Text: v.StorageType}},
+		}}
+		return htmlSection{Title: "Artifacts Summary", Tables: []htmlTable{summary, repoTypes}, Charts: []htmlChart{countChart("Repositories by Type", repoTypeCounts)}}
+	case []services.Project:
+		table := htmlTable{Headers: []string{"Project Key", "Display Name"}}
+		for _, project := range v {
+			table.Rows = append(table.Rows, []htmlCell{{Text: project.ProjectKey}, {Text: project.DisplayName}})
+		}
+		return htmlSection{Title: "Projects Summary", Tables: []htmlTable{limitHTMLTable(table, displayLimit)}}
+	case *[]JPD:
+		table := htmlTable{Headers: []string{"Name", "Status", "URL"}}
+		health := map[string]int{}
+		for _, jpd := range *v {
+			table.Rows = append(table.Rows, []htmlCell{{Text: jpd.Name}, {Text: jpd.Status.Code}, {Text: jpd.URL}})
+			health[jpd.Status.Code]++
+		}
+		return htmlSection{Title: "JFrog Platform Deployments (JPDs)", Tables: []htmlTable{limitHTMLTable(table, displayLimit)}, Charts: []htmlChart{countChart("JPDs by Status", health)}}
+	case *ReleaseBundleResponse:
+		table := htmlTable{Headers: []string{"Release Bundle", "Project Key", "Repository Key"}}
+		byProject := map[string]int{}
+		for _, rb := range v.ReleaseBundles {
+			table.Rows = append(table.Rows, []htmlCell{{Text: rb.ReleaseBundleName}, {Text: rb.ProjectKey}, {Text: rb.RepositoryKey}})
+			byProject[rb.ProjectKey]++
+		}
+		return htmlSection{Title: "Release Bundles", Tables: []htmlTable{limitHTMLTable(table, displayLimit)}, Charts: []htmlChart{countChart("Release Bundles by Project", byProject)}}
+	case *RepositoryStorageBreakdown:
+		return repositoryStorageSection("Repository Storage", v.Repositories, displayLimit)
+	case *LargestRepositories:
+		return repositoryStorageSection("Largest Repositories", v.Repositories, displayLimit)
+	case *BuildsStats:
+		table := htmlTable{Headers: []string{"Build Name", "Last Started"}}
+		for _, build := range v.Builds {
+			table.Rows = append(table.Rows, []htmlCell{{Text: build.Name}, {Text: build.LastStarted}})
+		}
+		return htmlSection{Title: fmt.Sprintf("Builds (%d)", v.BuildsCount), Tables: []htmlTable{limitHTMLTable(table, displayLimit)}}
+	case *ReplicationStats:
+		table := htmlTable{Headers: []string{"Repository", "Target", "Enabled", "Status", "Last Completed"}}
+		byStatus := map[string]int{}
+		for _, replication := range v.Replications {
+			table.Rows = append(table.Rows, []htmlCell{{Text: replication.RepoKey}, {Text: replication.URL}, {Text: strconv.FormatBool(replication.Enabled)}, {Text: replication.Status}, {Text: replication.LastCompleted}})
+			byStatus[replication.Status]++
+		}
+		return htmlSection{Title: "Replication", Tables: []htmlTable{limitHTMLTable(table, displayLimit)}, Charts: []htmlChart{countChart("Replications by Status", byStatus)}}
+	case *jpd.GenericError:
+		return htmlSection{Title: v.Product, Error: v.Error()}
+	}
+	return htmlSection{Title: fmt.Sprintf("%T", data), Error: "No HTML rendering for this section; see the raw JSON."}
+}
+
+func repositoryStorageSection(title string, repositories []RepositoryStorage, displayLimit int) htmlSection {
+	table := htmlTable{Headers: []string{"Repository", "Type", "Package Type", "Files", "Used Space", "Percentage"}}
+	chart := htmlChart{Title: "Used Space"}
+	var largest int64
+	for _, repo := range repositories {
+		largest = max(largest, repo.UsedSpaceBytes)
+	}
+	for i, repo := range repositories {
+		table.Rows = append(table.Rows, []htmlCell{
+			{Text: repo.RepoKey}, {Text: repo.RepoType}, {Text: repo.PackageType}, countCell(repo.FilesCount),
+			{Text: repo.UsedSpace, Sort: strconv.FormatInt(repo.UsedSpaceBytes, 10)}, {Text: repo.Percentage},
+		})
+		if i < displayLimit {
+			chart.Bars = append(chart.Bars, htmlBar{Label: repo.RepoKey, Display: repo.UsedSpace, Percent: barPercent(repo.UsedSpaceBytes, largest)})
+		}
+	}
+	return htmlSection{Title: title, Tables: []htmlTable{limitHTMLTable(table, displayLimit)}, Charts: []htmlChart{chart}}
+}
+
+func newComparisonSection(comparison *StatsComparison) htmlSection {
+	table := htmlTable{Title: "Changes since " + comparison.From.Format(time.RFC1123), Headers: []string{"Metric", "Previous", "Current", "Change"}}
+	for _, delta := range comparison.Deltas {
+		table.Rows = append(table.Rows, []htmlCell{
+			{Text: delta.Metric},
+			metricCell(delta.Metric, delta.Previous),
+			metricCell(delta.Metric, delta.Current),
+			{Text: formatMetricChange(delta), Sort: strconv.FormatInt(delta.Change, 10)},
+		})
+	}
+	return htmlSection{Title: "Changes", Tables: []htmlTable{table}}
+}
+
+func countCell(count int64) htmlCell {
+	value := strconv.FormatInt(count, 10)
+	return htmlCell{Text: value, Sort: value}
+}
+
+func metricCell(metric string, value *int64) htmlCell {
+	cell := htmlCell{Text: formatMetricValue(metric, value)}
+	if value != nil {
+		cell.Sort = strconv.FormatInt(*value, 10)
+	}
+	return cell
+}
+
+// limitHTMLTable keeps the first displayLimit rows; the raw JSON at the end of the report has all of them.
+func limitHTMLTable(table htmlTable, displayLimit int) htmlTable {
+	if displayLimit > 0 && len(table.Rows) > displayLimit {
+		table.More = len(table.Rows) - displayLimit
+		table.Rows = table.Rows[:displayLimit]
+	}
+	return table
+}
+
+// countChart charts counts by label, largest first.
+func countChart(title string, counts map[string]int) htmlChart {
+	labels := sortedKeys(counts)
+	sort.SliceStable(labels, func(i, j int) bool { return counts[labels[i]] > counts[labels[j]] })
+	chart := htmlChart{Title: title}
+	if len(labels) == 0 {
+		return chart
+	}
+	largest := int64(counts[labels[0]])
+	for _, label := range labels {
+		count := int64(counts[label])
+		if label == "" {
+			label = "(none)"
+		}
+		chart.Bars = append(chart.Bars, htmlBar{Label: label, Display: strconv.FormatInt(count, 10), Percent: barPercent(count, largest)})
+	}
+	return chart
+}
+
+func barPercent(value, largest int64) float64 {
+	if largest <= 0 {
+		return 0
+	}
+	return float64(value) * 100 / float64(largest)
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>JFrog Platform Stats</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 1100px; color: #1f2933; }
+h1 { margin-bottom: 0; }
+.meta { color: #616e7c; margin-top: .25em; }
+section { border: 1px solid #d9e2ec; border-radius: 6px; padding: 1em 1.5em; margin: 1.5em 0; }
+h2 { color: #0b69a3; margin-top: 0; }
+table { border-collapse: collapse; width: 100%; margin: .5em 0 1em; }
+th, td { text-align: left; padding: .35em .6em; border-bottom: 1px solid #e4e7eb; }
+th { background: #f0f4f8; cursor: pointer; user-select: none; }
+th::after { content: " \2195"; color: #9aa5b1; }
+.more { color: #8d6c00; font-style: italic; }
+.error { color: #ab091e; font-weight: bold; }
+.chart { margin: .5em 0 1em; }
+.bar { display: flex; align-items: center; margin: .2em 0; }
+.bar .label { width: 14em; overflow: hidden; text-overflow: ellipsis; white-space: nowrap; }
+.bar .track { flex: 1; background: #f0f4f8; border-radius: 3px; margin: 0 .6em; }
+.bar .fill { display: block; background: #2186eb; height: 1em; border-radius: 3px; min-width: 2px; }
+pre { background: #f5f7fa; padding: 1em; overflow: auto; max-height: 30em; }
+</style>
+</head>
+<body>
+<h1>JFrog Platform Stats</h1>
+<p class="meta">{{with .ServerUrl}}{{.}} &middot; {{end}}Generated {{.Generated}}</p>
+{{range .Sections}}<section>
+<h2>{{.Title}}</h2>
+{{with .Error}}<p class="error">{{.}}</p>{{end}}
+{{range .Charts}}{{if .Bars}}<div class="chart"><h3>{{.Title}}</h3>
+{{range .Bars}}<div class="bar"><span class="label" title="{{.Label}}">{{.Label}}</span><span class="track"><span class="fill" style="width: {{printf "%.1f" .Percent}}%"></span></span><span>{{.Display}}</span></div>
+{{end}}</div>{{end}}{{end}}
+{{range .Tables}}{{with .Title}}<h3>{{.}}</h3>{{end}}
+{{if .Rows}}<table class="sortable">
+<thead><tr>{{range .Headers}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>{{range .Rows}}<tr>{{range .}}<td{{with .Sort}} data-sort="{{.}}"{{end}}>{{.Text}}</td>{{end}}</tr>
+{{end}}</tbody>
+</table>{{else}}<p>No data found.</p>{{end}}
+{{if .More}}<p class="more">...and {{.More}} more. See the raw JSON below for the complete list.</p>{{end}}
+{{end}}</section>
+{{end}}<section>
+<h2>Raw JSON</h2>
+<details><summary>Show the collected data</summary><pre id="raw-json">{{.RawJSON}}</pre></details>
+</section>
+<script>
+document.querySelectorAll("table.sortable").forEach(function (table) {
+  table.querySelectorAll("th").forEach(function (th, column) {
+    var ascending = true;
+    th.addEventListener("click", function () {
+      var body = table.tBodies[0];
+      var rows = Array.prototype.slice.call(body.rows);
+      var key = function (row) {
+        var cell = row.cells[column];
+        var sort = cell.getAttribute("data-sort");
+        return sort !== null ? parseFloat(sort) : cell.textContent.toLowerCase();
+      };
+      rows.sort(function (a, b) {
+        var x = key(a), y = key(b);
+        return (x < y ? -1 : x > y ? 1 : 0) * (ascending ? 1 : -1);
+      });
+      ascending = !ascending;
+      rows.forEach(function (row) { body.appendChild(row); });
+    });
+  });
+});
+</script>
+</body>
+</html>
+`))
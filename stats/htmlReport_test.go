@@ -0,0 +1,63 @@
+package stats
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/jpd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const hostileRepoKey = `<script>alert('x')</script>&"co"`
+
+func TestRenderHTMLReport(t *testing.T) {
+	previous, current := int64(10), int64(12)
+	report := &StatsReport{
+		ServerUrl: "https://acme.jfrog.io",
+		Generated: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Results: map[string]interface{}{
+			"rt": &ArtifactoryStatsSummary{
+				TotalArtifactsCount: "1,234",
+				TotalArtifactsSize:  "1.50 GB",
+				RepositoriesDetails: []services.RepositoryDetails{{Key: "libs-release", Type: "LOCAL"}, {Key: "maven-remote", Type: "REMOTE"}},
+			},
+			"rb": jpd.NewGenericError("RELEASE-BUNDLES", errors.New("need admin privileges")),
+			"storage": &RepositoryStorageBreakdown{Repositories: []RepositoryStorage{
+				{RepoKey: hostileRepoKey, RepoType: "LOCAL", FilesCount: 3, UsedSpaceBytes: 2048, UsedSpace: "2.00 KB"},
+				{RepoKey: "libs-release", RepoType: "LOCAL", FilesCount: 1, UsedSpaceBytes: 1024, UsedSpace: "1.00 KB"},
+			}},
+		},
+		Comparison: &StatsComparison{Deltas: []MetricDelta{{Metric: MetricBuilds, Previous: &previous, Current: &current, Change: 2}}},
+	}
+
+	page, err := renderHTMLReport(report, 1)
+	require.NoError(t, err)
+
+	// Sections follow the collector registry, with the comparison last.
+	var titles []string
+	for _, part := range strings.Split(page, "<h2>")[1:] {
+		title, _, _ := strings.Cut(part, "</h2>")
+		titles = append(titles, title)
+	}
+	assert.Equal(t, []string{"Artifacts Summary", "RELEASE-BUNDLES", "Repository Storage", "Changes", "Raw JSON"}, titles)
+	assert.Equal(t, 4, strings.Count(page, `<table class="sortable">`), "summary, repository types, storage and changes tables")
+	assert.Contains(t, page, "<thead><tr><th>Repository Type</th><th>Count</th></tr></thead>")
+	assert.Contains(t, page, `<td>LOCAL</td><td data-sort="1">1</td>`)
+	assert.Contains(t, page, `<p class="error">failed to get stats for &#39;RELEASE-BUNDLES&#39;: need admin privileges</p>`)
+	assert.Contains(t, page, `<p class="more">...and 1 more.`)
+	assert.Contains(t, page, `<td data-sort="2">+2 (+20.0%)</td>`)
+
+	// The repository key is escaped everywhere it is shown, including the raw JSON.
+	assert.NotContains(t, page, "<script>alert")
+	assert.Contains(t, page, `<td>&lt;script&gt;alert(&#39;x&#39;)&lt;/script&gt;&amp;&#34;co&#34;</td>`)
+	_, rawJSON, found := strings.Cut(page, `<pre id="raw-json">`)
+	require.True(t, found)
+	rawJSON, _, found = strings.Cut(rawJSON, "</pre>")
+	require.True(t, found)
+	assert.Contains(t, rawJSON, `&#34;repoKey&#34;: &#34;\u003cscript\u003ealert(&#39;x&#39;)\u003c/script\u003e\u0026\&#34;co\&#34;&#34;`)
+	assert.NotContains(t, rawJSON, "<")
+}
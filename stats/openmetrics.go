@@ -0,0 +1,259 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/access/services"
+	"github.com/jfrog/jfrog-client-go/jpd"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	openMetricsFormat      = "openmetrics"
+	openMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+	openMetricsPath        = "/metrics"
+	openMetricsEOF         = "# EOF\n"
+	// serveReadHeaderTimeout bounds how long a scrape may take to send its request headers.
+	serveReadHeaderTimeout = 10 * time.Second
+)
+
+// openMetricsLabelEscaper escapes label values as the OpenMetrics text format requires.
+var openMetricsLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// gaugeSample is one sample of a gauge, with its labels as alternating names and values.
+type gaugeSample struct {
+	labels []string
+	value  int64
+}
+
+// writeGauge writes one gauge metric family. Families must be written whole, as OpenMetrics does not allow the
+// samples of a family to be interleaved with others.
+func writeGauge(w io.Writer, name, help string, samples ...gaugeSample) error {
+	if _, err := fmt.Fprintf(w, "# TYPE %s gauge\n# HELP %s %s\n", name, name, help); err != nil {
+		return err
+	}
+	for _, sample := range samples {
+		labels := make([]string, 0, len(sample.labels)/2)
+		for i := 0; i+1 < len(sample.labels); i += 2 {
+			labels = append(labels, fmt.Sprintf(`%s="%s"`, sample.labels[i], openMetricsLabelEscaper.Replace(sample.labels[i+1])))
+		}
+		labelSet := ""
+		if len(labels) > 0 {
+			labelSet = "{" + strings.Join(labels, ",") + "}"
+		}
+		if _, err := fmt.Fprintf(w, "%s%s %d\n", name, labelSet, sample.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeUpGauge reports whether the stats of a section could be collected.
+func writeUpGauge(w io.Writer, section string, up bool) error {
+	value := int64(0)
+	if up {
+		value = 1
+	}
+	return writeGauge(w, "jfrog_"+section+"_up", fmt.Sprintf("Whether the %s stats were collected.", strings.ReplaceAll(section, "_", " ")), gaugeSample{value: value})
+}
+
+// WriteOpenMetrics writes the gauges of one stats section, as returned by a GetCommandList function.
+func WriteOpenMetrics(w io.Writer, data interface{}) error {
+	switch v := data.(type) {
+	case *ArtifactoryStatsSummary:
+		return writeArtifactoryOpenMetrics(w, v)
+	case []services.Project:
+		if err := writeUpGauge(w, "projects", true); err != nil {
+			return err
+		}
+		return writeGauge(w, "jfrog_projects", "Number of projects.", gaugeSample{value: int64(len(v))})
+	case *[]JPD:
+		return writeJPDsOpenMetrics(w, *v)
+	case *ReleaseBundleResponse:
+		return writeReleaseBundlesOpenMetrics(w, v)
+	case *RepositoryStorageBreakdown:
+		return writeRepositoryStorageOpenMetrics(w, v.Repositories)
+	case *LargestRepositories:
+		// The sizes are already exposed by the storage collector; only report whether the section was collected.
+		return writeUpGauge(w, "top_repositories", true)
+	case *BuildsStats:
+		if err := writeUpGauge(w, "builds", true); err != nil {
+			return err
+		}
+		return writeGauge(w, "jfrog_builds", "Number of builds published to Artifactory.", gaugeSample{value: int64(v.BuildsCount)})
+	case *ReplicationStats:
+		return writeReplicationOpenMetrics(w, v.Replications)
+	case *jpd.GenericError:
+		return writeUpGauge(w, openMetricsSection(v.Product), false)
+	}
+	return nil
+}
+
+// openMetricsSection turns the product of a GenericError, such as RELEASE-BUNDLES, into a metric name part.
+func openMetricsSection(product string) string {
+	return strings.ReplaceAll(strings.ToLower(product), "-", "_")
+}
+
+func writeArtifactoryOpenMetrics(w io.Writer, summary *ArtifactoryStatsSummary) error {
+	if err := writeUpGauge(w, "artifactory", true); err != nil {
+		return err
+	}
+	gauges := []struct {
+		name, help, raw string
+		parse           func(string) (int64, error)
+	}{
+		{"jfrog_artifactory_artifacts", "Number of artifacts.", summary.TotalArtifactsCount, parseStatsCount},
+		{"jfrog_artifactory_artifacts_size_bytes", "Total size of artifacts in bytes.", summary.TotalArtifactsSize, summary.artifactsSizeBytes},
+		{"jfrog_artifactory_binaries", "Number of binaries.", summary.TotalBinariesCount, parseStatsCount},
+		{"jfrog_artifactory_binaries_size_bytes", "Total size of binaries in bytes.", summary.TotalBinariesSize, parseStorageSize},
+	}
+	for _, gauge := range gauges {
+		value, err := gauge.parse(gauge.raw)
+		if err != nil {
+			log.Debug(fmt.Sprintf("Skipping metric %s: %s", gauge.name, err.Error()))
+			continue
+		}
+		if err := writeGauge(w, gauge.name, gauge.help, gaugeSample{value: value}); err != nil {
+			return err
+		}
+	}
+	repoTypeCounts := countRepositoryTypes(summary)
+	samples := make([]gaugeSample, 0, len(repoTypeCounts))
+	for _, repoType := range sortedKeys(repoTypeCounts) {
+		samples = append(samples, gaugeSample{labels: []string{"type", strings.ToLower(repoType)}, value: int64(repoTypeCounts[repoType])})
+	}
+	return writeGauge(w, "jfrog_artifactory_repositories", "Number of repositories by type.", samples...)
+}
+
+func writeJPDsOpenMetrics(w io.Writer, jpdList []JPD) error {
+	if err := writeUpGauge(w, "jpds", true); err != nil {
+		return err
+	}
+	if err := writeGauge(w, "jfrog_jpds", "Number of JFrog Platform Deployments.", gaugeSample{value: int64(len(jpdList))}); err != nil {
+		return err
+	}
+	samples := make([]gaugeSample, 0, len(jpdList))
+	for _, jpd := range jpdList {
+		online := int64(0)
+		if isJPDOnline(jpd) {
+			online = 1
+		}
+		samples = append(samples, gaugeSample{labels: []string{"name", jpd.Name, "code", jpd.Status.Code}, value: online})
+	}
+	return writeGauge(w, "jfrog_jpd_online", "Whether the JFrog Platform Deployment reports itself online or healthy.", samples...)
+}
+
+// writeReleaseBundlesOpenMetrics reports the number of release bundles and the number of their versions by status.
+// The status family is left out when the versions could not be counted, rather than reported as empty.
+func writeReleaseBundlesOpenMetrics(w io.Writer, rbResponse *ReleaseBundleResponse) error {
+	if err := writeUpGauge(w, "release_bundles", true); err != nil {
+		return err
+	}
+	if err := writeGauge(w, "jfrog_release_bundles", "Number of release bundles.", gaugeSample{value: int64(len(rbResponse.ReleaseBundles))}); err != nil {
+		return err
+	}
+	if rbResponse.VersionsByStatus == nil {
+		return nil
+	}
+	samples := make([]gaugeSample, 0, len(rbResponse.VersionsByStatus))
+	for _, status := range sortedKeys(rbResponse.VersionsByStatus) {
+		samples = append(samples, gaugeSample{labels: []string{"status", status}, value: int64(rbResponse.VersionsByStatus[status])})
+	}
+	return writeGauge(w, "jfrog_release_bundle_versions", "Number of release bundle versions by status.", samples...)
+}
+
+func writeRepositoryStorageOpenMetrics(w io.Writer, repositories []RepositoryStorage) error {
+	if err := writeUpGauge(w, "storage", true); err != nil {
+		return err
+	}
+	usedSpace := make([]gaugeSample, 0, len(repositories))
+	files := make([]gaugeSample, 0, len(repositories))
+	for _, repo := range repositories {
+		labels := []string{"repository", repo.RepoKey, "type", strings.ToLower(repo.RepoType)}
+		usedSpace = append(usedSpace, gaugeSample{labels: labels, value: repo.UsedSpaceBytes})
+		files = append(files, gaugeSample{labels: labels, value: repo.FilesCount})
+	}
+	if err := writeGauge(w, "jfrog_repository_used_space_bytes", "Storage used by the repository in bytes.", usedSpace...); err != nil {
+		return err
+	}
+	return writeGauge(w, "jfrog_repository_files", "Number of files in the repository.", files...)
+}
+
+func writeReplicationOpenMetrics(w io.Writer, replications []ReplicationStatus) error {
+	if err := writeUpGauge(w, "replication", true); err != nil {
+		return err
+	}
+	samples := make([]gaugeSample, 0, len(replications))
+	for _, replication := range replications {
+		ok := int64(0)
+		if replication.Status == "ok" {
+			ok = 1
+		}
+		samples = append(samples, gaugeSample{labels: []string{"repository", replication.RepoKey, "target", replication.URL}, value: ok})
+	}
+	return writeGauge(w, "jfrog_replication_ok", "Whether the last replication run of the repository succeeded.", samples...)
+}
+
+func sortedKeys(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// PrintOpenMetrics prints the gauges of the section. PrintAllResults ends the exposition with the EOF marker.
+func (rw *GenericResultsWriter) PrintOpenMetrics() error {
+	var builder strings.Builder
+	if err := WriteOpenMetrics(&builder, rw.data); err != nil {
+		return err
+	}
+	log.Output(strings.TrimSuffix(builder.String(), "\n"))
+	return nil
+}
+
+// scrapeOpenMetrics collects the stats and returns them as one OpenMetrics exposition, ended by the EOF marker.
+func (sa *ArtifactoryStats) scrapeOpenMetrics() (string, error) {
+	results, err := sa.collectStats()
+	if err != nil {
+		return "", err
+	}
+	var builder strings.Builder
+	for _, collector := range collectorRegistry {
+		if err := WriteOpenMetrics(&builder, results[collector.Name]); err != nil {
+			return "", err
+		}
+	}
+	builder.WriteString(openMetricsEOF)
+	return builder.String(), nil
+}
+
+// Serve exposes the stats in the OpenMetrics format at ServeAddress, collecting them again on every scrape.
+func (sa *ArtifactoryStats) Serve() error {
+	// Runs share state such as ProjectCount and the storage info cache, so scrapes are served one at a time.
+	var scrapeMu sync.Mutex
+	mux := http.NewServeMux()
+	mux.HandleFunc(openMetricsPath, func(w http.ResponseWriter, _ *http.Request) {
+		scrapeMu.Lock()
+		defer scrapeMu.Unlock()
+		exposition, err := sa.scrapeOpenMetrics()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", openMetricsContentType)
+		if _, err := io.WriteString(w, exposition); err != nil {
+			log.Debug("Failed to write stats scrape response:", err.Error())
+		}
+	})
+	server := &http.Server{Addr: sa.ServeAddress, Handler: mux, ReadHeaderTimeout: serveReadHeaderTimeout}
+	log.Info(fmt.Sprintf("Serving stats in the OpenMetrics format at %s%s", sa.ServeAddress, openMetricsPath))
+	return server.ListenAndServe()
+}
@@ -0,0 +1,154 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	accessServices "github.com/jfrog/jfrog-client-go/access/services"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/jpd"
+	lifecycleServices "github.com/jfrog/jfrog-client-go/lifecycle/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const expectedOpenMetrics = `# TYPE jfrog_artifactory_up gauge
+# HELP jfrog_artifactory_up Whether the artifactory stats were collected.
+jfrog_artifactory_up 1
+# TYPE jfrog_artifactory_artifacts gauge
+# HELP jfrog_artifactory_artifacts Number of artifacts.
+jfrog_artifactory_artifacts 1234
+# TYPE jfrog_artifactory_artifacts_size_bytes gauge
+# HELP jfrog_artifactory_artifacts_size_bytes Total size of artifacts in bytes.
+jfrog_artifactory_artifacts_size_bytes 1610612736
+# TYPE jfrog_artifactory_binaries gauge
+# HELP jfrog_artifactory_binaries Number of binaries.
+jfrog_artifactory_binaries 10
+# TYPE jfrog_artifactory_repositories gauge
+# HELP jfrog_artifactory_repositories Number of repositories by type.
+jfrog_artifactory_repositories{type="local"} 2
+jfrog_artifactory_repositories{type="remote"} 1
+# TYPE jfrog_projects_up gauge
+# HELP jfrog_projects_up Whether the projects stats were collected.
+jfrog_projects_up 1
+# TYPE jfrog_projects gauge
+# HELP jfrog_projects Number of projects.
+jfrog_projects 2
+# TYPE jfrog_release_bundles_up gauge
+# HELP jfrog_release_bundles_up Whether the release bundles stats were collected.
+jfrog_release_bundles_up 0
+# TYPE jfrog_replication_up gauge
+# HELP jfrog_replication_up Whether the replication stats were collected.
+jfrog_replication_up 1
+# TYPE jfrog_replication_ok gauge
+# HELP jfrog_replication_ok Whether the last replication run of the repository succeeded.
+jfrog_replication_ok{repository="libs-release",target="https://edge.example.com/artifactory/libs-release"} 1
+jfrog_replication_ok{repository="odd\"key\\name",target="https://edge.example.com/a\nb"} 0
+# EOF
+`
+
+func TestScrapeOpenMetrics(t *testing.T) {
+	withCollectorRegistry(t, []Collector{
+		staticCollector("rt", &ArtifactoryStatsSummary{
+			TotalArtifactsCount: "1,234",
+			TotalArtifactsSize:  "1.50 GB",
+			TotalBinariesCount:  "10",
+			// An unparsable size is left out rather than reported as zero.
+			TotalBinariesSize: "n/a",
+			RepositoriesDetails: []services.RepositoryDetails{
+				{Key: "libs-release", Type: "LOCAL"},
+				{Key: "libs-snapshot", Type: "LOCAL"},
+				{Key: "maven-remote", Type: "REMOTE"},
+				{Key: "TOTAL", Type: "TOTAL"},
+			},
+		}),
+		staticCollector("project", []accessServices.Project{{ProjectKey: "a"}, {ProjectKey: "b"}}),
+		staticCollector("rb", jpd.NewGenericError("RELEASE-BUNDLES", errors.New("need admin privileges"))),
+		staticCollector("replication", &ReplicationStats{Replications: []ReplicationStatus{
+			{RepoKey: "libs-release", URL: "https://edge.example.com/artifactory/libs-release", Status: "ok"},
+			{RepoKey: `odd"key\name`, URL: "https://edge.example.com/a\nb", Status: "failure"},
+		}}),
+	})
+
+	exposition, err := NewArtifactoryStatsCommand().scrapeOpenMetrics()
+	require.NoError(t, err)
+	assert.Equal(t, expectedOpenMetrics, exposition)
+	assert.True(t, strings.HasSuffix(exposition, "\n"+openMetricsEOF), "the exposition must end with the EOF marker")
+}
+
+func TestWriteReleaseBundlesOpenMetrics(t *testing.T) {
+	rbResponse := &ReleaseBundleResponse{
+		ReleaseBundles:   []ReleaseBundleInfo{{ReleaseBundleName: "app"}, {ReleaseBundleName: "web"}},
+		VersionsByStatus: map[string]int{"failed": 1, "completed": 4},
+	}
+	var builder strings.Builder
+	require.NoError(t, WriteOpenMetrics(&builder, rbResponse))
+	assert.Equal(t, `# TYPE jfrog_release_bundles_up gauge
+# HELP jfrog_release_bundles_up Whether the release bundles stats were collected.
+jfrog_release_bundles_up 1
+# TYPE jfrog_release_bundles gauge
+# HELP jfrog_release_bundles Number of release bundles.
+jfrog_release_bundles 2
+# TYPE jfrog_release_bundle_versions gauge
+# HELP jfrog_release_bundle_versions Number of release bundle versions by status.
+jfrog_release_bundle_versions{status="completed"} 4
+jfrog_release_bundle_versions{status="failed"} 1
+`, builder.String())
+
+	// Without a count by status only the number of bundles is reported.
+	rbResponse.VersionsByStatus = nil
+	builder.Reset()
+	require.NoError(t, WriteOpenMetrics(&builder, rbResponse))
+	assert.NotContains(t, builder.String(), "jfrog_release_bundle_versions")
+}
+
+func TestCountReleaseBundleVersions(t *testing.T) {
+	versions := map[string][]string{"app": {"COMPLETED", "COMPLETED", "FAILED"}, "web": {"PROCESSING", ""}}
+	var searchedMu sync.Mutex
+	var searched []string
+	search := func(name string, params lifecycleServices.GetSearchOptionalQueryParams) (lifecycleServices.ReleaseBundleVersionsResponse, error) {
+		searchedMu.Lock()
+		searched = append(searched, fmt.Sprintf("%s@%d/%s", name, params.Offset, params.Project))
+		searchedMu.Unlock()
+		if name == "broken" {
+			return lifecycleServices.ReleaseBundleVersionsResponse{}, errors.New("permission denied")
+		}
+		// Serve one version per page to exercise paging.
+		response := lifecycleServices.ReleaseBundleVersionsResponse{Total: len(versions[name])}
+		if params.Offset < len(versions[name]) {
+			response.ReleaseBundles = []lifecycleServices.ReleaseBundleVersion{{Status: versions[name][params.Offset]}}
+		}
+		return response, nil
+	}
+
+	counts, err := countReleaseBundleVersions(context.Background(), []ReleaseBundleInfo{
+		{ReleaseBundleName: "app", ProjectKey: "default"},
+		{ReleaseBundleName: "web", ProjectKey: "shop"},
+	}, search)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"completed": 2, "failed": 1, "processing": 1, "unknown": 1}, counts)
+	assert.ElementsMatch(t, []string{"app@0/default", "app@1/default", "app@2/default", "web@0/shop", "web@1/shop"}, searched)
+
+	_, err = countReleaseBundleVersions(context.Background(), []ReleaseBundleInfo{{ReleaseBundleName: "app"}, {ReleaseBundleName: "broken"}}, search)
+	assert.EqualError(t, err, "release bundle 'broken': permission denied")
+}
+
+func TestWriteGauge(t *testing.T) {
+	var builder strings.Builder
+	require.NoError(t, writeGauge(&builder, "jfrog_test", "A test gauge.",
+		gaugeSample{value: 3},
+		gaugeSample{labels: []string{"name", "a\\b", "code", "say \"hi\"\n"}, value: 1},
+		// A label name without a value is dropped.
+		gaugeSample{labels: []string{"name", "c", "dangling"}, value: 2},
+	))
+	assert.Equal(t, `# TYPE jfrog_test gauge
+# HELP jfrog_test A test gauge.
+jfrog_test 3
+jfrog_test{name="a\\b",code="say \"hi\"\n"} 1
+jfrog_test{name="c"} 2
+`, builder.String())
+}
@@ -35,6 +35,10 @@ func (rw *GenericResultsWriter) Print() error {
 		return rw.PrintJson()
 	case "table":
 		return rw.PrintDashboard()
+	case openMetricsFormat:
+		return rw.PrintOpenMetrics()
+	case htmlFormat:
+		return rw.PrintHtml()
 	default:
 		return rw.PrintConsole()
 	}
@@ -86,6 +90,14 @@ func (rw *GenericResultsWriter) PrintDashboard() error {
 		PrintJPDsDashboard(*v, rw.displayLimit)
 	case *ReleaseBundleResponse:
 		PrintReleaseBundlesDashboard(v, rw.displayLimit)
+	case *RepositoryStorageBreakdown:
+		PrintRepositoryStorageDashboard(v.Repositories, "Repository Storage", rw.displayLimit)
+	case *LargestRepositories:
+		PrintRepositoryStorageDashboard(v.Repositories, "Largest Repositories", rw.displayLimit)
+	case *BuildsStats:
+		PrintBuildsDashboard(v, rw.displayLimit)
+	case *ReplicationStats:
+		PrintReplicationDashboard(v, rw.displayLimit)
 	case *jpd.GenericError:
 		PrintErrorsDashboard(v)
 	}
@@ -109,12 +121,7 @@ func PrintArtifactoryDashboard(stats *ArtifactoryStatsSummary) {
 	}
 	log.Output()
 
-	repoTypeCounts := make(map[string]int)
-	for _, repo := range stats.RepositoriesDetails {
-		if repo.Type != "TOTAL" && repo.Type != "NA" {
-			repoTypeCounts[repo.Type]++
-		}
-	}
+	repoTypeCounts := countRepositoryTypes(stats)
 	repoTypeTableData := []TableRow{
 		{Metric: text.FgCyan.Sprint("Repository Type"), Value: text.FgCyan.Sprint("Count")},
 	}
@@ -170,7 +177,7 @@ func PrintJPDsDashboard(jpdList []JPD, displayLimit int) {
 	for i := 0; i < loopRange; i++ {
 		jpd := jpdList[i]
 		var status string
-		if jpd.Status.Code == "ONLINE" || jpd.Status.Code == "Healthy" {
+		if isJPDOnline(jpd) {
 			status = text.FgGreen.Sprint(jpd.Status.Code)
 		} else {
 			status = text.FgRed.Sprint(jpd.Status.Code)
@@ -228,6 +235,107 @@ func PrintReleaseBundlesDashboard(rbResponse *ReleaseBundleResponse, displayLimi
 	log.Output()
 }
 
+type repositoryStorageRow struct {
+	Repository string `col-name:"Repository"`
+	UsedSpace  string `col-name:"Used Space"`
+	Percentage string `col-name:"Percentage"`
+}
+
+func PrintRepositoryStorageDashboard(repositories []RepositoryStorage, title string, displayLimit int) {
+	loopRange := len(repositories)
+	if loopRange > displayLimit {
+		loopRange = displayLimit
+	}
+	actualCount := len(repositories)
+
+	tableData := []repositoryStorageRow{{"Repository", "Used Space", "Percentage"}}
+	for i := 0; i < loopRange; i++ {
+		repo := repositories[i]
+		tableData = append(tableData, repositoryStorageRow{
+			Repository: text.FgHiBlue.Sprint(repo.RepoKey),
+			UsedSpace:  text.FgGreen.Sprint(repo.UsedSpace),
+			Percentage: text.FgWhite.Sprint(repo.Percentage),
+		})
+	}
+	if len(tableData) == 1 {
+		tableData = []repositoryStorageRow{}
+	}
+	footer := ""
+	if actualCount > displayLimit {
+		footer = text.FgYellow.Sprintf("\n...and %d more repositories. Refer JSON output format for complete list.", actualCount-displayLimit)
+	}
+
+	err := coreutils.PrintTableWithBorderless(tableData, text.FgCyan.Sprint(title), footer, "No Repositories Found", false)
+	if err != nil {
+		log.Error("Failed to print "+title+" table:", err)
+		return
+	}
+	log.Output()
+}
+
+func PrintBuildsDashboard(buildsStats *BuildsStats, displayLimit int) {
+	loopRange := len(buildsStats.Builds)
+	if loopRange > displayLimit {
+		loopRange = displayLimit
+	}
+	actualCount := len(buildsStats.Builds)
+
+	tableData := []TableRow{
+		{Metric: text.FgHiBlue.Sprint("Total Builds"), Value: text.FgGreen.Sprint(buildsStats.BuildsCount)},
+		{Metric: text.FgCyan.Sprint("Build Name"), Value: text.FgCyan.Sprint("Last Started")},
+	}
+	for i := 0; i < loopRange; i++ {
+		build := buildsStats.Builds[i]
+		tableData = append(tableData, TableRow{Metric: text.FgHiBlue.Sprint(build.Name), Value: text.FgGreen.Sprint(build.LastStarted)})
+	}
+	if actualCount == 0 {
+		tableData = tableData[:1]
+	}
+	footer := ""
+	if actualCount > displayLimit {
+		footer = text.FgYellow.Sprintf("\n...and %d more builds. Refer JSON output format for complete list.", actualCount-displayLimit)
+	}
+
+	err := coreutils.PrintTableWithBorderless(tableData, text.FgCyan.Sprint("Builds"), footer, "No Builds Found", false)
+	if err != nil {
+		log.Error("Failed to print Builds table:", err)
+		return
+	}
+	log.Output()
+}
+
+func PrintReplicationDashboard(replicationStats *ReplicationStats, displayLimit int) {
+	loopRange := len(replicationStats.Replications)
+	if loopRange > displayLimit {
+		loopRange = displayLimit
+	}
+	actualCount := len(replicationStats.Replications)
+
+	tableData := []TableRow{{"Repository", "Status"}}
+	for i := 0; i < loopRange; i++ {
+		replication := replicationStats.Replications[i]
+		status := text.FgRed.Sprint(replication.Status)
+		if replication.Status == "ok" {
+			status = text.FgGreen.Sprint(replication.Status)
+		}
+		tableData = append(tableData, TableRow{Metric: text.FgHiBlue.Sprint(replication.RepoKey), Value: status})
+	}
+	if len(tableData) == 1 {
+		tableData = []TableRow{}
+	}
+	footer := ""
+	if actualCount > displayLimit {
+		footer = text.FgYellow.Sprintf("\n...and %d more replications. Refer JSON output format for complete list.", actualCount-displayLimit)
+	}
+
+	err := coreutils.PrintTableWithBorderless(tableData, text.FgCyan.Sprint("Replication"), footer, "No Replications Found", false)
+	if err != nil {
+		log.Error("Failed to print Replication table:", err)
+		return
+	}
+	log.Output()
+}
+
 func PrintErrorsDashboard(genericError *jpd.GenericError) {
 	errRows := createErrorRows(*genericError)
 
@@ -256,6 +364,14 @@ func (rw *GenericResultsWriter) PrintConsole() error {
 		PrintJPDsStats(v, rw.displayLimit)
 	case *ReleaseBundleResponse:
 		PrintReleaseBundlesStats(v, rw.displayLimit)
+	case *RepositoryStorageBreakdown:
+		PrintRepositoryStorageStats(v.Repositories, "Repository Storage", rw.displayLimit)
+	case *LargestRepositories:
+		PrintRepositoryStorageStats(v.Repositories, "Largest Repositories", rw.displayLimit)
+	case *BuildsStats:
+		PrintBuildsStats(v, rw.displayLimit)
+	case *ReplicationStats:
+		PrintReplicationStats(v, rw.displayLimit)
 	case *jpd.GenericError:
 		PrintGenericError(v)
 	}
@@ -267,12 +383,7 @@ func PrintArtifactoryStats(stats *ArtifactoryStatsSummary) {
 	resultString := FormatWithDisplayTags(stats)
 	log.Output(resultString)
 
-	repoTypeCounts := make(map[string]int)
-	for _, repo := range stats.RepositoriesDetails {
-		if repo.Type != "TOTAL" && repo.Type != "NA" {
-			repoTypeCounts[repo.Type]++
-		}
-	}
+	repoTypeCounts := countRepositoryTypes(stats)
 	log.Output("--- Repositories Details ---")
 	for repoType, count := range repoTypeCounts {
 		log.Output(repoType, ": ", count)
@@ -341,6 +452,58 @@ func PrintReleaseBundlesStats(rbResponse *ReleaseBundleResponse, displayLimit in
 	}
 }
 
+func PrintRepositoryStorageStats(repositories []RepositoryStorage, title string, displayLimit int) {
+	log.Output("---", title, "---")
+	if len(repositories) == 0 {
+		log.Output("No Repositories Available\n")
+		return
+	}
+	loopRange := len(repositories)
+	if loopRange > displayLimit {
+		loopRange = displayLimit
+	}
+	for i := 0; i < loopRange; i++ {
+		log.Output(FormatWithDisplayTags(repositories[i]))
+	}
+	if len(repositories) > displayLimit {
+		log.Output(text.FgYellow.Sprintf("\n...and %d more repositories, Try JSON output format for complete list.", len(repositories)-displayLimit))
+	}
+}
+
+func PrintBuildsStats(buildsStats *BuildsStats, displayLimit int) {
+	log.Output("--- Builds ---")
+	log.Output("Total Builds:", buildsStats.BuildsCount)
+	loopRange := len(buildsStats.Builds)
+	if loopRange > displayLimit {
+		loopRange = displayLimit
+	}
+	for i := 0; i < loopRange; i++ {
+		log.Output(FormatWithDisplayTags(buildsStats.Builds[i]))
+	}
+	if len(buildsStats.Builds) > displayLimit {
+		log.Output(text.FgYellow.Sprintf("\n...and %d more builds, Try JSON output format for complete list.", len(buildsStats.Builds)-displayLimit))
+	}
+	log.Output()
+}
+
+func PrintReplicationStats(replicationStats *ReplicationStats, displayLimit int) {
+	log.Output("--- Replication ---")
+	if len(replicationStats.Replications) == 0 {
+		log.Output("No Replications Available\n")
+		return
+	}
+	loopRange := len(replicationStats.Replications)
+	if loopRange > displayLimit {
+		loopRange = displayLimit
+	}
+	for i := 0; i < loopRange; i++ {
+		log.Output(FormatWithDisplayTags(replicationStats.Replications[i]))
+	}
+	if len(replicationStats.Replications) > displayLimit {
+		log.Output(text.FgYellow.Sprintf("\n...and %d more replications, Try JSON output format for complete list.", len(replicationStats.Replications)-displayLimit))
+	}
+}
+
 func PrintGenericError(err *jpd.GenericError) {
 	log.Output("---", err.Product, "---")
 	resultString := FormatWithDisplayTags(err)
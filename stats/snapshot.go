@@ -0,0 +1,319 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/access/services"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Metric names recorded in a snapshot. Repository counts are recorded per type as "repositories.<type>".
+const (
+	MetricRepositories         = "repositories.total"
+	MetricArtifactsCount       = "artifacts.count"
+	MetricArtifactsSizeBytes   = "artifacts.size_bytes"
+	MetricBinariesCount        = "binaries.count"
+	MetricBinariesSizeBytes    = "binaries.size_bytes"
+	MetricProjects             = "projects.count"
+	MetricJPDs                 = "jpds.count"
+	MetricJPDsOnline           = "jpds.online"
+	MetricReleaseBundles       = "release_bundles.count"
+	MetricBuilds               = "builds.count"
+	repositoryTypeMetricPrefix = "repositories."
+	sizeMetricSuffix           = "_bytes"
+)
+
+// storageSizeUnits maps the units Artifactory uses in storage info to their size in bytes.
+var storageSizeUnits = map[string]float64{
+	"bytes": 1,
+	"B":     1,
+	"KB":    1 << 10,
+	"MB":    1 << 20,
+	"GB":    1 << 30,
+	"TB":    1 << 40,
+	"PB":    1 << 50,
+}
+
+// StatsSnapshot is the platform state at one point in time, saved with --save and read back with --compare.
+// Sections that failed to collect are absent from Metrics.
+type StatsSnapshot struct {
+	Timestamp time.Time        `json:"timestamp"`
+	ServerUrl string           `json:"serverUrl,omitempty"`
+	Metrics   map[string]int64 `json:"metrics"`
+}
+
+// MetricDelta is the change of one metric between two snapshots.
+type MetricDelta struct {
+	Metric   string `json:"metric"`
+	Previous *int64 `json:"previous,omitempty"`
+	Current  *int64 `json:"current,omitempty"`
+	Change   int64  `json:"change"`
+}
+
+// StatsComparison is the change of every metric between a saved snapshot and the current one.
+type StatsComparison struct {
+	From   time.Time     `json:"from"`
+	To     time.Time     `json:"to"`
+	Deltas []MetricDelta `json:"deltas"`
+}
+
+type deltaRow struct {
+	Metric   string `col-name:"Metric"`
+	Previous string `col-name:"Previous"`
+	Current  string `col-name:"Current"`
+	Change   string `col-name:"Change"`
+}
+
+// NewStatsSnapshot builds a snapshot from the results of the collectors, skipping sections that returned errors.
+func NewStatsSnapshot(results map[string]interface{}, serverUrl string) *StatsSnapshot {
+	snapshot := &StatsSnapshot{Timestamp: time.Now().UTC(), ServerUrl: serverUrl, Metrics: map[string]int64{}}
+	if summary, ok := results["rt"].(*ArtifactoryStatsSummary); ok {
+		snapshot.addArtifactoryMetrics(summary)
+	}
+	if projects, ok := results["project"].([]services.Project); ok {
+		snapshot.Metrics[MetricProjects] = int64(len(projects))
+	}
+	if jpdList, ok := results["jpd"].(*[]JPD); ok {
+		online := 0
+		for _, jpd := range *jpdList {
+			if isJPDOnline(jpd) {
+				online++
+			}
+		}
+		snapshot.Metrics[MetricJPDs] = int64(len(*jpdList))
+		snapshot.Metrics[MetricJPDsOnline] = int64(online)
+	}
+	if rbResponse, ok := results["rb"].(*ReleaseBundleResponse); ok {
+		snapshot.Metrics[MetricReleaseBundles] = int64(len(rbResponse.ReleaseBundles))
+	}
+	if buildsStats, ok := results["builds"].(*BuildsStats); ok {
+		snapshot.Metrics[MetricBuilds] = int64(buildsStats.BuildsCount)
+	}
+	return snapshot
+}
+
+func (s *StatsSnapshot) addArtifactoryMetrics(summary *ArtifactoryStatsSummary) {
+	total := 0
+	for repoType, count := range countRepositoryTypes(summary) {
+		s.Metrics[repositoryTypeMetricPrefix+strings.ToLower(repoType)] = int64(count)
+		total += count
+	}
+	s.Metrics[MetricRepositories] = int64(total)
+	s.addParsedMetric(MetricArtifactsCount, summary.TotalArtifactsCount, parseStatsCount)
+	s.addParsedMetric(MetricArtifactsSizeBytes, summary.TotalArtifactsSize, summary.artifactsSizeBytes)
+	s.addParsedMetric(MetricBinariesCount, summary.TotalBinariesCount, parseStatsCount)
+	// Storage info gives the binaries size only as rounded text, so this metric is approximate.
+	s.addParsedMetric(MetricBinariesSizeBytes, summary.TotalBinariesSize, parseStorageSize)
+}
+
+// artifactsSizeBytes returns the exact artifacts size, falling back to parsing raw, the rounded display text, when
+// storage info had no byte counts.
+func (summary *ArtifactoryStatsSummary) artifactsSizeBytes(raw string) (int64, error) {
+	if summary.TotalArtifactsSizeBytes != nil {
+		return *summary.TotalArtifactsSizeBytes, nil
+	}
+	return parseStorageSize(raw)
+}
+
+// sameServerUrl reports whether two server URLs name the same server. An unknown URL matches any.
+func sameServerUrl(a, b string) bool {
+	if a == "" || b == "" {
+		return true
+	}
+	normalize := func(url string) string { return strings.ToLower(strings.TrimRight(strings.TrimSpace(url), "/")) }
+	return normalize(a) == normalize(b)
+}
+
+func (s *StatsSnapshot) addParsedMetric(name, raw string, parse func(string) (int64, error)) {
+	value, err := parse(raw)
+	if err != nil {
+		log.Debug(fmt.Sprintf("Skipping metric %s: %s", name, err.Error()))
+		return
+	}
+	s.Metrics[name] = value
+}
+
+// countRepositoryTypes counts repositories per type, leaving out the TOTAL and NA rows of storage info.
+func countRepositoryTypes(summary *ArtifactoryStatsSummary) map[string]int {
+	repoTypeCounts := make(map[string]int)
+	for _, repo := range summary.RepositoriesDetails {
+		if repo.Type != "TOTAL" && repo.Type != "NA" {
+			repoTypeCounts[repo.Type]++
+		}
+	}
+	return repoTypeCounts
+}
+
+func isJPDOnline(jpd JPD) bool {
+	return jpd.Status.Code == "ONLINE" || jpd.Status.Code == "Healthy"
+}
+
+// parseStatsCount parses a count as Artifactory formats it, e.g. "1,234".
+func parseStatsCount(raw string) (int64, error) {
+	value, err := strconv.ParseInt(strings.ReplaceAll(strings.TrimSpace(raw), ",", ""), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid count %q", raw)
+	}
+	return value, nil
+}
+
+// parseStorageSize parses a size as Artifactory formats it, e.g. "1.50 GB" or "512 bytes", into bytes. The text is
+// rounded, so prefer the byte counts of storage info where there are any.
+func parseStorageSize(raw string) (int64, error) {
+	fields := strings.Fields(raw)
+	if len(fields) != 2 {
+		return 0, fmt.Errorf("invalid size %q", raw)
+	}
+	number, err := strconv.ParseFloat(strings.ReplaceAll(fields[0], ",", ""), 64)
+	unit, known := storageSizeUnits[fields[1]]
+	if err != nil || !known {
+		return 0, fmt.Errorf("invalid size %q", raw)
+	}
+	return int64(number * unit), nil
+}
+
+// SaveStatsSnapshot writes snapshot to path as indented JSON.
+func SaveStatsSnapshot(snapshot *StatsSnapshot, path string) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal stats snapshot: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to save stats snapshot to %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadStatsSnapshot reads a snapshot written by SaveStatsSnapshot.
+func LoadStatsSnapshot(path string) (*StatsSnapshot, error) {
+	// #nosec G304 -- path is the snapshot file chosen by the user.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stats snapshot %s: %w", path, err)
+	}
+	var snapshot StatsSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse stats snapshot %s: %w", path, err)
+	}
+	if snapshot.Metrics == nil {
+		return nil, fmt.Errorf("stats snapshot %s has no metrics", path)
+	}
+	return &snapshot, nil
+}
+
+// CompareStatsSnapshots returns the change of every metric in either snapshot, sorted by metric name.
+// A metric present in only one snapshot has no change.
+func CompareStatsSnapshots(previous, current *StatsSnapshot) *StatsComparison {
+	names := make([]string, 0, len(current.Metrics))
+	for name := range current.Metrics {
+		names = append(names, name)
+	}
+	for name := range previous.Metrics {
+		if _, found := current.Metrics[name]; !found {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	comparison := &StatsComparison{From: previous.Timestamp, To: current.Timestamp, Deltas: make([]MetricDelta, 0, len(names))}
+	for _, name := range names {
+		delta := MetricDelta{Metric: name}
+		if value, found := previous.Metrics[name]; found {
+			delta.Previous = &value
+		}
+		if value, found := current.Metrics[name]; found {
+			delta.Current = &value
+		}
+		if delta.Previous != nil && delta.Current != nil {
+			delta.Change = *delta.Current - *delta.Previous
+		}
+		comparison.Deltas = append(comparison.Deltas, delta)
+	}
+	return comparison
+}
+
+// PrintStatsComparison prints the deltas in the same format as the stats sections.
+func PrintStatsComparison(comparison *StatsComparison, format string) error {
+	switch format {
+	case "json", "simplejson":
+		jsonBytes, err := json.MarshalIndent(comparison, "", "  ")
+		if err != nil {
+			return err
+		}
+		log.Output(string(jsonBytes))
+	case "table":
+		rows := make([]deltaRow, 0, len(comparison.Deltas))
+		for _, delta := range comparison.Deltas {
+			rows = append(rows, deltaRow{
+				Metric:   text.FgHiBlue.Sprint(delta.Metric),
+				Previous: formatMetricValue(delta.Metric, delta.Previous),
+				Current:  formatMetricValue(delta.Metric, delta.Current),
+				Change:   formatMetricChange(delta),
+			})
+		}
+		title := text.FgCyan.Sprintf("Changes since %s", comparison.From.Format(time.RFC3339))
+		if err := coreutils.PrintTableWithBorderless(rows, title, "", "No metrics to compare", false); err != nil {
+			return err
+		}
+		log.Output()
+	default:
+		log.Output("--- Changes since", comparison.From.Format(time.RFC3339), "---")
+		for _, delta := range comparison.Deltas {
+			log.Output(fmt.Sprintf("%s: %s -> %s (%s)", delta.Metric, formatMetricValue(delta.Metric, delta.Previous),
+				formatMetricValue(delta.Metric, delta.Current), formatMetricChange(delta)))
+		}
+		log.Output()
+	}
+	return nil
+}
+
+func formatMetricValue(metric string, value *int64) string {
+	if value == nil {
+		return "-"
+	}
+	if strings.HasSuffix(metric, sizeMetricSuffix) {
+		return formatBytes(*value)
+	}
+	return strconv.FormatInt(*value, 10)
+}
+
+func formatMetricChange(delta MetricDelta) string {
+	if delta.Previous == nil || delta.Current == nil {
+		return "-"
+	}
+	change := delta.Change
+	sign := "+"
+	if change < 0 {
+		sign, change = "-", -change
+	}
+	formatted := sign + formatMetricValue(delta.Metric, &change)
+	if *delta.Previous != 0 {
+		formatted += fmt.Sprintf(" (%+.1f%%)", float64(delta.Change)*100/float64(*delta.Previous))
+	}
+	return formatted
+}
+
+// formatBytes formats a size in bytes with the largest binary unit that keeps the number at or above 1.
+func formatBytes(size int64) string {
+	units := []string{"KB", "MB", "GB", "TB", "PB"}
+	if size < 1<<10 {
+		return fmt.Sprintf("%d bytes", size)
+	}
+	value := float64(size)
+	unit := ""
+	for _, unit = range units {
+		value /= 1 << 10
+		if value < 1<<10 {
+			break
+		}
+	}
+	return fmt.Sprintf("%.2f %s", value, unit)
+}
@@ -0,0 +1,130 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStorageSize(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    int64
+		wantErr bool
+	}{
+		{raw: "512 bytes", want: 512},
+		{raw: "3 B", want: 3},
+		{raw: "2 KB", want: 2 << 10},
+		{raw: "1,024 KB", want: 1 << 20},
+		{raw: "1.50 GB", want: 3 << 29},
+		{raw: "0.5 MB", want: 1 << 19},
+		{raw: "2 TB", want: 2 << 40},
+		{raw: "1 PB", want: 1 << 50},
+		{raw: "  7 bytes  ", want: 7},
+		{raw: "", wantErr: true},
+		{raw: "12", wantErr: true},
+		{raw: "1.5GB", wantErr: true},
+		{raw: "1.5 GiB", wantErr: true},
+		{raw: "1.5 gb", wantErr: true},
+		{raw: "abc MB", wantErr: true},
+		{raw: "1 GB extra", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := parseStorageSize(tt.raw)
+			if tt.wantErr {
+				assert.EqualError(t, err, "invalid size \""+tt.raw+"\"")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompareStatsSnapshots(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	value := func(v int64) *int64 { return &v }
+
+	tests := []struct {
+		name     string
+		previous map[string]int64
+		current  map[string]int64
+		want     []MetricDelta
+	}{
+		{
+			name:     "same metrics",
+			previous: map[string]int64{MetricBuilds: 10, MetricProjects: 4},
+			current:  map[string]int64{MetricBuilds: 12, MetricProjects: 3},
+			want: []MetricDelta{
+				{Metric: MetricBuilds, Previous: value(10), Current: value(12), Change: 2},
+				{Metric: MetricProjects, Previous: value(4), Current: value(3), Change: -1},
+			},
+		},
+		{
+			name:     "collector only in the previous snapshot",
+			previous: map[string]int64{MetricBuilds: 10, MetricJPDs: 2, MetricJPDsOnline: 1},
+			current:  map[string]int64{MetricBuilds: 10},
+			want: []MetricDelta{
+				{Metric: MetricBuilds, Previous: value(10), Current: value(10)},
+				{Metric: MetricJPDs, Previous: value(2)},
+				{Metric: MetricJPDsOnline, Previous: value(1)},
+			},
+		},
+		{
+			name:     "collector only in the current snapshot",
+			previous: map[string]int64{MetricBuilds: 10},
+			current:  map[string]int64{MetricBuilds: 11, MetricReleaseBundles: 5},
+			want: []MetricDelta{
+				{Metric: MetricBuilds, Previous: value(10), Current: value(11), Change: 1},
+				{Metric: MetricReleaseBundles, Current: value(5)},
+			},
+		},
+		{
+			name:     "empty snapshots",
+			previous: map[string]int64{},
+			current:  map[string]int64{},
+			want:     []MetricDelta{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comparison := CompareStatsSnapshots(
+				&StatsSnapshot{Timestamp: from, Metrics: tt.previous},
+				&StatsSnapshot{Timestamp: to, Metrics: tt.current},
+			)
+			assert.Equal(t, from, comparison.From)
+			assert.Equal(t, to, comparison.To)
+			assert.Equal(t, tt.want, comparison.Deltas)
+		})
+	}
+}
+
+func TestFormatMetricChange(t *testing.T) {
+	previous, current := int64(1<<20), int64(3<<19)
+	delta := MetricDelta{Metric: MetricArtifactsSizeBytes, Previous: &previous, Current: &current, Change: current - previous}
+	assert.Equal(t, "+512.00 KB (+50.0%)", formatMetricChange(delta))
+	assert.Equal(t, "-", formatMetricChange(MetricDelta{Metric: MetricBuilds, Current: &current}))
+}
+
+func TestNewStatsSnapshot_ArtifactsSize(t *testing.T) {
+	// 1.5 TB plus 4 GB shows as "1.50 TB"; the byte count keeps the growth.
+	exact := int64(3<<39 + 4<<30)
+	summary := &ArtifactoryStatsSummary{TotalArtifactsSize: "1.50 TB", TotalBinariesSize: "1.20 TB", TotalArtifactsSizeBytes: &exact}
+	snapshot := NewStatsSnapshot(map[string]interface{}{"rt": summary}, "https://acme.jfrog.io")
+	assert.Equal(t, exact, snapshot.Metrics[MetricArtifactsSizeBytes])
+
+	// Without byte counts the display text is parsed.
+	summary.TotalArtifactsSizeBytes = nil
+	snapshot = NewStatsSnapshot(map[string]interface{}{"rt": summary}, "https://acme.jfrog.io")
+	assert.Equal(t, int64(3<<39), snapshot.Metrics[MetricArtifactsSizeBytes])
+}
+
+func TestSameServerUrl(t *testing.T) {
+	assert.True(t, sameServerUrl("https://acme.jfrog.io/", "https://ACME.jfrog.io"))
+	assert.True(t, sameServerUrl("", "https://acme.jfrog.io/"))
+	assert.False(t, sameServerUrl("https://acme.jfrog.io/", "https://other.jfrog.io/"))
+}
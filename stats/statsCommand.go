@@ -1,5 +1,7 @@
 package stats
 
+import "time"
+
 const displayLimit = 5
 
 type Stats struct {
@@ -8,6 +10,16 @@ type Stats struct {
 	AccessToken  string
 	ServerId     string
 	DisplayLimit int
+	// SavePath is where a snapshot of the collected stats is written, for a later --compare.
+	SavePath string
+	// ComparePath is a snapshot written with --save; the changes since it are printed after the stats.
+	ComparePath string
+	// ServeAddress, e.g. ":9090", serves the stats in the OpenMetrics format instead of printing them once.
+	ServeAddress string
+	// Collectors limits the run to the named collectors, e.g. "rt" and "storage". Opt-in collectors such as
+	// "storage" run only when named.
+	Collectors       []string
+	CollectorTimeout time.Duration
 }
 
 type CommandRunner interface {
@@ -33,6 +45,31 @@ func (s *Stats) SetServerId(id string) *Stats {
 	return s
 }
 
+func (s *Stats) SetSavePath(path string) *Stats {
+	s.SavePath = path
+	return s
+}
+
+func (s *Stats) SetComparePath(path string) *Stats {
+	s.ComparePath = path
+	return s
+}
+
+func (s *Stats) SetServeAddress(address string) *Stats {
+	s.ServeAddress = address
+	return s
+}
+
+func (s *Stats) SetCollectors(names []string) *Stats {
+	s.Collectors = names
+	return s
+}
+
+func (s *Stats) SetCollectorTimeout(timeout time.Duration) *Stats {
+	s.CollectorTimeout = timeout
+	return s
+}
+
 func (ss *Stats) Run() error {
 	cmd := ss.NewArtifactoryStatsCommand()
 	return cmd.Run()
@@ -43,6 +80,11 @@ func (ss *Stats) NewArtifactoryStatsCommand() *ArtifactoryStats {
 		SetServerId(ss.ServerId).
 		SetAccessToken(ss.AccessToken).
 		SetFormat(ss.Format).
-		SetDisplayLimit(ss.DisplayLimit)
+		SetDisplayLimit(ss.DisplayLimit).
+		SetSavePath(ss.SavePath).
+		SetComparePath(ss.ComparePath).
+		SetServeAddress(ss.ServeAddress).
+		SetCollectors(ss.Collectors).
+		SetCollectorTimeout(ss.CollectorTimeout)
 	return newStatsCommand
 }
@@ -1,9 +1,12 @@
 package stats
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
@@ -58,10 +61,15 @@ type ArtifactoryStatsSummary struct {
 	TotalArtifactsSize  string                       `display:"Total Artifacts Size"`
 	StorageType         string                       `display:"Storage Type"`
 	RepositoriesDetails []services.RepositoryDetails `json:"-"`
+	// TotalArtifactsSizeBytes is the exact size behind TotalArtifactsSize; nil when storage info has no byte counts.
+	TotalArtifactsSizeBytes *int64 `json:"-"`
 }
 
 type ReleaseBundleResponse struct {
 	ReleaseBundles []ReleaseBundleInfo `json:"release_bundles"`
+	// VersionsByStatus counts the versions of all bundles by status. It is only collected for the OpenMetrics
+	// exposition, and is nil when the versions could not be counted.
+	VersionsByStatus map[string]int `json:"-"`
 }
 
 type ReleaseBundleInfo struct {
@@ -81,6 +89,15 @@ type ArtifactoryStats struct {
 	ServerUrl               string
 	DisplayLimit            int
 	ProjectCount            int
+	SavePath                string
+	ComparePath             string
+	ServeAddress            string
+	// Collectors names the collectors to run; those that are not opt-in run when empty.
+	Collectors []string
+	// CollectorTimeout overrides the timeout of every collector when set.
+	CollectorTimeout time.Duration
+	// storage is the storage info cache of the current run, swapped atomically between runs.
+	storage atomic.Pointer[storageInfoCache]
 }
 
 func NewArtifactoryStatsCommand() *ArtifactoryStats {
@@ -107,7 +124,35 @@ func (sa *ArtifactoryStats) SetDisplayLimit(displayLimit int) *ArtifactoryStats
 	return sa
 }
 
+func (sa *ArtifactoryStats) SetSavePath(path string) *ArtifactoryStats {
+	sa.SavePath = path
+	return sa
+}
+
+func (sa *ArtifactoryStats) SetComparePath(path string) *ArtifactoryStats {
+	sa.ComparePath = path
+	return sa
+}
+
+func (sa *ArtifactoryStats) SetServeAddress(address string) *ArtifactoryStats {
+	sa.ServeAddress = address
+	return sa
+}
+
+func (sa *ArtifactoryStats) SetCollectors(names []string) *ArtifactoryStats {
+	sa.Collectors = names
+	return sa
+}
+
+func (sa *ArtifactoryStats) SetCollectorTimeout(timeout time.Duration) *ArtifactoryStats {
+	sa.CollectorTimeout = timeout
+	return sa
+}
+
 func (sa *ArtifactoryStats) Run() error {
+	if _, err := sa.selectedCollectors(); err != nil {
+		return err
+	}
 	serverDetails, err := config.GetSpecificConfig(sa.ServerId, true, false)
 	if err != nil {
 		return err
@@ -136,6 +181,9 @@ func (sa *ArtifactoryStats) Run() error {
 	}
 	sa.JPDServicesManager = *jpdServiceManager
 	sa.ServerUrl = serverDetails.Url
+	if sa.ServeAddress != "" {
+		return sa.Serve()
+	}
 	err = sa.GetStats()
 	if err != nil {
 		return err
@@ -145,44 +193,110 @@ func (sa *ArtifactoryStats) Run() error {
 
 type StatsFunc func() interface{}
 
+// GetCommandList returns the collect function of every registered collector by name.
 func (sa *ArtifactoryStats) GetCommandList() map[string]StatsFunc {
-	return map[string]StatsFunc{
-		"project": sa.GetProjectsStats,
-		"rb":      sa.GetReleaseBundlesStats,
-		"jpd":     sa.GetJPDsStats,
-		"rt":      sa.GetArtifactoryStats,
+	commandList := make(map[string]StatsFunc, len(collectorRegistry))
+	for _, collector := range collectorRegistry {
+		collect := collector.Collect
+		commandList[collector.Name] = func() interface{} { return collect(context.Background(), sa) }
 	}
+	return commandList
 }
 
 func (sa *ArtifactoryStats) GetStats() error {
-	commandList := sa.GetCommandList()
-	allResultsMap := make(map[string]interface{}, 0)
-	for name, statsFunc := range commandList {
-		allResultsMap[name] = statsFunc()
+	// Read the snapshot to compare with first, so a single file can be compared with and then overwritten.
+	var previous *StatsSnapshot
+	if sa.ComparePath != "" {
+		if sa.Format == openMetricsFormat {
+			return fmt.Errorf("--compare is not supported with the %s format", openMetricsFormat)
+		}
+		var err error
+		if previous, err = LoadStatsSnapshot(sa.ComparePath); err != nil {
+			return err
+		}
+		if !sameServerUrl(previous.ServerUrl, sa.ServerUrl) {
+			log.Warn(fmt.Sprintf("The snapshot %s was taken on %s, not on %s; the changes compare two different servers.", sa.ComparePath, previous.ServerUrl, sa.ServerUrl))
+		}
+	}
+	allResultsMap, err := sa.collectStats()
+	if err != nil {
+		return err
+	}
+	var snapshot *StatsSnapshot
+	var comparison *StatsComparison
+	if previous != nil || sa.SavePath != "" {
+		snapshot = NewStatsSnapshot(allResultsMap, sa.ServerUrl)
+	}
+	if previous != nil {
+		comparison = CompareStatsSnapshots(previous, snapshot)
+	}
+	if sa.Format == htmlFormat {
+		// The html format renders the whole run, including the comparison, as one page.
+		report := &StatsReport{ServerUrl: sa.ServerUrl, Generated: time.Now().UTC(), Results: allResultsMap, Comparison: comparison}
+		if err := NewGenericResultsWriter(report, sa.Format, sa.DisplayLimit).Print(); err != nil {
+			return err
+		}
+	} else {
+		if err := sa.PrintAllResults(allResultsMap); err != nil {
+			return err
+		}
+		if comparison != nil {
+			if err := PrintStatsComparison(comparison, sa.Format); err != nil {
+				return err
+			}
+		}
 	}
-	return sa.PrintAllResults(allResultsMap)
+	if sa.SavePath != "" {
+		if err := SaveStatsSnapshot(snapshot, sa.SavePath); err != nil {
+			return err
+		}
+		log.Info("Saved stats snapshot to", sa.SavePath)
+	}
+	return nil
 }
 
+// PrintAllResults prints the sections in collector order, followed by the collectors that failed.
 func (sa *ArtifactoryStats) PrintAllResults(results map[string]interface{}) error {
-	printOrder := []string{"rt", "jpd", "rb", "project"}
-	for _, stats := range printOrder {
-		err := NewGenericResultsWriter(results[stats], sa.Format, sa.DisplayLimit).Print()
-		if err != nil {
-			log.Error("Failed to print result:", err)
+	var failures []interface{}
+	for _, collector := range collectorRegistry {
+		result, found := results[collector.Name]
+		if !found {
+			continue
 		}
+		if _, failed := result.(*jpd.GenericError); failed {
+			failures = append(failures, result)
+			continue
+		}
+		sa.printResult(result)
+	}
+	for _, failure := range failures {
+		sa.printResult(failure)
+	}
+	switch {
+	case sa.Format == openMetricsFormat:
+		log.Output(strings.TrimSuffix(openMetricsEOF, "\n"))
+	case len(failures) > 0:
+		log.Warn(fmt.Sprintf("%d of %d stats collectors failed; their sections are incomplete.", len(failures), len(results)))
 	}
 	return nil
 }
 
+func (sa *ArtifactoryStats) printResult(result interface{}) {
+	if err := NewGenericResultsWriter(result, sa.Format, sa.DisplayLimit).Print(); err != nil {
+		log.Error("Failed to print result:", err)
+	}
+}
+
 func (sa *ArtifactoryStats) GetArtifactoryStats() interface{} {
 	var artifactoryStatsSummary ArtifactoryStatsSummary
-	storageInfo, err := sa.ServicesManager.GetStorageInfo()
+	storageInfo, err := sa.getStorageInfo()
 	if err != nil {
 		wrappedError := fmt.Errorf("failed to build ARTIFACTORY API endpoint: %w", err)
 		return jpd.NewGenericError("ARTIFACTORY", wrappedError)
 	}
 	artifactoryStatsSummary.TotalArtifactsCount = storageInfo.ArtifactsCount
 	artifactoryStatsSummary.TotalArtifactsSize = storageInfo.ArtifactsSize
+	artifactoryStatsSummary.TotalArtifactsSizeBytes = usedSpaceInBytes(storageInfo)
 	artifactoryStatsSummary.TotalBinariesCount = storageInfo.BinariesCount
 	artifactoryStatsSummary.TotalBinariesSize = storageInfo.BinariesSize
 	artifactoryStatsSummary.StorageType = storageInfo.StorageType
@@ -192,7 +306,6 @@ func (sa *ArtifactoryStats) GetArtifactoryStats() interface{} {
 		return jpd.NewGenericError("ARTIFACTORY", wrappedError)
 	}
 	artifactoryStatsSummary.RepositoriesDetails = *repositoriesDetails
-	artifactoryStatsSummary.ProjectsCount = sa.ProjectCount
 	return &artifactoryStatsSummary
 }
 
@@ -206,7 +319,6 @@ func (sa *ArtifactoryStats) GetProjectsStats() interface{} {
 		wrappedError := fmt.Errorf("failed to call PROJECTS API: %w", err)
 		return jpd.NewGenericError("PROJECTS", wrappedError)
 	}
-	sa.ProjectCount = len(projects)
 	return projects
 }
 